@@ -30,46 +30,102 @@ type Initialization struct {
 	AttendanceRepo         repository.AttendanceRepository
 	DashboardRepo          repository.DashboardRepository
 	NotifRepo              repository.NotificationRepository
+	CustomerRepo           repository.CustomerRepository
+	ReceivableRepo         repository.ReceivableRepository
+	BarcodeTemplateRepo    repository.BarcodeTemplateRepository
+	ReceiptTemplateRepo    repository.ReceiptTemplateRepository
+	EmailOutboxRepo        repository.EmailOutboxRepository
+	ApprovalRepo           repository.ApprovalRepository
+	StockMovementRepo      repository.StockMovementRepository
+	StockOpnameRepo        repository.StockOpnameRepository
+	StockAdjustmentRepo    repository.StockAdjustmentRepository
+	SupplierRepo           repository.SupplierRepository
+	PurchaseOrderRepo      repository.PurchaseOrderRepository
+	ReportRepo             repository.ReportRepository
+	StockLotRepo           repository.StockLotRepository
+	ReplenishmentRepo      repository.ReplenishmentRepository
+	CatalogRepo            repository.CatalogRepository
+	CategoryRepo           repository.CategoryRepository
+	BrandRepo              repository.BrandRepository
+	ProductImportJobRepo   repository.ProductImportJobRepository
+	BarcodeSequenceRepo    repository.BarcodeSequenceRepository
+	PriceChangeRepo        repository.PriceChangeRepository
 
-	AdminSvc          service.AdminService
-	ClientSvc         service.ClientService
-	CompanySvc        service.CompanyService
-	ParentMenuSvc     service.ParentMenuService
-	MenuSvc           service.MenuService
-	RoleSvc           service.RoleService
-	RoleAccessMenuSvc service.RoleMenuAccessService
-	AuthSvc           service.AuthService
-	SubscriptionSvc   service.SubscriptionService
-	FileSvc           service.FileService
-	ClientBranchSvc   service.ClientBranchService
-	UserSvc           service.UserService
-	ClientUserSvc     service.ClientUserService
-	ProductSvc        service.ProductService
-	StockTransferSvc  service.StockTransferService
-	PosTransactionSvc service.POSTransactionService
-	AttendanceSvc     service.AttendanceService
-	DashboardSvc      service.DashboardService
-	NotifSvc          service.NotificationService
+	AdminSvc           service.AdminService
+	ClientSvc          service.ClientService
+	CompanySvc         service.CompanyService
+	ParentMenuSvc      service.ParentMenuService
+	MenuSvc            service.MenuService
+	RoleSvc            service.RoleService
+	RoleAccessMenuSvc  service.RoleMenuAccessService
+	AuthSvc            service.AuthService
+	SubscriptionSvc    service.SubscriptionService
+	FileSvc            service.FileService
+	ClientBranchSvc    service.ClientBranchService
+	UserSvc            service.UserService
+	ClientUserSvc      service.ClientUserService
+	ProductSvc         service.ProductService
+	StockTransferSvc   service.StockTransferService
+	PosTransactionSvc  service.POSTransactionService
+	AttendanceSvc      service.AttendanceService
+	DashboardSvc       service.DashboardService
+	NotifSvc           service.NotificationService
+	CustomerSvc        service.CustomerService
+	ReceivableSvc      service.ReceivableService
+	BarcodeTemplateSvc service.BarcodeTemplateService
+	ReceiptSvc         service.ReceiptService
+	ApprovalSvc        service.ApprovalService
+	StockOpnameSvc     service.StockOpnameService
+	StockAdjustmentSvc service.StockAdjustmentService
+	SupplierSvc        service.SupplierService
+	PurchaseOrderSvc   service.PurchaseOrderService
+	ReportSvc          service.ReportService
+	StockLotSvc        service.StockLotService
+	ReplenishmentSvc   service.ReplenishmentService
+	CatalogSvc         service.CatalogService
+	CategorySvc        service.CategoryService
+	BrandSvc           service.BrandService
+	ProductImportSvc   service.ProductImportService
+	BarcodeLabelSvc    service.BarcodeLabelService
+	PriceChangeSvc     service.PriceChangeService
 
-	AdminCtrl          controller.AdminController
-	ClientCtrl         controller.ClientController
-	CompanyCtrl        controller.CompanyController
-	ParentMenuCtrl     controller.ParentMenuController
-	MenuCtrl           controller.MenuController
-	RoleCtrl           controller.RoleController
-	RoleAccessMenuCtrl controller.RoleAccessMenuController
-	AuthCtrl           controller.AuthController
-	SubscriptionCtrl   controller.SubscriptionController
-	FileCtrl           controller.FileController
-	ClientBranchCtrl   controller.ClientBranchController
-	UserCtrl           controller.UserController
-	ClientUserCtrl     controller.ClientUserController
-	ProductCtrl        controller.ProductController
-	StockTransferCtrl  controller.StockTransferController
-	PosTransactionCtrl controller.POSTransactionController
-	AttendanceCtrl     controller.AttendanceController
-	DashboardCtrl      controller.DashboardController
-	NotifCtrl          controller.NotificationController
+	AdminCtrl           controller.AdminController
+	ClientCtrl          controller.ClientController
+	CompanyCtrl         controller.CompanyController
+	ParentMenuCtrl      controller.ParentMenuController
+	MenuCtrl            controller.MenuController
+	RoleCtrl            controller.RoleController
+	RoleAccessMenuCtrl  controller.RoleAccessMenuController
+	AuthCtrl            controller.AuthController
+	SubscriptionCtrl    controller.SubscriptionController
+	FileCtrl            controller.FileController
+	ClientBranchCtrl    controller.ClientBranchController
+	UserCtrl            controller.UserController
+	ClientUserCtrl      controller.ClientUserController
+	ProductCtrl         controller.ProductController
+	StockTransferCtrl   controller.StockTransferController
+	PosTransactionCtrl  controller.POSTransactionController
+	AttendanceCtrl      controller.AttendanceController
+	DashboardCtrl       controller.DashboardController
+	NotifCtrl           controller.NotificationController
+	CustomerCtrl        controller.CustomerController
+	ReceivableCtrl      controller.ReceivableController
+	BarcodeTemplateCtrl controller.BarcodeTemplateController
+	ReceiptCtrl         controller.ReceiptController
+	ApprovalCtrl        controller.ApprovalController
+	StockOpnameCtrl     controller.StockOpnameController
+	StockAdjustmentCtrl controller.StockAdjustmentController
+	SupplierCtrl        controller.SupplierController
+	PurchaseOrderCtrl   controller.PurchaseOrderController
+	ReportCtrl          controller.ReportController
+	StockLotCtrl        controller.StockLotController
+	ReplenishmentCtrl   controller.ReplenishmentController
+	CatalogCtrl         controller.CatalogController
+	CategoryCtrl        controller.CategoryController
+	BrandCtrl           controller.BrandController
+	ProductImportCtrl   controller.ProductImportController
+	BarcodeLabelCtrl    controller.BarcodeLabelController
+	PriceChangeCtrl     controller.PriceChangeController
 }
 
 func NewInitialization(
@@ -95,6 +151,26 @@ func NewInitialization(
 	attendanceRepo repository.AttendanceRepository,
 	dashboardRepo repository.DashboardRepository,
 	notifRepo repository.NotificationRepository,
+	customerRepo repository.CustomerRepository,
+	receivableRepo repository.ReceivableRepository,
+	barcodeTemplateRepo repository.BarcodeTemplateRepository,
+	receiptTemplateRepo repository.ReceiptTemplateRepository,
+	emailOutboxRepo repository.EmailOutboxRepository,
+	approvalRepo repository.ApprovalRepository,
+	stockMovementRepo repository.StockMovementRepository,
+	stockOpnameRepo repository.StockOpnameRepository,
+	stockAdjustmentRepo repository.StockAdjustmentRepository,
+	supplierRepo repository.SupplierRepository,
+	purchaseOrderRepo repository.PurchaseOrderRepository,
+	reportRepo repository.ReportRepository,
+	stockLotRepo repository.StockLotRepository,
+	replenishmentRepo repository.ReplenishmentRepository,
+	catalogRepo repository.CatalogRepository,
+	categoryRepo repository.CategoryRepository,
+	brandRepo repository.BrandRepository,
+	productImportJobRepo repository.ProductImportJobRepository,
+	barcodeSequenceRepo repository.BarcodeSequenceRepository,
+	priceChangeRepo repository.PriceChangeRepository,
 
 	adminSvc service.AdminService,
 	clientSvc service.ClientService,
@@ -115,6 +191,24 @@ func NewInitialization(
 	attendanceSvc service.AttendanceService,
 	dashboardSvc service.DashboardService,
 	notifSvc service.NotificationService,
+	customerSvc service.CustomerService,
+	receivableSvc service.ReceivableService,
+	barcodeTemplateSvc service.BarcodeTemplateService,
+	receiptSvc service.ReceiptService,
+	approvalSvc service.ApprovalService,
+	stockOpnameSvc service.StockOpnameService,
+	stockAdjustmentSvc service.StockAdjustmentService,
+	supplierSvc service.SupplierService,
+	purchaseOrderSvc service.PurchaseOrderService,
+	reportSvc service.ReportService,
+	stockLotSvc service.StockLotService,
+	replenishmentSvc service.ReplenishmentService,
+	catalogSvc service.CatalogService,
+	categorySvc service.CategoryService,
+	brandSvc service.BrandService,
+	productImportSvc service.ProductImportService,
+	barcodeLabelSvc service.BarcodeLabelService,
+	priceChangeSvc service.PriceChangeService,
 
 	adminCtrl controller.AdminController,
 	clientCtrl controller.ClientController,
@@ -135,6 +229,24 @@ func NewInitialization(
 	attendanceCtrl controller.AttendanceController,
 	dashboardCtrl controller.DashboardController,
 	notifCtrl controller.NotificationController,
+	customerCtrl controller.CustomerController,
+	receivableCtrl controller.ReceivableController,
+	barcodeTemplateCtrl controller.BarcodeTemplateController,
+	receiptCtrl controller.ReceiptController,
+	approvalCtrl controller.ApprovalController,
+	stockOpnameCtrl controller.StockOpnameController,
+	stockAdjustmentCtrl controller.StockAdjustmentController,
+	supplierCtrl controller.SupplierController,
+	purchaseOrderCtrl controller.PurchaseOrderController,
+	reportCtrl controller.ReportController,
+	stockLotCtrl controller.StockLotController,
+	replenishmentCtrl controller.ReplenishmentController,
+	catalogCtrl controller.CatalogController,
+	categoryCtrl controller.CategoryController,
+	brandCtrl controller.BrandController,
+	productImportCtrl controller.ProductImportController,
+	barcodeLabelCtrl controller.BarcodeLabelController,
+	priceChangeCtrl controller.PriceChangeController,
 ) *Initialization {
 	return &Initialization{
 		AdminRepo:              adminRepo,
@@ -159,45 +271,101 @@ func NewInitialization(
 		AttendanceRepo:         attendanceRepo,
 		DashboardRepo:          dashboardRepo,
 		NotifRepo:              notifRepo,
+		CustomerRepo:           customerRepo,
+		ReceivableRepo:         receivableRepo,
+		BarcodeTemplateRepo:    barcodeTemplateRepo,
+		ReceiptTemplateRepo:    receiptTemplateRepo,
+		EmailOutboxRepo:        emailOutboxRepo,
+		ApprovalRepo:           approvalRepo,
+		StockMovementRepo:      stockMovementRepo,
+		StockOpnameRepo:        stockOpnameRepo,
+		StockAdjustmentRepo:    stockAdjustmentRepo,
+		SupplierRepo:           supplierRepo,
+		PurchaseOrderRepo:      purchaseOrderRepo,
+		ReportRepo:             reportRepo,
+		StockLotRepo:           stockLotRepo,
+		ReplenishmentRepo:      replenishmentRepo,
+		CatalogRepo:            catalogRepo,
+		CategoryRepo:           categoryRepo,
+		BrandRepo:              brandRepo,
+		ProductImportJobRepo:   productImportJobRepo,
+		BarcodeSequenceRepo:    barcodeSequenceRepo,
+		PriceChangeRepo:        priceChangeRepo,
 
-		AdminSvc:          adminSvc,
-		ClientSvc:         clientSvc,
-		CompanySvc:        companySvc,
-		ParentMenuSvc:     parentMenuSvc,
-		MenuSvc:           menuSvc,
-		RoleSvc:           roleSvc,
-		RoleAccessMenuSvc: roleAccessMenuSvc,
-		AuthSvc:           authSvc,
-		SubscriptionSvc:   subscriptionSvc,
-		FileSvc:           fileSvc,
-		ClientBranchSvc:   clientBranchSvc,
-		UserSvc:           userSvc,
-		ClientUserSvc:     clientUserSvc,
-		ProductSvc:        productSvc,
-		StockTransferSvc:  stockTransferSvc,
-		PosTransactionSvc: posTransactionSvc,
-		AttendanceSvc:     attendanceSvc,
-		DashboardSvc:      dashboardSvc,
-		NotifSvc:          notifSvc,
+		AdminSvc:           adminSvc,
+		ClientSvc:          clientSvc,
+		CompanySvc:         companySvc,
+		ParentMenuSvc:      parentMenuSvc,
+		MenuSvc:            menuSvc,
+		RoleSvc:            roleSvc,
+		RoleAccessMenuSvc:  roleAccessMenuSvc,
+		AuthSvc:            authSvc,
+		SubscriptionSvc:    subscriptionSvc,
+		FileSvc:            fileSvc,
+		ClientBranchSvc:    clientBranchSvc,
+		UserSvc:            userSvc,
+		ClientUserSvc:      clientUserSvc,
+		ProductSvc:         productSvc,
+		StockTransferSvc:   stockTransferSvc,
+		PosTransactionSvc:  posTransactionSvc,
+		AttendanceSvc:      attendanceSvc,
+		DashboardSvc:       dashboardSvc,
+		NotifSvc:           notifSvc,
+		CustomerSvc:        customerSvc,
+		ReceivableSvc:      receivableSvc,
+		BarcodeTemplateSvc: barcodeTemplateSvc,
+		ReceiptSvc:         receiptSvc,
+		ApprovalSvc:        approvalSvc,
+		StockOpnameSvc:     stockOpnameSvc,
+		StockAdjustmentSvc: stockAdjustmentSvc,
+		SupplierSvc:        supplierSvc,
+		PurchaseOrderSvc:   purchaseOrderSvc,
+		ReportSvc:          reportSvc,
+		StockLotSvc:        stockLotSvc,
+		ReplenishmentSvc:   replenishmentSvc,
+		CatalogSvc:         catalogSvc,
+		CategorySvc:        categorySvc,
+		BrandSvc:           brandSvc,
+		ProductImportSvc:   productImportSvc,
+		BarcodeLabelSvc:    barcodeLabelSvc,
+		PriceChangeSvc:     priceChangeSvc,
 
-		AdminCtrl:          adminCtrl,
-		ClientCtrl:         clientCtrl,
-		CompanyCtrl:        companyCtrl,
-		ParentMenuCtrl:     parentMenuCtrl,
-		MenuCtrl:           menuCtrl,
-		RoleCtrl:           roleCtrl,
-		RoleAccessMenuCtrl: roleAccessMenuCtrl,
-		AuthCtrl:           authCtrl,
-		SubscriptionCtrl:   subscriptionCtrl,
-		FileCtrl:           fileCtrl,
-		ClientBranchCtrl:   clientBranchCtrl,
-		UserCtrl:           userCtrl,
-		ClientUserCtrl:     clientUserCtrl,
-		ProductCtrl:        productCtrl,
-		StockTransferCtrl:  stockTransferCtrl,
-		PosTransactionCtrl: posTransactionCtrl,
-		AttendanceCtrl:     attendanceCtrl,
-		DashboardCtrl:      dashboardCtrl,
-		NotifCtrl:          notifCtrl,
+		AdminCtrl:           adminCtrl,
+		ClientCtrl:          clientCtrl,
+		CompanyCtrl:         companyCtrl,
+		ParentMenuCtrl:      parentMenuCtrl,
+		MenuCtrl:            menuCtrl,
+		RoleCtrl:            roleCtrl,
+		RoleAccessMenuCtrl:  roleAccessMenuCtrl,
+		AuthCtrl:            authCtrl,
+		SubscriptionCtrl:    subscriptionCtrl,
+		FileCtrl:            fileCtrl,
+		ClientBranchCtrl:    clientBranchCtrl,
+		UserCtrl:            userCtrl,
+		ClientUserCtrl:      clientUserCtrl,
+		ProductCtrl:         productCtrl,
+		StockTransferCtrl:   stockTransferCtrl,
+		PosTransactionCtrl:  posTransactionCtrl,
+		AttendanceCtrl:      attendanceCtrl,
+		DashboardCtrl:       dashboardCtrl,
+		NotifCtrl:           notifCtrl,
+		CustomerCtrl:        customerCtrl,
+		ReceivableCtrl:      receivableCtrl,
+		BarcodeTemplateCtrl: barcodeTemplateCtrl,
+		ReceiptCtrl:         receiptCtrl,
+		ApprovalCtrl:        approvalCtrl,
+		StockOpnameCtrl:     stockOpnameCtrl,
+		StockAdjustmentCtrl: stockAdjustmentCtrl,
+		SupplierCtrl:        supplierCtrl,
+		PurchaseOrderCtrl:   purchaseOrderCtrl,
+		ReportCtrl:          reportCtrl,
+		StockLotCtrl:        stockLotCtrl,
+		ReplenishmentCtrl:   replenishmentCtrl,
+		CatalogCtrl:         catalogCtrl,
+		CategoryCtrl:        categoryCtrl,
+		BrandCtrl:           brandCtrl,
+		ProductImportCtrl:   productImportCtrl,
+		BarcodeLabelCtrl:    barcodeLabelCtrl,
+		PriceChangeCtrl:     priceChangeCtrl,
 	}
 }
@@ -41,6 +41,26 @@ var repoSet = wire.NewSet(
 	repository.AttendanceRepositoryInit, wire.Bind(new(repository.AttendanceRepository), new(*repository.AttendanceRepositoryImpl)),
 	repository.DashboardRepositoryInit, wire.Bind(new(repository.DashboardRepository), new(*repository.DashboardRepositoryImpl)),
 	repository.NotificationRepositoryInit, wire.Bind(new(repository.NotificationRepository), new(*repository.NotificationRepositoryImpl)),
+	repository.CustomerRepositoryInit, wire.Bind(new(repository.CustomerRepository), new(*repository.CustomerRepositoryImpl)),
+	repository.ReceivableRepositoryInit, wire.Bind(new(repository.ReceivableRepository), new(*repository.ReceivableRepositoryImpl)),
+	repository.BarcodeTemplateRepositoryInit, wire.Bind(new(repository.BarcodeTemplateRepository), new(*repository.BarcodeTemplateRepositoryImpl)),
+	repository.ReceiptTemplateRepositoryInit, wire.Bind(new(repository.ReceiptTemplateRepository), new(*repository.ReceiptTemplateRepositoryImpl)),
+	repository.EmailOutboxRepositoryInit, wire.Bind(new(repository.EmailOutboxRepository), new(*repository.EmailOutboxRepositoryImpl)),
+	repository.ApprovalRepositoryInit, wire.Bind(new(repository.ApprovalRepository), new(*repository.ApprovalRepositoryImpl)),
+	repository.StockMovementRepositoryInit, wire.Bind(new(repository.StockMovementRepository), new(*repository.StockMovementRepositoryImpl)),
+	repository.StockOpnameRepositoryInit, wire.Bind(new(repository.StockOpnameRepository), new(*repository.StockOpnameRepositoryImpl)),
+	repository.StockAdjustmentRepositoryInit, wire.Bind(new(repository.StockAdjustmentRepository), new(*repository.StockAdjustmentRepositoryImpl)),
+	repository.SupplierRepositoryInit, wire.Bind(new(repository.SupplierRepository), new(*repository.SupplierRepositoryImpl)),
+	repository.PurchaseOrderRepositoryInit, wire.Bind(new(repository.PurchaseOrderRepository), new(*repository.PurchaseOrderRepositoryImpl)),
+	repository.ReportRepositoryInit, wire.Bind(new(repository.ReportRepository), new(*repository.ReportRepositoryImpl)),
+	repository.StockLotRepositoryInit, wire.Bind(new(repository.StockLotRepository), new(*repository.StockLotRepositoryImpl)),
+	repository.ReplenishmentRepositoryInit, wire.Bind(new(repository.ReplenishmentRepository), new(*repository.ReplenishmentRepositoryImpl)),
+	repository.CatalogRepositoryInit, wire.Bind(new(repository.CatalogRepository), new(*repository.CatalogRepositoryImpl)),
+	repository.CategoryRepositoryInit, wire.Bind(new(repository.CategoryRepository), new(*repository.CategoryRepositoryImpl)),
+	repository.BrandRepositoryInit, wire.Bind(new(repository.BrandRepository), new(*repository.BrandRepositoryImpl)),
+	repository.ProductImportJobRepositoryInit, wire.Bind(new(repository.ProductImportJobRepository), new(*repository.ProductImportJobRepositoryImpl)),
+	repository.BarcodeSequenceRepositoryInit, wire.Bind(new(repository.BarcodeSequenceRepository), new(*repository.BarcodeSequenceRepositoryImpl)),
+	repository.PriceChangeRepositoryInit, wire.Bind(new(repository.PriceChangeRepository), new(*repository.PriceChangeRepositoryImpl)),
 )
 
 var serviceSet = wire.NewSet(
@@ -64,6 +84,24 @@ var serviceSet = wire.NewSet(
 	service.NewAttendanceService, wire.Bind(new(service.AttendanceService), new(*service.AttendanceServiceImpl)),
 	service.NewDashboardService, wire.Bind(new(service.DashboardService), new(*service.DashboardServiceImpl)),
 	service.NewNotificationService, wire.Bind(new(service.NotificationService), new(*service.NotificationServiceImpl)),
+	service.NewCustomerService, wire.Bind(new(service.CustomerService), new(*service.CustomerServiceImpl)),
+	service.NewReceivableService, wire.Bind(new(service.ReceivableService), new(*service.ReceivableServiceImpl)),
+	service.NewBarcodeTemplateService, wire.Bind(new(service.BarcodeTemplateService), new(*service.BarcodeTemplateServiceImpl)),
+	service.NewReceiptService, wire.Bind(new(service.ReceiptService), new(*service.ReceiptServiceImpl)),
+	service.NewApprovalService, wire.Bind(new(service.ApprovalService), new(*service.ApprovalServiceImpl)),
+	service.NewStockOpnameService, wire.Bind(new(service.StockOpnameService), new(*service.StockOpnameServiceImpl)),
+	service.NewStockAdjustmentService, wire.Bind(new(service.StockAdjustmentService), new(*service.StockAdjustmentServiceImpl)),
+	service.NewSupplierService, wire.Bind(new(service.SupplierService), new(*service.SupplierServiceImpl)),
+	service.NewPurchaseOrderService, wire.Bind(new(service.PurchaseOrderService), new(*service.PurchaseOrderServiceImpl)),
+	service.NewReportService, wire.Bind(new(service.ReportService), new(*service.ReportServiceImpl)),
+	service.NewStockLotService, wire.Bind(new(service.StockLotService), new(*service.StockLotServiceImpl)),
+	service.NewReplenishmentService, wire.Bind(new(service.ReplenishmentService), new(*service.ReplenishmentServiceImpl)),
+	service.NewCatalogService, wire.Bind(new(service.CatalogService), new(*service.CatalogServiceImpl)),
+	service.NewCategoryService, wire.Bind(new(service.CategoryService), new(*service.CategoryServiceImpl)),
+	service.NewBrandService, wire.Bind(new(service.BrandService), new(*service.BrandServiceImpl)),
+	service.NewProductImportService, wire.Bind(new(service.ProductImportService), new(*service.ProductImportServiceImpl)),
+	service.NewBarcodeLabelService, wire.Bind(new(service.BarcodeLabelService), new(*service.BarcodeLabelServiceImpl)),
+	service.NewPriceChangeService, wire.Bind(new(service.PriceChangeService), new(*service.PriceChangeServiceImpl)),
 )
 
 var controllerSet = wire.NewSet(
@@ -86,6 +124,24 @@ var controllerSet = wire.NewSet(
 	controller.AttendanceControllerInit, wire.Bind(new(controller.AttendanceController), new(*controller.AttendanceControllerImpl)),
 	controller.DashboardControllerInit, wire.Bind(new(controller.DashboardController), new(*controller.DashboardControllerImpl)),
 	controller.NotificationControllerInit, wire.Bind(new(controller.NotificationController), new(*controller.NotificationControllerImpl)),
+	controller.CustomerControllerInit, wire.Bind(new(controller.CustomerController), new(*controller.CustomerControllerImpl)),
+	controller.ReceivableControllerInit, wire.Bind(new(controller.ReceivableController), new(*controller.ReceivableControllerImpl)),
+	controller.BarcodeTemplateControllerInit, wire.Bind(new(controller.BarcodeTemplateController), new(*controller.BarcodeTemplateControllerImpl)),
+	controller.ReceiptControllerInit, wire.Bind(new(controller.ReceiptController), new(*controller.ReceiptControllerImpl)),
+	controller.ApprovalControllerInit, wire.Bind(new(controller.ApprovalController), new(*controller.ApprovalControllerImpl)),
+	controller.StockOpnameControllerInit, wire.Bind(new(controller.StockOpnameController), new(*controller.StockOpnameControllerImpl)),
+	controller.StockAdjustmentControllerInit, wire.Bind(new(controller.StockAdjustmentController), new(*controller.StockAdjustmentControllerImpl)),
+	controller.SupplierControllerInit, wire.Bind(new(controller.SupplierController), new(*controller.SupplierControllerImpl)),
+	controller.PurchaseOrderControllerInit, wire.Bind(new(controller.PurchaseOrderController), new(*controller.PurchaseOrderControllerImpl)),
+	controller.ReportControllerInit, wire.Bind(new(controller.ReportController), new(*controller.ReportControllerImpl)),
+	controller.StockLotControllerInit, wire.Bind(new(controller.StockLotController), new(*controller.StockLotControllerImpl)),
+	controller.ReplenishmentControllerInit, wire.Bind(new(controller.ReplenishmentController), new(*controller.ReplenishmentControllerImpl)),
+	controller.CatalogControllerInit, wire.Bind(new(controller.CatalogController), new(*controller.CatalogControllerImpl)),
+	controller.CategoryControllerInit, wire.Bind(new(controller.CategoryController), new(*controller.CategoryControllerImpl)),
+	controller.BrandControllerInit, wire.Bind(new(controller.BrandController), new(*controller.BrandControllerImpl)),
+	controller.ProductImportControllerInit, wire.Bind(new(controller.ProductImportController), new(*controller.ProductImportControllerImpl)),
+	controller.BarcodeLabelControllerInit, wire.Bind(new(controller.BarcodeLabelController), new(*controller.BarcodeLabelControllerImpl)),
+	controller.PriceChangeControllerInit, wire.Bind(new(controller.PriceChangeController), new(*controller.PriceChangeControllerImpl)),
 )
 
 func Init() *Initialization {
@@ -40,6 +40,26 @@ func Init() *Initialization {
 	attendanceRepositoryImpl := repository.AttendanceRepositoryInit(client)
 	dashboardRepositoryImpl := repository.DashboardRepositoryInit(client)
 	notificationRepositoryImpl := repository.NotificationRepositoryInit(client)
+	customerRepositoryImpl := repository.CustomerRepositoryInit(client)
+	receivableRepositoryImpl := repository.ReceivableRepositoryInit(client)
+	barcodeTemplateRepositoryImpl := repository.BarcodeTemplateRepositoryInit(client)
+	receiptTemplateRepositoryImpl := repository.ReceiptTemplateRepositoryInit(client)
+	emailOutboxRepositoryImpl := repository.EmailOutboxRepositoryInit(client)
+	approvalRepositoryImpl := repository.ApprovalRepositoryInit(client)
+	stockMovementRepositoryImpl := repository.StockMovementRepositoryInit(client)
+	stockOpnameRepositoryImpl := repository.StockOpnameRepositoryInit(client)
+	stockAdjustmentRepositoryImpl := repository.StockAdjustmentRepositoryInit(client)
+	supplierRepositoryImpl := repository.SupplierRepositoryInit(client)
+	purchaseOrderRepositoryImpl := repository.PurchaseOrderRepositoryInit(client)
+	reportRepositoryImpl := repository.ReportRepositoryInit(client)
+	stockLotRepositoryImpl := repository.StockLotRepositoryInit(client)
+	replenishmentRepositoryImpl := repository.ReplenishmentRepositoryInit(client)
+	catalogRepositoryImpl := repository.CatalogRepositoryInit(client)
+	categoryRepositoryImpl := repository.CategoryRepositoryInit(client)
+	brandRepositoryImpl := repository.BrandRepositoryInit(client)
+	productImportJobRepositoryImpl := repository.ProductImportJobRepositoryInit(client)
+	barcodeSequenceRepositoryImpl := repository.BarcodeSequenceRepositoryInit(client)
+	priceChangeRepositoryImpl := repository.PriceChangeRepositoryInit(client)
 	adminServiceImpl := service.NewAdminService(adminRepositoryImpl)
 	clientServiceImpl := service.NewClientService(clientRepositoryImpl)
 	companyServiceImpl := service.NewCompanyService(companyRepositoryImpl)
@@ -55,12 +75,30 @@ func Init() *Initialization {
 	clientBranchServiceImpl := service.NewClientBranchService(clientBranchRepositoryImpl)
 	userServiceImpl := service.NewUserService(userRepositoryImpl)
 	clientUserServiceImpl := service.NewClientUserService(clientUserRepositoryImpl)
-	productServiceImpl := service.NewProductService(productRepositoryImpl)
-	stockTransferServiceImpl := service.NewStockTransferService(stockTransferRepositoryImpl, authRepositoryImpl, notificationRepositoryImpl)
-	posTransactionServiceImpl := service.NewPOSTransactionService(posTransactionRepositoryImpl, productRepositoryImpl, authRepositoryImpl, notificationRepositoryImpl)
+	productServiceImpl := service.NewProductService(productRepositoryImpl, stockMovementRepositoryImpl, categoryRepositoryImpl, brandRepositoryImpl, clientBranchRepositoryImpl, priceChangeRepositoryImpl, authRepositoryImpl, approvalRepositoryImpl)
+	stockTransferServiceImpl := service.NewStockTransferService(stockTransferRepositoryImpl, authRepositoryImpl, notificationRepositoryImpl, clientBranchRepositoryImpl, userRepositoryImpl)
+	posTransactionServiceImpl := service.NewPOSTransactionService(posTransactionRepositoryImpl, productRepositoryImpl, authRepositoryImpl, notificationRepositoryImpl, customerRepositoryImpl, receivableRepositoryImpl, barcodeTemplateRepositoryImpl, approvalRepositoryImpl, stockLotRepositoryImpl, catalogRepositoryImpl, priceChangeRepositoryImpl)
 	attendanceServiceImpl := service.NewAttendanceService(attendanceRepositoryImpl)
-	dashboardServiceImpl := service.NewDashboardService(dashboardRepositoryImpl, authRepositoryImpl, notificationRepositoryImpl)
+	dashboardServiceImpl := service.NewDashboardService(dashboardRepositoryImpl, reportRepositoryImpl, categoryRepositoryImpl, authRepositoryImpl)
 	notificationServiceImpl := service.NewNotificationService(notificationRepositoryImpl, authRepositoryImpl)
+	customerServiceImpl := service.NewCustomerService(customerRepositoryImpl, posTransactionRepositoryImpl, authRepositoryImpl)
+	receivableServiceImpl := service.NewReceivableService(receivableRepositoryImpl, customerRepositoryImpl, authRepositoryImpl, notificationRepositoryImpl)
+	barcodeTemplateServiceImpl := service.NewBarcodeTemplateService(barcodeTemplateRepositoryImpl, authRepositoryImpl)
+	receiptServiceImpl := service.NewReceiptService(posTransactionRepositoryImpl, clientBranchRepositoryImpl, receiptTemplateRepositoryImpl, authRepositoryImpl, emailOutboxRepositoryImpl, customerRepositoryImpl)
+	approvalServiceImpl := service.NewApprovalService(approvalRepositoryImpl, authRepositoryImpl, notificationRepositoryImpl)
+	stockOpnameServiceImpl := service.NewStockOpnameService(stockOpnameRepositoryImpl, productRepositoryImpl, stockMovementRepositoryImpl, clientBranchRepositoryImpl, authRepositoryImpl, notificationRepositoryImpl, stockLotRepositoryImpl)
+	stockAdjustmentServiceImpl := service.NewStockAdjustmentService(stockAdjustmentRepositoryImpl, productRepositoryImpl, approvalRepositoryImpl, clientBranchRepositoryImpl, authRepositoryImpl, notificationRepositoryImpl, stockLotRepositoryImpl)
+	supplierServiceImpl := service.NewSupplierService(supplierRepositoryImpl, authRepositoryImpl)
+	purchaseOrderServiceImpl := service.NewPurchaseOrderService(purchaseOrderRepositoryImpl, supplierRepositoryImpl, productRepositoryImpl, clientBranchRepositoryImpl, authRepositoryImpl, notificationRepositoryImpl, stockLotRepositoryImpl)
+	reportServiceImpl := service.NewReportService(reportRepositoryImpl, dashboardRepositoryImpl, categoryRepositoryImpl, clientBranchRepositoryImpl, authRepositoryImpl)
+	stockLotServiceImpl := service.NewStockLotService(stockLotRepositoryImpl, dashboardRepositoryImpl, clientBranchRepositoryImpl, authRepositoryImpl, notificationRepositoryImpl)
+	replenishmentServiceImpl := service.NewReplenishmentService(replenishmentRepositoryImpl, productRepositoryImpl, stockTransferRepositoryImpl, purchaseOrderRepositoryImpl, supplierRepositoryImpl, dashboardRepositoryImpl, clientBranchRepositoryImpl, authRepositoryImpl, notificationRepositoryImpl)
+	catalogServiceImpl := service.NewCatalogService(catalogRepositoryImpl, categoryRepositoryImpl, brandRepositoryImpl, clientBranchRepositoryImpl, authRepositoryImpl, priceChangeRepositoryImpl)
+	categoryServiceImpl := service.NewCategoryService(categoryRepositoryImpl, authRepositoryImpl)
+	brandServiceImpl := service.NewBrandService(brandRepositoryImpl, authRepositoryImpl)
+	productImportServiceImpl := service.NewProductImportService(productImportJobRepositoryImpl, productRepositoryImpl, categoryRepositoryImpl, brandRepositoryImpl, clientBranchRepositoryImpl, authRepositoryImpl, notificationRepositoryImpl, priceChangeRepositoryImpl)
+	barcodeLabelServiceImpl := service.NewBarcodeLabelService(barcodeSequenceRepositoryImpl, catalogRepositoryImpl, productRepositoryImpl, barcodeTemplateRepositoryImpl, clientBranchRepositoryImpl, authRepositoryImpl)
+	priceChangeServiceImpl := service.NewPriceChangeService(priceChangeRepositoryImpl, catalogRepositoryImpl, productRepositoryImpl, approvalRepositoryImpl, notificationRepositoryImpl, clientBranchRepositoryImpl, authRepositoryImpl)
 	adminControllerImpl := controller.AdminControllerInit(adminServiceImpl)
 	clientControllerImpl := controller.ClientControllerInit(clientServiceImpl)
 	companyControllerImpl := controller.CompanyControllerInit(companyServiceImpl)
@@ -80,7 +118,25 @@ func Init() *Initialization {
 	attendanceControllerImpl := controller.AttendanceControllerInit(attendanceServiceImpl)
 	dashboardControllerImpl := controller.DashboardControllerInit(dashboardServiceImpl)
 	notificationControllerImpl := controller.NotificationControllerInit(notificationServiceImpl)
-	initialization := NewInitialization(adminRepositoryImpl, clientRepositoryImpl, companyRepositoryImpl, parentMenuRepositoryImpl, menuRepositoryImpl, roleRepositoryImpl, roleMenuAccessRepositoryImpl, companyUserRepositoryImpl, clientUserRepositoryImpl, authRepositoryImpl, rateLimitRepositoryImpl, clientSubscriptionRepositoryImpl, subscriptionRepositoryImpl, imageRepositoryImpl, clientBranchRepositoryImpl, userRepositoryImpl, productRepositoryImpl, stockTransferRepositoryImpl, posTransactionRepositoryImpl, attendanceRepositoryImpl, dashboardRepositoryImpl, notificationRepositoryImpl, adminServiceImpl, clientServiceImpl, companyServiceImpl, parentMenuServiceImpl, menuServiceImpl, roleServiceImpl, roleMenuAccessServiceImpl, authServiceImpl, subscriptionServiceImpl, fileServiceImpl, clientBranchServiceImpl, userServiceImpl, clientUserServiceImpl, productServiceImpl, stockTransferServiceImpl, posTransactionServiceImpl, attendanceServiceImpl, dashboardServiceImpl, notificationServiceImpl, adminControllerImpl, clientControllerImpl, companyControllerImpl, parentMenuControllerImpl, menuControllerImpl, roleControllerImpl, roleAccessMenuControllerImpl, authControllerImpl, subscriptionControllerImpl, fileControllerImpl, clientBranchControllerImpl, userControllerImpl, clientUserControllerImpl, productControllerImpl, stockTransferControllerImpl, posTransactionControllerImpl, attendanceControllerImpl, dashboardControllerImpl, notificationControllerImpl)
+	customerControllerImpl := controller.CustomerControllerInit(customerServiceImpl)
+	receivableControllerImpl := controller.ReceivableControllerInit(receivableServiceImpl)
+	barcodeTemplateControllerImpl := controller.BarcodeTemplateControllerInit(barcodeTemplateServiceImpl)
+	receiptControllerImpl := controller.ReceiptControllerInit(receiptServiceImpl)
+	approvalControllerImpl := controller.ApprovalControllerInit(approvalServiceImpl)
+	stockOpnameControllerImpl := controller.StockOpnameControllerInit(stockOpnameServiceImpl)
+	stockAdjustmentControllerImpl := controller.StockAdjustmentControllerInit(stockAdjustmentServiceImpl)
+	supplierControllerImpl := controller.SupplierControllerInit(supplierServiceImpl)
+	purchaseOrderControllerImpl := controller.PurchaseOrderControllerInit(purchaseOrderServiceImpl)
+	reportControllerImpl := controller.ReportControllerInit(reportServiceImpl)
+	stockLotControllerImpl := controller.StockLotControllerInit(stockLotServiceImpl)
+	replenishmentControllerImpl := controller.ReplenishmentControllerInit(replenishmentServiceImpl)
+	catalogControllerImpl := controller.CatalogControllerInit(catalogServiceImpl)
+	categoryControllerImpl := controller.CategoryControllerInit(categoryServiceImpl)
+	brandControllerImpl := controller.BrandControllerInit(brandServiceImpl)
+	productImportControllerImpl := controller.ProductImportControllerInit(productImportServiceImpl)
+	barcodeLabelControllerImpl := controller.BarcodeLabelControllerInit(barcodeLabelServiceImpl)
+	priceChangeControllerImpl := controller.PriceChangeControllerInit(priceChangeServiceImpl)
+	initialization := NewInitialization(adminRepositoryImpl, clientRepositoryImpl, companyRepositoryImpl, parentMenuRepositoryImpl, menuRepositoryImpl, roleRepositoryImpl, roleMenuAccessRepositoryImpl, companyUserRepositoryImpl, clientUserRepositoryImpl, authRepositoryImpl, rateLimitRepositoryImpl, clientSubscriptionRepositoryImpl, subscriptionRepositoryImpl, imageRepositoryImpl, clientBranchRepositoryImpl, userRepositoryImpl, productRepositoryImpl, stockTransferRepositoryImpl, posTransactionRepositoryImpl, attendanceRepositoryImpl, dashboardRepositoryImpl, notificationRepositoryImpl, customerRepositoryImpl, receivableRepositoryImpl, barcodeTemplateRepositoryImpl, receiptTemplateRepositoryImpl, emailOutboxRepositoryImpl, approvalRepositoryImpl, stockMovementRepositoryImpl, stockOpnameRepositoryImpl, stockAdjustmentRepositoryImpl, supplierRepositoryImpl, purchaseOrderRepositoryImpl, reportRepositoryImpl, stockLotRepositoryImpl, replenishmentRepositoryImpl, catalogRepositoryImpl, categoryRepositoryImpl, brandRepositoryImpl, productImportJobRepositoryImpl, barcodeSequenceRepositoryImpl, priceChangeRepositoryImpl, adminServiceImpl, clientServiceImpl, companyServiceImpl, parentMenuServiceImpl, menuServiceImpl, roleServiceImpl, roleMenuAccessServiceImpl, authServiceImpl, subscriptionServiceImpl, fileServiceImpl, clientBranchServiceImpl, userServiceImpl, clientUserServiceImpl, productServiceImpl, stockTransferServiceImpl, posTransactionServiceImpl, attendanceServiceImpl, dashboardServiceImpl, notificationServiceImpl, customerServiceImpl, receivableServiceImpl, barcodeTemplateServiceImpl, receiptServiceImpl, approvalServiceImpl, stockOpnameServiceImpl, stockAdjustmentServiceImpl, supplierServiceImpl, purchaseOrderServiceImpl, reportServiceImpl, stockLotServiceImpl, replenishmentServiceImpl, catalogServiceImpl, categoryServiceImpl, brandServiceImpl, productImportServiceImpl, barcodeLabelServiceImpl, priceChangeServiceImpl, adminControllerImpl, clientControllerImpl, companyControllerImpl, parentMenuControllerImpl, menuControllerImpl, roleControllerImpl, roleAccessMenuControllerImpl, authControllerImpl, subscriptionControllerImpl, fileControllerImpl, clientBranchControllerImpl, userControllerImpl, clientUserControllerImpl, productControllerImpl, stockTransferControllerImpl, posTransactionControllerImpl, attendanceControllerImpl, dashboardControllerImpl, notificationControllerImpl, customerControllerImpl, receivableControllerImpl, barcodeTemplateControllerImpl, receiptControllerImpl, approvalControllerImpl, stockOpnameControllerImpl, stockAdjustmentControllerImpl, supplierControllerImpl, purchaseOrderControllerImpl, reportControllerImpl, stockLotControllerImpl, replenishmentControllerImpl, catalogControllerImpl, categoryControllerImpl, brandControllerImpl, productImportControllerImpl, barcodeLabelControllerImpl, priceChangeControllerImpl)
 	return initialization
 }
 
@@ -90,8 +146,8 @@ var db = wire.NewSet(ConnectToMongoDB)
 
 var r2Set = wire.NewSet(r2.MustNew)
 
-var repoSet = wire.NewSet(repository.AdminRepositoryInit, wire.Bind(new(repository.AdminRepository), new(*repository.AdminRepositoryImpl)), repository.ClientRepositoryInit, wire.Bind(new(repository.ClientRepository), new(*repository.ClientRepositoryImpl)), repository.CompanyRepositoryInit, wire.Bind(new(repository.CompanyRepository), new(*repository.CompanyRepositoryImpl)), repository.ParentMenuRepositoryInit, wire.Bind(new(repository.ParentMenuRepository), new(*repository.ParentMenuRepositoryImpl)), repository.MenuRepositoryInit, wire.Bind(new(repository.MenuRepository), new(*repository.MenuRepositoryImpl)), repository.RoleRepositoryInit, wire.Bind(new(repository.RoleRepository), new(*repository.RoleRepositoryImpl)), repository.RoleMenuAccessRepositoryInit, wire.Bind(new(repository.RoleMenuAccessRepository), new(*repository.RoleMenuAccessRepositoryImpl)), repository.CompanyUserRepositoryInit, wire.Bind(new(repository.CompanyUserRepository), new(*repository.CompanyUserRepositoryImpl)), repository.ClientUserRepositoryInit, wire.Bind(new(repository.ClientUserRepository), new(*repository.ClientUserRepositoryImpl)), repository.AuthRepositoryInit, wire.Bind(new(repository.AuthRepository), new(*repository.AuthRepositoryImpl)), repository.RateLimitRepositoryInit, wire.Bind(new(repository.RateLimitRepository), new(*repository.RateLimitRepositoryImpl)), repository.ClientSubscriptionRepositoryInit, wire.Bind(new(repository.ClientSubscriptionRepository), new(*repository.ClientSubscriptionRepositoryImpl)), repository.SubscriptionRepositoryInit, wire.Bind(new(repository.SubscriptionRepository), new(*repository.SubscriptionRepositoryImpl)), repository.ImageRepositoryInit, wire.Bind(new(repository.ImageRepository), new(*repository.ImageRepositoryImpl)), repository.ClientBranchRepositoryInit, wire.Bind(new(repository.ClientBranchRepository), new(*repository.ClientBranchRepositoryImpl)), repository.UserRepositoryInit, wire.Bind(new(repository.UserRepository), new(*repository.UserRepositoryImpl)), repository.ProductRepositoryInit, wire.Bind(new(repository.ProductRepository), new(*repository.ProductRepositoryImpl)), repository.StockTransferRepositoryInit, wire.Bind(new(repository.StockTransferRepository), new(*repository.StockTransferRepositoryImpl)), repository.POSTransactionRepositoryInit, wire.Bind(new(repository.POSTransactionRepository), new(*repository.POSTransactionRepositoryImpl)), repository.AttendanceRepositoryInit, wire.Bind(new(repository.AttendanceRepository), new(*repository.AttendanceRepositoryImpl)), repository.DashboardRepositoryInit, wire.Bind(new(repository.DashboardRepository), new(*repository.DashboardRepositoryImpl)), repository.NotificationRepositoryInit, wire.Bind(new(repository.NotificationRepository), new(*repository.NotificationRepositoryImpl)))
+var repoSet = wire.NewSet(repository.AdminRepositoryInit, wire.Bind(new(repository.AdminRepository), new(*repository.AdminRepositoryImpl)), repository.ClientRepositoryInit, wire.Bind(new(repository.ClientRepository), new(*repository.ClientRepositoryImpl)), repository.CompanyRepositoryInit, wire.Bind(new(repository.CompanyRepository), new(*repository.CompanyRepositoryImpl)), repository.ParentMenuRepositoryInit, wire.Bind(new(repository.ParentMenuRepository), new(*repository.ParentMenuRepositoryImpl)), repository.MenuRepositoryInit, wire.Bind(new(repository.MenuRepository), new(*repository.MenuRepositoryImpl)), repository.RoleRepositoryInit, wire.Bind(new(repository.RoleRepository), new(*repository.RoleRepositoryImpl)), repository.RoleMenuAccessRepositoryInit, wire.Bind(new(repository.RoleMenuAccessRepository), new(*repository.RoleMenuAccessRepositoryImpl)), repository.CompanyUserRepositoryInit, wire.Bind(new(repository.CompanyUserRepository), new(*repository.CompanyUserRepositoryImpl)), repository.ClientUserRepositoryInit, wire.Bind(new(repository.ClientUserRepository), new(*repository.ClientUserRepositoryImpl)), repository.AuthRepositoryInit, wire.Bind(new(repository.AuthRepository), new(*repository.AuthRepositoryImpl)), repository.RateLimitRepositoryInit, wire.Bind(new(repository.RateLimitRepository), new(*repository.RateLimitRepositoryImpl)), repository.ClientSubscriptionRepositoryInit, wire.Bind(new(repository.ClientSubscriptionRepository), new(*repository.ClientSubscriptionRepositoryImpl)), repository.SubscriptionRepositoryInit, wire.Bind(new(repository.SubscriptionRepository), new(*repository.SubscriptionRepositoryImpl)), repository.ImageRepositoryInit, wire.Bind(new(repository.ImageRepository), new(*repository.ImageRepositoryImpl)), repository.ClientBranchRepositoryInit, wire.Bind(new(repository.ClientBranchRepository), new(*repository.ClientBranchRepositoryImpl)), repository.UserRepositoryInit, wire.Bind(new(repository.UserRepository), new(*repository.UserRepositoryImpl)), repository.ProductRepositoryInit, wire.Bind(new(repository.ProductRepository), new(*repository.ProductRepositoryImpl)), repository.StockTransferRepositoryInit, wire.Bind(new(repository.StockTransferRepository), new(*repository.StockTransferRepositoryImpl)), repository.POSTransactionRepositoryInit, wire.Bind(new(repository.POSTransactionRepository), new(*repository.POSTransactionRepositoryImpl)), repository.AttendanceRepositoryInit, wire.Bind(new(repository.AttendanceRepository), new(*repository.AttendanceRepositoryImpl)), repository.DashboardRepositoryInit, wire.Bind(new(repository.DashboardRepository), new(*repository.DashboardRepositoryImpl)), repository.NotificationRepositoryInit, wire.Bind(new(repository.NotificationRepository), new(*repository.NotificationRepositoryImpl)), repository.CustomerRepositoryInit, wire.Bind(new(repository.CustomerRepository), new(*repository.CustomerRepositoryImpl)), repository.ReceivableRepositoryInit, wire.Bind(new(repository.ReceivableRepository), new(*repository.ReceivableRepositoryImpl)), repository.BarcodeTemplateRepositoryInit, wire.Bind(new(repository.BarcodeTemplateRepository), new(*repository.BarcodeTemplateRepositoryImpl)), repository.ReceiptTemplateRepositoryInit, wire.Bind(new(repository.ReceiptTemplateRepository), new(*repository.ReceiptTemplateRepositoryImpl)), repository.EmailOutboxRepositoryInit, wire.Bind(new(repository.EmailOutboxRepository), new(*repository.EmailOutboxRepositoryImpl)), repository.ApprovalRepositoryInit, wire.Bind(new(repository.ApprovalRepository), new(*repository.ApprovalRepositoryImpl)), repository.StockMovementRepositoryInit, wire.Bind(new(repository.StockMovementRepository), new(*repository.StockMovementRepositoryImpl)), repository.StockOpnameRepositoryInit, wire.Bind(new(repository.StockOpnameRepository), new(*repository.StockOpnameRepositoryImpl)), repository.StockAdjustmentRepositoryInit, wire.Bind(new(repository.StockAdjustmentRepository), new(*repository.StockAdjustmentRepositoryImpl)), repository.SupplierRepositoryInit, wire.Bind(new(repository.SupplierRepository), new(*repository.SupplierRepositoryImpl)), repository.PurchaseOrderRepositoryInit, wire.Bind(new(repository.PurchaseOrderRepository), new(*repository.PurchaseOrderRepositoryImpl)), repository.ReportRepositoryInit, wire.Bind(new(repository.ReportRepository), new(*repository.ReportRepositoryImpl)), repository.StockLotRepositoryInit, wire.Bind(new(repository.StockLotRepository), new(*repository.StockLotRepositoryImpl)), repository.ReplenishmentRepositoryInit, wire.Bind(new(repository.ReplenishmentRepository), new(*repository.ReplenishmentRepositoryImpl)), repository.CatalogRepositoryInit, wire.Bind(new(repository.CatalogRepository), new(*repository.CatalogRepositoryImpl)), repository.CategoryRepositoryInit, wire.Bind(new(repository.CategoryRepository), new(*repository.CategoryRepositoryImpl)), repository.BrandRepositoryInit, wire.Bind(new(repository.BrandRepository), new(*repository.BrandRepositoryImpl)), repository.ProductImportJobRepositoryInit, wire.Bind(new(repository.ProductImportJobRepository), new(*repository.ProductImportJobRepositoryImpl)), repository.BarcodeSequenceRepositoryInit, wire.Bind(new(repository.BarcodeSequenceRepository), new(*repository.BarcodeSequenceRepositoryImpl)), repository.PriceChangeRepositoryInit, wire.Bind(new(repository.PriceChangeRepository), new(*repository.PriceChangeRepositoryImpl)))
 
-var serviceSet = wire.NewSet(service.NewAdminService, wire.Bind(new(service.AdminService), new(*service.AdminServiceImpl)), service.NewClientService, wire.Bind(new(service.ClientService), new(*service.ClientServiceImpl)), service.NewCompanyService, wire.Bind(new(service.CompanyService), new(*service.CompanyServiceImpl)), service.NewParentMenuService, wire.Bind(new(service.ParentMenuService), new(*service.ParentMenuServiceImpl)), service.NewMenuService, wire.Bind(new(service.MenuService), new(*service.MenuServiceImpl)), service.NewRoleService, wire.Bind(new(service.RoleService), new(*service.RoleServiceImpl)), service.NewRoleMenuAccessService, wire.Bind(new(service.RoleMenuAccessService), new(*service.RoleMenuAccessServiceImpl)), service.NewAuthService, wire.Bind(new(service.AuthService), new(*service.AuthServiceImpl)), service.NewSubscriptionService, wire.Bind(new(service.SubscriptionService), new(*service.SubscriptionServiceImpl)), service.NewSubscriptionGuardService, wire.Bind(new(service.SubscriptionGuardService), new(*service.SubscriptionGuardServiceImpl)), service.NewFileService, wire.Bind(new(service.FileService), new(*service.FileServiceImpl)), service.NewClientBranchService, wire.Bind(new(service.ClientBranchService), new(*service.ClientBranchServiceImpl)), service.NewUserService, wire.Bind(new(service.UserService), new(*service.UserServiceImpl)), service.NewClientUserService, wire.Bind(new(service.ClientUserService), new(*service.ClientUserServiceImpl)), service.NewProductService, wire.Bind(new(service.ProductService), new(*service.ProductServiceImpl)), service.NewStockTransferService, wire.Bind(new(service.StockTransferService), new(*service.StockTransferServiceImpl)), service.NewPOSTransactionService, wire.Bind(new(service.POSTransactionService), new(*service.POSTransactionServiceImpl)), service.NewAttendanceService, wire.Bind(new(service.AttendanceService), new(*service.AttendanceServiceImpl)), service.NewDashboardService, wire.Bind(new(service.DashboardService), new(*service.DashboardServiceImpl)), service.NewNotificationService, wire.Bind(new(service.NotificationService), new(*service.NotificationServiceImpl)))
+var serviceSet = wire.NewSet(service.NewAdminService, wire.Bind(new(service.AdminService), new(*service.AdminServiceImpl)), service.NewClientService, wire.Bind(new(service.ClientService), new(*service.ClientServiceImpl)), service.NewCompanyService, wire.Bind(new(service.CompanyService), new(*service.CompanyServiceImpl)), service.NewParentMenuService, wire.Bind(new(service.ParentMenuService), new(*service.ParentMenuServiceImpl)), service.NewMenuService, wire.Bind(new(service.MenuService), new(*service.MenuServiceImpl)), service.NewRoleService, wire.Bind(new(service.RoleService), new(*service.RoleServiceImpl)), service.NewRoleMenuAccessService, wire.Bind(new(service.RoleMenuAccessService), new(*service.RoleMenuAccessServiceImpl)), service.NewAuthService, wire.Bind(new(service.AuthService), new(*service.AuthServiceImpl)), service.NewSubscriptionService, wire.Bind(new(service.SubscriptionService), new(*service.SubscriptionServiceImpl)), service.NewSubscriptionGuardService, wire.Bind(new(service.SubscriptionGuardService), new(*service.SubscriptionGuardServiceImpl)), service.NewFileService, wire.Bind(new(service.FileService), new(*service.FileServiceImpl)), service.NewClientBranchService, wire.Bind(new(service.ClientBranchService), new(*service.ClientBranchServiceImpl)), service.NewUserService, wire.Bind(new(service.UserService), new(*service.UserServiceImpl)), service.NewClientUserService, wire.Bind(new(service.ClientUserService), new(*service.ClientUserServiceImpl)), service.NewProductService, wire.Bind(new(service.ProductService), new(*service.ProductServiceImpl)), service.NewStockTransferService, wire.Bind(new(service.StockTransferService), new(*service.StockTransferServiceImpl)), service.NewPOSTransactionService, wire.Bind(new(service.POSTransactionService), new(*service.POSTransactionServiceImpl)), service.NewAttendanceService, wire.Bind(new(service.AttendanceService), new(*service.AttendanceServiceImpl)), service.NewDashboardService, wire.Bind(new(service.DashboardService), new(*service.DashboardServiceImpl)), service.NewNotificationService, wire.Bind(new(service.NotificationService), new(*service.NotificationServiceImpl)), service.NewCustomerService, wire.Bind(new(service.CustomerService), new(*service.CustomerServiceImpl)), service.NewReceivableService, wire.Bind(new(service.ReceivableService), new(*service.ReceivableServiceImpl)), service.NewBarcodeTemplateService, wire.Bind(new(service.BarcodeTemplateService), new(*service.BarcodeTemplateServiceImpl)), service.NewReceiptService, wire.Bind(new(service.ReceiptService), new(*service.ReceiptServiceImpl)), service.NewApprovalService, wire.Bind(new(service.ApprovalService), new(*service.ApprovalServiceImpl)), service.NewStockOpnameService, wire.Bind(new(service.StockOpnameService), new(*service.StockOpnameServiceImpl)), service.NewStockAdjustmentService, wire.Bind(new(service.StockAdjustmentService), new(*service.StockAdjustmentServiceImpl)), service.NewSupplierService, wire.Bind(new(service.SupplierService), new(*service.SupplierServiceImpl)), service.NewPurchaseOrderService, wire.Bind(new(service.PurchaseOrderService), new(*service.PurchaseOrderServiceImpl)), service.NewReportService, wire.Bind(new(service.ReportService), new(*service.ReportServiceImpl)), service.NewStockLotService, wire.Bind(new(service.StockLotService), new(*service.StockLotServiceImpl)), service.NewReplenishmentService, wire.Bind(new(service.ReplenishmentService), new(*service.ReplenishmentServiceImpl)), service.NewCatalogService, wire.Bind(new(service.CatalogService), new(*service.CatalogServiceImpl)), service.NewCategoryService, wire.Bind(new(service.CategoryService), new(*service.CategoryServiceImpl)), service.NewBrandService, wire.Bind(new(service.BrandService), new(*service.BrandServiceImpl)), service.NewProductImportService, wire.Bind(new(service.ProductImportService), new(*service.ProductImportServiceImpl)), service.NewBarcodeLabelService, wire.Bind(new(service.BarcodeLabelService), new(*service.BarcodeLabelServiceImpl)), service.NewPriceChangeService, wire.Bind(new(service.PriceChangeService), new(*service.PriceChangeServiceImpl)))
 
-var controllerSet = wire.NewSet(controller.AdminControllerInit, wire.Bind(new(controller.AdminController), new(*controller.AdminControllerImpl)), controller.ClientControllerInit, wire.Bind(new(controller.ClientController), new(*controller.ClientControllerImpl)), controller.CompanyControllerInit, wire.Bind(new(controller.CompanyController), new(*controller.CompanyControllerImpl)), controller.ParentMenuControllerInit, wire.Bind(new(controller.ParentMenuController), new(*controller.ParentMenuControllerImpl)), controller.MenuControllerInit, wire.Bind(new(controller.MenuController), new(*controller.MenuControllerImpl)), controller.RoleControllerInit, wire.Bind(new(controller.RoleController), new(*controller.RoleControllerImpl)), controller.RoleAccessMenuControllerInit, wire.Bind(new(controller.RoleAccessMenuController), new(*controller.RoleAccessMenuControllerImpl)), controller.AuthControllerInit, wire.Bind(new(controller.AuthController), new(*controller.AuthControllerImpl)), controller.SubscriptionControllerInit, wire.Bind(new(controller.SubscriptionController), new(*controller.SubscriptionControllerImpl)), controller.FileControllerInit, wire.Bind(new(controller.FileController), new(*controller.FileControllerImpl)), controller.ClientBranchControllerInit, wire.Bind(new(controller.ClientBranchController), new(*controller.ClientBranchControllerImpl)), controller.UserControllerInit, wire.Bind(new(controller.UserController), new(*controller.UserControllerImpl)), controller.ClientUserControllerInit, wire.Bind(new(controller.ClientUserController), new(*controller.ClientUserControllerImpl)), controller.ProductControllerInit, wire.Bind(new(controller.ProductController), new(*controller.ProductControllerImpl)), controller.StockTransferControllerInit, wire.Bind(new(controller.StockTransferController), new(*controller.StockTransferControllerImpl)), controller.POSTransactionControllerInit, wire.Bind(new(controller.POSTransactionController), new(*controller.POSTransactionControllerImpl)), controller.AttendanceControllerInit, wire.Bind(new(controller.AttendanceController), new(*controller.AttendanceControllerImpl)), controller.DashboardControllerInit, wire.Bind(new(controller.DashboardController), new(*controller.DashboardControllerImpl)), controller.NotificationControllerInit, wire.Bind(new(controller.NotificationController), new(*controller.NotificationControllerImpl)))
+var controllerSet = wire.NewSet(controller.AdminControllerInit, wire.Bind(new(controller.AdminController), new(*controller.AdminControllerImpl)), controller.ClientControllerInit, wire.Bind(new(controller.ClientController), new(*controller.ClientControllerImpl)), controller.CompanyControllerInit, wire.Bind(new(controller.CompanyController), new(*controller.CompanyControllerImpl)), controller.ParentMenuControllerInit, wire.Bind(new(controller.ParentMenuController), new(*controller.ParentMenuControllerImpl)), controller.MenuControllerInit, wire.Bind(new(controller.MenuController), new(*controller.MenuControllerImpl)), controller.RoleControllerInit, wire.Bind(new(controller.RoleController), new(*controller.RoleControllerImpl)), controller.RoleAccessMenuControllerInit, wire.Bind(new(controller.RoleAccessMenuController), new(*controller.RoleAccessMenuControllerImpl)), controller.AuthControllerInit, wire.Bind(new(controller.AuthController), new(*controller.AuthControllerImpl)), controller.SubscriptionControllerInit, wire.Bind(new(controller.SubscriptionController), new(*controller.SubscriptionControllerImpl)), controller.FileControllerInit, wire.Bind(new(controller.FileController), new(*controller.FileControllerImpl)), controller.ClientBranchControllerInit, wire.Bind(new(controller.ClientBranchController), new(*controller.ClientBranchControllerImpl)), controller.UserControllerInit, wire.Bind(new(controller.UserController), new(*controller.UserControllerImpl)), controller.ClientUserControllerInit, wire.Bind(new(controller.ClientUserController), new(*controller.ClientUserControllerImpl)), controller.ProductControllerInit, wire.Bind(new(controller.ProductController), new(*controller.ProductControllerImpl)), controller.StockTransferControllerInit, wire.Bind(new(controller.StockTransferController), new(*controller.StockTransferControllerImpl)), controller.POSTransactionControllerInit, wire.Bind(new(controller.POSTransactionController), new(*controller.POSTransactionControllerImpl)), controller.AttendanceControllerInit, wire.Bind(new(controller.AttendanceController), new(*controller.AttendanceControllerImpl)), controller.DashboardControllerInit, wire.Bind(new(controller.DashboardController), new(*controller.DashboardControllerImpl)), controller.NotificationControllerInit, wire.Bind(new(controller.NotificationController), new(*controller.NotificationControllerImpl)), controller.CustomerControllerInit, wire.Bind(new(controller.CustomerController), new(*controller.CustomerControllerImpl)), controller.ReceivableControllerInit, wire.Bind(new(controller.ReceivableController), new(*controller.ReceivableControllerImpl)), controller.BarcodeTemplateControllerInit, wire.Bind(new(controller.BarcodeTemplateController), new(*controller.BarcodeTemplateControllerImpl)), controller.ReceiptControllerInit, wire.Bind(new(controller.ReceiptController), new(*controller.ReceiptControllerImpl)), controller.ApprovalControllerInit, wire.Bind(new(controller.ApprovalController), new(*controller.ApprovalControllerImpl)), controller.StockOpnameControllerInit, wire.Bind(new(controller.StockOpnameController), new(*controller.StockOpnameControllerImpl)), controller.StockAdjustmentControllerInit, wire.Bind(new(controller.StockAdjustmentController), new(*controller.StockAdjustmentControllerImpl)), controller.SupplierControllerInit, wire.Bind(new(controller.SupplierController), new(*controller.SupplierControllerImpl)), controller.PurchaseOrderControllerInit, wire.Bind(new(controller.PurchaseOrderController), new(*controller.PurchaseOrderControllerImpl)), controller.ReportControllerInit, wire.Bind(new(controller.ReportController), new(*controller.ReportControllerImpl)), controller.StockLotControllerInit, wire.Bind(new(controller.StockLotController), new(*controller.StockLotControllerImpl)), controller.ReplenishmentControllerInit, wire.Bind(new(controller.ReplenishmentController), new(*controller.ReplenishmentControllerImpl)), controller.CatalogControllerInit, wire.Bind(new(controller.CatalogController), new(*controller.CatalogControllerImpl)), controller.CategoryControllerInit, wire.Bind(new(controller.CategoryController), new(*controller.CategoryControllerImpl)), controller.BrandControllerInit, wire.Bind(new(controller.BrandController), new(*controller.BrandControllerImpl)), controller.ProductImportControllerInit, wire.Bind(new(controller.ProductImportController), new(*controller.ProductImportControllerImpl)), controller.BarcodeLabelControllerInit, wire.Bind(new(controller.BarcodeLabelController), new(*controller.BarcodeLabelControllerImpl)), controller.PriceChangeControllerInit, wire.Bind(new(controller.PriceChangeController), new(*controller.PriceChangeControllerImpl)))
@@ -0,0 +1,32 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"harjonan.id/user-service/app/service"
+)
+
+type ApprovalController interface {
+	GetPolicy(c *gin.Context)
+	SavePolicy(c *gin.Context)
+	SetPin(c *gin.Context)
+	List(c *gin.Context)
+	Detail(c *gin.Context)
+	Approve(c *gin.Context)
+	Reject(c *gin.Context)
+}
+
+type ApprovalControllerImpl struct {
+	svc service.ApprovalService
+}
+
+func (a ApprovalControllerImpl) GetPolicy(c *gin.Context)  { a.svc.GetPolicy(c) }
+func (a ApprovalControllerImpl) SavePolicy(c *gin.Context) { a.svc.SavePolicy(c) }
+func (a ApprovalControllerImpl) SetPin(c *gin.Context)     { a.svc.SetPin(c) }
+func (a ApprovalControllerImpl) List(c *gin.Context)       { a.svc.List(c) }
+func (a ApprovalControllerImpl) Detail(c *gin.Context)     { a.svc.Detail(c) }
+func (a ApprovalControllerImpl) Approve(c *gin.Context)    { a.svc.Approve(c) }
+func (a ApprovalControllerImpl) Reject(c *gin.Context)     { a.svc.Reject(c) }
+
+func ApprovalControllerInit(s service.ApprovalService) *ApprovalControllerImpl {
+	return &ApprovalControllerImpl{svc: s}
+}
@@ -0,0 +1,28 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"harjonan.id/user-service/app/service"
+)
+
+type BarcodeLabelController interface {
+	Prefix(c *gin.Context)
+	SetPrefix(c *gin.Context)
+	Assign(c *gin.Context)
+	Templates(c *gin.Context)
+	Labels(c *gin.Context)
+}
+
+type BarcodeLabelControllerImpl struct {
+	svc service.BarcodeLabelService
+}
+
+func (a BarcodeLabelControllerImpl) Prefix(c *gin.Context)    { a.svc.Prefix(c) }
+func (a BarcodeLabelControllerImpl) SetPrefix(c *gin.Context) { a.svc.SetPrefix(c) }
+func (a BarcodeLabelControllerImpl) Assign(c *gin.Context)    { a.svc.Assign(c) }
+func (a BarcodeLabelControllerImpl) Templates(c *gin.Context) { a.svc.Templates(c) }
+func (a BarcodeLabelControllerImpl) Labels(c *gin.Context)    { a.svc.Labels(c) }
+
+func BarcodeLabelControllerInit(s service.BarcodeLabelService) *BarcodeLabelControllerImpl {
+	return &BarcodeLabelControllerImpl{svc: s}
+}
@@ -0,0 +1,24 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"harjonan.id/user-service/app/service"
+)
+
+type BarcodeTemplateController interface {
+	Upsert(c *gin.Context)
+	List(c *gin.Context)
+	Delete(c *gin.Context)
+}
+
+type BarcodeTemplateControllerImpl struct {
+	svc service.BarcodeTemplateService
+}
+
+func (a BarcodeTemplateControllerImpl) Upsert(c *gin.Context) { a.svc.Upsert(c) }
+func (a BarcodeTemplateControllerImpl) List(c *gin.Context)   { a.svc.List(c) }
+func (a BarcodeTemplateControllerImpl) Delete(c *gin.Context) { a.svc.Delete(c) }
+
+func BarcodeTemplateControllerInit(s service.BarcodeTemplateService) *BarcodeTemplateControllerImpl {
+	return &BarcodeTemplateControllerImpl{svc: s}
+}
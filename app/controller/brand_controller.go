@@ -0,0 +1,24 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"harjonan.id/user-service/app/service"
+)
+
+type BrandController interface {
+	List(c *gin.Context)
+	Upsert(c *gin.Context)
+	Delete(c *gin.Context)
+}
+
+type BrandControllerImpl struct {
+	svc service.BrandService
+}
+
+func (a BrandControllerImpl) List(c *gin.Context)   { a.svc.List(c) }
+func (a BrandControllerImpl) Upsert(c *gin.Context) { a.svc.Upsert(c) }
+func (a BrandControllerImpl) Delete(c *gin.Context) { a.svc.Delete(c) }
+
+func BrandControllerInit(s service.BrandService) *BrandControllerImpl {
+	return &BrandControllerImpl{svc: s}
+}
@@ -0,0 +1,32 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"harjonan.id/user-service/app/service"
+)
+
+type CatalogController interface {
+	List(c *gin.Context)
+	Detail(c *gin.Context)
+	Upsert(c *gin.Context)
+	SetInventory(c *gin.Context)
+	Migrate(c *gin.Context)
+	Tags(c *gin.Context)
+	GenerateVariants(c *gin.Context)
+}
+
+type CatalogControllerImpl struct {
+	svc service.CatalogService
+}
+
+func (a CatalogControllerImpl) List(c *gin.Context)             { a.svc.List(c) }
+func (a CatalogControllerImpl) Detail(c *gin.Context)           { a.svc.Detail(c) }
+func (a CatalogControllerImpl) Upsert(c *gin.Context)           { a.svc.Upsert(c) }
+func (a CatalogControllerImpl) SetInventory(c *gin.Context)     { a.svc.SetInventory(c) }
+func (a CatalogControllerImpl) Migrate(c *gin.Context)          { a.svc.Migrate(c) }
+func (a CatalogControllerImpl) Tags(c *gin.Context)             { a.svc.Tags(c) }
+func (a CatalogControllerImpl) GenerateVariants(c *gin.Context) { a.svc.GenerateVariants(c) }
+
+func CatalogControllerInit(s service.CatalogService) *CatalogControllerImpl {
+	return &CatalogControllerImpl{svc: s}
+}
@@ -0,0 +1,24 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"harjonan.id/user-service/app/service"
+)
+
+type CategoryController interface {
+	Tree(c *gin.Context)
+	Upsert(c *gin.Context)
+	Delete(c *gin.Context)
+}
+
+type CategoryControllerImpl struct {
+	svc service.CategoryService
+}
+
+func (a CategoryControllerImpl) Tree(c *gin.Context)   { a.svc.Tree(c) }
+func (a CategoryControllerImpl) Upsert(c *gin.Context) { a.svc.Upsert(c) }
+func (a CategoryControllerImpl) Delete(c *gin.Context) { a.svc.Delete(c) }
+
+func CategoryControllerInit(s service.CategoryService) *CategoryControllerImpl {
+	return &CategoryControllerImpl{svc: s}
+}
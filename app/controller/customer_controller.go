@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"harjonan.id/user-service/app/service"
+)
+
+type CustomerController interface {
+	Upsert(c *gin.Context)
+	Detail(c *gin.Context)
+	List(c *gin.Context)
+	Delete(c *gin.Context)
+	Lookup(c *gin.Context)
+	History(c *gin.Context)
+	PointLedger(c *gin.Context)
+	GetLoyaltyRule(c *gin.Context)
+	SaveLoyaltyRule(c *gin.Context)
+}
+
+type CustomerControllerImpl struct {
+	svc service.CustomerService
+}
+
+func (a CustomerControllerImpl) Upsert(c *gin.Context)          { a.svc.Upsert(c) }
+func (a CustomerControllerImpl) Detail(c *gin.Context)          { a.svc.Detail(c) }
+func (a CustomerControllerImpl) List(c *gin.Context)            { a.svc.List(c) }
+func (a CustomerControllerImpl) Delete(c *gin.Context)          { a.svc.Delete(c) }
+func (a CustomerControllerImpl) Lookup(c *gin.Context)          { a.svc.Lookup(c) }
+func (a CustomerControllerImpl) History(c *gin.Context)         { a.svc.History(c) }
+func (a CustomerControllerImpl) PointLedger(c *gin.Context)     { a.svc.PointLedger(c) }
+func (a CustomerControllerImpl) GetLoyaltyRule(c *gin.Context)  { a.svc.GetLoyaltyRule(c) }
+func (a CustomerControllerImpl) SaveLoyaltyRule(c *gin.Context) { a.svc.SaveLoyaltyRule(c) }
+
+func CustomerControllerInit(s service.CustomerService) *CustomerControllerImpl {
+	return &CustomerControllerImpl{svc: s}
+}
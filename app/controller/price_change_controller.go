@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"harjonan.id/user-service/app/service"
+)
+
+type PriceChangeController interface {
+	Schedule(c *gin.Context)
+	List(c *gin.Context)
+	Detail(c *gin.Context)
+	Approve(c *gin.Context)
+	Reject(c *gin.Context)
+	Cancel(c *gin.Context)
+	ProductHistory(c *gin.Context)
+	CatalogHistory(c *gin.Context)
+}
+
+type PriceChangeControllerImpl struct {
+	svc service.PriceChangeService
+}
+
+func (a PriceChangeControllerImpl) Schedule(c *gin.Context)       { a.svc.Schedule(c) }
+func (a PriceChangeControllerImpl) List(c *gin.Context)           { a.svc.List(c) }
+func (a PriceChangeControllerImpl) Detail(c *gin.Context)         { a.svc.Detail(c) }
+func (a PriceChangeControllerImpl) Approve(c *gin.Context)        { a.svc.Approve(c) }
+func (a PriceChangeControllerImpl) Reject(c *gin.Context)         { a.svc.Reject(c) }
+func (a PriceChangeControllerImpl) Cancel(c *gin.Context)         { a.svc.Cancel(c) }
+func (a PriceChangeControllerImpl) ProductHistory(c *gin.Context) { a.svc.ProductHistory(c) }
+func (a PriceChangeControllerImpl) CatalogHistory(c *gin.Context) { a.svc.CatalogHistory(c) }
+
+func PriceChangeControllerInit(s service.PriceChangeService) *PriceChangeControllerImpl {
+	return &PriceChangeControllerImpl{svc: s}
+}
@@ -0,0 +1,28 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"harjonan.id/user-service/app/service"
+)
+
+type ProductImportController interface {
+	Upload(c *gin.Context)
+	List(c *gin.Context)
+	Detail(c *gin.Context)
+	Report(c *gin.Context)
+	Export(c *gin.Context)
+}
+
+type ProductImportControllerImpl struct {
+	svc service.ProductImportService
+}
+
+func (a ProductImportControllerImpl) Upload(c *gin.Context) { a.svc.Upload(c) }
+func (a ProductImportControllerImpl) List(c *gin.Context)   { a.svc.List(c) }
+func (a ProductImportControllerImpl) Detail(c *gin.Context) { a.svc.Detail(c) }
+func (a ProductImportControllerImpl) Report(c *gin.Context) { a.svc.Report(c) }
+func (a ProductImportControllerImpl) Export(c *gin.Context) { a.svc.Export(c) }
+
+func ProductImportControllerInit(s service.ProductImportService) *ProductImportControllerImpl {
+	return &ProductImportControllerImpl{svc: s}
+}
@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"harjonan.id/user-service/app/service"
+)
+
+type PurchaseOrderController interface {
+	Save(c *gin.Context)
+	List(c *gin.Context)
+	Detail(c *gin.Context)
+	Send(c *gin.Context)
+	Cancel(c *gin.Context)
+	Receive(c *gin.Context)
+	Receipts(c *gin.Context)
+	PDF(c *gin.Context)
+}
+
+type PurchaseOrderControllerImpl struct {
+	svc service.PurchaseOrderService
+}
+
+func (a PurchaseOrderControllerImpl) Save(c *gin.Context)     { a.svc.Save(c) }
+func (a PurchaseOrderControllerImpl) List(c *gin.Context)     { a.svc.List(c) }
+func (a PurchaseOrderControllerImpl) Detail(c *gin.Context)   { a.svc.Detail(c) }
+func (a PurchaseOrderControllerImpl) Send(c *gin.Context)     { a.svc.Send(c) }
+func (a PurchaseOrderControllerImpl) Cancel(c *gin.Context)   { a.svc.Cancel(c) }
+func (a PurchaseOrderControllerImpl) Receive(c *gin.Context)  { a.svc.Receive(c) }
+func (a PurchaseOrderControllerImpl) Receipts(c *gin.Context) { a.svc.Receipts(c) }
+func (a PurchaseOrderControllerImpl) PDF(c *gin.Context)      { a.svc.PDF(c) }
+
+func PurchaseOrderControllerInit(s service.PurchaseOrderService) *PurchaseOrderControllerImpl {
+	return &PurchaseOrderControllerImpl{svc: s}
+}
@@ -0,0 +1,28 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"harjonan.id/user-service/app/service"
+)
+
+type ReceiptController interface {
+	GetTemplate(c *gin.Context)
+	SaveTemplate(c *gin.Context)
+	Render(c *gin.Context)
+	Reprint(c *gin.Context)
+	SendEmail(c *gin.Context)
+}
+
+type ReceiptControllerImpl struct {
+	svc service.ReceiptService
+}
+
+func (a ReceiptControllerImpl) GetTemplate(c *gin.Context)  { a.svc.GetTemplate(c) }
+func (a ReceiptControllerImpl) SaveTemplate(c *gin.Context) { a.svc.SaveTemplate(c) }
+func (a ReceiptControllerImpl) Render(c *gin.Context)       { a.svc.Render(c) }
+func (a ReceiptControllerImpl) Reprint(c *gin.Context)      { a.svc.Reprint(c) }
+func (a ReceiptControllerImpl) SendEmail(c *gin.Context)    { a.svc.SendEmail(c) }
+
+func ReceiptControllerInit(s service.ReceiptService) *ReceiptControllerImpl {
+	return &ReceiptControllerImpl{svc: s}
+}
@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"harjonan.id/user-service/app/service"
+)
+
+type ReceivableController interface {
+	List(c *gin.Context)
+	Detail(c *gin.Context)
+	Pay(c *gin.Context)
+	PayCustomer(c *gin.Context)
+	Aging(c *gin.Context)
+	OutstandingByBranch(c *gin.Context)
+}
+
+type ReceivableControllerImpl struct {
+	svc service.ReceivableService
+}
+
+func (a ReceivableControllerImpl) List(c *gin.Context)                { a.svc.List(c) }
+func (a ReceivableControllerImpl) Detail(c *gin.Context)              { a.svc.Detail(c) }
+func (a ReceivableControllerImpl) Pay(c *gin.Context)                 { a.svc.Pay(c) }
+func (a ReceivableControllerImpl) PayCustomer(c *gin.Context)         { a.svc.PayCustomer(c) }
+func (a ReceivableControllerImpl) Aging(c *gin.Context)               { a.svc.Aging(c) }
+func (a ReceivableControllerImpl) OutstandingByBranch(c *gin.Context) { a.svc.OutstandingByBranch(c) }
+
+func ReceivableControllerInit(s service.ReceivableService) *ReceivableControllerImpl {
+	return &ReceivableControllerImpl{svc: s}
+}
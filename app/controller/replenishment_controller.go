@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"harjonan.id/user-service/app/service"
+)
+
+type ReplenishmentController interface {
+	SetLevels(c *gin.Context)
+	Generate(c *gin.Context)
+	List(c *gin.Context)
+	Detail(c *gin.Context)
+	Approve(c *gin.Context)
+	Discard(c *gin.Context)
+}
+
+type ReplenishmentControllerImpl struct {
+	svc service.ReplenishmentService
+}
+
+func (a ReplenishmentControllerImpl) SetLevels(c *gin.Context) { a.svc.SetLevels(c) }
+func (a ReplenishmentControllerImpl) Generate(c *gin.Context)  { a.svc.Generate(c) }
+func (a ReplenishmentControllerImpl) List(c *gin.Context)      { a.svc.List(c) }
+func (a ReplenishmentControllerImpl) Detail(c *gin.Context)    { a.svc.Detail(c) }
+func (a ReplenishmentControllerImpl) Approve(c *gin.Context)   { a.svc.Approve(c) }
+func (a ReplenishmentControllerImpl) Discard(c *gin.Context)   { a.svc.Discard(c) }
+
+func ReplenishmentControllerInit(s service.ReplenishmentService) *ReplenishmentControllerImpl {
+	return &ReplenishmentControllerImpl{svc: s}
+}
@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"harjonan.id/user-service/app/service"
+)
+
+type ReportController interface {
+	MarginByTransaction(c *gin.Context)
+	MarginByProduct(c *gin.Context)
+	MarginByBranch(c *gin.Context)
+	MarginByCategory(c *gin.Context)
+}
+
+type ReportControllerImpl struct {
+	svc service.ReportService
+}
+
+func (a ReportControllerImpl) MarginByTransaction(c *gin.Context) { a.svc.MarginByTransaction(c) }
+func (a ReportControllerImpl) MarginByProduct(c *gin.Context)     { a.svc.MarginByProduct(c) }
+func (a ReportControllerImpl) MarginByBranch(c *gin.Context)      { a.svc.MarginByBranch(c) }
+func (a ReportControllerImpl) MarginByCategory(c *gin.Context)    { a.svc.MarginByCategory(c) }
+
+func ReportControllerInit(s service.ReportService) *ReportControllerImpl {
+	return &ReportControllerImpl{svc: s}
+}
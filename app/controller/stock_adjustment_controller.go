@@ -0,0 +1,28 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"harjonan.id/user-service/app/service"
+)
+
+type StockAdjustmentController interface {
+	Create(c *gin.Context)
+	List(c *gin.Context)
+	Detail(c *gin.Context)
+	Approve(c *gin.Context)
+	Reject(c *gin.Context)
+}
+
+type StockAdjustmentControllerImpl struct {
+	svc service.StockAdjustmentService
+}
+
+func (a StockAdjustmentControllerImpl) Create(c *gin.Context)  { a.svc.Create(c) }
+func (a StockAdjustmentControllerImpl) List(c *gin.Context)    { a.svc.List(c) }
+func (a StockAdjustmentControllerImpl) Detail(c *gin.Context)  { a.svc.Detail(c) }
+func (a StockAdjustmentControllerImpl) Approve(c *gin.Context) { a.svc.Approve(c) }
+func (a StockAdjustmentControllerImpl) Reject(c *gin.Context)  { a.svc.Reject(c) }
+
+func StockAdjustmentControllerInit(s service.StockAdjustmentService) *StockAdjustmentControllerImpl {
+	return &StockAdjustmentControllerImpl{svc: s}
+}
@@ -0,0 +1,22 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"harjonan.id/user-service/app/service"
+)
+
+type StockLotController interface {
+	List(c *gin.Context)
+	Expiring(c *gin.Context)
+}
+
+type StockLotControllerImpl struct {
+	svc service.StockLotService
+}
+
+func (a StockLotControllerImpl) List(c *gin.Context)     { a.svc.List(c) }
+func (a StockLotControllerImpl) Expiring(c *gin.Context) { a.svc.Expiring(c) }
+
+func StockLotControllerInit(s service.StockLotService) *StockLotControllerImpl {
+	return &StockLotControllerImpl{svc: s}
+}
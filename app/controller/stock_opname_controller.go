@@ -0,0 +1,32 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"harjonan.id/user-service/app/service"
+)
+
+type StockOpnameController interface {
+	Start(c *gin.Context)
+	List(c *gin.Context)
+	Detail(c *gin.Context)
+	Count(c *gin.Context)
+	Variance(c *gin.Context)
+	Approve(c *gin.Context)
+	Cancel(c *gin.Context)
+}
+
+type StockOpnameControllerImpl struct {
+	svc service.StockOpnameService
+}
+
+func (a StockOpnameControllerImpl) Start(c *gin.Context)    { a.svc.Start(c) }
+func (a StockOpnameControllerImpl) List(c *gin.Context)     { a.svc.List(c) }
+func (a StockOpnameControllerImpl) Detail(c *gin.Context)   { a.svc.Detail(c) }
+func (a StockOpnameControllerImpl) Count(c *gin.Context)    { a.svc.Count(c) }
+func (a StockOpnameControllerImpl) Variance(c *gin.Context) { a.svc.Variance(c) }
+func (a StockOpnameControllerImpl) Approve(c *gin.Context)  { a.svc.Approve(c) }
+func (a StockOpnameControllerImpl) Cancel(c *gin.Context)   { a.svc.Cancel(c) }
+
+func StockOpnameControllerInit(s service.StockOpnameService) *StockOpnameControllerImpl {
+	return &StockOpnameControllerImpl{svc: s}
+}
@@ -12,18 +12,34 @@ type StockTransferController interface {
 	WarehouseApprove(c *gin.Context)
 	DriverAccept(c *gin.Context)
 	ReceiveDone(c *gin.Context)
+	Reject(c *gin.Context)
+	Cancel(c *gin.Context)
+	DriverDecline(c *gin.Context)
+	DeliveryNote(c *gin.Context)
+	ListDiscrepancies(c *gin.Context)
+	DetailDiscrepancy(c *gin.Context)
+	ResolveDiscrepancy(c *gin.Context)
+	DiscrepancyReport(c *gin.Context)
 }
 
 type StockTransferControllerImpl struct {
 	svc service.StockTransferService
 }
 
-func (a StockTransferControllerImpl) Create(c *gin.Context)           { a.svc.Create(c) }
-func (a StockTransferControllerImpl) Detail(c *gin.Context)           { a.svc.Detail(c) }
-func (a StockTransferControllerImpl) List(c *gin.Context)             { a.svc.List(c) }
-func (a StockTransferControllerImpl) WarehouseApprove(c *gin.Context) { a.svc.WarehouseApprove(c) }
-func (a StockTransferControllerImpl) DriverAccept(c *gin.Context)     { a.svc.DriverAccept(c) }
-func (a StockTransferControllerImpl) ReceiveDone(c *gin.Context)      { a.svc.ReceiveDone(c) }
+func (a StockTransferControllerImpl) Create(c *gin.Context)             { a.svc.Create(c) }
+func (a StockTransferControllerImpl) Detail(c *gin.Context)             { a.svc.Detail(c) }
+func (a StockTransferControllerImpl) List(c *gin.Context)               { a.svc.List(c) }
+func (a StockTransferControllerImpl) WarehouseApprove(c *gin.Context)   { a.svc.WarehouseApprove(c) }
+func (a StockTransferControllerImpl) DriverAccept(c *gin.Context)       { a.svc.DriverAccept(c) }
+func (a StockTransferControllerImpl) ReceiveDone(c *gin.Context)        { a.svc.ReceiveDone(c) }
+func (a StockTransferControllerImpl) Reject(c *gin.Context)             { a.svc.Reject(c) }
+func (a StockTransferControllerImpl) Cancel(c *gin.Context)             { a.svc.Cancel(c) }
+func (a StockTransferControllerImpl) DriverDecline(c *gin.Context)      { a.svc.DriverDecline(c) }
+func (a StockTransferControllerImpl) DeliveryNote(c *gin.Context)       { a.svc.DeliveryNote(c) }
+func (a StockTransferControllerImpl) ListDiscrepancies(c *gin.Context)  { a.svc.ListDiscrepancies(c) }
+func (a StockTransferControllerImpl) DetailDiscrepancy(c *gin.Context)  { a.svc.DetailDiscrepancy(c) }
+func (a StockTransferControllerImpl) ResolveDiscrepancy(c *gin.Context) { a.svc.ResolveDiscrepancy(c) }
+func (a StockTransferControllerImpl) DiscrepancyReport(c *gin.Context)  { a.svc.DiscrepancyReport(c) }
 
 func StockTransferControllerInit(s service.StockTransferService) *StockTransferControllerImpl {
 	return &StockTransferControllerImpl{svc: s}
@@ -0,0 +1,28 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"harjonan.id/user-service/app/service"
+)
+
+type SupplierController interface {
+	Upsert(c *gin.Context)
+	List(c *gin.Context)
+	Detail(c *gin.Context)
+	Delete(c *gin.Context)
+	Prices(c *gin.Context)
+}
+
+type SupplierControllerImpl struct {
+	svc service.SupplierService
+}
+
+func (a SupplierControllerImpl) Upsert(c *gin.Context) { a.svc.Upsert(c) }
+func (a SupplierControllerImpl) List(c *gin.Context)   { a.svc.List(c) }
+func (a SupplierControllerImpl) Detail(c *gin.Context) { a.svc.Detail(c) }
+func (a SupplierControllerImpl) Delete(c *gin.Context) { a.svc.Delete(c) }
+func (a SupplierControllerImpl) Prices(c *gin.Context) { a.svc.Prices(c) }
+
+func SupplierControllerInit(s service.SupplierService) *SupplierControllerImpl {
+	return &SupplierControllerImpl{svc: s}
+}
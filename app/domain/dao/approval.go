@@ -0,0 +1,95 @@
+package dao
+
+type ApprovalType string
+
+const (
+	ApprovalVoid     ApprovalType = "VOID"
+	ApprovalDiscount ApprovalType = "DISCOUNT"
+)
+
+type ApprovalStatus string
+
+const (
+	ApprovalPending  ApprovalStatus = "PENDING"
+	ApprovalApproved ApprovalStatus = "APPROVED" // request async disetujui, belum dipakai
+	ApprovalRejected ApprovalStatus = "REJECTED"
+	ApprovalUsed     ApprovalStatus = "USED" // sudah dipakai untuk void/checkout
+)
+
+type ApprovalMethod string
+
+const (
+	ApprovalByPIN     ApprovalMethod = "PIN"     // supervisor input PIN di kasir
+	ApprovalByRequest ApprovalMethod = "REQUEST" // request async, disetujui dari app owner
+	ApprovalBySelf    ApprovalMethod = "SELF"    // pelaku sendiri supervisor
+)
+
+// ApprovalPolicy: threshold per client kapan void/diskon butuh approval supervisor
+type ApprovalPolicy struct {
+	BaseModel `bson:",inline"`
+
+	ClientUUID string `bson:"client_uuid" json:"client_uuid"`
+
+	// void transaksi yang umurnya > N menit butuh approval (0 = tidak dibatasi)
+	VoidMaxAgeMinutes int `bson:"void_max_age_minutes" json:"void_max_age_minutes"`
+	// semua void butuh approval
+	VoidAlwaysRequire bool `bson:"void_always_require" json:"void_always_require"`
+	// diskon di atas X% dari subtotal butuh approval (0 = tidak dibatasi)
+	DiscountMaxPercent float64 `bson:"discount_max_percent" json:"discount_max_percent"`
+	// adjustment stock manual butuh approval OWNER kalau total |qty| > N atau nilai (x cost) > X (0 = tidak dibatasi)
+	AdjustmentMaxQty   int64   `bson:"adjustment_max_qty" json:"adjustment_max_qty"`
+	AdjustmentMaxValue float64 `bson:"adjustment_max_value" json:"adjustment_max_value"`
+
+	// role yang boleh approve (default OWNER, SUPERADMIN)
+	ApproverRoles []string `bson:"approver_roles" json:"approver_roles"`
+	// request async kadaluarsa setelah N menit (default 60)
+	RequestTTLMinutes int `bson:"request_ttl_minutes" json:"request_ttl_minutes"`
+
+	UpdatedBy string `bson:"updated_by" json:"updated_by"`
+}
+
+// Approval: jejak audit setiap approval (PIN / request / self)
+type Approval struct {
+	BaseModel `bson:",inline"`
+
+	ClientUUID string         `bson:"client_uuid" json:"client_uuid"`
+	BranchUUID string         `bson:"branch_uuid" json:"branch_uuid"`
+	Type       ApprovalType   `bson:"type" json:"type"`
+	Method     ApprovalMethod `bson:"method" json:"method"`
+	Status     ApprovalStatus `bson:"status" json:"status"`
+
+	// VOID: uuid transaksi. DISCOUNT: kosong sampai checkout selesai (lihat UsedRef)
+	Ref    string `bson:"ref" json:"ref"`
+	Reason string `bson:"reason" json:"reason"` // kenapa butuh approval
+
+	DiscountPercent float64 `bson:"discount_percent" json:"discount_percent"`
+	Amount          float64 `bson:"amount" json:"amount"`
+
+	RequestedBy     string `bson:"requested_by" json:"requested_by"`
+	RequestedByName string `bson:"requested_by_name" json:"requested_by_name"`
+	RequestNote     string `bson:"request_note" json:"request_note"`
+
+	ApprovedBy     string `bson:"approved_by" json:"approved_by"`
+	ApprovedByName string `bson:"approved_by_name" json:"approved_by_name"`
+	DecisionNote   string `bson:"decision_note" json:"decision_note"`
+	DecidedAt      int64  `bson:"decided_at" json:"decided_at"`
+	DecidedAtStr   string `bson:"decided_at_str" json:"decided_at_str"`
+
+	ExpiresAt int64  `bson:"expires_at" json:"expires_at"`
+	UsedAt    int64  `bson:"used_at" json:"used_at"`
+	UsedRef   string `bson:"used_ref" json:"used_ref"` // uuid transaksi yang memakai approval
+}
+
+// SupervisorPin: PIN approval per user (hash, bukan plain)
+type SupervisorPin struct {
+	BaseModel `bson:",inline"`
+
+	ClientUUID string `bson:"client_uuid" json:"client_uuid"`
+	UserUUID   string `bson:"user_uuid" json:"user_uuid"`
+	Name       string `bson:"name" json:"name"`
+	RoleValue  string `bson:"role_value" json:"role_value"`
+	PinHash    string `bson:"pin_hash" json:"-"`
+
+	FailedAttempts int   `bson:"failed_attempts" json:"failed_attempts"`
+	LockedUntil    int64 `bson:"locked_until" json:"locked_until"`
+}
@@ -0,0 +1,12 @@
+package dao
+
+// BarcodeSequence: penomoran EAN-13 internal per client (produk tanpa barcode pabrik).
+// Kode = prefix + nomor urut + check digit. Prefix 20-29 = in-store GS1, jangan bentrok dengan template timbangan.
+type BarcodeSequence struct {
+	BaseModel `bson:",inline"`
+
+	ClientUUID string `bson:"client_uuid" json:"client_uuid"`
+	Prefix     string `bson:"prefix" json:"prefix"`
+	Next       int64  `bson:"next" json:"next"` // nomor urut berikutnya
+	UpdatedBy  string `bson:"updated_by" json:"updated_by"`
+}
@@ -0,0 +1,32 @@
+package dao
+
+type BarcodeValueType string
+
+const (
+	BarcodeValueWeight BarcodeValueType = "WEIGHT"
+	BarcodeValuePrice  BarcodeValueType = "PRICE"
+)
+
+// BarcodeTemplate: format label timbangan (EAN-13 prefix 20-29) per client.
+// Layout: prefix + PLU + [value check digit] + value + check digit (total 13 digit)
+// contoh 2 digit prefix "21", PLU 5, value 5: 21 PPPPP VVVVV C
+type BarcodeTemplate struct {
+	BaseModel `bson:",inline"`
+
+	ClientUUID string `bson:"client_uuid" json:"client_uuid"`
+	Name       string `bson:"name" json:"name"`
+
+	Prefix          string           `bson:"prefix" json:"prefix"` // "20".."29"
+	PLULength       int              `bson:"plu_length" json:"plu_length"`
+	ValueLength     int              `bson:"value_length" json:"value_length"`
+	ValueCheckDigit bool             `bson:"value_check_digit" json:"value_check_digit"` // beberapa timbangan ada check digit untuk value
+	ValueType       BarcodeValueType `bson:"value_type" json:"value_type"`               // WEIGHT / PRICE
+
+	// WEIGHT: qty base unit = value x QtyMultiplier (mis. label gram & base unit gram => 1)
+	QtyMultiplier float64 `bson:"qty_multiplier" json:"qty_multiplier"`
+	// PRICE: harga = value / 10^PriceDecimals
+	PriceDecimals int `bson:"price_decimals" json:"price_decimals"`
+
+	IsActive  bool   `bson:"is_active" json:"is_active"`
+	CreatedBy string `bson:"created_by" json:"created_by"`
+}
@@ -0,0 +1,52 @@
+package dao
+
+import "errors"
+
+// ErrCatalogMasterChanged: upsert produk branch / import mengubah field master, harus lewat /catalog/upsert
+var ErrCatalogMasterChanged = errors.New("master data can only be changed via /catalog/upsert")
+
+// CatalogProduct: master produk level client (1 SKU = 1 dokumen), dipakai bersama semua branch.
+// Inventory per branch tetap di collection products (stock, cost, harga override, aktif/nonaktif);
+// field master di sana adalah salinan yang selalu disinkron dari catalog.
+type CatalogProduct struct {
+	BaseModel `bson:",inline"`
+
+	ClientUUID  string        `bson:"client_uuid" json:"client_uuid"`
+	SKU         string        `bson:"sku" json:"sku"`
+	Barcode     string        `bson:"barcode" json:"barcode"`
+	PLU         string        `bson:"plu" json:"plu"`
+	Name        string        `bson:"name" json:"name"`
+	Description string        `bson:"description" json:"description"`
+	BaseUnit    string        `bson:"base_unit" json:"base_unit"`
+	Units       []ProductUnit `bson:"units" json:"units"`
+	Image       string        `bson:"image" json:"image"`
+
+	// kategori (leaf boleh level mana saja), brand & tag bebas
+	CategoryUUID string   `bson:"category_uuid" json:"category_uuid"`
+	CategoryPath string   `bson:"category_path" json:"category_path"`
+	BrandUUID    string   `bson:"brand_uuid" json:"brand_uuid"`
+	BrandName    string   `bson:"brand_name" json:"brand_name"`
+	Tags         []string `bson:"tags" json:"tags"`
+
+	Price     float64 `bson:"price" json:"price"` // harga jual default, branch boleh override
+	CreatedBy string  `bson:"created_by" json:"created_by"`
+
+	// varian: parent (has_variants) tidak punya stock, tiap kombinasi atribut = entry catalog sendiri
+	// (SKU, barcode, harga, stock per branch) dengan parent_uuid + variant_options
+	HasVariants       bool               `bson:"has_variants" json:"has_variants"`
+	VariantAttributes []VariantAttribute `bson:"variant_attributes,omitempty" json:"variant_attributes,omitempty"`
+	ParentUUID        string             `bson:"parent_uuid" json:"parent_uuid"`
+	VariantOptions    []VariantOption    `bson:"variant_options,omitempty" json:"variant_options,omitempty"`
+}
+
+// VariantAttribute: mis. { "name": "Ukuran", "values": ["S", "M", "L"] }
+type VariantAttribute struct {
+	Name   string   `bson:"name" json:"name"`
+	Values []string `bson:"values" json:"values"`
+}
+
+// VariantOption: nilai atribut 1 varian, mis. { "name": "Ukuran", "value": "M" }
+type VariantOption struct {
+	Name  string `bson:"name" json:"name"`
+	Value string `bson:"value" json:"value"`
+}
@@ -0,0 +1,85 @@
+package dao
+
+type CustomerTier string
+
+const (
+	CustomerTierRegular  CustomerTier = "REGULAR"
+	CustomerTierSilver   CustomerTier = "SILVER"
+	CustomerTierGold     CustomerTier = "GOLD"
+	CustomerTierPlatinum CustomerTier = "PLATINUM"
+)
+
+type Customer struct {
+	BaseModel `bson:",inline"`
+
+	ClientUUID string `bson:"client_uuid" json:"client_uuid"`
+	Name       string `bson:"name" json:"name" validate:"required"`
+	Phone      string `bson:"phone" json:"phone"`
+	Email      string `bson:"email" json:"email"`
+
+	// kode kartu member (barcode) untuk scan di kasir
+	MemberCode string       `bson:"member_code" json:"member_code"`
+	Tier       CustomerTier `bson:"tier" json:"tier"`
+
+	Points      int64   `bson:"points" json:"points"`
+	TotalSpent  float64 `bson:"total_spent" json:"total_spent"`
+	TotalVisits int64   `bson:"total_visits" json:"total_visits"`
+	LastVisitAt int64   `bson:"last_visit_at" json:"last_visit_at"`
+
+	// kasbon: limit di-set owner, balance = total piutang berjalan (hanya lewat transaksi/pembayaran)
+	CreditLimit    float64 `bson:"credit_limit" json:"credit_limit"`
+	CreditTermDays int     `bson:"credit_term_days" json:"credit_term_days"` // jatuh tempo (hari), default 30
+	CreditBalance  float64 `bson:"credit_balance" json:"credit_balance"`
+
+	Note      string `bson:"note" json:"note"`
+	IsActive  bool   `bson:"is_active" json:"is_active"`
+	CreatedBy string `bson:"created_by" json:"created_by"`
+}
+
+type PointLedgerType string
+
+const (
+	PointEarn     PointLedgerType = "EARN"
+	PointRedeem   PointLedgerType = "REDEEM"
+	PointReversal PointLedgerType = "REVERSAL"
+)
+
+// CustomerPointLedger: histori mutasi poin (append-only).
+// Points bertanda: + untuk earn / reversal redeem, - untuk redeem / reversal earn.
+type CustomerPointLedger struct {
+	BaseModel `bson:",inline"`
+
+	ClientUUID      string          `bson:"client_uuid" json:"client_uuid"`
+	CustomerUUID    string          `bson:"customer_uuid" json:"customer_uuid"`
+	TransactionUUID string          `bson:"transaction_uuid" json:"transaction_uuid"`
+	ReceiptNo       string          `bson:"receipt_no" json:"receipt_no"`
+	Type            PointLedgerType `bson:"type" json:"type"`
+	Points          int64           `bson:"points" json:"points"`
+	BalanceAfter    int64           `bson:"balance_after" json:"balance_after"`
+	Note            string          `bson:"note" json:"note"`
+	CreatedBy       string          `bson:"created_by" json:"created_by"`
+}
+
+// LoyaltyRule: aturan poin per client (1 dokumen per client).
+type LoyaltyRule struct {
+	BaseModel `bson:",inline"`
+
+	ClientUUID string `bson:"client_uuid" json:"client_uuid"`
+	IsActive   bool   `bson:"is_active" json:"is_active"`
+
+	// earn: tiap belanja EarnSpendAmount dapat EarnPoints (dikali multiplier tier)
+	EarnSpendAmount float64            `bson:"earn_spend_amount" json:"earn_spend_amount"`
+	EarnPoints      int64              `bson:"earn_points" json:"earn_points"`
+	MinSpendToEarn  float64            `bson:"min_spend_to_earn" json:"min_spend_to_earn"`
+	TierMultipliers map[string]float64 `bson:"tier_multipliers" json:"tier_multipliers"`
+
+	// naik tier otomatis kalau total_spent >= threshold
+	TierThresholds map[string]float64 `bson:"tier_thresholds" json:"tier_thresholds"`
+
+	// redeem: 1 poin = RedeemPointValue rupiah
+	RedeemPointValue float64 `bson:"redeem_point_value" json:"redeem_point_value"`
+	MinRedeemPoints  int64   `bson:"min_redeem_points" json:"min_redeem_points"`
+	MaxRedeemPercent float64 `bson:"max_redeem_percent" json:"max_redeem_percent"` // 0 = tanpa batas
+
+	UpdatedBy string `bson:"updated_by" json:"updated_by"`
+}
@@ -0,0 +1,46 @@
+package dao
+
+type EmailOutboxStatus string
+
+const (
+	EmailPending EmailOutboxStatus = "PENDING"
+	EmailSending EmailOutboxStatus = "SENDING"
+	EmailSent    EmailOutboxStatus = "SENT"
+	EmailFailed  EmailOutboxStatus = "FAILED" // sudah max attempts
+)
+
+const EmailTypeReceipt = "RECEIPT"
+
+type EmailOutboxAttachment struct {
+	Name        string `bson:"name" json:"name"`
+	ContentType string `bson:"content_type" json:"content_type"`
+	Data        []byte `bson:"data" json:"-"`
+}
+
+// EmailOutbox: antrian email, dikirim worker dengan retry (backoff).
+// Konten di-render saat enqueue supaya worker tidak perlu konteks user/branch.
+type EmailOutbox struct {
+	BaseModel `bson:",inline"`
+
+	ClientUUID   string `bson:"client_uuid" json:"client_uuid"`
+	BranchUUID   string `bson:"branch_uuid" json:"branch_uuid"`
+	Type         string `bson:"type" json:"type"` // RECEIPT
+	Ref          string `bson:"ref" json:"ref"`   // uuid transaksi
+	CustomerUUID string `bson:"customer_uuid,omitempty" json:"customer_uuid,omitempty"`
+
+	To          string                  `bson:"to" json:"to"`
+	Subject     string                  `bson:"subject" json:"subject"`
+	Body        string                  `bson:"body" json:"-"` // html
+	Attachments []EmailOutboxAttachment `bson:"attachments" json:"attachments"`
+
+	Status        EmailOutboxStatus `bson:"status" json:"status"`
+	Attempts      int               `bson:"attempts" json:"attempts"`
+	MaxAttempts   int               `bson:"max_attempts" json:"max_attempts"`
+	NextAttemptAt int64             `bson:"next_attempt_at" json:"next_attempt_at"`
+	LockedAt      int64             `bson:"locked_at" json:"locked_at"`
+	LastError     string            `bson:"last_error" json:"last_error"`
+	SentAt        int64             `bson:"sent_at" json:"sent_at"`
+	SentAtStr     string            `bson:"sent_at_str" json:"sent_at_str"`
+
+	CreatedBy string `bson:"created_by" json:"created_by"`
+}
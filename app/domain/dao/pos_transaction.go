@@ -8,9 +8,43 @@ type POSTransactionItem struct {
 	Description string        `bson:"description" json:"description"`
 	BaseUnit    string        `bson:"base_unit" json:"base_unit"`
 	Units       []ProductUnit `bson:"units" json:"units"`
-	Price       float64       `bson:"price" json:"price"`
-	Qty         int64         `bson:"qty" json:"qty"`
+	Price       float64       `bson:"price" json:"price"` // harga per Unit
+	Qty         int64         `bson:"qty" json:"qty"`     // qty dalam Unit
 	LineTotal   float64       `bson:"line_total" json:"line_total"`
+
+	// unit jual (box, karton, ...). Kosong = base unit
+	Unit       string  `bson:"unit" json:"unit"`
+	Conversion float64 `bson:"conversion" json:"conversion"`
+	QtyBase    int64   `bson:"qty_base" json:"qty_base"` // qty x conversion, yang dipotong dari stock
+
+	// cost per base unit saat terjual (weighted-average), CostTotal = Cost x QtyBase
+	Cost      float64 `bson:"cost" json:"cost"`
+	CostTotal float64 `bson:"cost_total" json:"cost_total"`
+
+	// lot yang terjual (produk track_lots, FEFO)
+	Lots []LotAllocation `bson:"lots,omitempty" json:"lots,omitempty"`
+
+	ScaleBarcode string `bson:"scale_barcode,omitempty" json:"scale_barcode,omitempty"`
+}
+
+// BaseQty: qty dalam base unit (trx lama belum punya qty_base)
+func (it POSTransactionItem) BaseQty() int64 {
+	if it.QtyBase > 0 {
+		return it.QtyBase
+	}
+	return it.Qty
+}
+
+// ReceiptEmailDelivery: status kirim struk digital terakhir (detail retry ada di email_outbox)
+type ReceiptEmailDelivery struct {
+	OutboxUUID   string            `bson:"outbox_uuid" json:"outbox_uuid"`
+	To           string            `bson:"to" json:"to"`
+	CustomerUUID string            `bson:"customer_uuid,omitempty" json:"customer_uuid,omitempty"`
+	Status       EmailOutboxStatus `bson:"status" json:"status"`
+	Attempts     int               `bson:"attempts" json:"attempts"`
+	LastError    string            `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	SentAt       int64             `bson:"sent_at,omitempty" json:"sent_at,omitempty"`
+	UpdatedAt    int64             `bson:"updated_at" json:"updated_at"`
 }
 
 type POSTransaction struct {
@@ -23,7 +57,7 @@ type POSTransaction struct {
 
 	Items []POSTransactionItem `bson:"items" json:"items"`
 
-	PaymentMethod string `bson:"payment_method" json:"payment_method"` // CASH / TRANSFER / QRIS
+	PaymentMethod string `bson:"payment_method" json:"payment_method"` // CASH / TRANSFER / QRIS / CREDIT
 
 	SubTotal float64 `bson:"sub_total" json:"sub_total"`
 	Discount float64 `bson:"discount" json:"discount"`
@@ -31,10 +65,41 @@ type POSTransaction struct {
 	Paid     float64 `bson:"paid" json:"paid"`
 	Change   float64 `bson:"change" json:"change"`
 
-	Status      string `bson:"status" json:"status"` // PAID / VOID
-	CreatedBy   string `bson:"created_by" json:"created_by"`
-	VoidedBy    string `bson:"voided_by" json:"voided_by"`
-	VoidedAt    int64  `bson:"voided_at" json:"voided_at"`
-	VoidedAtStr string `bson:"voided_at_str" json:"voided_at_str"`
-	Note        string `bson:"note" json:"note"`
+	// COGS = sum cost_total item, GrossProfit = Total - COGS
+	COGS        float64 `bson:"cogs" json:"cogs"`
+	GrossProfit float64 `bson:"gross_profit" json:"gross_profit"`
+
+	Status       string `bson:"status" json:"status"` // PAID / VOID
+	CreatedBy    string `bson:"created_by" json:"created_by"`
+	VoidedBy     string `bson:"voided_by" json:"voided_by"` // user uuid dari token
+	VoidedByName string `bson:"voided_by_name" json:"voided_by_name"`
+	VoidedAt     int64  `bson:"voided_at" json:"voided_at"`
+	VoidedAtStr  string `bson:"voided_at_str" json:"voided_at_str"`
+	Note         string `bson:"note" json:"note"`
+
+	// approval supervisor (audit detail di collection approvals)
+	VoidApprovalUUID     string `bson:"void_approval_uuid,omitempty" json:"void_approval_uuid,omitempty"`
+	DiscountApprovalUUID string `bson:"discount_approval_uuid,omitempty" json:"discount_approval_uuid,omitempty"`
+
+	// kasbon (payment_method CREDIT): sisa yang jadi piutang
+	CreditAmount   float64 `bson:"credit_amount" json:"credit_amount"`
+	ReceivableUUID string  `bson:"receivable_uuid,omitempty" json:"receivable_uuid,omitempty"`
+
+	// customer & loyalty (optional)
+	CustomerUUID   string  `bson:"customer_uuid,omitempty" json:"customer_uuid,omitempty"`
+	CustomerName   string  `bson:"customer_name,omitempty" json:"customer_name,omitempty"`
+	CustomerPhone  string  `bson:"customer_phone,omitempty" json:"customer_phone,omitempty"`
+	PointsEarned   int64   `bson:"points_earned" json:"points_earned"`
+	PointsRedeemed int64   `bson:"points_redeemed" json:"points_redeemed"`
+	PointsValue    float64 `bson:"points_value" json:"points_value"`         // nilai rupiah poin yang ditukar
+	PointsRedeemAs string  `bson:"points_redeem_as" json:"points_redeem_as"` // DISCOUNT / TENDER
+
+	// cetak ulang struk (reprint => "COPY")
+	ReprintCount       int    `bson:"reprint_count" json:"reprint_count"`
+	LastReprintedBy    string `bson:"last_reprinted_by,omitempty" json:"last_reprinted_by,omitempty"`
+	LastReprintedAt    int64  `bson:"last_reprinted_at,omitempty" json:"last_reprinted_at,omitempty"`
+	LastReprintedAtStr string `bson:"last_reprinted_at_str,omitempty" json:"last_reprinted_at_str,omitempty"`
+
+	// struk digital (email)
+	ReceiptEmail *ReceiptEmailDelivery `bson:"receipt_email,omitempty" json:"receipt_email,omitempty"`
 }
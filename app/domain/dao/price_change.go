@@ -0,0 +1,82 @@
+package dao
+
+type PriceChangeScope string
+
+const (
+	PriceScopeClient PriceChangeScope = "CLIENT" // harga catalog, ikut ke branch tanpa price_override
+	PriceScopeBranch PriceChangeScope = "BRANCH" // price_override 1 branch
+)
+
+type PriceChangeStatus string
+
+const (
+	PriceChangePending   PriceChangeStatus = "PENDING_APPROVAL"
+	PriceChangeScheduled PriceChangeStatus = "SCHEDULED" // disetujui, menunggu effective_from
+	PriceChangeApplied   PriceChangeStatus = "APPLIED"
+	PriceChangeRejected  PriceChangeStatus = "REJECTED"
+	PriceChangeCancelled PriceChangeStatus = "CANCELLED"
+	PriceChangeFailed    PriceChangeStatus = "FAILED" // gagal diterapkan (mis. inventory branch sudah tidak ada)
+)
+
+// PriceChange: perubahan harga terjadwal, diterapkan worker saat effective_from lewat
+// (POS membaca harga yang berlaku tanpa menunggu worker, lihat effectivePrice)
+type PriceChange struct {
+	BaseModel `bson:",inline"`
+
+	ClientUUID  string           `bson:"client_uuid" json:"client_uuid"`
+	CatalogUUID string           `bson:"catalog_uuid" json:"catalog_uuid"`
+	Scope       PriceChangeScope `bson:"scope" json:"scope"`
+	BranchUUID  string           `bson:"branch_uuid" json:"branch_uuid"` // BRANCH saja
+	SKU         string           `bson:"sku" json:"sku"`
+	Name        string           `bson:"name" json:"name"`
+
+	OldPrice float64 `bson:"old_price" json:"old_price"` // harga saat request, diperbarui saat diterapkan
+	NewPrice float64 `bson:"new_price" json:"new_price"`
+
+	EffectiveFrom    int64  `bson:"effective_from" json:"effective_from"`
+	EffectiveFromStr string `bson:"effective_from_str" json:"effective_from_str"`
+
+	Status PriceChangeStatus `bson:"status" json:"status"`
+	Note   string            `bson:"note" json:"note"`
+
+	RequestedBy     string `bson:"requested_by" json:"requested_by"`
+	RequestedByName string `bson:"requested_by_name" json:"requested_by_name"`
+
+	ApprovedBy     string `bson:"approved_by" json:"approved_by"`
+	ApprovedByName string `bson:"approved_by_name" json:"approved_by_name"`
+	DecisionNote   string `bson:"decision_note" json:"decision_note"`
+	DecidedAt      int64  `bson:"decided_at" json:"decided_at"`
+	DecidedAtStr   string `bson:"decided_at_str" json:"decided_at_str"`
+
+	LockedAt     int64  `bson:"locked_at" json:"locked_at"`
+	AppliedAt    int64  `bson:"applied_at" json:"applied_at"`
+	AppliedAtStr string `bson:"applied_at_str" json:"applied_at_str"`
+	LastError    string `bson:"last_error" json:"last_error"`
+}
+
+type PriceHistorySource string
+
+const (
+	PriceSourceManual    PriceHistorySource = "MANUAL"    // edit catalog / inventory / produk
+	PriceSourceScheduled PriceHistorySource = "SCHEDULED" // price change terjadwal
+	PriceSourceImport    PriceHistorySource = "IMPORT"    // import produk (Excel / CSV)
+)
+
+// PriceHistory: jejak harga per produk. branch_uuid kosong = harga catalog (semua branch tanpa override)
+type PriceHistory struct {
+	BaseModel `bson:",inline"`
+
+	ClientUUID  string `bson:"client_uuid" json:"client_uuid"`
+	CatalogUUID string `bson:"catalog_uuid" json:"catalog_uuid"`
+	BranchUUID  string `bson:"branch_uuid" json:"branch_uuid"`
+	ProductUUID string `bson:"product_uuid" json:"product_uuid"` // record branch (BRANCH / produk tanpa catalog)
+	SKU         string `bson:"sku" json:"sku"`
+	Name        string `bson:"name" json:"name"`
+
+	OldPrice float64 `bson:"old_price" json:"old_price"`
+	NewPrice float64 `bson:"new_price" json:"new_price"`
+
+	Source    PriceHistorySource `bson:"source" json:"source"`
+	Ref       string             `bson:"ref" json:"ref"` // uuid price change (SCHEDULED) / job import (IMPORT)
+	ChangedBy string             `bson:"changed_by" json:"changed_by"`
+}
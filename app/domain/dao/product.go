@@ -3,14 +3,19 @@ package dao
 type ProductUnit struct {
 	Name             string  `bson:"name" json:"name"`
 	ConversionToBase float64 `bson:"conversion_to_base" json:"conversion_to_base"`
+
+	// harga jual per unit ini (optional). 0 = Price produk x ConversionToBase
+	Price float64 `bson:"price,omitempty" json:"price,omitempty"`
 }
 
 type Product struct {
 	BaseModel `bson:",inline"`
 
 	BranchUUID  string        `bson:"branch_uuid" json:"branch_uuid"`
+	CatalogUUID string        `bson:"catalog_uuid" json:"catalog_uuid"` // master di catalog_products
 	SKU         string        `bson:"sku" json:"sku"`
 	Barcode     string        `bson:"barcode" json:"barcode"`
+	PLU         string        `bson:"plu" json:"plu"` // kode PLU timbangan (optional)
 	Name        string        `bson:"name" json:"name"`
 	Description string        `bson:"description" json:"description"`
 	BaseUnit    string        `bson:"base_unit" json:"base_unit"`
@@ -19,6 +24,27 @@ type Product struct {
 	Price       float64       `bson:"price" json:"price"`
 	Image       string        `bson:"image" json:"image"`
 	Stock       int64         `bson:"stock" json:"stock"`
+	TrackLots   bool          `bson:"track_lots" json:"track_lots"` // stock dirinci per lot + expiry (FEFO)
 	IsActive    bool          `bson:"is_active" json:"is_active"`
 	CreatedBy   string        `bson:"created_by" json:"created_by"`
+
+	// kategori / brand / tag: salinan dari catalog (filter & POS tanpa join)
+	CategoryUUID string   `bson:"category_uuid" json:"category_uuid"`
+	CategoryPath string   `bson:"category_path" json:"category_path"`
+	BrandUUID    string   `bson:"brand_uuid" json:"brand_uuid"`
+	BrandName    string   `bson:"brand_name" json:"brand_name"`
+	Tags         []string `bson:"tags" json:"tags"`
+
+	// varian: parent_uuid = catalog parent (scan barcode varian => grup parent)
+	ParentUUID     string          `bson:"parent_uuid" json:"parent_uuid"`
+	VariantOptions []VariantOption `bson:"variant_options,omitempty" json:"variant_options,omitempty"`
+
+	// PriceOverride: harga khusus branch ini; nil = Price ikut harga catalog
+	PriceOverride *float64 `bson:"price_override,omitempty" json:"price_override,omitempty"`
+
+	// reorder point & stock maksimum per produk per branch (0 = pakai threshold global / velocity)
+	MinStock int64 `bson:"min_stock" json:"min_stock"`
+	MaxStock int64 `bson:"max_stock" json:"max_stock"`
+	// LowStockAlert: notifikasi stock menipis sudah dikirim, reset saat stock naik lagi
+	LowStockAlert bool `bson:"low_stock_alert" json:"low_stock_alert"`
 }
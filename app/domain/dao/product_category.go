@@ -0,0 +1,29 @@
+package dao
+
+// CategoryPathSep: pemisah path kategori, mis. "Minuman > Kopi"
+const CategoryPathSep = " > "
+
+// ProductCategory: pohon kategori per client. Ancestors (uuid root..parent) dipakai untuk query subtree & roll-up.
+type ProductCategory struct {
+	BaseModel `bson:",inline"`
+
+	ClientUUID string   `bson:"client_uuid" json:"client_uuid"`
+	ParentUUID string   `bson:"parent_uuid" json:"parent_uuid"`
+	Name       string   `bson:"name" json:"name"`
+	Path       string   `bson:"path" json:"path"`
+	Ancestors  []string `bson:"ancestors" json:"ancestors"`
+	Level      int      `bson:"level" json:"level"` // root = 0
+	SortOrder  int      `bson:"sort_order" json:"sort_order"`
+	CreatedBy  string   `bson:"created_by" json:"created_by"`
+
+	Children []ProductCategory `bson:"-" json:"children,omitempty"`
+}
+
+type ProductBrand struct {
+	BaseModel `bson:",inline"`
+
+	ClientUUID string `bson:"client_uuid" json:"client_uuid"`
+	Name       string `bson:"name" json:"name"`
+	Logo       string `bson:"logo" json:"logo"`
+	CreatedBy  string `bson:"created_by" json:"created_by"`
+}
@@ -0,0 +1,79 @@
+package dao
+
+type ProductImportStatus string
+
+const (
+	ImportPending ProductImportStatus = "PENDING"
+	ImportRunning ProductImportStatus = "RUNNING"
+	ImportDone    ProductImportStatus = "DONE"
+	ImportFailed  ProductImportStatus = "FAILED" // file tidak bisa dibaca / error sistem
+)
+
+type ProductImportMode string
+
+const (
+	ImportModeUpsert     ProductImportMode = "UPSERT"      // produk yang sudah ada di-update
+	ImportModeCreateOnly ProductImportMode = "CREATE_ONLY" // produk yang sudah ada => baris error
+)
+
+// ProductImportJob: import produk CSV/XLSX, diproses worker per batch (bulk write).
+// dry_run: hanya validasi + diff terhadap produk yang ada, tidak ada yang ditulis.
+type ProductImportJob struct {
+	BaseModel `bson:",inline"`
+
+	ClientUUID string `bson:"client_uuid" json:"client_uuid"`
+	BranchUUID string `bson:"branch_uuid" json:"branch_uuid"` // non-OWNER: hanya boleh import ke branch ini
+	FileName   string `bson:"file_name" json:"file_name"`
+	File       []byte `bson:"file" json:"-"`
+
+	Mode      ProductImportMode `bson:"mode" json:"mode"`
+	DryRun    bool              `bson:"dry_run" json:"dry_run"`
+	BatchSize int               `bson:"batch_size" json:"batch_size"`
+
+	Status    ProductImportStatus `bson:"status" json:"status"`
+	Attempts  int                 `bson:"attempts" json:"attempts"`
+	LockedAt  int64               `bson:"locked_at" json:"locked_at"`
+	LastError string              `bson:"last_error" json:"last_error"`
+
+	TotalRows     int `bson:"total_rows" json:"total_rows"`
+	ProcessedRows int `bson:"processed_rows" json:"processed_rows"`
+	Created       int `bson:"created" json:"created"`
+	Updated       int `bson:"updated" json:"updated"`
+	Unchanged     int `bson:"unchanged" json:"unchanged"`
+	Failed        int `bson:"failed" json:"failed"` // jumlah baris gagal
+
+	Errors []ProductImportError `bson:"errors" json:"errors"`
+	Diffs  []ProductImportDiff  `bson:"diffs,omitempty" json:"diffs,omitempty"` // dry_run saja
+
+	StartedAt     int64  `bson:"started_at" json:"started_at"`
+	StartedAtStr  string `bson:"started_at_str" json:"started_at_str"`
+	FinishedAt    int64  `bson:"finished_at" json:"finished_at"`
+	FinishedAtStr string `bson:"finished_at_str" json:"finished_at_str"`
+
+	CreatedBy string `bson:"created_by" json:"created_by"`
+}
+
+// ProductImportError: row = nomor baris di file (header = 1), column kosong = error baris (mis. gagal simpan)
+type ProductImportError struct {
+	Row     int    `bson:"row" json:"row"`
+	Column  string `bson:"column" json:"column"`
+	Value   string `bson:"value" json:"value"`
+	Message string `bson:"message" json:"message"`
+}
+
+// ProductImportDiff: action CREATE / UPDATE / UNCHANGED
+type ProductImportDiff struct {
+	Row         int                   `bson:"row" json:"row"`
+	Action      string                `bson:"action" json:"action"`
+	ProductUUID string                `bson:"product_uuid,omitempty" json:"product_uuid,omitempty"`
+	BranchUUID  string                `bson:"branch_uuid" json:"branch_uuid"`
+	SKU         string                `bson:"sku" json:"sku"`
+	Name        string                `bson:"name" json:"name"`
+	Changes     []ProductImportChange `bson:"changes,omitempty" json:"changes,omitempty"`
+}
+
+type ProductImportChange struct {
+	Field string `bson:"field" json:"field"`
+	Old   string `bson:"old" json:"old"`
+	New   string `bson:"new" json:"new"`
+}
@@ -0,0 +1,111 @@
+package dao
+
+type PurchaseOrderStatus string
+
+const (
+	PODraft     PurchaseOrderStatus = "DRAFT"
+	POSent      PurchaseOrderStatus = "SENT"
+	POPartial   PurchaseOrderStatus = "PARTIAL" // sebagian sudah diterima
+	POReceived  PurchaseOrderStatus = "RECEIVED"
+	POCancelled PurchaseOrderStatus = "CANCELLED"
+)
+
+type PurchaseOrder struct {
+	BaseModel `bson:",inline"`
+
+	ClientUUID   string              `bson:"client_uuid" json:"client_uuid"`
+	BranchUUID   string              `bson:"branch_uuid" json:"branch_uuid"` // branch penerima
+	SupplierUUID string              `bson:"supplier_uuid" json:"supplier_uuid"`
+	SupplierName string              `bson:"supplier_name" json:"supplier_name"`
+	PONo         string              `bson:"po_no" json:"po_no"`
+	Status       PurchaseOrderStatus `bson:"status" json:"status"`
+
+	ExpectedDate int64  `bson:"expected_date" json:"expected_date"` // unix, optional
+	Note         string `bson:"note" json:"note"`
+
+	Items     []PurchaseOrderItem `bson:"items" json:"items"`
+	SubTotal  float64             `bson:"sub_total" json:"sub_total"`
+	Discount  float64             `bson:"discount" json:"discount"`
+	Tax       float64             `bson:"tax" json:"tax"`
+	Total     float64             `bson:"total" json:"total"`
+	CreatedBy string              `bson:"created_by" json:"created_by"`
+
+	SentAt       int64  `bson:"sent_at" json:"sent_at"`
+	SentBy       string `bson:"sent_by" json:"sent_by"`
+	ReceivedAt   int64  `bson:"received_at" json:"received_at"`
+	CancelledAt  int64  `bson:"cancelled_at" json:"cancelled_at"`
+	CancelledBy  string `bson:"cancelled_by" json:"cancelled_by"`
+	CancelReason string `bson:"cancel_reason" json:"cancel_reason"`
+
+	// Rev: optimistic lock, naik tiap penerimaan barang
+	Rev int64 `bson:"rev" json:"rev"`
+}
+
+type PurchaseOrderItem struct {
+	ProductUUID string `bson:"product_uuid" json:"product_uuid"`
+	SKU         string `bson:"sku" json:"sku"`
+	Name        string `bson:"name" json:"name"`
+	BaseUnit    string `bson:"base_unit" json:"base_unit"`
+
+	// unit beli (mis. box isi 12), qty dalam unit ini
+	Unit             string  `bson:"unit" json:"unit"`
+	ConversionToBase float64 `bson:"conversion_to_base" json:"conversion_to_base"`
+	Qty              float64 `bson:"qty" json:"qty"`
+	QtyBase          int64   `bson:"qty_base" json:"qty_base"`
+
+	// harga beli yang diharapkan per unit beli
+	UnitCost  float64 `bson:"unit_cost" json:"unit_cost"`
+	LineTotal float64 `bson:"line_total" json:"line_total"`
+
+	ReceivedBase int64 `bson:"received_base" json:"received_base"`
+}
+
+// RemainingBase: qty (base unit) yang belum diterima
+func (it PurchaseOrderItem) RemainingBase() int64 {
+	if r := it.QtyBase - it.ReceivedBase; r > 0 {
+		return r
+	}
+	return 0
+}
+
+// GoodsReceipt: bukti terima barang (GRN). 1 PO bisa punya beberapa GRN (pengiriman parsial).
+type GoodsReceipt struct {
+	BaseModel `bson:",inline"`
+
+	ClientUUID   string `bson:"client_uuid" json:"client_uuid"`
+	BranchUUID   string `bson:"branch_uuid" json:"branch_uuid"`
+	POUUID       string `bson:"po_uuid" json:"po_uuid"`
+	PONo         string `bson:"po_no" json:"po_no"`
+	SupplierUUID string `bson:"supplier_uuid" json:"supplier_uuid"`
+	SupplierName string `bson:"supplier_name" json:"supplier_name"`
+	GRNNo        string `bson:"grn_no" json:"grn_no"`
+
+	// no surat jalan / invoice dari supplier
+	SupplierRef string `bson:"supplier_ref" json:"supplier_ref"`
+	Note        string `bson:"note" json:"note"`
+
+	Items      []GoodsReceiptItem `bson:"items" json:"items"`
+	Total      float64            `bson:"total" json:"total"`
+	ReceivedBy string             `bson:"received_by" json:"received_by"`
+}
+
+type GoodsReceiptItem struct {
+	ProductUUID string `bson:"product_uuid" json:"product_uuid"`
+	SKU         string `bson:"sku" json:"sku"`
+	Name        string `bson:"name" json:"name"`
+
+	Unit             string  `bson:"unit" json:"unit"`
+	ConversionToBase float64 `bson:"conversion_to_base" json:"conversion_to_base"`
+	Qty              float64 `bson:"qty" json:"qty"`
+	QtyBase          int64   `bson:"qty_base" json:"qty_base"`
+
+	UnitCost    float64 `bson:"unit_cost" json:"unit_cost"`
+	CostPerBase float64 `bson:"cost_per_base" json:"cost_per_base"`
+	LineTotal   float64 `bson:"line_total" json:"line_total"`
+
+	LotNo         string `bson:"lot_no,omitempty" json:"lot_no,omitempty"`
+	ExpiryDate    int64  `bson:"expiry_date,omitempty" json:"expiry_date,omitempty"`
+	ExpiryDateStr string `bson:"expiry_date_str,omitempty" json:"expiry_date_str,omitempty"`
+
+	BalanceAfter int64 `bson:"balance_after" json:"balance_after"`
+}
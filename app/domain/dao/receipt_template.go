@@ -0,0 +1,35 @@
+package dao
+
+// ReceiptTemplate: pengaturan struk per client (1 dokumen per client)
+type ReceiptTemplate struct {
+	BaseModel `bson:",inline"`
+
+	ClientUUID string `bson:"client_uuid" json:"client_uuid"`
+
+	PaperWidth int `bson:"paper_width" json:"paper_width"` // 58 / 80 (mm)
+
+	HeaderText string `bson:"header_text" json:"header_text"`
+	FooterText string `bson:"footer_text" json:"footer_text"`
+
+	ShowLogo     bool   `bson:"show_logo" json:"show_logo"`
+	LogoURL      string `bson:"logo_url" json:"logo_url"` // kosong = pakai logo client
+	ShowAddress  bool   `bson:"show_address" json:"show_address"`
+	ShowCustomer bool   `bson:"show_customer" json:"show_customer"`
+	ShowPoints   bool   `bson:"show_points" json:"show_points"`
+	OpenDrawer   bool   `bson:"open_drawer" json:"open_drawer"` // ESC/POS: buka laci kas saat cetak
+
+	UpdatedBy string `bson:"updated_by" json:"updated_by"`
+}
+
+// DefaultReceiptTemplate: dipakai kalau client belum pernah simpan template
+func DefaultReceiptTemplate(clientUUID string) ReceiptTemplate {
+	return ReceiptTemplate{
+		ClientUUID:   clientUUID,
+		PaperWidth:   58,
+		FooterText:   "Terima kasih atas kunjungan Anda",
+		ShowLogo:     true,
+		ShowAddress:  true,
+		ShowCustomer: true,
+		ShowPoints:   true,
+	}
+}
@@ -0,0 +1,49 @@
+package dao
+
+type ReceivableStatus string
+
+const (
+	ReceivableOpen    ReceivableStatus = "OPEN"
+	ReceivablePartial ReceivableStatus = "PARTIAL"
+	ReceivablePaid    ReceivableStatus = "PAID"
+	ReceivableVoid    ReceivableStatus = "VOID"
+)
+
+type ReceivablePayment struct {
+	UUID      string  `bson:"uuid" json:"uuid"`
+	Amount    float64 `bson:"amount" json:"amount"`
+	Method    string  `bson:"method" json:"method"` // CASH / TRANSFER / QRIS
+	Note      string  `bson:"note" json:"note"`
+	PaidAt    int64   `bson:"paid_at" json:"paid_at"`
+	PaidAtStr string  `bson:"paid_at_str" json:"paid_at_str"`
+	CreatedBy string  `bson:"created_by" json:"created_by"`
+}
+
+// Receivable: piutang (kasbon) per transaksi POS dengan tender CREDIT.
+type Receivable struct {
+	BaseModel `bson:",inline"`
+
+	ClientUUID   string `bson:"client_uuid" json:"client_uuid"`
+	BranchUUID   string `bson:"branch_uuid" json:"branch_uuid"`
+	CustomerUUID string `bson:"customer_uuid" json:"customer_uuid"`
+	CustomerName string `bson:"customer_name" json:"customer_name"`
+
+	TransactionUUID string `bson:"transaction_uuid" json:"transaction_uuid"`
+	ReceiptNo       string `bson:"receipt_no" json:"receipt_no"`
+
+	Amount      float64 `bson:"amount" json:"amount"`
+	PaidAmount  float64 `bson:"paid_amount" json:"paid_amount"`
+	Outstanding float64 `bson:"outstanding" json:"outstanding"`
+
+	DueDate    int64  `bson:"due_date" json:"due_date"`
+	DueDateStr string `bson:"due_date_str" json:"due_date_str"`
+
+	Status   ReceivableStatus    `bson:"status" json:"status"`
+	Payments []ReceivablePayment `bson:"payments" json:"payments"`
+
+	// reminder jatuh tempo (worker), biar tidak spam
+	LastRemindedAt int64 `bson:"last_reminded_at" json:"last_reminded_at"`
+
+	CreatedBy string `bson:"created_by" json:"created_by"`
+	VoidedBy  string `bson:"voided_by,omitempty" json:"voided_by,omitempty"`
+}
@@ -0,0 +1,70 @@
+package dao
+
+type ReplenishmentStatus string
+
+const (
+	ReplenishmentDraft     ReplenishmentStatus = "DRAFT"
+	ReplenishmentApproved  ReplenishmentStatus = "APPROVED"
+	ReplenishmentDiscarded ReplenishmentStatus = "DISCARDED"
+)
+
+type ReplenishmentSource string
+
+const (
+	ReplenishFromTransfer ReplenishmentSource = "TRANSFER" // dari gudang
+	ReplenishFromPurchase ReplenishmentSource = "PURCHASE" // PO ke supplier
+)
+
+// ReplenishmentPlan: usulan restock 1 branch (DRAFT). Approve => stock transfer dari gudang + PO per supplier.
+type ReplenishmentPlan struct {
+	BaseModel `bson:",inline"`
+
+	ClientUUID string              `bson:"client_uuid" json:"client_uuid"`
+	BranchUUID string              `bson:"branch_uuid" json:"branch_uuid"`
+	Code       string              `bson:"code" json:"code"` // RPL-...
+	Status     ReplenishmentStatus `bson:"status" json:"status"`
+
+	// parameter engine
+	VelocityDays int `bson:"velocity_days" json:"velocity_days"`
+	LeadDays     int `bson:"lead_days" json:"lead_days"`
+	CoverDays    int `bson:"cover_days" json:"cover_days"`
+
+	Lines     []ReplenishmentLine `bson:"lines" json:"lines"`
+	CreatedBy string              `bson:"created_by" json:"created_by"`
+
+	ApprovedBy    string   `bson:"approved_by,omitempty" json:"approved_by,omitempty"`
+	ApprovedAt    int64    `bson:"approved_at,omitempty" json:"approved_at,omitempty"`
+	DiscardedBy   string   `bson:"discarded_by,omitempty" json:"discarded_by,omitempty"`
+	TransferUUIDs []string `bson:"transfer_uuids,omitempty" json:"transfer_uuids,omitempty"`
+	POUUIDs       []string `bson:"po_uuids,omitempty" json:"po_uuids,omitempty"`
+	// line yang tidak bisa dibuatkan dokumen (mis. supplier belum ada)
+	Skipped []string `bson:"skipped,omitempty" json:"skipped,omitempty"`
+}
+
+type ReplenishmentLine struct {
+	ProductUUID string `bson:"product_uuid" json:"product_uuid"`
+	SKU         string `bson:"sku" json:"sku"`
+	Name        string `bson:"name" json:"name"`
+	BaseUnit    string `bson:"base_unit" json:"base_unit"`
+
+	Stock         int64   `bson:"stock" json:"stock"`
+	MinStock      int64   `bson:"min_stock" json:"min_stock"`
+	MaxStock      int64   `bson:"max_stock" json:"max_stock"`
+	SoldQty       int64   `bson:"sold_qty" json:"sold_qty"` // terjual selama VelocityDays
+	DailyVelocity float64 `bson:"daily_velocity" json:"daily_velocity"`
+	ReorderPoint  int64   `bson:"reorder_point" json:"reorder_point"`
+	TargetStock   int64   `bson:"target_stock" json:"target_stock"`
+
+	Source ReplenishmentSource `bson:"source" json:"source"`
+	Qty    int64               `bson:"qty" json:"qty"` // base unit, boleh diubah saat approve
+
+	// TRANSFER: produk yang sama (SKU) di gudang
+	FromBranchUUID  string `bson:"from_branch_uuid,omitempty" json:"from_branch_uuid,omitempty"`
+	FromProductUUID string `bson:"from_product_uuid,omitempty" json:"from_product_uuid,omitempty"`
+	FromAvailable   int64  `bson:"from_available,omitempty" json:"from_available,omitempty"`
+
+	// PURCHASE: supplier dari harga beli terakhir
+	SupplierUUID string  `bson:"supplier_uuid,omitempty" json:"supplier_uuid,omitempty"`
+	SupplierName string  `bson:"supplier_name,omitempty" json:"supplier_name,omitempty"`
+	CostPerBase  float64 `bson:"cost_per_base,omitempty" json:"cost_per_base,omitempty"`
+}
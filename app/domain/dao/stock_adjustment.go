@@ -0,0 +1,76 @@
+package dao
+
+type StockAdjustmentReason string
+
+const (
+	AdjustDamaged StockAdjustmentReason = "DAMAGED"
+	AdjustExpired StockAdjustmentReason = "EXPIRED"
+	AdjustLost    StockAdjustmentReason = "LOST"
+	AdjustFound   StockAdjustmentReason = "FOUND"
+	AdjustSample  StockAdjustmentReason = "SAMPLE"
+)
+
+// IsIncrease: FOUND menambah stock, sisanya mengurangi
+func (r StockAdjustmentReason) IsIncrease() bool { return r == AdjustFound }
+
+func (r StockAdjustmentReason) Valid() bool {
+	switch r {
+	case AdjustDamaged, AdjustExpired, AdjustLost, AdjustFound, AdjustSample:
+		return true
+	}
+	return false
+}
+
+type StockAdjustmentStatus string
+
+const (
+	StockAdjustmentPending  StockAdjustmentStatus = "PENDING" // menunggu approval OWNER
+	StockAdjustmentApplied  StockAdjustmentStatus = "APPLIED"
+	StockAdjustmentRejected StockAdjustmentStatus = "REJECTED"
+	StockAdjustmentFailed   StockAdjustmentStatus = "FAILED" // stock tidak cukup saat diterapkan
+)
+
+// StockAdjustment: koreksi stock manual (delta, bukan set), diterapkan lewat ledger
+type StockAdjustment struct {
+	BaseModel `bson:",inline"`
+
+	ClientUUID string                `bson:"client_uuid" json:"client_uuid"`
+	BranchUUID string                `bson:"branch_uuid" json:"branch_uuid"`
+	Code       string                `bson:"code" json:"code"`
+	Reason     StockAdjustmentReason `bson:"reason" json:"reason"`
+	Note       string                `bson:"note" json:"note"`
+	Status     StockAdjustmentStatus `bson:"status" json:"status"`
+
+	Items      []StockAdjustmentItem `bson:"items" json:"items"`
+	TotalQty   int64                 `bson:"total_qty" json:"total_qty"`     // sum |delta|
+	TotalValue float64               `bson:"total_value" json:"total_value"` // sum |delta| x cost
+
+	RequestedBy     string `bson:"requested_by" json:"requested_by"`
+	RequestedByName string `bson:"requested_by_name" json:"requested_by_name"`
+
+	ApprovalRequired bool   `bson:"approval_required" json:"approval_required"`
+	DecidedBy        string `bson:"decided_by" json:"decided_by"`
+	DecidedByName    string `bson:"decided_by_name" json:"decided_by_name"`
+	DecisionNote     string `bson:"decision_note" json:"decision_note"`
+	DecidedAt        int64  `bson:"decided_at" json:"decided_at"`
+
+	AppliedAt int64  `bson:"applied_at" json:"applied_at"`
+	LastError string `bson:"last_error" json:"last_error"`
+}
+
+type StockAdjustmentItem struct {
+	ProductUUID string  `bson:"product_uuid" json:"product_uuid"`
+	SKU         string  `bson:"sku" json:"sku"`
+	Name        string  `bson:"name" json:"name"`
+	BaseUnit    string  `bson:"base_unit" json:"base_unit"`
+	Delta       int64   `bson:"delta" json:"delta"`
+	Cost        float64 `bson:"cost" json:"cost"`
+	Value       float64 `bson:"value" json:"value"` // delta x cost
+
+	LotNo      string `bson:"lot_no,omitempty" json:"lot_no,omitempty"`
+	ExpiryDate int64  `bson:"expiry_date,omitempty" json:"expiry_date,omitempty"`
+
+	BalanceAfter int64 `bson:"balance_after" json:"balance_after"`
+	// lot yang terpakai (keluar FEFO, termasuk lot kedaluwarsa) / lot tujuan (masuk)
+	Lots []LotAllocation `bson:"lots,omitempty" json:"lots,omitempty"`
+}
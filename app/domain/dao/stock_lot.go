@@ -0,0 +1,34 @@
+package dao
+
+// StockLot: rincian stock per lot/batch untuk produk dengan TrackLots.
+// products.stock tetap jadi angka utama; qty lot menjelaskan isi stock tsb (FEFO).
+type StockLot struct {
+	BaseModel `bson:",inline"`
+
+	BranchUUID  string `bson:"branch_uuid" json:"branch_uuid"`
+	ProductUUID string `bson:"product_uuid" json:"product_uuid"`
+	SKU         string `bson:"sku" json:"sku"`
+	Name        string `bson:"name" json:"name"`
+
+	LotNo         string `bson:"lot_no" json:"lot_no"`
+	ExpiryDate    int64  `bson:"expiry_date" json:"expiry_date"` // unix awal hari (WIB), 0 = tanpa expiry
+	ExpiryDateStr string `bson:"expiry_date_str" json:"expiry_date_str"`
+	Qty           int64  `bson:"qty" json:"qty"` // base unit
+
+	// 0 belum, 1 sudah diingatkan "segera kedaluwarsa", 2 sudah diingatkan "kedaluwarsa"
+	NotifiedStage int `bson:"notified_stage" json:"notified_stage"`
+}
+
+// Expired: mulai hari H expiry lot sudah tidak boleh dijual / dikirim
+func (l StockLot) Expired(now int64) bool {
+	return l.ExpiryDate > 0 && now >= l.ExpiryDate
+}
+
+// LotAllocation: bagian qty yang diambil / dimasukkan ke 1 lot (disimpan di dokumen sumber untuk audit & rollback)
+type LotAllocation struct {
+	LotUUID       string `bson:"lot_uuid" json:"lot_uuid"`
+	LotNo         string `bson:"lot_no" json:"lot_no"`
+	ExpiryDate    int64  `bson:"expiry_date" json:"expiry_date"`
+	ExpiryDateStr string `bson:"expiry_date_str" json:"expiry_date_str"`
+	Qty           int64  `bson:"qty" json:"qty"`
+}
@@ -0,0 +1,50 @@
+package dao
+
+type StockMovementReason string
+
+const (
+	StockMoveSale        StockMovementReason = "SALE"
+	StockMoveVoid        StockMovementReason = "VOID"
+	StockMoveTransferOut StockMovementReason = "TRANSFER_OUT"
+	StockMoveTransferIn  StockMovementReason = "TRANSFER_IN"
+	// stock TRANSFER_OUT kembali ke branch asal (transfer ditolak / batal / kedaluwarsa)
+	StockMoveTransferReturn StockMovementReason = "TRANSFER_RETURN"
+	StockMoveAdjustment     StockMovementReason = "ADJUSTMENT"
+	StockMoveImport         StockMovementReason = "IMPORT"
+	StockMoveOpname         StockMovementReason = "OPNAME"
+	StockMovePurchase       StockMovementReason = "PURCHASE"
+)
+
+// StockRef: konteks perubahan stock yang dicatat ke ledger
+type StockRef struct {
+	Reason    StockMovementReason
+	RefUUID   string // uuid transaksi / transfer / dokumen lain
+	RefNo     string // nomor yang human readable (receipt no, dsb)
+	Note      string
+	CreatedBy string
+}
+
+// StockBefore: stock + cost produk sebelum perubahan, dipakai rollback untuk mengembalikan weighted-average cost
+type StockBefore struct {
+	Stock int64
+	Cost  float64
+}
+
+// StockMovement: ledger append-only, 1 dokumen per perubahan stock produk
+type StockMovement struct {
+	BaseModel `bson:",inline"`
+
+	ProductUUID string `bson:"product_uuid" json:"product_uuid"`
+	BranchUUID  string `bson:"branch_uuid" json:"branch_uuid"`
+	SKU         string `bson:"sku" json:"sku"`
+	Name        string `bson:"name" json:"name"`
+
+	Delta        int64 `bson:"delta" json:"delta"` // + masuk, - keluar (base unit)
+	BalanceAfter int64 `bson:"balance_after" json:"balance_after"`
+
+	Reason    StockMovementReason `bson:"reason" json:"reason"`
+	RefUUID   string              `bson:"ref_uuid" json:"ref_uuid"`
+	RefNo     string              `bson:"ref_no" json:"ref_no"`
+	Note      string              `bson:"note" json:"note"`
+	CreatedBy string              `bson:"created_by" json:"created_by"`
+}
@@ -0,0 +1,72 @@
+package dao
+
+import "time"
+
+type StockOpnameStatus string
+
+const (
+	StockOpnameOpen      StockOpnameStatus = "OPEN"
+	StockOpnameApproved  StockOpnameStatus = "APPROVED" // adjustment sudah diposting
+	StockOpnameCancelled StockOpnameStatus = "CANCELLED"
+)
+
+// StockOpname: sesi hitung fisik per branch. Item (snapshot + hasil hitung) di collection stock_opname_items.
+type StockOpname struct {
+	BaseModel `bson:",inline"`
+
+	ClientUUID string            `bson:"client_uuid" json:"client_uuid"`
+	BranchUUID string            `bson:"branch_uuid" json:"branch_uuid"`
+	Code       string            `bson:"code" json:"code"`
+	Status     StockOpnameStatus `bson:"status" json:"status"`
+	Note       string            `bson:"note" json:"note"`
+
+	// waktu snapshot stock sistem; penjualan setelah ini dihitung dari stock_movements
+	SnapshotAt time.Time `bson:"snapshot_at" json:"snapshot_at"`
+	TotalItems int       `bson:"total_items" json:"total_items"`
+
+	StartedBy   string `bson:"started_by" json:"started_by"`
+	ApprovedBy  string `bson:"approved_by" json:"approved_by"`
+	ApprovedAt  int64  `bson:"approved_at" json:"approved_at"`
+	CancelledBy string `bson:"cancelled_by" json:"cancelled_by"`
+
+	// ringkasan hasil posting (diisi saat approve)
+	AdjustedItems  int     `bson:"adjusted_items" json:"adjusted_items"`
+	VarianceQty    int64   `bson:"variance_qty" json:"variance_qty"`
+	VarianceValue  float64 `bson:"variance_value" json:"variance_value"`
+	UncountedItems int     `bson:"uncounted_items" json:"uncounted_items"`
+}
+
+type StockOpnameCount struct {
+	CounterUUID string `bson:"counter_uuid" json:"counter_uuid"`
+	CounterName string `bson:"counter_name" json:"counter_name"`
+	Qty         int64  `bson:"qty" json:"qty"`
+	At          int64  `bson:"at" json:"at"`
+}
+
+type StockOpnameItem struct {
+	BaseModel `bson:",inline"`
+
+	OpnameUUID  string `bson:"opname_uuid" json:"opname_uuid"`
+	ProductUUID string `bson:"product_uuid" json:"product_uuid"`
+	SKU         string `bson:"sku" json:"sku"`
+	Barcode     string `bson:"barcode" json:"barcode"`
+	Name        string `bson:"name" json:"name"`
+	BaseUnit    string `bson:"base_unit" json:"base_unit"`
+
+	Cost        float64 `bson:"cost" json:"cost"`
+	SnapshotQty int64   `bson:"snapshot_qty" json:"snapshot_qty"`
+
+	// hasil hitung (bisa dari beberapa counter, dijumlah)
+	Counted       bool               `bson:"counted" json:"counted"`
+	CountedQty    int64              `bson:"counted_qty" json:"counted_qty"`
+	LastCountedAt time.Time          `bson:"last_counted_at" json:"last_counted_at"`
+	Counts        []StockOpnameCount `bson:"counts" json:"counts"`
+
+	// dihitung saat variance / approve:
+	// expected = snapshot + movement (selain opname ini) antara snapshot dan hitungan terakhir
+	MovementQty   int64   `bson:"movement_qty" json:"movement_qty"`
+	ExpectedQty   int64   `bson:"expected_qty" json:"expected_qty"`
+	VarianceQty   int64   `bson:"variance_qty" json:"variance_qty"`
+	VarianceValue float64 `bson:"variance_value" json:"variance_value"` // variance x cost
+	Posted        bool    `bson:"posted" json:"posted"`
+}
@@ -9,10 +9,20 @@ const (
 	StockTransferWaitingDriver    StockTransferStatus = "WAITING_DRIVER"
 	StockTransferInProgress       StockTransferStatus = "IN_PROGRESS"
 	StockTransferDone             StockTransferStatus = "DONE"
+
+	// status akhir tanpa barang sampai; stock yang sudah dipotong saat approve gudang dikembalikan
+	StockTransferRejected       StockTransferStatus = "REJECTED"        // ditolak gudang
+	StockTransferCancelled      StockTransferStatus = "CANCELLED"       // dibatalkan peminta / OWNER
+	StockTransferDriverDeclined StockTransferStatus = "DRIVER_DECLINED" // driver menolak job
+	StockTransferExpired        StockTransferStatus = "EXPIRED"         // tidak diproses sampai batas waktu
 )
 
+// StockTransferOpen: status yang masih bisa ditolak / dibatalkan / kedaluwarsa (barang belum jalan)
+var StockTransferOpen = []StockTransferStatus{StockTransferPendingWarehouse, StockTransferWaitingDriver}
+
 type StockTransferItem struct {
 	ProductUUID string        `bson:"product_uuid" json:"product_uuid" validate:"required"`
+	CatalogUUID string        `bson:"catalog_uuid" json:"catalog_uuid"`
 	SKU         string        `bson:"sku" json:"sku"`
 	Barcode     string        `bson:"barcode" json:"barcode"`
 	Name        string        `bson:"name" json:"name"`
@@ -23,6 +33,17 @@ type StockTransferItem struct {
 	Price       float64       `bson:"price" json:"price"`
 	Qty         int64         `bson:"qty" json:"qty" validate:"required,min=1"`
 	Image       string        `bson:"image" json:"image"`
+
+	// lot asal yang dikirim (FEFO saat approve gudang), diterima dengan lot_no + expiry yang sama
+	Lots []LotAllocation `bson:"lots,omitempty" json:"lots,omitempty"`
+
+	// hasil terima (base unit): received masuk stock tujuan, damaged + missing jadi discrepancy
+	ReceivedQty int64 `bson:"received_qty" json:"received_qty"`
+	DamagedQty  int64 `bson:"damaged_qty" json:"damaged_qty"`
+	MissingQty  int64 `bson:"missing_qty" json:"missing_qty"`
+
+	// Restored: stock item ini sudah dikembalikan ke branch asal (transfer batal setelah approve)
+	Restored bool `bson:"restored,omitempty" json:"restored,omitempty"`
 }
 
 type StockTransfer struct {
@@ -55,5 +76,40 @@ type StockTransfer struct {
 	AcceptedStr string `bson:"accepted_at_str" json:"accepted_at_str"`
 	ReceivedStr string `bson:"received_at_str" json:"received_at_str"`
 
+	// dokumen lama menulis *_notes (typo), dibaca lewat NormalizeNotes
+	LegacyApproverNote string `bson:"approver_notes,omitempty" json:"-"`
+	LegacyAccepterNote string `bson:"acceptor_notes,omitempty" json:"-"`
+	LegacyReceiverNote string `bson:"receiver_notes,omitempty" json:"-"`
+
+	// History: jejak transisi status (lihat stock_transfer_fsm.go)
+	History []StockTransferHistory `bson:"history" json:"history"`
+
+	// HasDiscrepancy: diterima sebagian, selisih dicatat di stock_transfer_discrepancies
+	HasDiscrepancy bool `bson:"has_discrepancy" json:"has_discrepancy"`
+	// ReshipOf: transfer kirim ulang untuk discrepancy ini
+	ReshipOf string `bson:"reship_of,omitempty" json:"reship_of,omitempty"`
+
+	// penutupan tanpa diterima (REJECTED / CANCELLED / DRIVER_DECLINED / EXPIRED)
+	ClosedBy    string `bson:"closed_by" json:"closed_by"`
+	CloseReason string `bson:"close_reason" json:"close_reason"`
+	ClosedAt    int64  `bson:"closed_at" json:"closed_at"`
+	ClosedStr   string `bson:"closed_at_str" json:"closed_at_str"`
+
+	// StockRestored: semua stock yang dipotong saat approve sudah kembali (worker retry kalau gagal di tengah)
+	StockRestored bool `bson:"stock_restored" json:"stock_restored"`
+
 	Driver bson.M `bson:"driver,omitempty" json:"driver,omitempty"`
 }
+
+// NormalizeNotes: isi approver/accepter/receiver note dari field lama kalau kosong
+func (t *StockTransfer) NormalizeNotes() {
+	if t.ApproverNote == "" {
+		t.ApproverNote = t.LegacyApproverNote
+	}
+	if t.AccepterNote == "" {
+		t.AccepterNote = t.LegacyAccepterNote
+	}
+	if t.ReceiverNote == "" {
+		t.ReceiverNote = t.LegacyReceiverNote
+	}
+}
@@ -0,0 +1,54 @@
+package dao
+
+type StockDiscrepancyStatus string
+
+const (
+	DiscrepancyOpen     StockDiscrepancyStatus = "OPEN" // menunggu keputusan gudang
+	DiscrepancyResolved StockDiscrepancyStatus = "RESOLVED"
+)
+
+type StockDiscrepancyResolution string
+
+const (
+	DiscrepancyReturn   StockDiscrepancyResolution = "RETURN"    // barang kembali ke gudang, stock asal bertambah lagi
+	DiscrepancyWriteOff StockDiscrepancyResolution = "WRITE_OFF" // dianggap hilang / rusak, tidak ada perubahan stock
+	DiscrepancyReship   StockDiscrepancyResolution = "RESHIP"    // kirim ulang lewat transfer baru
+)
+
+// StockTransferDiscrepancy: selisih in-transit 1 item transfer (rusak + hilang) saat diterima sebagian
+type StockTransferDiscrepancy struct {
+	BaseModel `bson:",inline"`
+
+	ClientUUID     string `bson:"client_uuid" json:"client_uuid"`
+	TransferUUID   string `bson:"transfer_uuid" json:"transfer_uuid"`
+	FromBranchUUID string `bson:"from_branch_uuid" json:"from_branch_uuid"`
+	ToBranchUUID   string `bson:"to_branch_uuid" json:"to_branch_uuid"`
+	DriverUUID     string `bson:"driver_uuid" json:"driver_uuid"`
+
+	ProductUUID string  `bson:"product_uuid" json:"product_uuid"` // produk di branch asal
+	CatalogUUID string  `bson:"catalog_uuid" json:"catalog_uuid"`
+	SKU         string  `bson:"sku" json:"sku"`
+	Name        string  `bson:"name" json:"name"`
+	BaseUnit    string  `bson:"base_unit" json:"base_unit"`
+	Cost        float64 `bson:"cost" json:"cost"` // cost per base unit saat dikirim
+
+	Shipped  int64 `bson:"shipped" json:"shipped"`
+	Received int64 `bson:"received" json:"received"`
+	Damaged  int64 `bson:"damaged" json:"damaged"`
+	Missing  int64 `bson:"missing" json:"missing"`
+	Qty      int64 `bson:"qty" json:"qty"` // damaged + missing
+
+	// lot asal porsi selisih (dikembalikan saat RETURN)
+	Lots []LotAllocation `bson:"lots,omitempty" json:"lots,omitempty"`
+
+	Note       string `bson:"note" json:"note"` // catatan penerima
+	ReportedBy string `bson:"reported_by" json:"reported_by"`
+
+	Status             StockDiscrepancyStatus     `bson:"status" json:"status"`
+	Resolution         StockDiscrepancyResolution `bson:"resolution" json:"resolution"`
+	ResolveNote        string                     `bson:"resolve_note" json:"resolve_note"`
+	ResolvedBy         string                     `bson:"resolved_by" json:"resolved_by"`
+	ResolvedAt         int64                      `bson:"resolved_at" json:"resolved_at"`
+	ResolvedAtStr      string                     `bson:"resolved_at_str" json:"resolved_at_str"`
+	ReshipTransferUUID string                     `bson:"reship_transfer_uuid" json:"reship_transfer_uuid"`
+}
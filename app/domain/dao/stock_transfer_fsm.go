@@ -0,0 +1,215 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// =====================================================
+// State machine stock transfer: semua perpindahan status lewat FireStockTransfer.
+// Tabel transisi hanya berisi status asal/tujuan, role dan guard. Repository menjalankan stock
+// (approve potong, receive masuk, event close mengembalikan) + simpan history, service mengirim notifikasi.
+//
+//	(baru) --REQUESTED--> PENDING_WAREHOUSE --WAREHOUSE_APPROVED--> WAITING_DRIVER
+//	WAITING_DRIVER --DRIVER_ACCEPTED--> IN_PROGRESS --RECEIVE_DONE--> DONE
+//	PENDING_WAREHOUSE / WAITING_DRIVER --REJECTED / CANCELLED / EXPIRED--> status akhir
+//	WAITING_DRIVER --DRIVER_DECLINED--> DRIVER_DECLINED
+//
+// =====================================================
+
+// StockTransferEvent: nama event = key notifikasi
+type StockTransferEvent string
+
+const (
+	StockTransferEventRequest       StockTransferEvent = "REQUESTED"
+	StockTransferEventApprove       StockTransferEvent = "WAREHOUSE_APPROVED"
+	StockTransferEventDriverAccept  StockTransferEvent = "DRIVER_ACCEPTED"
+	StockTransferEventReceive       StockTransferEvent = "RECEIVE_DONE"
+	StockTransferEventReject        StockTransferEvent = "REJECTED"
+	StockTransferEventCancel        StockTransferEvent = "CANCELLED"
+	StockTransferEventDriverDecline StockTransferEvent = "DRIVER_DECLINED"
+	StockTransferEventExpire        StockTransferEvent = "EXPIRED"
+)
+
+const StockTransferRoleSystem = "SYSTEM"
+
+// StockTransferActor: siapa yang menjalankan event (dari profile token, atau worker)
+type StockTransferActor struct {
+	UserUUID   string
+	Role       string
+	BranchUUID string
+}
+
+var StockTransferSystemActor = StockTransferActor{UserUUID: StockTransferRoleSystem, Role: StockTransferRoleSystem}
+
+func (a StockTransferActor) IsOwner() bool {
+	return a.Role == "OWNER" || a.Role == "SUPERADMIN"
+}
+
+// StockTransferHistory: satu baris per perpindahan status, disimpan di transfer.history
+type StockTransferHistory struct {
+	Event StockTransferEvent  `bson:"event" json:"event"`
+	From  StockTransferStatus `bson:"from" json:"from"`
+	To    StockTransferStatus `bson:"to" json:"to"`
+	By    string              `bson:"by" json:"by"`
+	Role  string              `bson:"role" json:"role"`
+	Note  string              `bson:"note" json:"note"`
+	At    int64               `bson:"at" json:"at"`
+	AtStr string              `bson:"at_str" json:"at_str"`
+}
+
+type StockTransferTransition struct {
+	Event StockTransferEvent
+	From  []StockTransferStatus
+	To    StockTransferStatus
+	// Roles: role yang boleh menjalankan, kosong = semua role (Guard yang menentukan)
+	Roles []string
+	Guard func(tr StockTransfer, a StockTransferActor) error
+}
+
+var (
+	ErrStockTransferState     = errors.New("invalid status")
+	ErrStockTransferForbidden = errors.New("forbidden")
+	ErrStockTransferInvalid   = errors.New("invalid stock transfer") // data request salah (item / produk)
+)
+
+var stockTransferOwnerRoles = []string{"OWNER", "SUPERADMIN"}
+
+var stockTransferTransitions = map[StockTransferEvent]StockTransferTransition{
+	StockTransferEventRequest: {
+		To:    StockTransferPendingWarehouse,
+		Guard: validTransferRequest,
+	},
+	StockTransferEventApprove: {
+		From:  []StockTransferStatus{StockTransferPendingWarehouse},
+		To:    StockTransferWaitingDriver,
+		Roles: []string{"GUDANG"},
+		Guard: driverAssigned,
+	},
+	StockTransferEventDriverAccept: {
+		From:  []StockTransferStatus{StockTransferWaitingDriver},
+		To:    StockTransferInProgress,
+		Roles: []string{"DRIVER"},
+		Guard: isAssignedDriver,
+	},
+	StockTransferEventReceive: {
+		From:  []StockTransferStatus{StockTransferInProgress},
+		To:    StockTransferDone,
+		Roles: []string{"KASIR", "ADMIN"},
+	},
+	StockTransferEventReject: {
+		From:  StockTransferOpen,
+		To:    StockTransferRejected,
+		Roles: append([]string{"GUDANG"}, stockTransferOwnerRoles...),
+	},
+	StockTransferEventCancel: {
+		From:  StockTransferOpen,
+		To:    StockTransferCancelled,
+		Guard: isRequesterOrOwner,
+	},
+	StockTransferEventDriverDecline: {
+		From:  []StockTransferStatus{StockTransferWaitingDriver},
+		To:    StockTransferDriverDeclined,
+		Roles: []string{"DRIVER"},
+		Guard: isAssignedDriver,
+	},
+	StockTransferEventExpire: {
+		From:  StockTransferOpen,
+		To:    StockTransferExpired,
+		Roles: []string{StockTransferRoleSystem},
+	},
+}
+
+func init() {
+	for ev, t := range stockTransferTransitions {
+		t.Event = ev
+		if t.From == nil {
+			t.From = []StockTransferStatus{""} // event pembuatan transfer
+		}
+		stockTransferTransitions[ev] = t
+	}
+}
+
+// StockTransferTransitionOf: definisi transisi untuk event (ok=false kalau event tidak dikenal)
+func StockTransferTransitionOf(ev StockTransferEvent) (StockTransferTransition, bool) {
+	t, ok := stockTransferTransitions[ev]
+	return t, ok
+}
+
+// FireStockTransfer: cek status asal, role dan guard. Tidak mengubah tr; caller simpan t.To + history.
+// Error dibungkus ErrStockTransferState (status / event), ErrStockTransferForbidden (role / actor)
+// atau ErrStockTransferInvalid (data transfer tidak lolos guard).
+func FireStockTransfer(tr StockTransfer, ev StockTransferEvent, a StockTransferActor) (StockTransferTransition, error) {
+	t, ok := stockTransferTransitions[ev]
+	if !ok {
+		return StockTransferTransition{}, fmt.Errorf("%w: unknown event %s", ErrStockTransferState, ev)
+	}
+	if !slices.Contains(t.From, tr.Status) {
+		return StockTransferTransition{}, fmt.Errorf("%w: cannot %s from %s", ErrStockTransferState, ev, statusLabel(tr.Status))
+	}
+	if len(t.Roles) > 0 && !slices.Contains(t.Roles, a.Role) {
+		return StockTransferTransition{}, fmt.Errorf("%w: role %q cannot %s", ErrStockTransferForbidden, a.Role, ev)
+	}
+	if t.Guard != nil {
+		if err := t.Guard(tr, a); err != nil {
+			return StockTransferTransition{}, err
+		}
+	}
+	return t, nil
+}
+
+// Record: baris history untuk transisi ini
+func (t StockTransferTransition) Record(from StockTransferStatus, a StockTransferActor, note string, at time.Time) StockTransferHistory {
+	return StockTransferHistory{
+		Event: t.Event,
+		From:  from,
+		To:    t.To,
+		By:    a.UserUUID,
+		Role:  a.Role,
+		Note:  note,
+		At:    at.Unix(),
+		AtStr: at.Format(time.RFC3339),
+	}
+}
+
+func statusLabel(s StockTransferStatus) string {
+	if s == "" {
+		return "-"
+	}
+	return string(s)
+}
+
+// ---- guard ----
+
+func validTransferRequest(tr StockTransfer, _ StockTransferActor) error {
+	if tr.FromBranchUUID == "" || tr.ToBranchUUID == "" || tr.FromBranchUUID == tr.ToBranchUUID {
+		return fmt.Errorf("%w: from_branch_uuid and to_branch_uuid must differ", ErrStockTransferInvalid)
+	}
+	if len(tr.Items) == 0 {
+		return fmt.Errorf("%w: items required", ErrStockTransferInvalid)
+	}
+	return nil
+}
+
+func driverAssigned(tr StockTransfer, _ StockTransferActor) error {
+	if tr.DriverUUID == "" {
+		return fmt.Errorf("%w: driver_uuid required", ErrStockTransferInvalid)
+	}
+	return nil
+}
+
+func isAssignedDriver(tr StockTransfer, a StockTransferActor) error {
+	if tr.DriverUUID == "" || tr.DriverUUID != a.UserUUID {
+		return fmt.Errorf("%w: driver not assigned", ErrStockTransferForbidden)
+	}
+	return nil
+}
+
+func isRequesterOrOwner(tr StockTransfer, a StockTransferActor) error {
+	if a.IsOwner() || (a.UserUUID != "" && tr.RequestedBy == a.UserUUID) {
+		return nil
+	}
+	return fmt.Errorf("%w: only requester or OWNER can cancel", ErrStockTransferForbidden)
+}
@@ -0,0 +1,202 @@
+package dao
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var allTransferStatuses = []StockTransferStatus{
+	"",
+	StockTransferPendingWarehouse,
+	StockTransferWaitingDriver,
+	StockTransferInProgress,
+	StockTransferDone,
+	StockTransferRejected,
+	StockTransferCancelled,
+	StockTransferDriverDeclined,
+	StockTransferExpired,
+}
+
+var allTransferEvents = []StockTransferEvent{
+	StockTransferEventRequest,
+	StockTransferEventApprove,
+	StockTransferEventDriverAccept,
+	StockTransferEventReceive,
+	StockTransferEventReject,
+	StockTransferEventCancel,
+	StockTransferEventDriverDecline,
+	StockTransferEventExpire,
+}
+
+// edge yang valid: status asal -> event -> status tujuan. Kombinasi lain harus ErrStockTransferState.
+var transferEdges = map[StockTransferStatus]map[StockTransferEvent]StockTransferStatus{
+	"": {
+		StockTransferEventRequest: StockTransferPendingWarehouse,
+	},
+	StockTransferPendingWarehouse: {
+		StockTransferEventApprove: StockTransferWaitingDriver,
+		StockTransferEventReject:  StockTransferRejected,
+		StockTransferEventCancel:  StockTransferCancelled,
+		StockTransferEventExpire:  StockTransferExpired,
+	},
+	StockTransferWaitingDriver: {
+		StockTransferEventDriverAccept:  StockTransferInProgress,
+		StockTransferEventReject:        StockTransferRejected,
+		StockTransferEventCancel:        StockTransferCancelled,
+		StockTransferEventDriverDecline: StockTransferDriverDeclined,
+		StockTransferEventExpire:        StockTransferExpired,
+	},
+	StockTransferInProgress: {
+		StockTransferEventReceive: StockTransferDone,
+	},
+}
+
+const (
+	testRequester = "user-requester"
+	testDriver    = "user-driver"
+)
+
+func testTransfer(status StockTransferStatus) StockTransfer {
+	return StockTransfer{
+		FromBranchUUID: "branch-gudang",
+		ToBranchUUID:   "branch-toko",
+		DriverUUID:     testDriver,
+		RequestedBy:    testRequester,
+		Status:         status,
+		Items:          []StockTransferItem{{ProductUUID: "p1", Qty: 1}},
+	}
+}
+
+// allowedActor: actor yang lolos role + guard untuk event
+func allowedActor(ev StockTransferEvent) StockTransferActor {
+	switch ev {
+	case StockTransferEventRequest, StockTransferEventCancel:
+		return StockTransferActor{UserUUID: testRequester, Role: "KASIR"}
+	case StockTransferEventApprove, StockTransferEventReject:
+		return StockTransferActor{UserUUID: "user-gudang", Role: "GUDANG"}
+	case StockTransferEventDriverAccept, StockTransferEventDriverDecline:
+		return StockTransferActor{UserUUID: testDriver, Role: "DRIVER"}
+	case StockTransferEventReceive:
+		return StockTransferActor{UserUUID: "user-kasir", Role: "KASIR"}
+	case StockTransferEventExpire:
+		return StockTransferSystemActor
+	}
+	return StockTransferActor{}
+}
+
+func TestFireStockTransferEdges(t *testing.T) {
+	for _, from := range allTransferStatuses {
+		for _, ev := range allTransferEvents {
+			want, ok := transferEdges[from][ev]
+			tr, err := FireStockTransfer(testTransfer(from), ev, allowedActor(ev))
+			if !ok {
+				if !errors.Is(err, ErrStockTransferState) {
+					t.Errorf("%q --%s--> want ErrStockTransferState, got %v", from, ev, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Errorf("%q --%s--> want %s, got error %v", from, ev, want, err)
+				continue
+			}
+			if tr.To != want || tr.Event != ev {
+				t.Errorf("%q --%s--> want %s, got %s (event %s)", from, ev, want, tr.To, tr.Event)
+			}
+		}
+	}
+}
+
+func TestFireStockTransferUnknownEvent(t *testing.T) {
+	_, err := FireStockTransfer(testTransfer(StockTransferPendingWarehouse), "TELEPORT", StockTransferSystemActor)
+	if !errors.Is(err, ErrStockTransferState) {
+		t.Fatalf("want ErrStockTransferState, got %v", err)
+	}
+}
+
+func TestFireStockTransferRoles(t *testing.T) {
+	cases := []struct {
+		name  string
+		from  StockTransferStatus
+		ev    StockTransferEvent
+		actor StockTransferActor
+		want  error
+	}{
+		{"approve by kasir", StockTransferPendingWarehouse, StockTransferEventApprove, StockTransferActor{UserUUID: "u", Role: "KASIR"}, ErrStockTransferForbidden},
+		{"approve by owner", StockTransferPendingWarehouse, StockTransferEventApprove, StockTransferActor{UserUUID: "u", Role: "OWNER"}, ErrStockTransferForbidden},
+		{"accept by gudang", StockTransferWaitingDriver, StockTransferEventDriverAccept, StockTransferActor{UserUUID: testDriver, Role: "GUDANG"}, ErrStockTransferForbidden},
+		{"accept by other driver", StockTransferWaitingDriver, StockTransferEventDriverAccept, StockTransferActor{UserUUID: "other", Role: "DRIVER"}, ErrStockTransferForbidden},
+		{"receive by admin", StockTransferInProgress, StockTransferEventReceive, StockTransferActor{UserUUID: "u", Role: "ADMIN"}, nil},
+		{"receive by driver", StockTransferInProgress, StockTransferEventReceive, StockTransferActor{UserUUID: testDriver, Role: "DRIVER"}, ErrStockTransferForbidden},
+		{"reject by owner", StockTransferWaitingDriver, StockTransferEventReject, StockTransferActor{UserUUID: "u", Role: "OWNER"}, nil},
+		{"reject by superadmin", StockTransferPendingWarehouse, StockTransferEventReject, StockTransferActor{UserUUID: "u", Role: "SUPERADMIN"}, nil},
+		{"reject by kasir", StockTransferPendingWarehouse, StockTransferEventReject, StockTransferActor{UserUUID: testRequester, Role: "KASIR"}, ErrStockTransferForbidden},
+		{"cancel by owner", StockTransferWaitingDriver, StockTransferEventCancel, StockTransferActor{UserUUID: "u", Role: "OWNER"}, nil},
+		{"cancel by other user", StockTransferPendingWarehouse, StockTransferEventCancel, StockTransferActor{UserUUID: "other", Role: "GUDANG"}, ErrStockTransferForbidden},
+		{"decline by other driver", StockTransferWaitingDriver, StockTransferEventDriverDecline, StockTransferActor{UserUUID: "other", Role: "DRIVER"}, ErrStockTransferForbidden},
+		{"decline by owner", StockTransferWaitingDriver, StockTransferEventDriverDecline, StockTransferActor{UserUUID: testDriver, Role: "OWNER"}, ErrStockTransferForbidden},
+		{"expire by owner", StockTransferPendingWarehouse, StockTransferEventExpire, StockTransferActor{UserUUID: "u", Role: "OWNER"}, ErrStockTransferForbidden},
+	}
+	for _, c := range cases {
+		_, err := FireStockTransfer(testTransfer(c.from), c.ev, c.actor)
+		if c.want == nil && err != nil {
+			t.Errorf("%s: want ok, got %v", c.name, err)
+		}
+		if c.want != nil && !errors.Is(err, c.want) {
+			t.Errorf("%s: want %v, got %v", c.name, c.want, err)
+		}
+	}
+}
+
+func TestFireStockTransferGuards(t *testing.T) {
+	same := testTransfer("")
+	same.ToBranchUUID = same.FromBranchUUID
+	if _, err := FireStockTransfer(same, StockTransferEventRequest, allowedActor(StockTransferEventRequest)); !errors.Is(err, ErrStockTransferInvalid) {
+		t.Errorf("request same branch: want ErrStockTransferInvalid, got %v", err)
+	}
+
+	empty := testTransfer("")
+	empty.Items = nil
+	if _, err := FireStockTransfer(empty, StockTransferEventRequest, allowedActor(StockTransferEventRequest)); !errors.Is(err, ErrStockTransferInvalid) {
+		t.Errorf("request without items: want ErrStockTransferInvalid, got %v", err)
+	}
+
+	noDriver := testTransfer(StockTransferPendingWarehouse)
+	noDriver.DriverUUID = ""
+	if _, err := FireStockTransfer(noDriver, StockTransferEventApprove, allowedActor(StockTransferEventApprove)); !errors.Is(err, ErrStockTransferInvalid) {
+		t.Errorf("approve without driver: want ErrStockTransferInvalid, got %v", err)
+	}
+
+	noDriver.Status = StockTransferWaitingDriver
+	if _, err := FireStockTransfer(noDriver, StockTransferEventDriverAccept, StockTransferActor{Role: "DRIVER"}); !errors.Is(err, ErrStockTransferForbidden) {
+		t.Errorf("accept unassigned transfer with empty user: want ErrStockTransferForbidden, got %v", err)
+	}
+}
+
+func TestStockTransferRecord(t *testing.T) {
+	tr, err := FireStockTransfer(testTransfer(StockTransferWaitingDriver), StockTransferEventDriverDecline, allowedActor(StockTransferEventDriverDecline))
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	h := tr.Record(StockTransferWaitingDriver, allowedActor(StockTransferEventDriverDecline), "ban bocor", at)
+	if h.Event != StockTransferEventDriverDecline || h.From != StockTransferWaitingDriver || h.To != StockTransferDriverDeclined {
+		t.Errorf("unexpected transition in history: %+v", h)
+	}
+	if h.By != testDriver || h.Role != "DRIVER" || h.Note != "ban bocor" || h.At != at.Unix() || h.AtStr != "2026-01-02T03:04:05Z" {
+		t.Errorf("unexpected history fields: %+v", h)
+	}
+}
+
+func TestStockTransferNormalizeNotes(t *testing.T) {
+	tr := StockTransfer{
+		ApproverNote:       "baru",
+		LegacyApproverNote: "lama",
+		LegacyAccepterNote: "jalan",
+		LegacyReceiverNote: "diterima",
+	}
+	tr.NormalizeNotes()
+	if tr.ApproverNote != "baru" || tr.AccepterNote != "jalan" || tr.ReceiverNote != "diterima" {
+		t.Errorf("unexpected notes: %q %q %q", tr.ApproverNote, tr.AccepterNote, tr.ReceiverNote)
+	}
+}
@@ -0,0 +1,42 @@
+package dao
+
+type Supplier struct {
+	BaseModel `bson:",inline"`
+
+	ClientUUID  string `bson:"client_uuid" json:"client_uuid"`
+	Code        string `bson:"code" json:"code"`
+	Name        string `bson:"name" json:"name" validate:"required"`
+	ContactName string `bson:"contact_name" json:"contact_name"`
+	Phone       string `bson:"phone" json:"phone"`
+	Email       string `bson:"email" json:"email"`
+	Address     string `bson:"address" json:"address"`
+	TaxID       string `bson:"tax_id" json:"tax_id"` // NPWP
+
+	// termin pembayaran (hari), dicetak di PO
+	PaymentTermDays int `bson:"payment_term_days" json:"payment_term_days"`
+
+	Note      string `bson:"note" json:"note"`
+	IsActive  bool   `bson:"is_active" json:"is_active"`
+	CreatedBy string `bson:"created_by" json:"created_by"`
+}
+
+// SupplierPrice: histori harga beli per supplier per produk (append-only).
+// UnitCost per unit yang dibeli, CostPerBase sudah dibagi konversi.
+type SupplierPrice struct {
+	BaseModel `bson:",inline"`
+
+	ClientUUID   string `bson:"client_uuid" json:"client_uuid"`
+	SupplierUUID string `bson:"supplier_uuid" json:"supplier_uuid"`
+	ProductUUID  string `bson:"product_uuid" json:"product_uuid"`
+	BranchUUID   string `bson:"branch_uuid" json:"branch_uuid"`
+	SKU          string `bson:"sku" json:"sku"`
+	Name         string `bson:"name" json:"name"`
+
+	Unit        string  `bson:"unit" json:"unit"`
+	UnitCost    float64 `bson:"unit_cost" json:"unit_cost"`
+	CostPerBase float64 `bson:"cost_per_base" json:"cost_per_base"`
+
+	RefUUID   string `bson:"ref_uuid" json:"ref_uuid"` // goods receipt
+	RefNo     string `bson:"ref_no" json:"ref_no"`
+	CreatedBy string `bson:"created_by" json:"created_by"`
+}
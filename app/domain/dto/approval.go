@@ -0,0 +1,20 @@
+package dto
+
+// ApprovalInput: ikut di body void/checkout kalau aksi butuh approval supervisor.
+// Salah satu: PIN inline (approver_uuid + approver_pin), approval_uuid hasil request
+// yang sudah disetujui, atau request_approval=true untuk kirim request ke OWNER.
+type ApprovalInput struct {
+	ApprovalUUID    string `json:"approval_uuid"`
+	ApproverUUID    string `json:"approver_uuid"`
+	ApproverPIN     string `json:"approver_pin"`
+	RequestApproval bool   `json:"request_approval"`
+	RequestNote     string `json:"request_note"`
+}
+
+type ApprovalDecisionRequest struct {
+	Note string `json:"note"`
+}
+
+type SupervisorPinRequest struct {
+	PIN string `json:"pin"`
+}
@@ -0,0 +1,60 @@
+package dto
+
+// BarcodePrefixRequest: prefix EAN-13 internal client, 2-7 digit (disarankan 20-29 = in-store GS1)
+type BarcodePrefixRequest struct {
+	Prefix string `json:"prefix"`
+}
+
+// BarcodeAssignRequest: catalog_uuids kosong = semua produk catalog tanpa barcode (max limit)
+type BarcodeAssignRequest struct {
+	CatalogUUIDs []string `json:"catalog_uuids"`
+	Limit        int64    `json:"limit"`
+}
+
+type BarcodeAssignResult struct {
+	Prefix   string            `json:"prefix"`
+	Assigned []BarcodeAssigned `json:"assigned"`
+	Failed   []BarcodeAssigned `json:"failed"`
+}
+
+type BarcodeAssigned struct {
+	CatalogUUID string `json:"catalog_uuid"`
+	SKU         string `json:"sku"`
+	Name        string `json:"name"`
+	Barcode     string `json:"barcode"`
+	Error       string `json:"error,omitempty"`
+}
+
+// LabelLayout: ukuran mm, page_width 0 = roll (1 label per halaman)
+type LabelLayout struct {
+	PageWidth   float64 `json:"page_width"`
+	PageHeight  float64 `json:"page_height"`
+	Columns     int     `json:"columns"`
+	Rows        int     `json:"rows"`
+	LabelWidth  float64 `json:"label_width"`
+	LabelHeight float64 `json:"label_height"`
+	MarginTop   float64 `json:"margin_top"`
+	MarginLeft  float64 `json:"margin_left"`
+	GapX        float64 `json:"gap_x"`
+	GapY        float64 `json:"gap_y"`
+}
+
+// LabelPrintRequest: template preset (A4_3X8, A4_5X13, ROLL_40X30, ROLL_50X25) atau layout custom.
+// Harga dari produk branch; unit diisi => harga unit tsb.
+type LabelPrintRequest struct {
+	BranchUUID string       `json:"branch_uuid"`
+	Template   string       `json:"template"`
+	Layout     *LabelLayout `json:"layout"`
+	Items      []LabelItem  `json:"items"`
+
+	ShowPrice     *bool `json:"show_price"` // default true
+	ShowStoreName bool  `json:"show_store_name"`
+	Border        bool  `json:"border"`
+	SkipSlots     int   `json:"skip_slots"` // A4: lewati slot stiker yang sudah terpakai
+}
+
+type LabelItem struct {
+	ProductUUID string `json:"product_uuid"`
+	Unit        string `json:"unit"`
+	Copies      int    `json:"copies"` // default 1
+}
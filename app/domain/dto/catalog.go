@@ -0,0 +1,52 @@
+package dto
+
+import "harjonan.id/user-service/app/domain/dao"
+
+// CatalogListRow: entry catalog + ringkasan inventory semua branch
+type CatalogListRow struct {
+	dao.CatalogProduct `bson:",inline"`
+
+	BranchCount int   `json:"branch_count"`
+	TotalStock  int64 `json:"total_stock"` // parent: total semua varian
+
+	Variants []dao.CatalogProduct `json:"variants,omitempty"`
+}
+
+// CatalogDetailResponse: master catalog + record inventory per branch
+type CatalogDetailResponse struct {
+	dao.CatalogProduct
+
+	Inventories []dao.Product        `json:"inventories"`
+	Variants    []dao.CatalogProduct `json:"variants,omitempty"`
+}
+
+// CatalogInventoryRequest: buka / ubah inventory produk catalog di 1 branch.
+// price_override null = ikut harga catalog. is_active null = aktif (branch baru) / tidak berubah.
+type CatalogInventoryRequest struct {
+	BranchUUID    string   `json:"branch_uuid"`
+	PriceOverride *float64 `json:"price_override"`
+	IsActive      *bool    `json:"is_active"`
+}
+
+type CatalogMigrateRequest struct {
+	DryRun bool `json:"dry_run"`
+}
+
+type CatalogMigrateResult struct {
+	DryRun         bool                     `json:"dry_run"`
+	Products       int                      `json:"products"`
+	AlreadyLinked  int                      `json:"already_linked"`
+	Linked         int                      `json:"linked"`
+	CatalogCreated int                      `json:"catalog_created"`
+	PriceOverrides int                      `json:"price_overrides"`
+	Conflicts      []CatalogMigrateConflict `json:"conflicts"`
+}
+
+// CatalogMigrateConflict: produk yang datanya beda dari master terpilih (linked tetap jalan, kecuali base_unit beda)
+type CatalogMigrateConflict struct {
+	SKU         string `json:"sku"`
+	BranchUUID  string `json:"branch_uuid"`
+	ProductUUID string `json:"product_uuid"`
+	Name        string `json:"name"`
+	Reason      string `json:"reason"`
+}
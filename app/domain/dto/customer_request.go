@@ -0,0 +1,6 @@
+package dto
+
+type CustomerLookupRequest struct {
+	Phone      string `json:"phone"`
+	MemberCode string `json:"member_code"`
+}
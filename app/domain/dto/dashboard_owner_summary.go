@@ -9,10 +9,13 @@ type OwnerDashboardSummary struct {
 	TransactionToday    int64   `json:"transaction_today"`
 	RevenueMonth        float64 `json:"revenue_month"`
 	LowStockSKU         int64   `json:"low_stock_sku"`
+
+	// omzet bulan ini per kategori root
+	RevenueByCategory []MarginRow `json:"revenue_by_category"`
 }
 
 type OwnerDashboardRequest struct {
-	LowStockThreshold int64 `json:"low_stock_threshold"`
+	LowStockThreshold int64 `json:"low_stock_threshold"` // fallback untuk produk tanpa min_stock
 }
 
 // =========================
@@ -23,6 +26,7 @@ type KasirDashboardSummary struct {
 	TransactionMonth      int64 `json:"transaction_month"`
 	ProductInBranch       int64 `json:"product_in_branch"`
 	StockRequestInProcess int64 `json:"stock_request_process"`
+	LowStockSKU           int64 `json:"low_stock_sku"`
 }
 
 // =========================
@@ -34,6 +38,7 @@ type GudangDashboardSummary struct {
 	StockRequestInDriver   int64 `json:"stock_request_in_driver"`
 	DriverAvailable        int64 `json:"driver_available"`
 	ProductSentToBranch    int64 `json:"product_sent_to_branch"` // sum qty items terkirim
+	LowStockSKU            int64 `json:"low_stock_sku"`
 }
 
 // =========================
@@ -3,6 +3,10 @@ package dto
 type POSCheckoutItem struct {
 	ProductUUID string `json:"product_uuid"`
 	Qty         int64  `json:"qty"`
+	Unit        string `json:"unit"` // optional: nama unit (box, karton). Kosong = base unit
+
+	// label timbangan (optional): qty & harga diambil dari barcode, field qty/unit diabaikan
+	ScaleBarcode string `json:"scale_barcode"`
 }
 
 type POSCheckoutRequest struct {
@@ -12,7 +16,19 @@ type POSCheckoutRequest struct {
 	Discount float64 `json:"discount"`
 	Paid     float64 `json:"paid"`
 
-	PaymentMethod string `json:"payment_method"` // CASH / TRANSFER / QRIS
+	PaymentMethod string `json:"payment_method"` // CASH / TRANSFER / QRIS / CREDIT
 	CreatedBy     string `json:"created_by"`
 	Note          string `json:"note"`
+
+	// customer (optional): cukup salah satu
+	CustomerUUID  string `json:"customer_uuid"`
+	CustomerPhone string `json:"customer_phone"`
+	MemberCode    string `json:"member_code"`
+
+	// tukar poin (optional)
+	RedeemPoints int64  `json:"redeem_points"`
+	RedeemAs     string `json:"redeem_as"` // DISCOUNT (default) / TENDER
+
+	// approval supervisor kalau diskon melewati batas policy
+	ApprovalInput
 }
@@ -17,6 +17,11 @@ type POSHistoryItem struct {
 	VoidedBy  string `json:"voided_by"`
 	Note      string `json:"note"`
 
+	CustomerUUID   string `json:"customer_uuid,omitempty"`
+	CustomerName   string `json:"customer_name,omitempty"`
+	PointsEarned   int64  `json:"points_earned"`
+	PointsRedeemed int64  `json:"points_redeemed"`
+
 	// ✅ formatted time (dd-MM-YYYY hh:mm:ss)
 	TrxAt  string `json:"trx_at"`
 	VoidAt string `json:"void_at"`
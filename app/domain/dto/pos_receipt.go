@@ -0,0 +1,8 @@
+package dto
+
+// SendReceiptRequest: POST /pos/transactions/:uuid/send-receipt
+// email kosong => pakai email customer (customer_uuid atau customer transaksi)
+type SendReceiptRequest struct {
+	Email        string `json:"email"`
+	CustomerUUID string `json:"customer_uuid"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+import "harjonan.id/user-service/app/domain/dao"
+
+type POSScanRequest struct {
+	BranchUUID string `json:"branch_uuid"`
+	Barcode    string `json:"barcode"`
+	Unit       string `json:"unit"` // optional
+}
+
+// POSScanResult: product (field sama seperti sebelumnya) + unit jual yang sudah di-resolve
+type POSScanResult struct {
+	dao.Product
+
+	Unit       string  `json:"unit"`
+	Conversion float64 `json:"conversion"`
+	UnitPrice  float64 `json:"unit_price"`
+
+	// line siap checkout (label timbangan: qty & total dari barcode)
+	Qty          int64   `json:"qty"`
+	LineTotal    float64 `json:"line_total"`
+	ScaleBarcode string  `json:"scale_barcode,omitempty"`
+
+	// barcode varian / parent: semua varian parent di branch ini (kasir bisa ganti ukuran / rasa).
+	// Barcode parent => product kosong, kasir wajib pilih varian.
+	VariantGroup *VariantGroup `json:"variant_group,omitempty"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+// PriceChangeRequest: jadwalkan harga baru. product_uuid (record branch) atau catalog_uuid.
+// scope kosong: BRANCH kalau pakai product_uuid, CLIENT kalau catalog_uuid.
+// effective_from: "YYYY-MM-DD HH:MM" / "YYYY-MM-DD" (WIB) / RFC3339, kosong = segera setelah disetujui.
+type PriceChangeRequest struct {
+	CatalogUUID   string  `json:"catalog_uuid"`
+	ProductUUID   string  `json:"product_uuid"`
+	Scope         string  `json:"scope"`
+	BranchUUID    string  `json:"branch_uuid"`
+	NewPrice      float64 `json:"new_price"`
+	EffectiveFrom string  `json:"effective_from"`
+	Note          string  `json:"note"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+// ProductExportRequest: filter sama dengan /products/fetch (pagination diabaikan), format "xlsx" (default) / "csv"
+type ProductExportRequest struct {
+	FilterRequest
+	Format string `json:"format"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import "harjonan.id/user-service/app/domain/dao"
+
+// CatalogVariantRequest: kombinasi semua value atribut dibuat jadi varian (yang sudah ada dilewati).
+// branch_uuids (optional): varian baru langsung dibuka di branch tsb dengan stock 0.
+type CatalogVariantRequest struct {
+	Attributes  []dao.VariantAttribute `json:"attributes"`
+	BranchUUIDs []string               `json:"branch_uuids"`
+}
+
+// VariantGroup: 1 baris POS per parent (varian dikelompokkan); produk tanpa varian = grup berisi 1 produk
+type VariantGroup struct {
+	ParentUUID   string                 `json:"parent_uuid"` // kosong = produk tanpa varian
+	SKU          string                 `json:"sku"`
+	Name         string                 `json:"name"`
+	Image        string                 `json:"image"`
+	CategoryPath string                 `json:"category_path"`
+	BrandName    string                 `json:"brand_name"`
+	Attributes   []dao.VariantAttribute `json:"attributes,omitempty"`
+	PriceMin     float64                `json:"price_min"`
+	PriceMax     float64                `json:"price_max"`
+	TotalStock   int64                  `json:"total_stock"`
+	Variants     []dao.Product          `json:"variants"`
+}
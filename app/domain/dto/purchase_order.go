@@ -0,0 +1,41 @@
+package dto
+
+type PurchaseOrderRequest struct {
+	UUID         string                     `json:"uuid"` // isi untuk edit DRAFT
+	BranchUUID   string                     `json:"branch_uuid"`
+	SupplierUUID string                     `json:"supplier_uuid"`
+	ExpectedDate int64                      `json:"expected_date"`
+	Note         string                     `json:"note"`
+	Discount     float64                    `json:"discount"`
+	Tax          float64                    `json:"tax"`
+	Items        []PurchaseOrderItemRequest `json:"items"`
+}
+
+// PurchaseOrderItemRequest: unit kosong = base unit. unit_cost 0 = harga terakhir supplier / cost produk.
+type PurchaseOrderItemRequest struct {
+	ProductUUID string  `json:"product_uuid"`
+	Unit        string  `json:"unit"`
+	Qty         float64 `json:"qty"`
+	UnitCost    float64 `json:"unit_cost"`
+}
+
+type PurchaseOrderCancelRequest struct {
+	Reason string `json:"reason"`
+}
+
+// GoodsReceiptRequest: qty per produk dalam unit PO. unit_cost 0 = harga di PO.
+type GoodsReceiptRequest struct {
+	SupplierRef string                    `json:"supplier_ref"`
+	Note        string                    `json:"note"`
+	Items       []GoodsReceiptItemRequest `json:"items"`
+}
+
+type GoodsReceiptItemRequest struct {
+	ProductUUID string  `json:"product_uuid"`
+	Qty         float64 `json:"qty"`
+	UnitCost    float64 `json:"unit_cost"`
+
+	// produk track_lots: lot_no default nomor GRN, expiry_date "YYYY-MM-DD" (optional)
+	LotNo      string `json:"lot_no"`
+	ExpiryDate string `json:"expiry_date"`
+}
@@ -0,0 +1,35 @@
+package dto
+
+type ReceivablePayRequest struct {
+	Amount float64 `json:"amount"`
+	Method string  `json:"method"` // CASH / TRANSFER / QRIS
+	Note   string  `json:"note"`
+}
+
+type ReceivableAgingRequest struct {
+	BranchUUID   string `json:"branch_uuid"`
+	CustomerUUID string `json:"customer_uuid"`
+}
+
+type ReceivableAgingRow struct {
+	CustomerUUID string  `json:"customer_uuid"`
+	CustomerName string  `json:"customer_name"`
+	Bucket0To30  float64 `json:"bucket_0_30"`
+	Bucket31To60 float64 `json:"bucket_31_60"`
+	Bucket60Plus float64 `json:"bucket_60_plus"`
+	Total        float64 `json:"total"`
+}
+
+type ReceivableAgingResponse struct {
+	Bucket0To30  float64              `json:"bucket_0_30"`
+	Bucket31To60 float64              `json:"bucket_31_60"`
+	Bucket60Plus float64              `json:"bucket_60_plus"`
+	Total        float64              `json:"total"`
+	Customers    []ReceivableAgingRow `json:"customers"`
+}
+
+type ReceivableBranchOutstanding struct {
+	BranchUUID  string  `bson:"_id" json:"branch_uuid"`
+	Outstanding float64 `bson:"outstanding" json:"outstanding"`
+	Count       int64   `bson:"count" json:"count"`
+}
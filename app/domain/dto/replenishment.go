@@ -0,0 +1,34 @@
+package dto
+
+type StockLevelRequest struct {
+	Items []StockLevelItem `json:"items"`
+}
+
+type StockLevelItem struct {
+	ProductUUID string `json:"product_uuid"`
+	MinStock    int64  `json:"min_stock"`
+	MaxStock    int64  `json:"max_stock"`
+}
+
+// ReplenishmentGenerateRequest: velocity dari penjualan VelocityDays hari terakhir (default 28).
+// Produk tanpa min_stock: reorder point = velocity x LeadDays (default 7), target = + velocity x CoverDays (default 14).
+type ReplenishmentGenerateRequest struct {
+	BranchUUID   string `json:"branch_uuid"`
+	VelocityDays int    `json:"velocity_days"`
+	LeadDays     int    `json:"lead_days"`
+	CoverDays    int    `json:"cover_days"`
+}
+
+// ReplenishmentApproveRequest: lines optional untuk ubah qty (0 = dilewati) / supplier sebelum dokumen dibuat
+type ReplenishmentApproveRequest struct {
+	DriverUUID string                      `json:"driver_uuid"` // wajib kalau ada line TRANSFER
+	Note       string                      `json:"note"`
+	Lines      []ReplenishmentLineOverride `json:"lines"`
+}
+
+type ReplenishmentLineOverride struct {
+	ProductUUID  string `json:"product_uuid"`
+	Source       string `json:"source"`
+	Qty          *int64 `json:"qty"`
+	SupplierUUID string `json:"supplier_uuid"`
+}
@@ -0,0 +1,34 @@
+package dto
+
+// ReportRequest: filter umum laporan. Tanggal "YYYY-MM-DD" (WIB), default 30 hari terakhir.
+type ReportRequest struct {
+	BranchUUID string `json:"branch_uuid"`
+	DateFrom   string `json:"date_from"`
+	DateTo     string `json:"date_to"`
+	Limit      int64  `json:"limit"`
+	ParentUUID string `json:"parent_uuid"` // laporan kategori: roll-up ke anak kategori ini
+}
+
+// MarginRow: revenue (setelah diskon transaksi), COGS dari cost saat terjual
+type MarginRow struct {
+	Key   string `bson:"_id" json:"key"` // trx uuid / product uuid / branch uuid / category uuid
+	Label string `bson:"label" json:"label"`
+	SKU   string `bson:"sku,omitempty" json:"sku,omitempty"`
+
+	Transactions int64   `bson:"transactions" json:"transactions"`
+	QtyBase      int64   `bson:"qty_base" json:"qty_base"`
+	Revenue      float64 `bson:"revenue" json:"revenue"`
+	COGS         float64 `bson:"cogs" json:"cogs"`
+	GrossProfit  float64 `bson:"gross_profit" json:"gross_profit"`
+	MarginPct    float64 `bson:"margin_pct" json:"margin_pct"`
+
+	// item tanpa cost (trx sebelum costing aktif) => margin terlalu tinggi
+	MissingCostItems int64 `bson:"missing_cost_items" json:"missing_cost_items"`
+}
+
+type MarginReportResponse struct {
+	DateFrom string      `json:"date_from"`
+	DateTo   string      `json:"date_to"`
+	Total    MarginRow   `json:"total"`
+	Rows     []MarginRow `json:"rows"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+// StockAdjustmentRequest: delta per produk. Tanda delta harus sesuai reason
+// (FOUND positif, DAMAGED/EXPIRED/LOST/SAMPLE negatif).
+type StockAdjustmentRequest struct {
+	BranchUUID string                       `json:"branch_uuid"`
+	Reason     string                       `json:"reason"`
+	Note       string                       `json:"note"`
+	Items      []StockAdjustmentItemRequest `json:"items"`
+}
+
+type StockAdjustmentItemRequest struct {
+	ProductUUID string `json:"product_uuid"`
+	Delta       int64  `json:"delta"`
+
+	// FOUND pada produk track_lots: lot tujuan (default kode adjustment), expiry "YYYY-MM-DD" optional
+	LotNo      string `json:"lot_no"`
+	ExpiryDate string `json:"expiry_date"`
+}
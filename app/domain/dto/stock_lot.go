@@ -0,0 +1,16 @@
+package dto
+
+import "harjonan.id/user-service/app/domain/dao"
+
+// StockLotExpiringRequest: lot yang kedaluwarsa dalam Days hari ke depan (default 30), termasuk yang sudah lewat
+type StockLotExpiringRequest struct {
+	BranchUUID string `json:"branch_uuid"`
+	Days       int    `json:"days"`
+	Limit      int64  `json:"limit"`
+}
+
+type StockLotExpiringRow struct {
+	dao.StockLot
+	DaysLeft int  `json:"days_left"` // negatif = sudah lewat
+	Expired  bool `json:"expired"`
+}
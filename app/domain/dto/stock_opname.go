@@ -0,0 +1,41 @@
+package dto
+
+type StockOpnameStartRequest struct {
+	BranchUUID string `json:"branch_uuid"` // kosong = branch user login
+	Note       string `json:"note"`
+}
+
+// StockOpnameCountRequest: hasil scan counter. code = barcode / sku / product_uuid.
+// qty default 1 (1x scan), minus untuk koreksi.
+type StockOpnameCountRequest struct {
+	Code string `json:"code"`
+	Qty  int64  `json:"qty"`
+}
+
+type StockOpnameVarianceResponse struct {
+	Items         []StockOpnameVarianceRow `json:"items"`
+	Uncounted     []StockOpnameVarianceRow `json:"uncounted"`
+	TotalItems    int                      `json:"total_items"`
+	CountedItems  int                      `json:"counted_items"`
+	VarianceItems int                      `json:"variance_items"`
+	VarianceQty   int64                    `json:"variance_qty"`
+	VarianceValue float64                  `json:"variance_value"`
+	ShortageValue float64                  `json:"shortage_value"`
+	SurplusValue  float64                  `json:"surplus_value"`
+}
+
+type StockOpnameVarianceRow struct {
+	ItemUUID      string  `json:"item_uuid"`
+	ProductUUID   string  `json:"product_uuid"`
+	SKU           string  `json:"sku"`
+	Name          string  `json:"name"`
+	BaseUnit      string  `json:"base_unit"`
+	Cost          float64 `json:"cost"`
+	SnapshotQty   int64   `json:"snapshot_qty"`
+	MovementQty   int64   `json:"movement_qty"`
+	ExpectedQty   int64   `json:"expected_qty"`
+	CountedQty    int64   `json:"counted_qty"`
+	VarianceQty   int64   `json:"variance_qty"`
+	VarianceValue float64 `json:"variance_value"`
+	Posted        bool    `json:"posted"`
+}
@@ -5,7 +5,6 @@ type StockTransferCreateReq struct {
 	ToBranchUUID   string `json:"to_branch_uuid" validate:"required"`
 	DriverUUID     string `json:"driver_uuid"`
 	Notes          string `json:"notes"`
-	RequestedBy    string `json:"requested_by"`
 	Items          []struct {
 		ProductUUID string `json:"product_uuid" validate:"required"`
 		Qty         int64  `json:"qty" validate:"required,min=1"`
@@ -22,4 +21,56 @@ type StockTransferDriverAcceptReq struct {
 
 type StockTransferReceiveReq struct {
 	Notes string `json:"notes"`
+	// Items kosong / item tidak disebut = diterima utuh
+	Items []StockTransferReceiveItem `json:"items"`
+}
+
+// StockTransferReceiveItem: received + damaged + missing = qty kirim (missing kosong = sisanya)
+type StockTransferReceiveItem struct {
+	ProductUUID string `json:"product_uuid"`
+	Received    int64  `json:"received"`
+	Damaged     int64  `json:"damaged"`
+	Missing     *int64 `json:"missing"`
+	Note        string `json:"note"`
+}
+
+// StockTransferCloseReq: reject / cancel / driver decline, reason wajib
+type StockTransferCloseReq struct {
+	Reason string `json:"reason"`
+}
+
+// StockDiscrepancyResolveReq: resolution RETURN / WRITE_OFF / RESHIP.
+// RESHIP: driver_uuid kosong = driver transfer asal.
+type StockDiscrepancyResolveReq struct {
+	Resolution string `json:"resolution"`
+	Note       string `json:"note"`
+	DriverUUID string `json:"driver_uuid"`
+}
+
+// StockDiscrepancyReportRow: selisih transfer per driver + pasangan branch
+type StockDiscrepancyReportRow struct {
+	DriverUUID     string `bson:"driver_uuid" json:"driver_uuid"`
+	DriverName     string `bson:"-" json:"driver_name"`
+	FromBranchUUID string `bson:"from_branch_uuid" json:"from_branch_uuid"`
+	FromBranchName string `bson:"-" json:"from_branch_name"`
+	ToBranchUUID   string `bson:"to_branch_uuid" json:"to_branch_uuid"`
+	ToBranchName   string `bson:"-" json:"to_branch_name"`
+
+	Transfers int64   `bson:"transfers" json:"transfers"` // transfer dengan selisih
+	Items     int64   `bson:"items" json:"items"`
+	Damaged   int64   `bson:"damaged" json:"damaged"`
+	Missing   int64   `bson:"missing" json:"missing"`
+	Value     float64 `bson:"value" json:"value"` // (damaged + missing) x cost
+
+	Open     int64 `bson:"open" json:"open"`
+	Returned int64 `bson:"returned" json:"returned"` // qty
+	WriteOff int64 `bson:"write_off" json:"write_off"`
+	Reship   int64 `bson:"reship" json:"reship"`
+}
+
+type StockDiscrepancyReport struct {
+	DateFrom string                      `json:"date_from"`
+	DateTo   string                      `json:"date_to"`
+	Total    StockDiscrepancyReportRow   `json:"total"`
+	Rows     []StockDiscrepancyReportRow `json:"rows"`
 }
@@ -0,0 +1,78 @@
+package helpers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// IsNumeric: semua karakter digit 0-9
+func IsNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// EANCheckDigit: hitung check digit GS1 (mod 10) untuk body EAN-8/UPC-A/EAN-13/GTIN-14 (tanpa check digit).
+// Bobot dari kanan: 3,1,3,1,...
+func EANCheckDigit(body string) (int, error) {
+	if !IsNumeric(body) {
+		return 0, errors.New("barcode must be numeric")
+	}
+	sum := 0
+	weight := 3
+	for i := len(body) - 1; i >= 0; i-- {
+		sum += int(body[i]-'0') * weight
+		if weight == 3 {
+			weight = 1
+		} else {
+			weight = 3
+		}
+	}
+	return (10 - sum%10) % 10, nil
+}
+
+// IsEANLike: panjang 8/12/13/14 dan numeric (kandidat EAN/UPC yang wajib valid check digit)
+func IsEANLike(code string) bool {
+	switch len(code) {
+	case 8, 12, 13, 14:
+		return IsNumeric(code)
+	}
+	return false
+}
+
+// ValidEAN: cek check digit EAN-8 / UPC-A / EAN-13 / GTIN-14
+func ValidEAN(code string) bool {
+	if !IsEANLike(code) {
+		return false
+	}
+	cd, err := EANCheckDigit(code[:len(code)-1])
+	if err != nil {
+		return false
+	}
+	return int(code[len(code)-1]-'0') == cd
+}
+
+// BuildEAN13: kode internal = prefix client + nomor urut (zero pad) + check digit, total 13 digit
+func BuildEAN13(prefix string, seq int64) (string, error) {
+	if !IsNumeric(prefix) || len(prefix) < 2 || len(prefix) > 7 {
+		return "", errors.New("prefix must be 2-7 digits")
+	}
+	width := 12 - len(prefix)
+	body := strconv.FormatInt(seq, 10)
+	if seq <= 0 || len(body) > width {
+		return "", errors.New("barcode sequence exhausted for prefix " + prefix)
+	}
+	body = prefix + strings.Repeat("0", width-len(body)) + body
+	cd, err := EANCheckDigit(body)
+	if err != nil {
+		return "", err
+	}
+	return body + strconv.Itoa(cd), nil
+}
@@ -0,0 +1,97 @@
+package helpers
+
+import "testing"
+
+func TestEANCheckDigit(t *testing.T) {
+	cases := []struct {
+		body string
+		want int
+	}{
+		{"400638133393", 1},  // EAN-13 4006381333931
+		{"899100210123", 4},  // EAN-13 prefix Indonesia
+		{"7351353", 7},       // EAN-8 73513537
+		{"03600029145", 2},   // UPC-A 036000291452
+		{"1001234567890", 2}, // GTIN-14
+		{"000000000000", 0},
+	}
+	for _, c := range cases {
+		got, err := EANCheckDigit(c.body)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", c.body, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("%s: want %d, got %d", c.body, c.want, got)
+		}
+	}
+
+	for _, body := range []string{"", "12345a", "12 34"} {
+		if _, err := EANCheckDigit(body); err == nil {
+			t.Errorf("%q: want error for non numeric body", body)
+		}
+	}
+}
+
+func TestValidEAN(t *testing.T) {
+	cases := []struct {
+		code string
+		want bool
+	}{
+		{"4006381333931", true},
+		{"4006381333932", false}, // check digit salah
+		{"73513537", true},
+		{"036000291452", true},
+		{"10012345678902", true},
+		{"2012345012509", true},    // label timbangan
+		{"400638133393", false},    // EAN-13 tanpa check digit dibaca sebagai UPC-A, check digit salah
+		{"40063813339", false},     // 11 digit
+		{"400638133393A", false},   // bukan angka
+		{"400638133393100", false}, // 15 digit
+		{"", false},
+	}
+	for _, c := range cases {
+		if got := ValidEAN(c.code); got != c.want {
+			t.Errorf("%q: want %v, got %v", c.code, c.want, got)
+		}
+	}
+}
+
+func TestBuildEAN13(t *testing.T) {
+	cases := []struct {
+		prefix  string
+		seq     int64
+		want    string
+		wantErr bool
+	}{
+		{"899123", 1, "8991230000019", false},
+		{"899123", 999999, "8991239999994", false}, // nomor terakhir untuk prefix 6 digit
+		{"899123", 1000000, "", true},              // lewat lebar nomor urut (12 - 6 digit)
+		{"20", 42, "2000000000428", false},
+		{"1234567", 99999, "1234567999999", false},
+		{"1234567", 100000, "", true},
+		{"899123", 0, "", true},
+		{"899123", -1, "", true},
+		{"8", 1, "", true},        // prefix terlalu pendek
+		{"89912345", 1, "", true}, // prefix terlalu panjang
+		{"89A123", 1, "", true},   // prefix bukan angka
+	}
+	for _, c := range cases {
+		got, err := BuildEAN13(c.prefix, c.seq)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("%s/%d: want error, got %s", c.prefix, c.seq, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s/%d: unexpected error %v", c.prefix, c.seq, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("%s/%d: want %s, got %s", c.prefix, c.seq, c.want, got)
+		}
+		if len(got) != 13 || !ValidEAN(got) {
+			t.Errorf("%s/%d: %s is not a valid EAN-13", c.prefix, c.seq, got)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package helpers
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FormatIDR: 1234567 -> "1.234.567" (tanpa Rp, dibulatkan ke rupiah)
+func FormatIDR(v float64) string {
+	neg := v < 0
+	if neg {
+		v = -v
+	}
+	s := strconv.FormatInt(int64(v+0.5), 10)
+	n := len(s)
+	var b strings.Builder
+	if neg {
+		b.WriteString("-")
+	}
+	if n <= 3 {
+		b.WriteString(s)
+		return b.String()
+	}
+	pre := n % 3
+	if pre == 0 {
+		pre = 3
+	}
+	b.WriteString(s[:pre])
+	for i := pre; i < n; i += 3 {
+		b.WriteString(".")
+		b.WriteString(s[i : i+3])
+	}
+	return b.String()
+}
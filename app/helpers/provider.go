@@ -1,6 +1,10 @@
 package helpers
 
-import "os"
+import (
+	"os"
+	"strings"
+	"time"
+)
 
 func ProvideDBName() string {
 	dbName := os.Getenv("DB_NAME")
@@ -9,3 +13,22 @@ func ProvideDBName() string {
 	}
 	return dbName
 }
+
+// ProvideTransferExpiry: stock transfer PENDING_WAREHOUSE / WAITING_DRIVER yang tidak diproses selama ini => EXPIRED
+func ProvideTransferExpiry() time.Duration {
+	if v := os.Getenv("STOCK_TRANSFER_EXPIRY"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+	}
+	// default: 3 days
+	return 72 * time.Hour
+}
+
+// ProvideAppURL: base URL frontend, dipakai link / QR di dokumen (mis. surat jalan -> layar terima transfer)
+func ProvideAppURL() string {
+	if v := strings.TrimRight(strings.TrimSpace(os.Getenv("APP_URL")), "/"); v != "" {
+		return v
+	}
+	return "http://localhost:5173"
+}
@@ -2,6 +2,7 @@ package helpers
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"strconv"
@@ -196,3 +197,48 @@ func SendPasswordResetEmail(toEmail, username, verificationLink string) error {
 	log.Println("Password reset email sent to:", toEmail)
 	return nil
 }
+
+type EmailAttachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// SendEmailWithAttachments: email html + lampiran (dipakai outbox, mis. struk digital)
+func SendEmailWithAttachments(toEmail, subject, htmlBody string, attachments []EmailAttachment) error {
+	host := os.Getenv("SMTP_HOST")
+	port, err := strconv.Atoi(os.Getenv("SMTP_PORT"))
+	if err != nil {
+		return fmt.Errorf("invalid SMTP_PORT: %v", err)
+	}
+
+	senderEmail := os.Getenv("SMTP_EMAIL")
+	senderPassword := os.Getenv("SMTP_PASSWORD")
+
+	m := mail.NewMessage()
+	m.SetHeader("From", senderEmail)
+	m.SetHeader("To", toEmail)
+	m.SetHeader("Subject", subject)
+	m.SetBody("text/html", htmlBody)
+
+	for _, a := range attachments {
+		data := a.Data
+		m.Attach(a.Name,
+			mail.SetCopyFunc(func(w io.Writer) error {
+				_, err := w.Write(data)
+				return err
+			}),
+			mail.SetHeader(map[string][]string{"Content-Type": {a.ContentType}}),
+		)
+	}
+
+	dialer := mail.NewDialer(host, port, senderEmail, senderPassword)
+	dialer.StartTLSPolicy = mail.MandatoryStartTLS
+
+	if err := dialer.DialAndSend(m); err != nil {
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+
+	log.Println("Email sent to:", toEmail, "-", subject)
+	return nil
+}
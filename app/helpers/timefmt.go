@@ -28,3 +28,64 @@ func FormatPOSUnix(sec int64) string {
 	}
 	return time.Unix(sec, 0).In(jakartaLoc).Format(POSDateTimeLayout)
 }
+
+const POSDateLayout = "02-01-2006"
+
+func FormatPOSDate(sec int64) string {
+	if sec <= 0 {
+		return ""
+	}
+	return time.Unix(sec, 0).In(jakartaLoc).Format(POSDateLayout)
+}
+
+// ParseDateRange: "YYYY-MM-DD" (WIB) inklusif => [from, to+1 hari). Kosong: default N hari terakhir s/d hari ini.
+func ParseDateRange(from, to string, defaultDays int) (time.Time, time.Time, error) {
+	now := time.Now().In(jakartaLoc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, jakartaLoc)
+
+	end := today.AddDate(0, 0, 1)
+	if to != "" {
+		t, err := time.ParseInLocation("2006-01-02", to, jakartaLoc)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		end = t.AddDate(0, 0, 1)
+	}
+	start := end.AddDate(0, 0, -defaultDays)
+	if from != "" {
+		t, err := time.ParseInLocation("2006-01-02", from, jakartaLoc)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		start = t
+	}
+	return start, end, nil
+}
+
+// ParseDateWIB: "YYYY-MM-DD" => unix awal hari WIB (mis. expiry date)
+func ParseDateWIB(s string) (int64, error) {
+	t, err := time.ParseInLocation("2006-01-02", s, jakartaLoc)
+	if err != nil {
+		return 0, err
+	}
+	return t.Unix(), nil
+}
+
+// FormatDateISO: unix => "YYYY-MM-DD" (WIB), pasangan ParseDateWIB
+func FormatDateISO(sec int64) string {
+	if sec <= 0 {
+		return ""
+	}
+	return time.Unix(sec, 0).In(jakartaLoc).Format("2006-01-02")
+}
+
+// ParseDateTimeWIB: "YYYY-MM-DD HH:MM" / "YYYY-MM-DD" (WIB, awal hari) / RFC3339 => unix
+func ParseDateTimeWIB(s string) (int64, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.Unix(), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04", s, jakartaLoc); err == nil {
+		return t.Unix(), nil
+	}
+	return ParseDateWIB(s)
+}
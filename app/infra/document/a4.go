@@ -0,0 +1,252 @@
+package document
+
+import (
+	"bytes"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/skip2/go-qrcode"
+)
+
+// ---------------------------------------------
+// Layout dokumen A4 (PO, surat jalan, dll)
+// ---------------------------------------------
+
+const a4Margin = 12.0
+
+type a4Doc struct {
+	pdf *fpdf.Fpdf
+	tr  func(string) string // utf-8 -> cp1252 (core font)
+}
+
+type a4Column struct {
+	Title string
+	Width float64 // mm
+	Align string  // L / C / R
+}
+
+func newA4Doc(title string) *a4Doc {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(a4Margin, a4Margin, a4Margin)
+	pdf.SetAutoPageBreak(true, a4Margin)
+	pdf.SetTitle(title, true)
+	pdf.AddPage()
+	return &a4Doc{pdf: pdf, tr: pdf.UnicodeTranslatorFromDescriptor("")}
+}
+
+func (d *a4Doc) contentWidth() float64 {
+	w, _ := d.pdf.GetPageSize()
+	return w - 2*a4Margin
+}
+
+// header: logo (optional) + nama toko kiri, judul dokumen kanan
+func (d *a4Doc) header(storeName string, storeLines []string, logo []byte, logoType string, title string) {
+	pdf := d.pdf
+	x := a4Margin
+	top := pdf.GetY()
+
+	if len(logo) > 0 {
+		info := pdf.RegisterImageOptionsReader("logo", fpdf.ImageOptions{ImageType: logoType}, bytes.NewReader(logo))
+		if pdf.Ok() && info != nil && info.Width() > 0 {
+			h := 16.0
+			w := h * info.Width() / info.Height()
+			pdf.ImageOptions("logo", x, top, w, h, false, fpdf.ImageOptions{ImageType: logoType}, 0, "")
+			x += w + 4
+		} else {
+			pdf.ClearError()
+		}
+	}
+
+	pdf.SetXY(x, top)
+	pdf.SetFont("Helvetica", "B", 13)
+	pdf.CellFormat(100, 6, d.tr(storeName), "", 2, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 8.5)
+	for _, ln := range storeLines {
+		if ln == "" {
+			continue
+		}
+		pdf.SetX(x)
+		pdf.CellFormat(100, 4, d.tr(ln), "", 2, "L", false, 0, "")
+	}
+	bottom := max(pdf.GetY(), top+16)
+
+	pdf.SetXY(a4Margin, top)
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(d.contentWidth(), 8, d.tr(title), "", 2, "R", false, 0, "")
+
+	pdf.SetY(bottom + 2)
+	pdf.SetLineWidth(0.4)
+	pdf.Line(a4Margin, pdf.GetY(), a4Margin+d.contentWidth(), pdf.GetY())
+	pdf.Ln(3)
+}
+
+// keyValues: baris "label : value" di kanan atas / blok info
+func (d *a4Doc) keyValues(x, y, labelW, valueW float64, rows [][2]string) float64 {
+	pdf := d.pdf
+	pdf.SetXY(x, y)
+	for _, r := range rows {
+		pdf.SetX(x)
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.CellFormat(labelW, 5, d.tr(r[0]), "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.CellFormat(valueW, 5, d.tr(r[1]), "", 1, "L", false, 0, "")
+	}
+	return pdf.GetY()
+}
+
+// box: blok alamat (judul + isi multi baris) dengan border
+func (d *a4Doc) box(x, y, w float64, title string, lines []string) float64 {
+	pdf := d.pdf
+	pdf.SetXY(x, y)
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetFillColor(235, 235, 235)
+	pdf.CellFormat(w, 6, d.tr(title), "1", 2, "L", true, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	startY := pdf.GetY()
+	for _, ln := range lines {
+		if ln == "" {
+			continue
+		}
+		pdf.SetX(x)
+		pdf.MultiCell(w, 4.5, d.tr(ln), "LR", "L", false)
+	}
+	pdf.SetX(x)
+	pdf.CellFormat(w, 1.5, "", "LRB", 2, "L", false, 0, "")
+	if pdf.GetY() < startY+10 {
+		pdf.Rect(x, startY, w, 10, "D")
+		pdf.SetY(startY + 10)
+	}
+	return pdf.GetY()
+}
+
+// table: header abu-abu + baris. Header diulang kalau pindah halaman.
+func (d *a4Doc) table(cols []a4Column, rows [][]string) {
+	pdf := d.pdf
+	_, pageH := pdf.GetPageSize()
+
+	drawHeader := func() {
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.SetFillColor(235, 235, 235)
+		for _, c := range cols {
+			pdf.CellFormat(c.Width, 7, d.tr(c.Title), "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	drawHeader()
+	pdf.SetFont("Helvetica", "", 9)
+	for _, row := range rows {
+		// tinggi baris ikut kolom paling panjang (nama barang bisa wrap)
+		lines := 1
+		for i, c := range cols {
+			if i < len(row) {
+				n := len(pdf.SplitLines([]byte(d.tr(row[i])), c.Width-2))
+				lines = max(lines, n)
+			}
+		}
+		h := float64(lines) * 5
+		if pdf.GetY()+h > pageH-a4Margin {
+			pdf.AddPage()
+			drawHeader()
+			pdf.SetFont("Helvetica", "", 9)
+		}
+
+		x, y := pdf.GetX(), pdf.GetY()
+		for i, c := range cols {
+			val := ""
+			if i < len(row) {
+				val = row[i]
+			}
+			pdf.Rect(x, y, c.Width, h, "D")
+			pdf.SetXY(x+1, y)
+			pdf.MultiCell(c.Width-2, 5, d.tr(val), "", c.Align, false)
+			x += c.Width
+		}
+		pdf.SetXY(a4Margin, y+h)
+	}
+}
+
+// totals: ringkasan kanan bawah tabel
+func (d *a4Doc) totals(rows [][2]string, boldLast bool) {
+	pdf := d.pdf
+	labelW, valueW := 40.0, 38.0
+	x := a4Margin + d.contentWidth() - labelW - valueW
+	for i, r := range rows {
+		style := ""
+		if boldLast && i == len(rows)-1 {
+			style = "B"
+		}
+		pdf.SetX(x)
+		pdf.SetFont("Helvetica", style, 9)
+		pdf.CellFormat(labelW, 6, d.tr(r[0]), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(valueW, 6, d.tr(r[1]), "1", 1, "R", false, 0, "")
+	}
+}
+
+func (d *a4Doc) paragraph(title, text string) {
+	if text == "" {
+		return
+	}
+	pdf := d.pdf
+	pdf.Ln(2)
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.CellFormat(d.contentWidth(), 5, d.tr(title), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.MultiCell(d.contentWidth(), 4.5, d.tr(text), "", "L", false)
+}
+
+// signatures: kotak tanda tangan sejajar (judul di atas, nama di bawah garis)
+func (d *a4Doc) signatures(titles []string, names []string) {
+	pdf := d.pdf
+	_, pageH := pdf.GetPageSize()
+	const boxH = 32.0
+	if pdf.GetY()+boxH+8 > pageH-a4Margin {
+		pdf.AddPage()
+	}
+	pdf.Ln(6)
+
+	n := float64(len(titles))
+	gap := 6.0
+	w := (d.contentWidth() - gap*(n-1)) / n
+	y := pdf.GetY()
+	for i, t := range titles {
+		x := a4Margin + float64(i)*(w+gap)
+		pdf.SetXY(x, y)
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.CellFormat(w, 6, d.tr(t), "1", 2, "C", false, 0, "")
+		pdf.Rect(x, y+6, w, boxH-6, "D")
+		pdf.SetFont("Helvetica", "", 8.5)
+		name := "(..............................)"
+		if i < len(names) && names[i] != "" {
+			name = "( " + names[i] + " )"
+		}
+		pdf.SetXY(x, y+boxH-6)
+		pdf.CellFormat(w, 5, d.tr(name), "", 0, "C", false, 0, "")
+	}
+	pdf.SetY(y + boxH + 2)
+}
+
+// qrCode: QR persegi ukuran size mm di (x, y), caption kecil di bawahnya
+func (d *a4Doc) qrCode(content string, x, y, size float64, caption string) error {
+	png, err := qrcode.Encode(content, qrcode.Medium, 256)
+	if err != nil {
+		return err
+	}
+	pdf := d.pdf
+	opt := fpdf.ImageOptions{ImageType: "PNG"}
+	pdf.RegisterImageOptionsReader("qr", opt, bytes.NewReader(png))
+	pdf.ImageOptions("qr", x, y, size, size, false, opt, 0, "")
+	if caption != "" {
+		pdf.SetXY(x-4, y+size)
+		pdf.SetFont("Helvetica", "", 7)
+		pdf.CellFormat(size+8, 3.5, d.tr(caption), "", 0, "C", false, 0, "")
+	}
+	return pdf.Error()
+}
+
+func (d *a4Doc) output() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := d.pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,157 @@
+package document
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/helpers"
+)
+
+type DeliveryNoteDoc struct {
+	Transfer    dao.StockTransfer
+	Branding    ReceiptBranding
+	From        dao.ClientBranch
+	To          dao.ClientBranch
+	DriverName  string // kosong = data driver tidak ditemukan, dicetak placeholder
+	DriverPhone string
+	ReceiveURL  string // isi QR: layar terima transfer di branch tujuan
+	IssuedBy    string
+}
+
+// DeliveryNoteNo: nomor surat jalan dari tanggal approve gudang + uuid transfer
+func DeliveryNoteNo(tr dao.StockTransfer) string {
+	date := tr.CreatedAt.Unix()
+	if tr.ApprovedAt > 0 {
+		date = tr.ApprovedAt
+	}
+	ref := strings.ToUpper(strings.ReplaceAll(tr.UUID, "-", ""))
+	if len(ref) > 8 {
+		ref = ref[:8]
+	}
+	return "SJ/" + strings.ReplaceAll(helpers.FormatDateISO(date), "-", "") + "/" + ref
+}
+
+// RenderDeliveryNotePDF: surat jalan A4 yang dibawa driver (transfer sudah di-approve gudang)
+func RenderDeliveryNotePDF(doc DeliveryNoteDoc) ([]byte, error) {
+	tr := doc.Transfer
+	b := doc.Branding
+	no := DeliveryNoteNo(tr)
+
+	d := newA4Doc(no)
+	d.header(b.StoreName, []string{b.Address, joinNonEmpty(" | ", phoneLine(b.Phone), b.Website)}, b.Logo, b.LogoImageType, "SURAT JALAN")
+
+	driver := doc.DriverName
+	if driver == "" {
+		driver = "(driver tidak ditemukan)"
+	}
+	info := [][2]string{
+		{"No. SJ", no},
+		{"No. Transfer", tr.UUID[:min(8, len(tr.UUID))]},
+		{"Tanggal", helpers.FormatPOSDate(tr.ApprovedAt)},
+		{"Driver", driver},
+	}
+	if doc.DriverPhone != "" {
+		info = append(info, [2]string{"Telp. Driver", doc.DriverPhone})
+	}
+
+	const qrSize = 30.0
+	y := d.pdf.GetY()
+	w := d.contentWidth()
+	boxW := (w - qrSize - 6 - 70) / 2
+	y1 := d.box(a4Margin, y, boxW, "Dari", []string{
+		doc.From.Name,
+		doc.From.Address,
+		phoneLine(doc.From.PhoneNumber),
+	})
+	y2 := d.box(a4Margin+boxW+3, y, boxW, "Tujuan", []string{
+		doc.To.Name,
+		doc.To.Address,
+		phoneLine(doc.To.PhoneNumber),
+	})
+	y3 := d.keyValues(a4Margin+2*boxW+6, y, 24, 70-24-3, info)
+	qrY := y
+	if doc.ReceiveURL != "" {
+		if err := d.qrCode(doc.ReceiveURL, a4Margin+w-qrSize, y, qrSize, "Scan untuk terima"); err != nil {
+			return nil, err
+		}
+		qrY = y + qrSize + 4
+	}
+	d.pdf.SetXY(a4Margin, max(y1, y2, y3, qrY)+4)
+
+	cols := []a4Column{
+		{Title: "No", Width: 9, Align: "C"},
+		{Title: "SKU", Width: 26, Align: "L"},
+		{Title: "Nama Barang", Width: w - 9 - 26 - 16 - 16 - 30 - 36 - 12, Align: "L"},
+		{Title: "Qty", Width: 16, Align: "R"},
+		{Title: "Unit", Width: 16, Align: "C"},
+		{Title: "Kemasan", Width: 30, Align: "L"},
+		{Title: "Lot / ED", Width: 36, Align: "L"},
+		{Title: "Cek", Width: 12, Align: "C"},
+	}
+	rows := make([][]string, 0, len(tr.Items))
+	var total int64
+	for i, it := range tr.Items {
+		total += it.Qty
+		rows = append(rows, []string{
+			strconv.Itoa(i + 1),
+			it.SKU,
+			it.Name,
+			strconv.FormatInt(it.Qty, 10),
+			it.BaseUnit,
+			packUnits(it.Qty, it.BaseUnit, it.Units),
+			lotLines(it.Lots),
+			"",
+		})
+	}
+	d.table(cols, rows)
+
+	d.pdf.Ln(2)
+	d.totals([][2]string{
+		{"Jumlah item", strconv.Itoa(len(tr.Items))},
+		{"Total qty", strconv.FormatInt(total, 10)},
+	}, false)
+
+	d.paragraph("Catatan", joinNonEmpty("\n", tr.RequesterNote, tr.ApproverNote))
+	d.paragraph("Perhatian", "Barang rusak / kurang dicatat saat terima di aplikasi (scan QR), jangan dicoret di surat jalan.")
+	d.signatures([]string{"Gudang (Pengirim)", "Driver", "Penerima"}, []string{doc.IssuedBy, doc.DriverName, ""})
+
+	return d.output()
+}
+
+// packUnits: qty base unit dipecah ke kemasan terbesar, mis. 27 PCS => "2 DUS + 3 PCS"
+func packUnits(qty int64, baseUnit string, units []dao.ProductUnit) string {
+	packs := make([]dao.ProductUnit, 0, len(units))
+	for _, u := range units {
+		if u.ConversionToBase > 1 && u.ConversionToBase == float64(int64(u.ConversionToBase)) && !strings.EqualFold(u.Name, baseUnit) {
+			packs = append(packs, u)
+		}
+	}
+	if len(packs) == 0 {
+		return ""
+	}
+	sort.Slice(packs, func(i, j int) bool { return packs[i].ConversionToBase > packs[j].ConversionToBase })
+
+	parts := []string{}
+	rest := qty
+	for _, u := range packs {
+		conv := int64(u.ConversionToBase)
+		if n := rest / conv; n > 0 {
+			parts = append(parts, strconv.FormatInt(n, 10)+" "+u.Name)
+			rest -= n * conv
+		}
+	}
+	if rest > 0 && len(parts) > 0 {
+		parts = append(parts, strconv.FormatInt(rest, 10)+" "+baseUnit)
+	}
+	return strings.Join(parts, " + ")
+}
+
+func lotLines(lots []dao.LotAllocation) string {
+	lines := make([]string, 0, len(lots))
+	for _, l := range lots {
+		lines = append(lines, joinNonEmpty(" ", l.LotNo, prefixed("ED ", helpers.FormatPOSDate(l.ExpiryDate)), "x"+strconv.FormatInt(l.Qty, 10)))
+	}
+	return strings.Join(lines, "\n")
+}
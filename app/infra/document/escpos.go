@@ -0,0 +1,72 @@
+package document
+
+import (
+	"bytes"
+	"strings"
+)
+
+// ESC/POS command bytes (Epson compatible)
+var (
+	escInit      = []byte{0x1B, 0x40}
+	escAlignL    = []byte{0x1B, 0x61, 0x00}
+	escAlignC    = []byte{0x1B, 0x61, 0x01}
+	escAlignR    = []byte{0x1B, 0x61, 0x02}
+	escBoldOn    = []byte{0x1B, 0x45, 0x01}
+	escBoldOff   = []byte{0x1B, 0x45, 0x00}
+	escSizeNorm  = []byte{0x1D, 0x21, 0x00}
+	escSizeDbl   = []byte{0x1D, 0x21, 0x11}
+	escFeedCut   = []byte{0x1D, 0x56, 0x42, 0x03} // feed 3 baris + partial cut
+	escOpenDrawr = []byte{0x1B, 0x70, 0x00, 0x19, 0xFA}
+)
+
+// escposColumns: jumlah karakter Font A per baris
+func escposColumns(paperMM int) int {
+	if paperMM >= 80 {
+		return 48
+	}
+	return 32
+}
+
+// encodeEscPos: text lines -> byte stream siap kirim ke printer (raw / WebUSB / bluetooth)
+func encodeEscPos(lines []textLine, cols int, openDrawer bool) []byte {
+	var b bytes.Buffer
+	b.Write(escInit)
+
+	for _, ln := range lines {
+		switch ln.Align {
+		case alignCenter:
+			b.Write(escAlignC)
+		case alignRight:
+			b.Write(escAlignR)
+		default:
+			b.Write(escAlignL)
+		}
+		if ln.Bold {
+			b.Write(escBoldOn)
+		}
+		if ln.Double {
+			b.Write(escSizeDbl)
+		}
+
+		if ln.Rule {
+			b.WriteString(strings.Repeat("-", cols))
+		} else {
+			b.WriteString(asciiOnly(ln.Text))
+		}
+		b.WriteByte('\n')
+
+		if ln.Double {
+			b.Write(escSizeNorm)
+		}
+		if ln.Bold {
+			b.Write(escBoldOff)
+		}
+	}
+
+	b.Write(escAlignL)
+	b.Write(escFeedCut)
+	if openDrawer {
+		b.Write(escOpenDrawr)
+	}
+	return b.Bytes()
+}
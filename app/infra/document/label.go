@@ -0,0 +1,244 @@
+package document
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+
+	"harjonan.id/user-service/app/helpers"
+)
+
+// ---------------------------------------------
+// Label rak / stiker harga (barcode + nama + harga)
+// ---------------------------------------------
+
+// LabelLayout: ukuran dalam mm. PageWidth 0 = roll (1 halaman = 1 label).
+type LabelLayout struct {
+	PageWidth   float64
+	PageHeight  float64
+	Columns     int
+	Rows        int
+	LabelWidth  float64
+	LabelHeight float64
+	MarginTop   float64
+	MarginLeft  float64
+	GapX        float64
+	GapY        float64
+}
+
+// LabelPresets: kertas stiker A4 (3x8, 5x13) dan roll printer label thermal
+var LabelPresets = map[string]LabelLayout{
+	"A4_3X8":     {PageWidth: 210, PageHeight: 297, Columns: 3, Rows: 8, LabelWidth: 70, LabelHeight: 37, MarginTop: 0.5},
+	"A4_5X13":    {PageWidth: 210, PageHeight: 297, Columns: 5, Rows: 13, LabelWidth: 38.1, LabelHeight: 21.2, MarginTop: 10.7, MarginLeft: 4.75, GapX: 2.5},
+	"ROLL_40X30": {LabelWidth: 40, LabelHeight: 30},
+	"ROLL_50X25": {LabelWidth: 50, LabelHeight: 25},
+}
+
+func (l LabelLayout) Validate() error {
+	if l.LabelWidth < 20 || l.LabelHeight < 12 {
+		return errors.New("label min 20x12 mm")
+	}
+	if l.PageWidth == 0 {
+		return nil
+	}
+	if l.Columns <= 0 || l.Rows <= 0 {
+		return errors.New("columns & rows required for sheet layout")
+	}
+	if l.MarginLeft+float64(l.Columns)*l.LabelWidth+float64(l.Columns-1)*l.GapX > l.PageWidth+0.01 ||
+		l.MarginTop+float64(l.Rows)*l.LabelHeight+float64(l.Rows-1)*l.GapY > l.PageHeight+0.01 {
+		return errors.New("labels do not fit the page")
+	}
+	return nil
+}
+
+type LabelItem struct {
+	Name    string
+	Barcode string
+	Price   float64
+	Unit    string // kosong = tidak ditampilkan
+}
+
+type LabelOptions struct {
+	StoreName string // kosong = tidak ditampilkan
+	ShowPrice bool
+	Border    bool // garis potong (kertas polos)
+	SkipSlots int  // sheet: lewati n slot pertama (sisa stiker yang sudah terpakai)
+}
+
+// RenderLabelsPDF: 1 item = 1 label (duplikasi copy dilakukan pemanggil)
+func RenderLabelsPDF(layout LabelLayout, items []LabelItem, opt LabelOptions) ([]byte, error) {
+	if err := layout.Validate(); err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, errors.New("no labels")
+	}
+
+	roll := layout.PageWidth == 0
+	size := fpdf.SizeType{Wd: layout.PageWidth, Ht: layout.PageHeight}
+	if roll {
+		size = fpdf.SizeType{Wd: layout.LabelWidth, Ht: layout.LabelHeight}
+	}
+	pdf := fpdf.NewCustom(&fpdf.InitType{OrientationStr: "P", UnitStr: "mm", Size: size})
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetTitle("Label", true)
+	d := &a4Doc{pdf: pdf, tr: pdf.UnicodeTranslatorFromDescriptor("")}
+
+	perPage := 1
+	if !roll {
+		perPage = layout.Columns * layout.Rows
+	}
+	slot := 0
+	if !roll {
+		slot = opt.SkipSlots % perPage
+	}
+	for i, it := range items {
+		if i == 0 || slot == 0 {
+			pdf.AddPage()
+		}
+		x, y := 0.0, 0.0
+		if !roll {
+			col, row := slot%layout.Columns, slot/layout.Columns
+			x = layout.MarginLeft + float64(col)*(layout.LabelWidth+layout.GapX)
+			y = layout.MarginTop + float64(row)*(layout.LabelHeight+layout.GapY)
+		}
+		d.label(x, y, layout.LabelWidth, layout.LabelHeight, it, opt)
+		slot = (slot + 1) % perPage
+	}
+	return d.output()
+}
+
+func (d *a4Doc) label(x, y, w, h float64, it LabelItem, opt LabelOptions) {
+	pdf := d.pdf
+	if opt.Border {
+		pdf.SetLineWidth(0.1)
+		pdf.SetDrawColor(180, 180, 180)
+		pdf.Rect(x, y, w, h, "D")
+		pdf.SetDrawColor(0, 0, 0)
+	}
+
+	pad := 1.5
+	small := h < 25
+	innerW := w - 2*pad
+	cy := y + pad
+
+	if opt.StoreName != "" && !small {
+		pdf.SetFont("Helvetica", "", 5.5)
+		pdf.SetXY(x+pad, cy)
+		pdf.CellFormat(innerW, 2.5, d.tr(opt.StoreName), "", 0, "C", false, 0, "")
+		cy += 2.8
+	}
+
+	// nama max 2 baris
+	nameSize, lineH := 7.5, 3.0
+	if small {
+		nameSize, lineH = 6.0, 2.5
+	}
+	pdf.SetFont("Helvetica", "B", nameSize)
+	lines := pdf.SplitText(d.tr(it.Name), innerW)
+	if len(lines) > 2 {
+		lines = lines[:2]
+		lines[1] = strings.TrimRight(lines[1], " ") + "..."
+	}
+	for _, ln := range lines {
+		pdf.SetXY(x+pad, cy)
+		pdf.CellFormat(innerW, lineH, ln, "", 0, "C", false, 0, "")
+		cy += lineH
+	}
+
+	if opt.ShowPrice {
+		priceSize, priceH := 11.0, 4.5
+		if small {
+			priceSize, priceH = 8.5, 3.5
+		}
+		price := "Rp " + helpers.FormatIDR(it.Price)
+		if it.Unit != "" {
+			price += " /" + it.Unit
+		}
+		pdf.SetFont("Helvetica", "B", priceSize)
+		pdf.SetXY(x+pad, cy+0.3)
+		pdf.CellFormat(innerW, priceH, d.tr(price), "", 0, "C", false, 0, "")
+		cy += priceH + 0.5
+	}
+
+	// barcode mengisi sisa tinggi, angka di bawahnya
+	textH := 2.5
+	barH := y + h - pad - textH - cy
+	if it.Barcode == "" || barH < 3 {
+		return
+	}
+	modules, ok := ean13Modules(it.Barcode)
+	if !ok {
+		// bukan EAN-13/UPC-A: tampilkan kodenya saja
+		pdf.SetFont("Courier", "B", 8)
+		pdf.SetXY(x+pad, cy+barH/2-2)
+		pdf.CellFormat(innerW, 4, d.tr(it.Barcode), "", 0, "C", false, 0, "")
+		return
+	}
+
+	mw := min(innerW/float64(len(modules)), 0.33)
+	bx := x + (w-mw*float64(len(modules)))/2
+	pdf.SetFillColor(0, 0, 0)
+	for i, m := range modules {
+		if m != '1' {
+			continue
+		}
+		hh := barH
+		if isEANGuard(i) {
+			hh += 1.2
+		}
+		pdf.Rect(bx+float64(i)*mw, cy, mw, hh, "F")
+	}
+	pdf.SetFont("Helvetica", "", 6)
+	pdf.SetXY(x+pad, cy+barH+0.2)
+	pdf.CellFormat(innerW, textH, ean13Text(it.Barcode), "", 0, "C", false, 0, "")
+}
+
+var (
+	eanL = [10]string{"0001101", "0011001", "0010011", "0111101", "0100011", "0110001", "0101111", "0111011", "0110111", "0001011"}
+	eanG = [10]string{"0100111", "0110011", "0011011", "0100001", "0011101", "0111001", "0000101", "0010001", "0001001", "0010111"}
+	eanR = [10]string{"1110010", "1100110", "1101100", "1000010", "1011100", "1001110", "1010000", "1000100", "1001000", "1110100"}
+	// parity 6 digit kiri ditentukan digit pertama
+	eanParity = [10]string{"LLLLLL", "LLGLGG", "LLGGLG", "LLGGGL", "LGLLGG", "LGGLLG", "LGGGLL", "LGLGLG", "LGLGGL", "LGGLGL"}
+)
+
+// ean13Modules: 95 modul (1 = bar). UPC-A 12 digit dianggap EAN-13 dengan awalan 0.
+func ean13Modules(code string) (string, bool) {
+	if len(code) == 12 {
+		code = "0" + code
+	}
+	if len(code) != 13 || !helpers.ValidEAN(code) {
+		return "", false
+	}
+	var b strings.Builder
+	b.WriteString("101")
+	parity := eanParity[code[0]-'0']
+	for i := 1; i <= 6; i++ {
+		digit := code[i] - '0'
+		if parity[i-1] == 'L' {
+			b.WriteString(eanL[digit])
+		} else {
+			b.WriteString(eanG[digit])
+		}
+	}
+	b.WriteString("01010")
+	for i := 7; i <= 12; i++ {
+		b.WriteString(eanR[code[i]-'0'])
+	}
+	b.WriteString("101")
+	return b.String(), true
+}
+
+func isEANGuard(i int) bool {
+	return i < 3 || (i >= 45 && i < 50) || i >= 92
+}
+
+// ean13Text: "8 991234 567890"
+func ean13Text(code string) string {
+	if len(code) == 12 {
+		code = "0" + code
+	}
+	return code[:1] + " " + code[1:7] + " " + code[7:]
+}
@@ -0,0 +1,130 @@
+package document
+
+import (
+	"fmt"
+	"strconv"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/helpers"
+)
+
+type PurchaseOrderDoc struct {
+	PO       dao.PurchaseOrder
+	Supplier dao.Supplier
+	Branding ReceiptBranding // toko pemesan
+	ShipTo   dao.ClientBranch
+	IssuedBy string
+}
+
+// RenderPurchaseOrderPDF: PO A4 untuk dikirim ke supplier
+func RenderPurchaseOrderPDF(doc PurchaseOrderDoc) ([]byte, error) {
+	po := doc.PO
+	b := doc.Branding
+
+	d := newA4Doc(po.PONo)
+	d.header(b.StoreName, []string{b.Address, joinNonEmpty(" | ", phoneLine(b.Phone), b.Website)}, b.Logo, b.LogoImageType, "PURCHASE ORDER")
+
+	orderDate := helpers.FormatPOSDate(po.CreatedAt.Unix())
+	if po.SentAt > 0 {
+		orderDate = helpers.FormatPOSDate(po.SentAt)
+	}
+	info := [][2]string{
+		{"No. PO", po.PONo},
+		{"Tanggal", orderDate},
+		{"Status", string(po.Status)},
+	}
+	if po.ExpectedDate > 0 {
+		info = append(info, [2]string{"Tgl. Kirim", helpers.FormatPOSDate(po.ExpectedDate)})
+	}
+	if doc.Supplier.PaymentTermDays > 0 {
+		info = append(info, [2]string{"Termin", strconv.Itoa(doc.Supplier.PaymentTermDays) + " hari"})
+	}
+
+	y := d.pdf.GetY()
+	w := d.contentWidth()
+	colW := (w - 6) / 2
+	s := doc.Supplier
+	y1 := d.box(a4Margin, y, colW*0.62, "Supplier", []string{
+		s.Name,
+		s.ContactName,
+		s.Address,
+		joinNonEmpty(" | ", phoneLine(s.Phone), s.Email),
+		prefixed("NPWP: ", s.TaxID),
+	})
+	y2 := d.box(a4Margin+colW*0.62+4, y, colW*0.62, "Kirim ke", []string{
+		doc.ShipTo.Name,
+		doc.ShipTo.Address,
+		phoneLine(doc.ShipTo.PhoneNumber),
+	})
+	y3 := d.keyValues(a4Margin+colW*1.24+10, y, 22, w-colW*1.24-10-22, info)
+	d.pdf.SetY(max(y1, y2, y3) + 4)
+
+	cols := []a4Column{
+		{Title: "No", Width: 9, Align: "C"},
+		{Title: "SKU", Width: 26, Align: "L"},
+		{Title: "Nama Barang", Width: w - 9 - 26 - 18 - 18 - 30 - 32, Align: "L"},
+		{Title: "Qty", Width: 18, Align: "R"},
+		{Title: "Unit", Width: 18, Align: "C"},
+		{Title: "Harga", Width: 30, Align: "R"},
+		{Title: "Jumlah", Width: 32, Align: "R"},
+	}
+	rows := make([][]string, 0, len(po.Items))
+	for i, it := range po.Items {
+		rows = append(rows, []string{
+			strconv.Itoa(i + 1),
+			it.SKU,
+			it.Name,
+			formatQty(it.Qty),
+			it.Unit,
+			helpers.FormatIDR(it.UnitCost),
+			helpers.FormatIDR(it.LineTotal),
+		})
+	}
+	d.table(cols, rows)
+
+	d.pdf.Ln(2)
+	tot := [][2]string{{"Subtotal", helpers.FormatIDR(po.SubTotal)}}
+	if po.Discount > 0 {
+		tot = append(tot, [2]string{"Diskon", "-" + helpers.FormatIDR(po.Discount)})
+	}
+	if po.Tax > 0 {
+		tot = append(tot, [2]string{"Pajak", helpers.FormatIDR(po.Tax)})
+	}
+	tot = append(tot, [2]string{"Total", "Rp " + helpers.FormatIDR(po.Total)})
+	d.totals(tot, true)
+
+	d.paragraph("Catatan", po.Note)
+	d.signatures([]string{"Dibuat oleh", "Disetujui", "Supplier"}, []string{doc.IssuedBy, "", s.Name})
+
+	return d.output()
+}
+
+func formatQty(q float64) string {
+	if q == float64(int64(q)) {
+		return strconv.FormatInt(int64(q), 10)
+	}
+	return fmt.Sprintf("%.2f", q)
+}
+
+func phoneLine(p string) string { return prefixed("Telp. ", p) }
+
+func prefixed(prefix, v string) string {
+	if v == "" {
+		return ""
+	}
+	return prefix + v
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	out := ""
+	for _, p := range parts {
+		if p == "" {
+			continue
+		}
+		if out != "" {
+			out += sep
+		}
+		out += p
+	}
+	return out
+}
@@ -0,0 +1,265 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/helpers"
+)
+
+// ReceiptBranding: identitas toko yang dicetak di header struk
+type ReceiptBranding struct {
+	StoreName  string
+	BranchName string
+	Address    string
+	Phone      string
+	Website    string
+
+	// logo (optional). Logo dipakai PDF (ImageType: PNG / JPG), LogoURL dipakai HTML
+	Logo          []byte
+	LogoImageType string
+	LogoURL       string
+
+	HeaderText string
+	FooterText string
+}
+
+type ReceiptOptions struct {
+	PaperWidthMM int  // 58 / 80
+	Copy         bool // reprint => "COPY"
+	ShowCustomer bool
+	ShowPoints   bool
+	OpenDrawer   bool // ESC/POS: kick cash drawer
+}
+
+type Receipt struct {
+	Trx      dao.POSTransaction
+	Branding ReceiptBranding
+	Options  ReceiptOptions
+}
+
+func (r Receipt) columns() int {
+	return escposColumns(r.Options.PaperWidthMM)
+}
+
+// RenderReceiptEscPos: byte stream ESC/POS untuk printer thermal 58/80mm
+func RenderReceiptEscPos(r Receipt) []byte {
+	cols := r.columns()
+	return encodeEscPos(receiptLines(r, cols), cols, r.Options.OpenDrawer && !r.Options.Copy)
+}
+
+// RenderReceiptPDF: PDF selebar kertas thermal (monospace, layout sama dengan ESC/POS)
+func RenderReceiptPDF(r Receipt) ([]byte, error) {
+	cols := r.columns()
+	lines := receiptLines(r, cols)
+
+	paper := float64(r.Options.PaperWidthMM)
+	if paper <= 0 {
+		paper = 58
+	}
+	const margin = 3.0
+	const ptToMM = 0.352778
+
+	// Courier: lebar karakter = 0.6 x font size
+	fontPt := (paper - 2*margin) / ptToMM / (float64(cols) * 0.6)
+	lineH := fontPt * 1.3 * ptToMM
+
+	logoW, logoH := 0.0, 0.0
+	pdf := fpdf.NewCustom(&fpdf.InitType{UnitStr: "mm", Size: fpdf.SizeType{Wd: paper, Ht: 100}})
+	if len(r.Branding.Logo) > 0 {
+		info := pdf.RegisterImageOptionsReader("logo", fpdf.ImageOptions{ImageType: r.Branding.LogoImageType}, bytes.NewReader(r.Branding.Logo))
+		if pdf.Ok() && info != nil && info.Width() > 0 {
+			logoW = min(paper*0.5, 30)
+			logoH = logoW * info.Height() / info.Width()
+		} else {
+			// logo rusak jangan gagalkan struk
+			pdf.ClearError()
+		}
+	}
+
+	height := margin*2 + logoH + 2
+	for _, ln := range lines {
+		if ln.Double {
+			height += lineH * 2
+		} else {
+			height += lineH
+		}
+	}
+
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddPageFormat("P", fpdf.SizeType{Wd: paper, Ht: height})
+
+	y := margin
+	if logoH > 0 {
+		pdf.ImageOptions("logo", (paper-logoW)/2, y, logoW, logoH, false, fpdf.ImageOptions{ImageType: r.Branding.LogoImageType}, 0, "")
+		y += logoH + 2
+	}
+
+	width := paper - 2*margin
+	for _, ln := range lines {
+		size := fontPt
+		h := lineH
+		if ln.Double {
+			size = fontPt * 2
+			h = lineH * 2
+		}
+		style := ""
+		if ln.Bold {
+			style = "B"
+		}
+		pdf.SetFont("Courier", style, size)
+		pdf.SetXY(margin, y)
+
+		if ln.Rule {
+			pdf.SetLineWidth(0.2)
+			pdf.SetDashPattern([]float64{0.8, 0.6}, 0)
+			pdf.Line(margin, y+h/2, paper-margin, y+h/2)
+			pdf.SetDashPattern([]float64{}, 0)
+		} else {
+			alignStr := "L"
+			switch ln.Align {
+			case alignCenter:
+				alignStr = "C"
+			case alignRight:
+				alignStr = "R"
+			}
+			pdf.CellFormat(width, h, asciiOnly(ln.Text), "", 0, alignStr, false, 0, "")
+		}
+		y += h
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// receiptLines: isi struk (dipakai ESC/POS & PDF)
+func receiptLines(r Receipt, cols int) []textLine {
+	trx := r.Trx
+	b := r.Branding
+	var out []textLine
+
+	add := func(text string, a align) { out = append(out, textLine{Text: text, Align: a}) }
+	addBold := func(text string, a align) { out = append(out, textLine{Text: text, Align: a, Bold: true}) }
+	addLR := func(left, right string) { add(leftRight(left, right, cols), alignLeft) }
+	rule := func() { out = append(out, textLine{Rule: true}) }
+	copyBanner := func() {
+		out = append(out, textLine{Text: "*** COPY ***", Align: alignCenter, Bold: true, Double: true})
+	}
+
+	if r.Options.Copy {
+		copyBanner()
+	}
+
+	// header toko
+	for _, s := range wrap(b.StoreName, cols/2) {
+		out = append(out, textLine{Text: s, Align: alignCenter, Bold: true, Double: true})
+	}
+	if b.BranchName != "" && !strings.EqualFold(b.BranchName, b.StoreName) {
+		for _, s := range wrap(b.BranchName, cols) {
+			addBold(s, alignCenter)
+		}
+	}
+	for _, s := range wrap(b.Address, cols) {
+		add(s, alignCenter)
+	}
+	if b.Phone != "" {
+		add("Telp. "+b.Phone, alignCenter)
+	}
+	if b.Website != "" {
+		add(b.Website, alignCenter)
+	}
+	for _, s := range wrap(b.HeaderText, cols) {
+		add(s, alignCenter)
+	}
+
+	rule()
+	addLR("No", trx.ReceiptNo)
+	addLR("Tanggal", helpers.FormatPOSDateTime(trx.CreatedAt))
+	if trx.CreatedBy != "" {
+		addLR("Kasir", trx.CreatedBy)
+	}
+	if r.Options.ShowCustomer && trx.CustomerName != "" {
+		addLR("Customer", trx.CustomerName)
+	}
+	rule()
+
+	// items
+	for _, it := range trx.Items {
+		for _, s := range wrap(it.Name, cols) {
+			add(s, alignLeft)
+		}
+		unit := it.Unit
+		if unit == "" {
+			unit = it.BaseUnit
+		}
+		qty := strconv.FormatInt(it.Qty, 10)
+		if unit != "" {
+			qty += " " + unit
+		}
+		addLR("  "+qty+" x "+helpers.FormatIDR(it.Price), helpers.FormatIDR(it.LineTotal))
+	}
+	rule()
+
+	addLR("Subtotal", helpers.FormatIDR(trx.SubTotal))
+	if trx.Discount > 0 {
+		addLR("Diskon", "-"+helpers.FormatIDR(trx.Discount))
+	}
+	if trx.PointsValue > 0 && trx.PointsRedeemAs == "DISCOUNT" {
+		addLR(fmt.Sprintf("Tukar %d poin", trx.PointsRedeemed), "-"+helpers.FormatIDR(trx.PointsValue))
+	}
+	out = append(out, textLine{Text: leftRight("TOTAL", helpers.FormatIDR(trx.Total), cols), Bold: true})
+
+	addLR("Bayar ("+trx.PaymentMethod+")", helpers.FormatIDR(trx.Paid))
+	if trx.PointsValue > 0 && trx.PointsRedeemAs == "TENDER" {
+		addLR(fmt.Sprintf("Poin (%d)", trx.PointsRedeemed), helpers.FormatIDR(trx.PointsValue))
+	}
+	if trx.CreditAmount > 0 {
+		addLR("Kasbon", helpers.FormatIDR(trx.CreditAmount))
+	}
+	addLR("Kembali", helpers.FormatIDR(trx.Change))
+
+	if r.Options.ShowPoints && trx.CustomerUUID != "" && trx.PointsEarned > 0 {
+		rule()
+		addLR("Poin didapat", strconv.FormatInt(trx.PointsEarned, 10))
+	}
+
+	if trx.Status == "VOID" {
+		rule()
+		out = append(out, textLine{Text: "*** VOID ***", Align: alignCenter, Bold: true, Double: true})
+	}
+
+	if b.FooterText != "" {
+		rule()
+		for _, s := range wrap(b.FooterText, cols) {
+			add(s, alignCenter)
+		}
+	}
+
+	if r.Options.Copy {
+		rule()
+		copyBanner()
+	}
+	return out
+}
+
+// ImageType: PNG / JPG dari magic bytes, "" kalau format tidak didukung fpdf
+func ImageType(b []byte) string {
+	switch {
+	case bytes.HasPrefix(b, []byte("\x89PNG")):
+		return "PNG"
+	case bytes.HasPrefix(b, []byte("\xff\xd8")):
+		return "JPG"
+	case bytes.HasPrefix(b, []byte("GIF8")):
+		return "GIF"
+	}
+	return ""
+}
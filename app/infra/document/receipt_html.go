@@ -0,0 +1,127 @@
+package document
+
+import (
+	"bytes"
+	"html/template"
+	"strconv"
+
+	"harjonan.id/user-service/app/helpers"
+)
+
+type htmlReceiptRow struct {
+	Label string
+	Value string
+	Bold  bool
+}
+
+type htmlReceiptItem struct {
+	Name      string
+	Detail    string
+	LineTotal string
+}
+
+type htmlReceipt struct {
+	B       ReceiptBranding
+	Receipt string
+	Date    string
+	Cashier string
+	Cust    string
+	Items   []htmlReceiptItem
+	Totals  []htmlReceiptRow
+	Void    bool
+	Copy    bool
+}
+
+var receiptHTMLTmpl = template.Must(template.New("receipt").Parse(`<!DOCTYPE html>
+<html>
+<body style="margin:0;padding:16px;background:#f4f4f4;font-family:Arial,Helvetica,sans-serif;color:#222;">
+<table role="presentation" width="100%" cellpadding="0" cellspacing="0">
+<tr><td align="center">
+<table role="presentation" width="380" cellpadding="0" cellspacing="0" style="background:#fff;border-radius:6px;padding:20px;font-size:13px;">
+	{{if .Copy}}<tr><td colspan="2" align="center" style="font-weight:bold;letter-spacing:2px;">*** COPY ***</td></tr>{{end}}
+	{{if .B.LogoURL}}<tr><td colspan="2" align="center" style="padding-bottom:8px;"><img src="{{.B.LogoURL}}" alt="" style="max-width:120px;max-height:80px;"></td></tr>{{end}}
+	<tr><td colspan="2" align="center" style="font-size:18px;font-weight:bold;">{{.B.StoreName}}</td></tr>
+	{{if .B.BranchName}}<tr><td colspan="2" align="center" style="font-weight:bold;">{{.B.BranchName}}</td></tr>{{end}}
+	{{if .B.Address}}<tr><td colspan="2" align="center" style="color:#666;">{{.B.Address}}</td></tr>{{end}}
+	{{if .B.Phone}}<tr><td colspan="2" align="center" style="color:#666;">Telp. {{.B.Phone}}</td></tr>{{end}}
+	{{if .B.HeaderText}}<tr><td colspan="2" align="center" style="padding-top:6px;">{{.B.HeaderText}}</td></tr>{{end}}
+	<tr><td colspan="2" style="border-bottom:1px dashed #bbb;padding-top:10px;"></td></tr>
+	<tr><td style="padding-top:8px;">No</td><td align="right" style="padding-top:8px;">{{.Receipt}}</td></tr>
+	<tr><td>Tanggal</td><td align="right">{{.Date}}</td></tr>
+	{{if .Cashier}}<tr><td>Kasir</td><td align="right">{{.Cashier}}</td></tr>{{end}}
+	{{if .Cust}}<tr><td>Customer</td><td align="right">{{.Cust}}</td></tr>{{end}}
+	<tr><td colspan="2" style="border-bottom:1px dashed #bbb;padding-top:8px;"></td></tr>
+	{{range .Items}}
+	<tr><td colspan="2" style="padding-top:8px;">{{.Name}}</td></tr>
+	<tr><td style="color:#666;">{{.Detail}}</td><td align="right">{{.LineTotal}}</td></tr>
+	{{end}}
+	<tr><td colspan="2" style="border-bottom:1px dashed #bbb;padding-top:8px;"></td></tr>
+	{{range .Totals}}
+	<tr><td style="padding-top:4px;{{if .Bold}}font-weight:bold;font-size:15px;{{end}}">{{.Label}}</td><td align="right" style="padding-top:4px;{{if .Bold}}font-weight:bold;font-size:15px;{{end}}">{{.Value}}</td></tr>
+	{{end}}
+	{{if .Void}}<tr><td colspan="2" align="center" style="padding-top:10px;font-weight:bold;color:#c00;">*** VOID ***</td></tr>{{end}}
+	{{if .B.FooterText}}<tr><td colspan="2" align="center" style="padding-top:14px;color:#666;">{{.B.FooterText}}</td></tr>{{end}}
+</table>
+</td></tr>
+</table>
+</body>
+</html>`))
+
+// RenderReceiptHTML: struk untuk body email (isi sama dengan struk cetak)
+func RenderReceiptHTML(r Receipt) (string, error) {
+	trx := r.Trx
+	d := htmlReceipt{
+		B:       r.Branding,
+		Receipt: trx.ReceiptNo,
+		Date:    helpers.FormatPOSDateTime(trx.CreatedAt),
+		Cashier: trx.CreatedBy,
+		Void:    trx.Status == "VOID",
+		Copy:    r.Options.Copy,
+	}
+	if r.Options.ShowCustomer {
+		d.Cust = trx.CustomerName
+	}
+
+	for _, it := range trx.Items {
+		unit := it.Unit
+		if unit == "" {
+			unit = it.BaseUnit
+		}
+		detail := strconv.FormatInt(it.Qty, 10)
+		if unit != "" {
+			detail += " " + unit
+		}
+		d.Items = append(d.Items, htmlReceiptItem{
+			Name:      it.Name,
+			Detail:    detail + " x " + helpers.FormatIDR(it.Price),
+			LineTotal: helpers.FormatIDR(it.LineTotal),
+		})
+	}
+
+	add := func(label, value string) { d.Totals = append(d.Totals, htmlReceiptRow{Label: label, Value: value}) }
+	add("Subtotal", helpers.FormatIDR(trx.SubTotal))
+	if trx.Discount > 0 {
+		add("Diskon", "-"+helpers.FormatIDR(trx.Discount))
+	}
+	if trx.PointsValue > 0 && trx.PointsRedeemAs == "DISCOUNT" {
+		add("Tukar "+strconv.FormatInt(trx.PointsRedeemed, 10)+" poin", "-"+helpers.FormatIDR(trx.PointsValue))
+	}
+	d.Totals = append(d.Totals, htmlReceiptRow{Label: "TOTAL", Value: "Rp " + helpers.FormatIDR(trx.Total), Bold: true})
+	add("Bayar ("+trx.PaymentMethod+")", helpers.FormatIDR(trx.Paid))
+	if trx.PointsValue > 0 && trx.PointsRedeemAs == "TENDER" {
+		add("Poin ("+strconv.FormatInt(trx.PointsRedeemed, 10)+")", helpers.FormatIDR(trx.PointsValue))
+	}
+	if trx.CreditAmount > 0 {
+		add("Kasbon", helpers.FormatIDR(trx.CreditAmount))
+	}
+	add("Kembali", helpers.FormatIDR(trx.Change))
+	if r.Options.ShowPoints && trx.CustomerUUID != "" && trx.PointsEarned > 0 {
+		add("Poin didapat", strconv.FormatInt(trx.PointsEarned, 10))
+	}
+
+	var buf bytes.Buffer
+	if err := receiptHTMLTmpl.Execute(&buf, d); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
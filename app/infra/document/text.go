@@ -0,0 +1,96 @@
+package document
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// ---------------------------------------------
+// Text layout (monospace) dipakai ESC/POS & PDF struk
+// ---------------------------------------------
+
+type align byte
+
+const (
+	alignLeft align = iota
+	alignCenter
+	alignRight
+)
+
+type textLine struct {
+	Text   string
+	Align  align
+	Bold   bool
+	Double bool // double width + height (ESC/POS), font lebih besar di PDF
+	Rule   bool // garis pemisah
+}
+
+// asciiOnly: printer thermal default codepage tidak support unicode
+func asciiOnly(s string) string {
+	repl := strings.NewReplacer("•", "-", "–", "-", "—", "-", "✅", "", "×", "x", "’", "'", "“", "\"", "”", "\"")
+	s = repl.Replace(s)
+	var b strings.Builder
+	for _, r := range s {
+		if r == '\n' || (r >= 32 && r < 127) {
+			b.WriteRune(r)
+			continue
+		}
+		b.WriteByte('?')
+	}
+	return b.String()
+}
+
+// wrap: pecah text per kata supaya muat di kolom
+func wrap(s string, cols int) []string {
+	s = strings.TrimSpace(s)
+	if s == "" || cols <= 0 {
+		return nil
+	}
+	var out []string
+	for _, para := range strings.Split(s, "\n") {
+		words := strings.Fields(para)
+		cur := ""
+		for _, w := range words {
+			for utf8.RuneCountInString(w) > cols {
+				if cur != "" {
+					out = append(out, cur)
+					cur = ""
+				}
+				out = append(out, w[:cols])
+				w = w[cols:]
+			}
+			switch {
+			case cur == "":
+				cur = w
+			case utf8.RuneCountInString(cur)+1+utf8.RuneCountInString(w) <= cols:
+				cur += " " + w
+			default:
+				out = append(out, cur)
+				cur = w
+			}
+		}
+		if cur != "" {
+			out = append(out, cur)
+		}
+	}
+	return out
+}
+
+// leftRight: "Subtotal          12.000"
+func leftRight(left, right string, cols int) string {
+	l := utf8.RuneCountInString(left)
+	r := utf8.RuneCountInString(right)
+	if l+r+1 > cols {
+		maxLeft := cols - r - 1
+		if maxLeft < 0 {
+			maxLeft = 0
+		}
+		left = string([]rune(left)[:min(l, maxLeft)])
+		l = utf8.RuneCountInString(left)
+	}
+	pad := cols - l - r
+	if pad < 1 {
+		pad = 1
+	}
+	return left + strings.Repeat(" ", pad) + right
+}
@@ -0,0 +1,280 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+)
+
+type ApprovalRepository interface {
+	// Policy: default (tanpa threshold) kalau client belum set
+	GetPolicy(clientUUID string) (dao.ApprovalPolicy, error)
+	SavePolicy(p *dao.ApprovalPolicy) (dao.ApprovalPolicy, error)
+
+	Insert(a *dao.Approval) (dao.Approval, error)
+	Detail(uuid string) (dao.Approval, error)
+	List(req *dto.FilterRequest) ([]dao.Approval, error)
+	// Decide: PENDING -> APPROVED/REJECTED (atomic, sekali saja)
+	Decide(uuid string, status dao.ApprovalStatus, by, byName, note string) (dao.Approval, error)
+	// Consume: APPROVED -> USED (atomic, approval hanya bisa dipakai sekali)
+	Consume(uuid string, usedRef string) (dao.Approval, error)
+	// Release: USED -> APPROVED, rollback kalau aksi gagal setelah consume
+	Release(uuid string) error
+	SetUsedRef(uuid string, usedRef string) error
+
+	GetPin(clientUUID, userUUID string) (dao.SupervisorPin, error)
+	SavePin(p *dao.SupervisorPin) error
+	PinFailed(uuid string, lockUntil int64) (dao.SupervisorPin, error)
+	PinSucceeded(uuid string) error
+}
+
+type ApprovalRepositoryImpl struct {
+	policyCol *mongo.Collection
+	col       *mongo.Collection
+	pinCol    *mongo.Collection
+}
+
+func ApprovalRepositoryInit(mongoClient *mongo.Client) *ApprovalRepositoryImpl {
+	dbName := helpers.ProvideDBName()
+	db := mongoClient.Database(dbName)
+	return &ApprovalRepositoryImpl{
+		policyCol: db.Collection("approval_policies"),
+		col:       db.Collection("approvals"),
+		pinCol:    db.Collection("supervisor_pins"),
+	}
+}
+
+func (r *ApprovalRepositoryImpl) GetPolicy(clientUUID string) (dao.ApprovalPolicy, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.ApprovalPolicy
+	err := r.policyCol.FindOne(ctx, bson.M{"client_uuid": clientUUID}).Decode(&out)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		out = dao.ApprovalPolicy{ClientUUID: clientUUID}
+		err = nil
+	}
+	if len(out.ApproverRoles) == 0 {
+		out.ApproverRoles = []string{"OWNER", "SUPERADMIN"}
+	}
+	if out.RequestTTLMinutes <= 0 {
+		out.RequestTTLMinutes = 60
+	}
+	return out, err
+}
+
+func (r *ApprovalRepositoryImpl) SavePolicy(p *dao.ApprovalPolicy) (dao.ApprovalPolicy, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if p.ClientUUID == "" {
+		return dao.ApprovalPolicy{}, errors.New("client_uuid required")
+	}
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+	update := bson.M{
+		"$set": bson.M{
+			"void_max_age_minutes": p.VoidMaxAgeMinutes,
+			"void_always_require":  p.VoidAlwaysRequire,
+			"discount_max_percent": p.DiscountMaxPercent,
+			"adjustment_max_qty":   p.AdjustmentMaxQty,
+			"adjustment_max_value": p.AdjustmentMaxValue,
+			"approver_roles":       p.ApproverRoles,
+			"request_ttl_minutes":  p.RequestTTLMinutes,
+			"updated_by":           p.UpdatedBy,
+			"updated_at":           now.Unix(),
+			"updated_at_str":       nowStr,
+		},
+		"$setOnInsert": bson.M{
+			"uuid":           helpers.GenerateUUID(),
+			"created_at":     now,
+			"created_at_str": nowStr,
+		},
+	}
+	if _, err := r.policyCol.UpdateOne(ctx, bson.M{"client_uuid": p.ClientUUID}, update, options.Update().SetUpsert(true)); err != nil {
+		return dao.ApprovalPolicy{}, err
+	}
+	return r.GetPolicy(p.ClientUUID)
+}
+
+func (r *ApprovalRepositoryImpl) Insert(a *dao.Approval) (dao.Approval, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if a.ClientUUID == "" || a.Type == "" {
+		return dao.Approval{}, errors.New("client_uuid & type required")
+	}
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+	if a.UUID == "" {
+		a.UUID = helpers.GenerateUUID()
+	}
+	a.CreatedAt = now
+	a.CreatedAtStr = nowStr
+	a.UpdatedAt = now.Unix()
+	a.UpdatedAtStr = nowStr
+
+	if _, err := r.col.InsertOne(ctx, a); err != nil {
+		return dao.Approval{}, err
+	}
+	return *a, nil
+}
+
+func (r *ApprovalRepositoryImpl) Detail(uuid string) (dao.Approval, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.Approval
+	err := r.col.FindOne(ctx, bson.M{"uuid": uuid}).Decode(&out)
+	return out, err
+}
+
+func (r *ApprovalRepositoryImpl) List(req *dto.FilterRequest) ([]dao.Approval, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	filter := buildListFilter(req, "reason", "requested_by_name", "approved_by_name")
+	cur, err := r.col.Find(ctx, filter, buildListOptions(req))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var out []dao.Approval
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *ApprovalRepositoryImpl) Decide(uuid string, status dao.ApprovalStatus, by, byName, note string) (dao.Approval, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	var out dao.Approval
+	err := r.col.FindOneAndUpdate(ctx, bson.M{
+		"uuid":       uuid,
+		"status":     dao.ApprovalPending,
+		"expires_at": bson.M{"$gt": now.Unix()},
+	}, bson.M{"$set": bson.M{
+		"status":           status,
+		"approved_by":      by,
+		"approved_by_name": byName,
+		"decision_note":    note,
+		"decided_at":       now.Unix(),
+		"decided_at_str":   now.Format(time.RFC3339),
+		"updated_at":       now.Unix(),
+		"updated_at_str":   now.Format(time.RFC3339),
+	}}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&out)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return dao.Approval{}, errors.New("approval not pending or expired")
+	}
+	return out, err
+}
+
+func (r *ApprovalRepositoryImpl) Consume(uuid string, usedRef string) (dao.Approval, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	var out dao.Approval
+	err := r.col.FindOneAndUpdate(ctx, bson.M{
+		"uuid":   uuid,
+		"status": dao.ApprovalApproved,
+	}, bson.M{"$set": bson.M{
+		"status":         dao.ApprovalUsed,
+		"used_at":        now.Unix(),
+		"used_ref":       usedRef,
+		"updated_at":     now.Unix(),
+		"updated_at_str": now.Format(time.RFC3339),
+	}}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&out)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return dao.Approval{}, errors.New("approval not approved or already used")
+	}
+	return out, err
+}
+
+func (r *ApprovalRepositoryImpl) Release(uuid string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.col.UpdateOne(ctx, bson.M{"uuid": uuid, "status": dao.ApprovalUsed, "method": dao.ApprovalByRequest},
+		bson.M{"$set": bson.M{"status": dao.ApprovalApproved, "used_at": 0, "used_ref": ""}})
+	return err
+}
+
+func (r *ApprovalRepositoryImpl) SetUsedRef(uuid string, usedRef string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.col.UpdateOne(ctx, bson.M{"uuid": uuid}, bson.M{"$set": bson.M{"used_ref": usedRef}})
+	return err
+}
+
+func (r *ApprovalRepositoryImpl) GetPin(clientUUID, userUUID string) (dao.SupervisorPin, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.SupervisorPin
+	err := r.pinCol.FindOne(ctx, bson.M{"client_uuid": clientUUID, "user_uuid": userUUID}).Decode(&out)
+	return out, err
+}
+
+func (r *ApprovalRepositoryImpl) SavePin(p *dao.SupervisorPin) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+	_, err := r.pinCol.UpdateOne(ctx, bson.M{"client_uuid": p.ClientUUID, "user_uuid": p.UserUUID}, bson.M{
+		"$set": bson.M{
+			"name":            p.Name,
+			"role_value":      p.RoleValue,
+			"pin_hash":        p.PinHash,
+			"failed_attempts": 0,
+			"locked_until":    0,
+			"updated_at":      now.Unix(),
+			"updated_at_str":  nowStr,
+		},
+		"$setOnInsert": bson.M{
+			"uuid":           helpers.GenerateUUID(),
+			"created_at":     now,
+			"created_at_str": nowStr,
+		},
+	}, options.Update().SetUpsert(true))
+	return err
+}
+
+// PinFailed: tambah counter gagal; lockUntil > 0 => kunci PIN sampai waktu tsb
+func (r *ApprovalRepositoryImpl) PinFailed(uuid string, lockUntil int64) (dao.SupervisorPin, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	update := bson.M{"$inc": bson.M{"failed_attempts": 1}}
+	if lockUntil > 0 {
+		update = bson.M{"$set": bson.M{"failed_attempts": 0, "locked_until": lockUntil}}
+	}
+	var out dao.SupervisorPin
+	err := r.pinCol.FindOneAndUpdate(ctx, bson.M{"uuid": uuid}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&out)
+	return out, err
+}
+
+func (r *ApprovalRepositoryImpl) PinSucceeded(uuid string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.pinCol.UpdateOne(ctx, bson.M{"uuid": uuid}, bson.M{"$set": bson.M{"failed_attempts": 0, "locked_until": 0}})
+	return err
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/helpers"
+)
+
+type BarcodeSequenceRepository interface {
+	Get(clientUUID string) (dao.BarcodeSequence, error)
+	// SetPrefix: ganti prefix => nomor urut mulai lagi dari 1
+	SetPrefix(clientUUID, prefix, updatedBy string) (dao.BarcodeSequence, error)
+	// Reserve: ambil n nomor urut sekaligus (atomic), return prefix & nomor pertama
+	Reserve(clientUUID string, n int64) (string, int64, error)
+}
+
+type BarcodeSequenceRepositoryImpl struct {
+	col *mongo.Collection
+}
+
+func BarcodeSequenceRepositoryInit(mongoClient *mongo.Client) *BarcodeSequenceRepositoryImpl {
+	dbName := helpers.ProvideDBName()
+	return &BarcodeSequenceRepositoryImpl{
+		col: mongoClient.Database(dbName).Collection("barcode_sequences"),
+	}
+}
+
+func (r *BarcodeSequenceRepositoryImpl) Get(clientUUID string) (dao.BarcodeSequence, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.BarcodeSequence
+	err := r.col.FindOne(ctx, bson.M{"client_uuid": clientUUID}).Decode(&out)
+	return out, err
+}
+
+func (r *BarcodeSequenceRepositoryImpl) SetPrefix(clientUUID, prefix, updatedBy string) (dao.BarcodeSequence, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+
+	var out dao.BarcodeSequence
+	err := r.col.FindOneAndUpdate(ctx, bson.M{"client_uuid": clientUUID}, bson.M{
+		"$set": bson.M{
+			"prefix":         prefix,
+			"next":           int64(1),
+			"updated_by":     updatedBy,
+			"updated_at":     now.Unix(),
+			"updated_at_str": nowStr,
+		},
+		"$setOnInsert": bson.M{
+			"uuid":           helpers.GenerateUUID(),
+			"client_uuid":    clientUUID,
+			"created_at":     now,
+			"created_at_str": nowStr,
+		},
+	}, options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)).Decode(&out)
+	return out, err
+}
+
+func (r *BarcodeSequenceRepositoryImpl) Reserve(clientUUID string, n int64) (string, int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if n <= 0 {
+		return "", 0, errors.New("n must be > 0")
+	}
+	var before dao.BarcodeSequence
+	err := r.col.FindOneAndUpdate(ctx, bson.M{"client_uuid": clientUUID, "prefix": bson.M{"$ne": ""}},
+		bson.M{"$inc": bson.M{"next": n}},
+		options.FindOneAndUpdate().SetReturnDocument(options.Before)).Decode(&before)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", 0, errors.New("barcode prefix not set")
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	return before.Prefix, before.Next, nil
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/helpers"
+)
+
+type BarcodeTemplateRepository interface {
+	Save(t *dao.BarcodeTemplate) (dao.BarcodeTemplate, error)
+	Detail(uuid string) (dao.BarcodeTemplate, error)
+	List(clientUUID string) ([]dao.BarcodeTemplate, error)
+	Delete(uuid string) error
+	ListActive(clientUUID string) ([]dao.BarcodeTemplate, error)
+}
+
+type BarcodeTemplateRepositoryImpl struct {
+	col *mongo.Collection
+}
+
+func BarcodeTemplateRepositoryInit(mongoClient *mongo.Client) *BarcodeTemplateRepositoryImpl {
+	dbName := helpers.ProvideDBName()
+	return &BarcodeTemplateRepositoryImpl{
+		col: mongoClient.Database(dbName).Collection("barcode_templates"),
+	}
+}
+
+func (r *BarcodeTemplateRepositoryImpl) Save(t *dao.BarcodeTemplate) (dao.BarcodeTemplate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if t.ClientUUID == "" {
+		return dao.BarcodeTemplate{}, errors.New("client_uuid required")
+	}
+
+	// prefix unik per client
+	dup := bson.M{"client_uuid": t.ClientUUID, "prefix": t.Prefix}
+	if t.UUID != "" {
+		dup["uuid"] = bson.M{"$ne": t.UUID}
+	}
+	n, err := r.col.CountDocuments(ctx, dup)
+	if err != nil {
+		return dao.BarcodeTemplate{}, err
+	}
+	if n > 0 {
+		return dao.BarcodeTemplate{}, errors.New("prefix already used by another template")
+	}
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+	uuid := t.UUID
+	if uuid == "" {
+		uuid = helpers.GenerateUUID()
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"client_uuid":       t.ClientUUID,
+			"name":              t.Name,
+			"prefix":            t.Prefix,
+			"plu_length":        t.PLULength,
+			"value_length":      t.ValueLength,
+			"value_check_digit": t.ValueCheckDigit,
+			"value_type":        t.ValueType,
+			"qty_multiplier":    t.QtyMultiplier,
+			"price_decimals":    t.PriceDecimals,
+			"is_active":         t.IsActive,
+			"updated_at":        now.Unix(),
+			"updated_at_str":    nowStr,
+		},
+		"$setOnInsert": bson.M{
+			"uuid":           uuid,
+			"created_by":     t.CreatedBy,
+			"created_at":     now,
+			"created_at_str": nowStr,
+		},
+	}
+	if _, err := r.col.UpdateOne(ctx, bson.M{"uuid": uuid}, update, options.Update().SetUpsert(true)); err != nil {
+		return dao.BarcodeTemplate{}, err
+	}
+	return r.Detail(uuid)
+}
+
+func (r *BarcodeTemplateRepositoryImpl) Detail(uuid string) (dao.BarcodeTemplate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.BarcodeTemplate
+	err := r.col.FindOne(ctx, bson.M{"uuid": uuid}).Decode(&out)
+	return out, err
+}
+
+func (r *BarcodeTemplateRepositoryImpl) List(clientUUID string) ([]dao.BarcodeTemplate, error) {
+	return r.find(bson.M{"client_uuid": clientUUID})
+}
+
+func (r *BarcodeTemplateRepositoryImpl) ListActive(clientUUID string) ([]dao.BarcodeTemplate, error) {
+	return r.find(bson.M{"client_uuid": clientUUID, "is_active": true})
+}
+
+func (r *BarcodeTemplateRepositoryImpl) find(filter bson.M) ([]dao.BarcodeTemplate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cur, err := r.col.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "prefix", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var out []dao.BarcodeTemplate
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *BarcodeTemplateRepositoryImpl) Delete(uuid string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.col.DeleteOne(ctx, bson.M{"uuid": uuid})
+	return err
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+)
+
+type BrandRepository interface {
+	List(clientUUID string, req *dto.FilterRequest) ([]dao.ProductBrand, error)
+	Detail(uuid string) (dao.ProductBrand, error)
+	// Save: rename ikut mengubah brand_name di catalog & produk
+	Save(data *dao.ProductBrand) (dao.ProductBrand, error)
+	Delete(uuid string) error
+	// EnsureByName: brand dengan nama tsb (case-insensitive), dibuat kalau belum ada
+	EnsureByName(clientUUID, name, createdBy string) (dao.ProductBrand, error)
+}
+
+type BrandRepositoryImpl struct {
+	col        *mongo.Collection
+	catalogCol *mongo.Collection
+	productCol *mongo.Collection
+}
+
+func BrandRepositoryInit(mongoClient *mongo.Client) *BrandRepositoryImpl {
+	dbName := helpers.ProvideDBName()
+	db := mongoClient.Database(dbName)
+	return &BrandRepositoryImpl{
+		col:        db.Collection("product_brands"),
+		catalogCol: db.Collection("catalog_products"),
+		productCol: db.Collection("products"),
+	}
+}
+
+func (r *BrandRepositoryImpl) List(clientUUID string, req *dto.FilterRequest) ([]dao.ProductBrand, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := buildListFilter(req, "name")
+	filter["client_uuid"] = clientUUID
+
+	opts := buildListOptions(req)
+	if req == nil || len(req.SortBy) == 0 {
+		opts.SetSort(bson.D{{Key: "name", Value: 1}})
+	}
+	cur, err := r.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	out := []dao.ProductBrand{}
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *BrandRepositoryImpl) Detail(uuid string) (dao.ProductBrand, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.ProductBrand
+	err := r.col.FindOne(ctx, bson.M{"uuid": uuid}).Decode(&out)
+	return out, err
+}
+
+func (r *BrandRepositoryImpl) Save(data *dao.ProductBrand) (dao.ProductBrand, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return r.save(ctx, data)
+}
+
+func (r *BrandRepositoryImpl) save(ctx context.Context, data *dao.ProductBrand) (dao.ProductBrand, error) {
+	data.Name = strings.TrimSpace(data.Name)
+	if data.ClientUUID == "" {
+		return dao.ProductBrand{}, errors.New("client_uuid required")
+	}
+	if data.Name == "" {
+		return dao.ProductBrand{}, errors.New("name required")
+	}
+
+	dup := nameFilter(data.ClientUUID, data.Name)
+	dup["uuid"] = bson.M{"$ne": data.UUID}
+	if n, err := r.col.CountDocuments(ctx, dup); err != nil {
+		return dao.ProductBrand{}, err
+	} else if n > 0 {
+		return dao.ProductBrand{}, errors.New("brand " + data.Name + " already exists")
+	}
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+	data.UpdatedAt = now.Unix()
+	data.UpdatedAtStr = nowStr
+
+	if data.UUID == "" {
+		data.UUID = helpers.GenerateUUID()
+		data.CreatedAt = now
+		data.CreatedAtStr = nowStr
+		if _, err := r.col.InsertOne(ctx, data); err != nil {
+			return dao.ProductBrand{}, err
+		}
+		return *data, nil
+	}
+
+	var out dao.ProductBrand
+	err := r.col.FindOneAndUpdate(ctx, bson.M{"uuid": data.UUID, "client_uuid": data.ClientUUID}, bson.M{"$set": bson.M{
+		"name":           data.Name,
+		"logo":           data.Logo,
+		"updated_at":     data.UpdatedAt,
+		"updated_at_str": data.UpdatedAtStr,
+	}}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&out)
+	if err != nil {
+		return dao.ProductBrand{}, err
+	}
+	for _, col := range []*mongo.Collection{r.catalogCol, r.productCol} {
+		if _, err := col.UpdateMany(ctx, bson.M{"brand_uuid": out.UUID}, bson.M{"$set": bson.M{"brand_name": out.Name}}); err != nil {
+			return dao.ProductBrand{}, err
+		}
+	}
+	return out, nil
+}
+
+func (r *BrandRepositoryImpl) Delete(uuid string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if n, err := r.catalogCol.CountDocuments(ctx, bson.M{"brand_uuid": uuid}); err != nil {
+		return err
+	} else if n > 0 {
+		return fmt.Errorf("brand still used by %d products", n)
+	}
+	_, err := r.col.DeleteOne(ctx, bson.M{"uuid": uuid})
+	return err
+}
+
+func (r *BrandRepositoryImpl) EnsureByName(clientUUID, name, createdBy string) (dao.ProductBrand, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	name = strings.TrimSpace(name)
+	var out dao.ProductBrand
+	err := r.col.FindOne(ctx, nameFilter(clientUUID, name)).Decode(&out)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return r.save(ctx, &dao.ProductBrand{ClientUUID: clientUUID, Name: name, CreatedBy: createdBy})
+	}
+	return out, err
+}
@@ -0,0 +1,819 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+)
+
+type CatalogRepository interface {
+	List(clientUUID string, req *dto.FilterRequest) ([]dto.CatalogListRow, error)
+	Detail(uuid string) (dao.CatalogProduct, error)
+	// Inventories: record products (per branch) yang terhubung ke catalog
+	Inventories(catalogUUID string) ([]dao.Product, error)
+	// Save: simpan master catalog lalu sinkron ke semua branch
+	Save(data *dao.CatalogProduct) (dao.CatalogProduct, error)
+	// SetInventory: buka produk catalog di branch (stock 0) atau ubah harga override / aktif
+	SetInventory(cat dao.CatalogProduct, req dto.CatalogInventoryRequest, createdBy string) (dao.Product, error)
+	// Migrate: hubungkan produk lama ke catalog, produk dengan SKU sama di beberapa branch digabung ke 1 master
+	Migrate(clientUUID string, dryRun bool) (dto.CatalogMigrateResult, error)
+	// Variants: varian milik parent (urut SKU)
+	Variants(parentUUIDs ...string) ([]dao.CatalogProduct, error)
+	// SetVariantAttributes: tandai parent punya varian; record inventory parent (tanpa stock) dinonaktifkan
+	SetVariantAttributes(parentUUID string, attrs []dao.VariantAttribute) (dao.CatalogProduct, error)
+	// FindByBarcode: entry catalog client dengan barcode tsb (parent varian tidak punya record branch)
+	FindByBarcode(clientUUID, barcode string) (dao.CatalogProduct, error)
+	// WithoutBarcode: entry catalog (bukan parent varian) yang belum punya barcode; uuids kosong = semua
+	WithoutBarcode(clientUUID string, uuids []string, limit int64) ([]dao.CatalogProduct, error)
+	// Tags: semua tag yang dipakai catalog client (untuk autocomplete / filter POS)
+	Tags(clientUUID string) ([]string, error)
+}
+
+type CatalogRepositoryImpl struct {
+	catalogCol *mongo.Collection
+	productCol *mongo.Collection
+	link       catalogLink
+}
+
+func CatalogRepositoryInit(mongoClient *mongo.Client) *CatalogRepositoryImpl {
+	dbName := helpers.ProvideDBName()
+	db := mongoClient.Database(dbName)
+	return &CatalogRepositoryImpl{
+		catalogCol: db.Collection("catalog_products"),
+		productCol: db.Collection("products"),
+		link:       newCatalogLink(db),
+	}
+}
+
+// catalogLink: sinkron catalog_products <-> products, dipakai juga oleh ProductRepository saat upsert / import
+type catalogLink struct {
+	catalogCol *mongo.Collection
+	productCol *mongo.Collection
+	branchCol  *mongo.Collection
+}
+
+func newCatalogLink(db *mongo.Database) catalogLink {
+	return catalogLink{
+		catalogCol: db.Collection("catalog_products"),
+		productCol: db.Collection("products"),
+		branchCol:  db.Collection("client_branches"),
+	}
+}
+
+func (l catalogLink) clientOf(ctx context.Context, branchUUID string) (string, error) {
+	var b dao.ClientBranch
+	if err := l.branchCol.FindOne(ctx, bson.M{"uuid": branchUUID}).Decode(&b); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return "", errors.New("branch not found")
+		}
+		return "", err
+	}
+	return b.ClientUUID, nil
+}
+
+func (l catalogLink) branchesOf(ctx context.Context, clientUUID string) ([]string, error) {
+	cur, err := l.branchCol.Find(ctx, bson.M{"client_uuid": clientUUID}, options.Find().SetProjection(bson.M{"uuid": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var rows []dao.ClientBranch
+	if err := cur.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(rows))
+	for _, b := range rows {
+		out = append(out, b.UUID)
+	}
+	return out, nil
+}
+
+// resolve: cari master by catalog_uuid, kalau kosong by SKU
+func (l catalogLink) resolve(ctx context.Context, clientUUID, catalogUUID, sku string) (dao.CatalogProduct, bool, error) {
+	var filter bson.M
+	switch {
+	case catalogUUID != "":
+		filter = bson.M{"uuid": catalogUUID, "client_uuid": clientUUID}
+	case sku != "":
+		filter = bson.M{"sku": sku, "client_uuid": clientUUID}
+	default:
+		return dao.CatalogProduct{}, false, nil
+	}
+
+	var c dao.CatalogProduct
+	err := l.catalogCol.FindOne(ctx, filter).Decode(&c)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return dao.CatalogProduct{}, false, nil
+	}
+	if err != nil {
+		return dao.CatalogProduct{}, false, err
+	}
+	return c, true, nil
+}
+
+// fromProduct: upsert produk branch / import => catalog_uuid + price_override produk di-resolve.
+// Entry baru dibuat dari data produk (harga catalog = harga produk). Entry lama tidak diubah: master
+// (nama, SKU, units, dst) hanya lewat /catalog/upsert, field master yang beda ditolak, yang kosong diisi dari catalog.
+// Harga beda dari catalog jadi price_override branch ini (sama dengan harga catalog = override dihapus).
+// Ini perubahan harga tanpa price change: service membatasi perubahan harga lewat upsert ke role approver.
+func (l catalogLink) fromProduct(ctx context.Context, data *dao.Product) (dao.CatalogProduct, error) {
+	clientUUID, err := l.clientOf(ctx, data.BranchUUID)
+	if err != nil {
+		return dao.CatalogProduct{}, err
+	}
+	cat, found, err := l.resolve(ctx, clientUUID, data.CatalogUUID, data.SKU)
+	if err != nil {
+		return dao.CatalogProduct{}, err
+	}
+	if found {
+		if cat.HasVariants {
+			return dao.CatalogProduct{}, errors.New("product " + cat.Name + " has variants, save the variant instead")
+		}
+		if diff := masterChanges(cat, data); len(diff) > 0 {
+			return dao.CatalogProduct{}, fmt.Errorf("%w: %s differ from catalog %s", dao.ErrCatalogMasterChanged, strings.Join(diff, ", "), cat.Name)
+		}
+		copyMaster(cat, data)
+		data.PriceOverride = nil
+		if data.Price != cat.Price {
+			price := data.Price
+			data.PriceOverride = &price
+		}
+		return cat, nil
+	}
+
+	cat = dao.CatalogProduct{ClientUUID: clientUUID, Price: data.Price, CreatedBy: data.CreatedBy}
+	cat.SKU = data.SKU
+	cat.Barcode = data.Barcode
+	cat.PLU = data.PLU
+	cat.Name = data.Name
+	cat.Description = data.Description
+	cat.BaseUnit = data.BaseUnit
+	cat.Units = data.Units
+	cat.Image = data.Image
+	// kategori / brand / tag kosong = tidak diubah (form & import lama tidak mengirim field ini)
+	if data.CategoryUUID != "" {
+		cat.CategoryUUID = data.CategoryUUID
+		cat.CategoryPath = data.CategoryPath
+	}
+	if data.BrandUUID != "" {
+		cat.BrandUUID = data.BrandUUID
+		cat.BrandName = data.BrandName
+	}
+	if data.Tags != nil {
+		cat.Tags = data.Tags
+	}
+
+	saved, err := l.save(ctx, &cat)
+	if err != nil {
+		return dao.CatalogProduct{}, err
+	}
+
+	data.CatalogUUID = saved.UUID
+	data.PriceOverride = nil
+	return saved, nil
+}
+
+// masterChanges: field master yang dikirim produk branch tapi beda dengan catalog.
+// Field optional kosong (barcode, plu, deskripsi, gambar, units, kategori, brand, tag) = tidak diubah.
+func masterChanges(c dao.CatalogProduct, p *dao.Product) []string {
+	diff := []string{}
+	check := func(field string, changed bool) {
+		if changed {
+			diff = append(diff, field)
+		}
+	}
+	check("sku", p.SKU != "" && p.SKU != c.SKU)
+	check("name", p.Name != c.Name)
+	check("base_unit", p.BaseUnit != c.BaseUnit)
+	check("barcode", p.Barcode != "" && p.Barcode != c.Barcode)
+	check("plu", p.PLU != "" && p.PLU != c.PLU)
+	check("description", p.Description != "" && p.Description != c.Description)
+	check("image", p.Image != "" && p.Image != c.Image)
+	check("units", len(p.Units) > 0 && !sameUnits(p.Units, c.Units))
+	check("category", p.CategoryUUID != "" && p.CategoryUUID != c.CategoryUUID)
+	check("brand", p.BrandUUID != "" && p.BrandUUID != c.BrandUUID)
+	check("tags", len(p.Tags) > 0 && !slices.Equal(p.Tags, c.Tags))
+	return diff
+}
+
+// copyMaster: field master produk mengikuti catalog (sama dengan masterFields)
+func copyMaster(c dao.CatalogProduct, p *dao.Product) {
+	p.CatalogUUID = c.UUID
+	p.SKU = c.SKU
+	p.Barcode = c.Barcode
+	p.PLU = c.PLU
+	p.Name = c.Name
+	p.Description = c.Description
+	p.BaseUnit = c.BaseUnit
+	p.Units = c.Units
+	p.Image = c.Image
+	p.CategoryUUID = c.CategoryUUID
+	p.CategoryPath = c.CategoryPath
+	p.BrandUUID = c.BrandUUID
+	p.BrandName = c.BrandName
+	p.Tags = c.Tags
+	p.ParentUUID = c.ParentUUID
+	p.VariantOptions = c.VariantOptions
+}
+
+func (l catalogLink) save(ctx context.Context, c *dao.CatalogProduct) (dao.CatalogProduct, error) {
+	if c.ClientUUID == "" {
+		return dao.CatalogProduct{}, errors.New("client_uuid required")
+	}
+	if c.Name == "" {
+		return dao.CatalogProduct{}, errors.New("name required")
+	}
+	if c.BaseUnit == "" {
+		return dao.CatalogProduct{}, errors.New("base_unit required")
+	}
+	if c.SKU != "" {
+		n, err := l.catalogCol.CountDocuments(ctx, bson.M{"client_uuid": c.ClientUUID, "sku": c.SKU, "uuid": bson.M{"$ne": c.UUID}})
+		if err != nil {
+			return dao.CatalogProduct{}, err
+		}
+		if n > 0 {
+			return dao.CatalogProduct{}, errors.New("sku " + c.SKU + " already used by another catalog product")
+		}
+	}
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+	c.UpdatedAt = now.Unix()
+	c.UpdatedAtStr = nowStr
+
+	if c.UUID == "" {
+		c.UUID = helpers.GenerateUUID()
+		c.CreatedAt = now
+		c.CreatedAtStr = nowStr
+		if _, err := l.catalogCol.InsertOne(ctx, c); err != nil {
+			return dao.CatalogProduct{}, err
+		}
+		return *c, nil
+	}
+
+	var out dao.CatalogProduct
+	err := l.catalogCol.FindOneAndUpdate(ctx, bson.M{"uuid": c.UUID, "client_uuid": c.ClientUUID}, bson.M{"$set": bson.M{
+		"sku":                c.SKU,
+		"barcode":            c.Barcode,
+		"plu":                c.PLU,
+		"name":               c.Name,
+		"description":        c.Description,
+		"base_unit":          c.BaseUnit,
+		"units":              c.Units,
+		"image":              c.Image,
+		"category_uuid":      c.CategoryUUID,
+		"category_path":      c.CategoryPath,
+		"brand_uuid":         c.BrandUUID,
+		"brand_name":         c.BrandName,
+		"tags":               c.Tags,
+		"price":              c.Price,
+		"has_variants":       c.HasVariants,
+		"variant_attributes": c.VariantAttributes,
+		"parent_uuid":        c.ParentUUID,
+		"variant_options":    c.VariantOptions,
+		"updated_at":         c.UpdatedAt,
+		"updated_at_str":     c.UpdatedAtStr,
+	}}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&out)
+	return out, err
+}
+
+// masterFields: field master yang disalin ke record products
+func masterFields(c dao.CatalogProduct) bson.M {
+	return bson.M{
+		"catalog_uuid":    c.UUID,
+		"sku":             c.SKU,
+		"barcode":         c.Barcode,
+		"plu":             c.PLU,
+		"name":            c.Name,
+		"description":     c.Description,
+		"base_unit":       c.BaseUnit,
+		"units":           c.Units,
+		"image":           c.Image,
+		"category_uuid":   c.CategoryUUID,
+		"category_path":   c.CategoryPath,
+		"brand_uuid":      c.BrandUUID,
+		"brand_name":      c.BrandName,
+		"tags":            c.Tags,
+		"parent_uuid":     c.ParentUUID,
+		"variant_options": c.VariantOptions,
+	}
+}
+
+// link: salin master ke record products yang match filter (produk yang baru di-upsert / import)
+func (l catalogLink) link(ctx context.Context, filter bson.M, c dao.CatalogProduct) error {
+	_, err := l.productCol.UpdateMany(ctx, filter, bson.M{"$set": masterFields(c)})
+	return err
+}
+
+// propagate: salin master ke semua branch; harga hanya untuk branch tanpa price_override
+func (l catalogLink) propagate(ctx context.Context, c dao.CatalogProduct) error {
+	now := time.Now()
+	set := masterFields(c)
+	set["updated_at"] = now.Unix()
+	set["updated_at_str"] = now.Format(time.RFC3339)
+
+	if _, err := l.productCol.UpdateMany(ctx, bson.M{"catalog_uuid": c.UUID}, bson.M{"$set": set}); err != nil {
+		return err
+	}
+	_, err := l.productCol.UpdateMany(ctx,
+		bson.M{"catalog_uuid": c.UUID, "price_override": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"price": c.Price}},
+	)
+	return err
+}
+
+func (r *CatalogRepositoryImpl) List(clientUUID string, req *dto.FilterRequest) ([]dto.CatalogListRow, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	filter := buildListFilter(req, "name", "sku", "barcode", "category_path", "brand_name", "tags")
+	filter["client_uuid"] = clientUUID
+	// varian tampil di bawah parent-nya, kecuali diminta langsung (filter_by.parent_uuid)
+	if _, ok := filter["parent_uuid"]; !ok {
+		filter["parent_uuid"] = bson.M{"$in": bson.A{"", nil}}
+	}
+
+	cur, err := r.catalogCol.Find(ctx, filter, buildListOptions(req))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var list []dao.CatalogProduct
+	if err := cur.All(ctx, &list); err != nil {
+		return nil, err
+	}
+
+	out := make([]dto.CatalogListRow, 0, len(list))
+	if len(list) == 0 {
+		return out, nil
+	}
+	uuids := make([]string, 0, len(list))
+	var parents []string
+	for _, c := range list {
+		uuids = append(uuids, c.UUID)
+		if c.HasVariants {
+			parents = append(parents, c.UUID)
+		}
+	}
+	variants := map[string][]dao.CatalogProduct{}
+	if len(parents) > 0 {
+		vs, err := r.Variants(parents...)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range vs {
+			variants[v.ParentUUID] = append(variants[v.ParentUUID], v)
+		}
+	}
+
+	// stock varian dijumlah ke parent (products.parent_uuid)
+	agg, err := r.productCol.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"$or": bson.A{
+			bson.M{"catalog_uuid": bson.M{"$in": uuids}},
+			bson.M{"parent_uuid": bson.M{"$in": uuids}},
+		}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{"$cond": bson.A{
+				bson.M{"$gt": bson.A{"$parent_uuid", ""}}, "$parent_uuid", "$catalog_uuid",
+			}},
+			"branch_count": bson.M{"$sum": 1},
+			"total_stock":  bson.M{"$sum": "$stock"},
+		}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var sums []struct {
+		CatalogUUID string `bson:"_id"`
+		BranchCount int    `bson:"branch_count"`
+		TotalStock  int64  `bson:"total_stock"`
+	}
+	if err := agg.All(ctx, &sums); err != nil {
+		return nil, err
+	}
+	byUUID := map[string]int{}
+	for i, s := range sums {
+		byUUID[s.CatalogUUID] = i
+	}
+
+	for _, c := range list {
+		row := dto.CatalogListRow{CatalogProduct: c, Variants: variants[c.UUID]}
+		if i, ok := byUUID[c.UUID]; ok {
+			row.BranchCount = sums[i].BranchCount
+			row.TotalStock = sums[i].TotalStock
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+func (r *CatalogRepositoryImpl) Detail(uuid string) (dao.CatalogProduct, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.CatalogProduct
+	err := r.catalogCol.FindOne(ctx, bson.M{"uuid": uuid}).Decode(&out)
+	return out, err
+}
+
+func (r *CatalogRepositoryImpl) Inventories(catalogUUID string) ([]dao.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cur, err := r.productCol.Find(ctx, bson.M{"catalog_uuid": catalogUUID}, options.Find().SetSort(bson.D{{Key: "branch_uuid", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	out := []dao.Product{}
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *CatalogRepositoryImpl) Save(data *dao.CatalogProduct) (dao.CatalogProduct, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	out, err := r.link.save(ctx, data)
+	if err != nil {
+		return dao.CatalogProduct{}, err
+	}
+	if err := r.link.propagate(ctx, out); err != nil {
+		return dao.CatalogProduct{}, err
+	}
+	if !out.HasVariants {
+		return out, nil
+	}
+
+	// kategori / brand / tag parent berlaku untuk semua varian
+	if _, err := r.catalogCol.UpdateMany(ctx, bson.M{"parent_uuid": out.UUID}, bson.M{"$set": bson.M{
+		"category_uuid": out.CategoryUUID,
+		"category_path": out.CategoryPath,
+		"brand_uuid":    out.BrandUUID,
+		"brand_name":    out.BrandName,
+		"tags":          out.Tags,
+	}}); err != nil {
+		return dao.CatalogProduct{}, err
+	}
+	variants, err := r.Variants(out.UUID)
+	if err != nil {
+		return dao.CatalogProduct{}, err
+	}
+	for _, v := range variants {
+		if err := r.link.propagate(ctx, v); err != nil {
+			return dao.CatalogProduct{}, err
+		}
+	}
+	return out, nil
+}
+
+func (r *CatalogRepositoryImpl) SetInventory(cat dao.CatalogProduct, req dto.CatalogInventoryRequest, createdBy string) (dao.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if req.BranchUUID == "" {
+		return dao.Product{}, errors.New("branch_uuid required")
+	}
+	if cat.HasVariants {
+		return dao.Product{}, errors.New("product has variants, open inventory per variant")
+	}
+
+	// produk lama (belum migrasi) dengan SKU sama di branch ini dipakai, tidak dibuat dobel
+	filter := bson.M{"branch_uuid": req.BranchUUID, "catalog_uuid": cat.UUID}
+	if cat.SKU != "" {
+		filter = bson.M{"branch_uuid": req.BranchUUID, "$or": bson.A{
+			bson.M{"catalog_uuid": cat.UUID},
+			bson.M{"sku": cat.SKU},
+		}}
+	}
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+
+	set := masterFields(cat)
+	set["branch_uuid"] = req.BranchUUID
+	set["updated_at"] = now.Unix()
+	set["updated_at_str"] = nowStr
+	update := bson.M{"$set": set}
+
+	if req.PriceOverride != nil {
+		if *req.PriceOverride < 0 {
+			return dao.Product{}, errors.New("price_override must be >= 0")
+		}
+		set["price_override"] = *req.PriceOverride
+		set["price"] = *req.PriceOverride
+	} else {
+		set["price"] = cat.Price
+		update["$unset"] = bson.M{"price_override": ""}
+	}
+
+	setOnInsert := bson.M{
+		"uuid":           helpers.GenerateUUID(),
+		"stock":          int64(0),
+		"cost":           float64(0),
+		"track_lots":     false,
+		"min_stock":      int64(0),
+		"max_stock":      int64(0),
+		"created_by":     createdBy,
+		"created_at":     now,
+		"created_at_str": nowStr,
+	}
+	if req.IsActive != nil {
+		set["is_active"] = *req.IsActive
+	} else {
+		setOnInsert["is_active"] = true
+	}
+	update["$setOnInsert"] = setOnInsert
+
+	var out dao.Product
+	err := r.productCol.FindOneAndUpdate(ctx, filter, update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&out)
+	return out, err
+}
+
+func (r *CatalogRepositoryImpl) Migrate(clientUUID string, dryRun bool) (dto.CatalogMigrateResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	res := dto.CatalogMigrateResult{DryRun: dryRun, Conflicts: []dto.CatalogMigrateConflict{}}
+
+	branchUUIDs, err := r.link.branchesOf(ctx, clientUUID)
+	if err != nil || len(branchUUIDs) == 0 {
+		return res, err
+	}
+
+	// master yang sudah ada (migrasi boleh diulang)
+	cur, err := r.catalogCol.Find(ctx, bson.M{"client_uuid": clientUUID})
+	if err != nil {
+		return res, err
+	}
+	var existing []dao.CatalogProduct
+	if err := cur.All(ctx, &existing); err != nil {
+		return res, err
+	}
+	byUUID := map[string]*dao.CatalogProduct{}
+	bySKU := map[string]*dao.CatalogProduct{}
+	for i := range existing {
+		c := &existing[i]
+		byUUID[c.UUID] = c
+		if c.SKU != "" {
+			bySKU[c.SKU] = c
+		}
+	}
+
+	// terbaru dulu: produk yang paling baru diubah jadi master untuk SKU-nya
+	cur, err = r.productCol.Find(ctx, bson.M{"branch_uuid": bson.M{"$in": branchUUIDs}},
+		options.Find().SetSort(bson.D{{Key: "updated_at", Value: -1}}))
+	if err != nil {
+		return res, err
+	}
+	var products []dao.Product
+	if err := cur.All(ctx, &products); err != nil {
+		return res, err
+	}
+	res.Products = len(products)
+
+	conflict := func(p dao.Product, reason string) {
+		res.Conflicts = append(res.Conflicts, dto.CatalogMigrateConflict{
+			SKU: p.SKU, BranchUUID: p.BranchUUID, ProductUUID: p.UUID, Name: p.Name, Reason: reason,
+		})
+	}
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+	touched := map[string]bool{}
+	inBranch := map[string]bool{}
+
+	for _, p := range products {
+		if _, ok := byUUID[p.CatalogUUID]; ok && p.CatalogUUID != "" {
+			res.AlreadyLinked++
+			inBranch[p.BranchUUID+"|"+p.CatalogUUID] = true
+			continue
+		}
+
+		cat := bySKU[p.SKU]
+		if p.SKU == "" {
+			cat = nil
+		}
+		if cat == nil {
+			c := dao.CatalogProduct{
+				BaseModel: dao.BaseModel{
+					UUID:         helpers.GenerateUUID(),
+					CreatedAt:    now,
+					CreatedAtStr: nowStr,
+					UpdatedAt:    now.Unix(),
+					UpdatedAtStr: nowStr,
+				},
+				ClientUUID:  clientUUID,
+				SKU:         p.SKU,
+				Barcode:     p.Barcode,
+				PLU:         p.PLU,
+				Name:        p.Name,
+				Description: p.Description,
+				BaseUnit:    p.BaseUnit,
+				Units:       p.Units,
+				Image:       p.Image,
+				Price:       p.Price,
+
+				CategoryUUID: p.CategoryUUID,
+				CategoryPath: p.CategoryPath,
+				BrandUUID:    p.BrandUUID,
+				BrandName:    p.BrandName,
+				Tags:         p.Tags,
+				CreatedBy:    p.CreatedBy,
+			}
+			if !dryRun {
+				if _, err := r.catalogCol.InsertOne(ctx, c); err != nil {
+					return res, err
+				}
+			}
+			res.CatalogCreated++
+			cat = &c
+			byUUID[c.UUID] = cat
+			if c.SKU != "" {
+				bySKU[c.SKU] = cat
+			}
+		} else {
+			// base unit beda => stock tidak sebanding, jangan digabung
+			if !strings.EqualFold(cat.BaseUnit, p.BaseUnit) {
+				conflict(p, fmt.Sprintf("base_unit %s != %s (master), not linked", p.BaseUnit, cat.BaseUnit))
+				continue
+			}
+			if diff := masterDiff(*cat, p); len(diff) > 0 {
+				conflict(p, strings.Join(diff, ", ")+" differ from master, overwritten")
+			}
+		}
+
+		key := p.BranchUUID + "|" + cat.UUID
+		if inBranch[key] {
+			conflict(p, "duplicate sku in the same branch, linked to the same master")
+		}
+		inBranch[key] = true
+
+		set := bson.M{"catalog_uuid": cat.UUID}
+		if p.Price != cat.Price {
+			set["price_override"] = p.Price
+			res.PriceOverrides++
+		}
+		if !dryRun {
+			if _, err := r.productCol.UpdateOne(ctx, bson.M{"uuid": p.UUID}, bson.M{"$set": set}); err != nil {
+				return res, err
+			}
+		}
+		res.Linked++
+		touched[cat.UUID] = true
+	}
+
+	if !dryRun {
+		for uuid := range touched {
+			if err := r.link.propagate(ctx, *byUUID[uuid]); err != nil {
+				return res, err
+			}
+		}
+	}
+	return res, nil
+}
+
+func (r *CatalogRepositoryImpl) Variants(parentUUIDs ...string) ([]dao.CatalogProduct, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cur, err := r.catalogCol.Find(ctx, bson.M{"parent_uuid": bson.M{"$in": parentUUIDs}},
+		options.Find().SetSort(bson.D{{Key: "sku", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	out := []dao.CatalogProduct{}
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *CatalogRepositoryImpl) SetVariantAttributes(parentUUID string, attrs []dao.VariantAttribute) (dao.CatalogProduct, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// parent tidak boleh masih menyimpan stock: stock harus dipindah ke varian dulu
+	n, err := r.productCol.CountDocuments(ctx, bson.M{"catalog_uuid": parentUUID, "stock": bson.M{"$ne": 0}})
+	if err != nil {
+		return dao.CatalogProduct{}, err
+	}
+	if n > 0 {
+		return dao.CatalogProduct{}, fmt.Errorf("product still has stock in %d branches, move it to a variant first", n)
+	}
+
+	now := time.Now()
+	var out dao.CatalogProduct
+	err = r.catalogCol.FindOneAndUpdate(ctx, bson.M{"uuid": parentUUID}, bson.M{"$set": bson.M{
+		"has_variants":       true,
+		"variant_attributes": attrs,
+		"updated_at":         now.Unix(),
+		"updated_at_str":     now.Format(time.RFC3339),
+	}}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&out)
+	if err != nil {
+		return dao.CatalogProduct{}, err
+	}
+	_, err = r.productCol.UpdateMany(ctx, bson.M{"catalog_uuid": parentUUID}, bson.M{"$set": bson.M{"is_active": false}})
+	return out, err
+}
+
+func (r *CatalogRepositoryImpl) FindByBarcode(clientUUID, barcode string) (dao.CatalogProduct, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.CatalogProduct
+	err := r.catalogCol.FindOne(ctx, bson.M{"client_uuid": clientUUID, "barcode": barcode}).Decode(&out)
+	return out, err
+}
+
+func (r *CatalogRepositoryImpl) WithoutBarcode(clientUUID string, uuids []string, limit int64) ([]dao.CatalogProduct, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"client_uuid":  clientUUID,
+		"has_variants": bson.M{"$ne": true},
+		"barcode":      bson.M{"$in": bson.A{"", nil}},
+	}
+	if len(uuids) > 0 {
+		filter["uuid"] = bson.M{"$in": uuids}
+	}
+	cur, err := r.catalogCol.Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "sku", Value: 1}, {Key: "name", Value: 1}}).
+		SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	out := []dao.CatalogProduct{}
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *CatalogRepositoryImpl) Tags(clientUUID string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	vals, err := r.catalogCol.Distinct(ctx, "tags", bson.M{"client_uuid": clientUUID})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(vals))
+	for _, v := range vals {
+		if t, ok := v.(string); ok && t != "" {
+			out = append(out, t)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func masterDiff(c dao.CatalogProduct, p dao.Product) []string {
+	var out []string
+	if c.Name != p.Name {
+		out = append(out, "name")
+	}
+	if c.Barcode != p.Barcode {
+		out = append(out, "barcode")
+	}
+	if !sameUnits(c.Units, p.Units) {
+		out = append(out, "units")
+	}
+	return out
+}
+
+func sameUnits(a, b []dao.ProductUnit) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !strings.EqualFold(a[i].Name, b[i].Name) || a[i].ConversionToBase != b[i].ConversionToBase || a[i].Price != b[i].Price {
+			return false
+		}
+	}
+	return true
+}
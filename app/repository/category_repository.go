@@ -0,0 +1,269 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/helpers"
+)
+
+type CategoryRepository interface {
+	List(clientUUID string) ([]dao.ProductCategory, error)
+	Detail(uuid string) (dao.ProductCategory, error)
+	// Save: rename / pindah parent ikut mengubah path semua turunan + produk yang memakainya
+	Save(data *dao.ProductCategory) (dao.ProductCategory, error)
+	// Delete: hanya kategori tanpa sub-kategori & tanpa produk
+	Delete(uuid string) error
+	// EnsurePath: "Minuman > Kopi" => kategori leaf, node yang belum ada dibuat
+	EnsurePath(clientUUID, path, createdBy string) (dao.ProductCategory, error)
+	// SubtreeUUIDs: uuid kategori + semua turunannya
+	SubtreeUUIDs(uuid string) ([]string, error)
+}
+
+type CategoryRepositoryImpl struct {
+	col        *mongo.Collection
+	catalogCol *mongo.Collection
+	productCol *mongo.Collection
+}
+
+func CategoryRepositoryInit(mongoClient *mongo.Client) *CategoryRepositoryImpl {
+	dbName := helpers.ProvideDBName()
+	db := mongoClient.Database(dbName)
+	return &CategoryRepositoryImpl{
+		col:        db.Collection("product_categories"),
+		catalogCol: db.Collection("catalog_products"),
+		productCol: db.Collection("products"),
+	}
+}
+
+// nameFilter: nama sama persis (case-insensitive) dalam 1 client
+func nameFilter(clientUUID, name string) bson.M {
+	return bson.M{
+		"client_uuid": clientUUID,
+		"name":        bson.M{"$regex": "^" + regexp.QuoteMeta(name) + "$", "$options": "i"},
+	}
+}
+
+// siblingFilter: nama kategori unik di bawah parent yang sama
+func siblingFilter(clientUUID, parentUUID, name string) bson.M {
+	f := nameFilter(clientUUID, name)
+	f["parent_uuid"] = parentUUID
+	return f
+}
+
+func (r *CategoryRepositoryImpl) List(clientUUID string) ([]dao.ProductCategory, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "level", Value: 1}, {Key: "sort_order", Value: 1}, {Key: "name", Value: 1}})
+	cur, err := r.col.Find(ctx, bson.M{"client_uuid": clientUUID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	out := []dao.ProductCategory{}
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *CategoryRepositoryImpl) Detail(uuid string) (dao.ProductCategory, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.ProductCategory
+	err := r.col.FindOne(ctx, bson.M{"uuid": uuid}).Decode(&out)
+	return out, err
+}
+
+func (r *CategoryRepositoryImpl) Save(data *dao.ProductCategory) (dao.ProductCategory, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	return r.save(ctx, data)
+}
+
+func (r *CategoryRepositoryImpl) save(ctx context.Context, data *dao.ProductCategory) (dao.ProductCategory, error) {
+	data.Name = strings.TrimSpace(data.Name)
+	if data.ClientUUID == "" {
+		return dao.ProductCategory{}, errors.New("client_uuid required")
+	}
+	if data.Name == "" {
+		return dao.ProductCategory{}, errors.New("name required")
+	}
+	if strings.Contains(data.Name, ">") {
+		return dao.ProductCategory{}, errors.New("name must not contain '>'")
+	}
+
+	data.Path = data.Name
+	data.Ancestors = []string{}
+	if data.ParentUUID != "" {
+		var parent dao.ProductCategory
+		if err := r.col.FindOne(ctx, bson.M{"uuid": data.ParentUUID, "client_uuid": data.ClientUUID}).Decode(&parent); err != nil {
+			return dao.ProductCategory{}, errors.New("parent category not found")
+		}
+		if data.UUID != "" && (parent.UUID == data.UUID || slices.Contains(parent.Ancestors, data.UUID)) {
+			return dao.ProductCategory{}, errors.New("category cannot be moved under itself")
+		}
+		data.Path = parent.Path + dao.CategoryPathSep + data.Name
+		data.Ancestors = append(append([]string{}, parent.Ancestors...), parent.UUID)
+	}
+	data.Level = len(data.Ancestors)
+
+	dup := siblingFilter(data.ClientUUID, data.ParentUUID, data.Name)
+	dup["uuid"] = bson.M{"$ne": data.UUID}
+	if n, err := r.col.CountDocuments(ctx, dup); err != nil {
+		return dao.ProductCategory{}, err
+	} else if n > 0 {
+		return dao.ProductCategory{}, errors.New("category " + data.Path + " already exists")
+	}
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+	data.UpdatedAt = now.Unix()
+	data.UpdatedAtStr = nowStr
+
+	if data.UUID == "" {
+		data.UUID = helpers.GenerateUUID()
+		data.CreatedAt = now
+		data.CreatedAtStr = nowStr
+		if _, err := r.col.InsertOne(ctx, data); err != nil {
+			return dao.ProductCategory{}, err
+		}
+		return *data, nil
+	}
+
+	var old dao.ProductCategory
+	err := r.col.FindOneAndUpdate(ctx, bson.M{"uuid": data.UUID, "client_uuid": data.ClientUUID}, bson.M{"$set": bson.M{
+		"parent_uuid":    data.ParentUUID,
+		"name":           data.Name,
+		"path":           data.Path,
+		"ancestors":      data.Ancestors,
+		"level":          data.Level,
+		"sort_order":     data.SortOrder,
+		"updated_at":     data.UpdatedAt,
+		"updated_at_str": data.UpdatedAtStr,
+	}}).Decode(&old)
+	if err != nil {
+		return dao.ProductCategory{}, err
+	}
+	if old.Path != data.Path {
+		if err := r.reparent(ctx, old, *data); err != nil {
+			return dao.ProductCategory{}, err
+		}
+	}
+	return r.Detail(data.UUID)
+}
+
+// reparent: turunan ikut path & ancestors baru, category_path produk disinkron
+func (r *CategoryRepositoryImpl) reparent(ctx context.Context, old, cur dao.ProductCategory) error {
+	changed := map[string]string{cur.UUID: cur.Path}
+
+	cursor, err := r.col.Find(ctx, bson.M{"ancestors": cur.UUID})
+	if err != nil {
+		return err
+	}
+	var children []dao.ProductCategory
+	if err := cursor.All(ctx, &children); err != nil {
+		return err
+	}
+	for _, c := range children {
+		idx := slices.Index(c.Ancestors, cur.UUID)
+		ancestors := append(append(append([]string{}, cur.Ancestors...), cur.UUID), c.Ancestors[idx+1:]...)
+		path := cur.Path + strings.TrimPrefix(c.Path, old.Path)
+		if _, err := r.col.UpdateOne(ctx, bson.M{"uuid": c.UUID}, bson.M{"$set": bson.M{
+			"ancestors": ancestors,
+			"path":      path,
+			"level":     len(ancestors),
+		}}); err != nil {
+			return err
+		}
+		changed[c.UUID] = path
+	}
+
+	for uuid, path := range changed {
+		for _, col := range []*mongo.Collection{r.catalogCol, r.productCol} {
+			if _, err := col.UpdateMany(ctx, bson.M{"category_uuid": uuid}, bson.M{"$set": bson.M{"category_path": path}}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *CategoryRepositoryImpl) Delete(uuid string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if n, err := r.col.CountDocuments(ctx, bson.M{"parent_uuid": uuid}); err != nil {
+		return err
+	} else if n > 0 {
+		return errors.New("category still has sub-categories")
+	}
+	if n, err := r.catalogCol.CountDocuments(ctx, bson.M{"category_uuid": uuid}); err != nil {
+		return err
+	} else if n > 0 {
+		return fmt.Errorf("category still used by %d products", n)
+	}
+	_, err := r.col.DeleteOne(ctx, bson.M{"uuid": uuid})
+	return err
+}
+
+func (r *CategoryRepositoryImpl) EnsurePath(clientUUID, path, createdBy string) (dao.ProductCategory, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var node dao.ProductCategory
+	for _, name := range strings.Split(path, ">") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		var next dao.ProductCategory
+		err := r.col.FindOne(ctx, siblingFilter(clientUUID, node.UUID, name)).Decode(&next)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			next, err = r.save(ctx, &dao.ProductCategory{ClientUUID: clientUUID, ParentUUID: node.UUID, Name: name, CreatedBy: createdBy})
+		}
+		if err != nil {
+			return dao.ProductCategory{}, err
+		}
+		node = next
+	}
+	if node.UUID == "" {
+		return dao.ProductCategory{}, errors.New("category path empty")
+	}
+	return node, nil
+}
+
+func (r *CategoryRepositoryImpl) SubtreeUUIDs(uuid string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cur, err := r.col.Find(ctx, bson.M{"$or": bson.A{bson.M{"uuid": uuid}, bson.M{"ancestors": uuid}}},
+		options.Find().SetProjection(bson.M{"uuid": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var rows []dao.ProductCategory
+	if err := cur.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(rows))
+	for _, c := range rows {
+		out = append(out, c.UUID)
+	}
+	return out, nil
+}
@@ -0,0 +1,418 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+)
+
+type CustomerRepository interface {
+	SaveCustomer(data *dao.Customer) (dao.Customer, error)
+	DetailCustomer(uuid string) (dao.Customer, error)
+	ListCustomer(req *dto.FilterRequest) ([]dao.Customer, error)
+	DeleteCustomer(uuid string) error
+	FindCustomer(clientUUID, phone, memberCode string) (dao.Customer, error)
+
+	AdjustPoints(customerUUID string, delta int64, allowNegative bool) (int64, error)
+	RecordVisit(customerUUID string, spent float64, visits int64) (dao.Customer, error)
+	SetTier(customerUUID string, tier dao.CustomerTier) error
+	AdjustCredit(customerUUID string, delta float64) (float64, error)
+
+	InsertPointLedger(l *dao.CustomerPointLedger) (dao.CustomerPointLedger, error)
+	ListPointLedger(req *dto.FilterRequest) ([]dao.CustomerPointLedger, error)
+	ListPointLedgerByTransaction(trxUUID string) ([]dao.CustomerPointLedger, error)
+
+	GetLoyaltyRule(clientUUID string) (dao.LoyaltyRule, error)
+	SaveLoyaltyRule(rule *dao.LoyaltyRule) (dao.LoyaltyRule, error)
+}
+
+type CustomerRepositoryImpl struct {
+	customerCol *mongo.Collection
+	ledgerCol   *mongo.Collection
+	ruleCol     *mongo.Collection
+}
+
+func CustomerRepositoryInit(mongoClient *mongo.Client) *CustomerRepositoryImpl {
+	dbName := helpers.ProvideDBName()
+	db := mongoClient.Database(dbName)
+	return &CustomerRepositoryImpl{
+		customerCol: db.Collection("customers"),
+		ledgerCol:   db.Collection("customer_point_ledgers"),
+		ruleCol:     db.Collection("loyalty_rules"),
+	}
+}
+
+func (r *CustomerRepositoryImpl) SaveCustomer(data *dao.Customer) (dao.Customer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if data.ClientUUID == "" {
+		return dao.Customer{}, errors.New("client_uuid required")
+	}
+	if data.Name == "" {
+		return dao.Customer{}, errors.New("name required")
+	}
+
+	filter := bson.M{}
+	switch {
+	case data.UUID != "":
+		filter["uuid"] = data.UUID
+	case data.Phone != "":
+		filter["client_uuid"] = data.ClientUUID
+		filter["phone"] = data.Phone
+	default:
+		filter["client_uuid"] = data.ClientUUID
+		filter["name"] = data.Name
+		filter["phone"] = ""
+	}
+
+	// phone & member_code harus unik per client
+	if data.Phone != "" || data.MemberCode != "" {
+		or := []bson.M{}
+		if data.Phone != "" {
+			or = append(or, bson.M{"phone": data.Phone})
+		}
+		if data.MemberCode != "" {
+			or = append(or, bson.M{"member_code": data.MemberCode})
+		}
+		dupFilter := bson.M{"client_uuid": data.ClientUUID, "$or": or}
+		if data.UUID != "" {
+			dupFilter["uuid"] = bson.M{"$ne": data.UUID}
+		} else if data.Phone != "" {
+			dupFilter["phone"] = bson.M{"$ne": data.Phone}
+		}
+		n, err := r.customerCol.CountDocuments(ctx, dupFilter)
+		if err != nil {
+			return dao.Customer{}, err
+		}
+		if n > 0 {
+			return dao.Customer{}, errors.New("phone or member_code already used by another customer")
+		}
+	}
+
+	if data.Tier == "" {
+		data.Tier = dao.CustomerTierRegular
+	}
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+
+	newUUID := data.UUID
+	if newUUID == "" {
+		newUUID = helpers.GenerateUUID()
+	}
+
+	// points / total_spent / total_visits TIDAK di-set dari upsert, hanya lewat transaksi
+	update := bson.M{
+		"$set": bson.M{
+			"client_uuid":      data.ClientUUID,
+			"name":             data.Name,
+			"phone":            data.Phone,
+			"email":            data.Email,
+			"member_code":      data.MemberCode,
+			"tier":             data.Tier,
+			"credit_limit":     data.CreditLimit,
+			"credit_term_days": data.CreditTermDays,
+			"note":             data.Note,
+			"is_active":        data.IsActive,
+			"updated_at":       now.Unix(),
+			"updated_at_str":   nowStr,
+		},
+		"$setOnInsert": bson.M{
+			"uuid":           newUUID,
+			"points":         int64(0),
+			"total_spent":    float64(0),
+			"total_visits":   int64(0),
+			"last_visit_at":  int64(0),
+			"credit_balance": float64(0),
+			"created_by":     data.CreatedBy,
+			"created_at":     now,
+			"created_at_str": nowStr,
+		},
+	}
+
+	opts := options.Update().SetUpsert(true)
+	if _, err := r.customerCol.UpdateOne(ctx, filter, update, opts); err != nil {
+		return dao.Customer{}, err
+	}
+
+	var out dao.Customer
+	if err := r.customerCol.FindOne(ctx, filter).Decode(&out); err != nil {
+		return dao.Customer{}, err
+	}
+	return out, nil
+}
+
+func (r *CustomerRepositoryImpl) DetailCustomer(uuid string) (dao.Customer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.Customer
+	err := r.customerCol.FindOne(ctx, bson.M{"uuid": uuid}).Decode(&out)
+	return out, err
+}
+
+func (r *CustomerRepositoryImpl) ListCustomer(req *dto.FilterRequest) ([]dao.Customer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := buildListFilter(req, "name", "phone", "email", "member_code")
+	cur, err := r.customerCol.Find(ctx, filter, buildListOptions(req))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var out []dao.Customer
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *CustomerRepositoryImpl) DeleteCustomer(uuid string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.customerCol.DeleteOne(ctx, bson.M{"uuid": uuid})
+	return err
+}
+
+// FindCustomer: lookup untuk kasir (by phone ATAU member_code / barcode kartu member)
+func (r *CustomerRepositoryImpl) FindCustomer(clientUUID, phone, memberCode string) (dao.Customer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if clientUUID == "" {
+		return dao.Customer{}, errors.New("client_uuid required")
+	}
+	or := []bson.M{}
+	if phone != "" {
+		or = append(or, bson.M{"phone": phone})
+	}
+	if memberCode != "" {
+		or = append(or, bson.M{"member_code": memberCode})
+	}
+	if len(or) == 0 {
+		return dao.Customer{}, errors.New("phone or member_code required")
+	}
+
+	var out dao.Customer
+	err := r.customerCol.FindOne(ctx, bson.M{
+		"client_uuid": clientUUID,
+		"is_active":   true,
+		"$or":         or,
+	}).Decode(&out)
+	return out, err
+}
+
+// AdjustPoints: $inc atomic. Untuk delta negatif (redeem) default-nya dijaga supaya saldo tidak minus.
+func (r *CustomerRepositoryImpl) AdjustPoints(customerUUID string, delta int64, allowNegative bool) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if customerUUID == "" {
+		return 0, errors.New("customer_uuid required")
+	}
+	if delta == 0 {
+		c, err := r.DetailCustomer(customerUUID)
+		return c.Points, err
+	}
+
+	filter := bson.M{"uuid": customerUUID}
+	if delta < 0 && !allowNegative {
+		filter["points"] = bson.M{"$gte": -delta}
+	}
+
+	now := time.Now()
+	var out dao.Customer
+	err := r.customerCol.FindOneAndUpdate(ctx, filter, bson.M{
+		"$inc": bson.M{"points": delta},
+		"$set": bson.M{"updated_at": now.Unix(), "updated_at_str": now.Format(time.RFC3339)},
+	}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&out)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 0, errors.New("points not enough or customer not found")
+		}
+		return 0, err
+	}
+	return out.Points, nil
+}
+
+// RecordVisit: akumulasi belanja (spent bisa negatif untuk void, visits -1)
+func (r *CustomerRepositoryImpl) RecordVisit(customerUUID string, spent float64, visits int64) (dao.Customer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	set := bson.M{"updated_at": now.Unix(), "updated_at_str": now.Format(time.RFC3339)}
+	if visits > 0 {
+		set["last_visit_at"] = now.Unix()
+	}
+
+	var out dao.Customer
+	err := r.customerCol.FindOneAndUpdate(ctx, bson.M{"uuid": customerUUID}, bson.M{
+		"$inc": bson.M{"total_spent": spent, "total_visits": visits},
+		"$set": set,
+	}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&out)
+	return out, err
+}
+
+func (r *CustomerRepositoryImpl) SetTier(customerUUID string, tier dao.CustomerTier) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.customerCol.UpdateOne(ctx, bson.M{"uuid": customerUUID}, bson.M{
+		"$set": bson.M{"tier": tier},
+	})
+	return err
+}
+
+// AdjustCredit: $inc credit_balance. Delta positif (kasbon baru) dijaga tidak melebihi credit_limit.
+func (r *CustomerRepositoryImpl) AdjustCredit(customerUUID string, delta float64) (float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if customerUUID == "" {
+		return 0, errors.New("customer_uuid required")
+	}
+
+	filter := bson.M{"uuid": customerUUID}
+	if delta > 0 {
+		filter["$expr"] = bson.M{
+			"$lte": bson.A{
+				bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$credit_balance", 0}}, delta}},
+				bson.M{"$ifNull": bson.A{"$credit_limit", 0}},
+			},
+		}
+	}
+
+	now := time.Now()
+	var out dao.Customer
+	err := r.customerCol.FindOneAndUpdate(ctx, filter, bson.M{
+		"$inc": bson.M{"credit_balance": delta},
+		"$set": bson.M{"updated_at": now.Unix(), "updated_at_str": now.Format(time.RFC3339)},
+	}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&out)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 0, errors.New("credit limit exceeded or customer not found")
+		}
+		return 0, err
+	}
+	return out.CreditBalance, nil
+}
+
+func (r *CustomerRepositoryImpl) InsertPointLedger(l *dao.CustomerPointLedger) (dao.CustomerPointLedger, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if l.CustomerUUID == "" {
+		return dao.CustomerPointLedger{}, errors.New("customer_uuid required")
+	}
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+	l.UUID = helpers.GenerateUUID()
+	l.CreatedAt = now
+	l.CreatedAtStr = nowStr
+	l.UpdatedAt = now.Unix()
+	l.UpdatedAtStr = nowStr
+
+	if _, err := r.ledgerCol.InsertOne(ctx, l); err != nil {
+		return dao.CustomerPointLedger{}, err
+	}
+	return *l, nil
+}
+
+func (r *CustomerRepositoryImpl) ListPointLedger(req *dto.FilterRequest) ([]dao.CustomerPointLedger, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := buildListFilter(req, "receipt_no", "type", "note")
+	cur, err := r.ledgerCol.Find(ctx, filter, buildListOptions(req))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var out []dao.CustomerPointLedger
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *CustomerRepositoryImpl) ListPointLedgerByTransaction(trxUUID string) ([]dao.CustomerPointLedger, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cur, err := r.ledgerCol.Find(ctx, bson.M{"transaction_uuid": trxUUID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var out []dao.CustomerPointLedger
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *CustomerRepositoryImpl) GetLoyaltyRule(clientUUID string) (dao.LoyaltyRule, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.LoyaltyRule
+	err := r.ruleCol.FindOne(ctx, bson.M{"client_uuid": clientUUID}).Decode(&out)
+	return out, err
+}
+
+func (r *CustomerRepositoryImpl) SaveLoyaltyRule(rule *dao.LoyaltyRule) (dao.LoyaltyRule, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if rule.ClientUUID == "" {
+		return dao.LoyaltyRule{}, errors.New("client_uuid required")
+	}
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+	filter := bson.M{"client_uuid": rule.ClientUUID}
+
+	update := bson.M{
+		"$set": bson.M{
+			"is_active":          rule.IsActive,
+			"earn_spend_amount":  rule.EarnSpendAmount,
+			"earn_points":        rule.EarnPoints,
+			"min_spend_to_earn":  rule.MinSpendToEarn,
+			"tier_multipliers":   rule.TierMultipliers,
+			"tier_thresholds":    rule.TierThresholds,
+			"redeem_point_value": rule.RedeemPointValue,
+			"min_redeem_points":  rule.MinRedeemPoints,
+			"max_redeem_percent": rule.MaxRedeemPercent,
+			"updated_by":         rule.UpdatedBy,
+			"updated_at":         now.Unix(),
+			"updated_at_str":     nowStr,
+		},
+		"$setOnInsert": bson.M{
+			"uuid":           helpers.GenerateUUID(),
+			"client_uuid":    rule.ClientUUID,
+			"created_at":     now,
+			"created_at_str": nowStr,
+		},
+	}
+
+	if _, err := r.ruleCol.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+		return dao.LoyaltyRule{}, err
+	}
+	return r.GetLoyaltyRule(rule.ClientUUID)
+}
@@ -113,15 +113,22 @@ func (r *DashboardRepositoryImpl) GetCompanyBranchUUIDs(clientUUID string) ([]st
 // ----------------------------------------------------
 // Products
 // ----------------------------------------------------
+
+// lowStockFilter: stock <= min_stock kalau diset per produk, selain itu stock <= threshold global
+func lowStockFilter(threshold int64) bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"min_stock": bson.M{"$gt": 0}, "$expr": bson.M{"$lte": bson.A{"$stock", "$min_stock"}}},
+		bson.M{"min_stock": bson.M{"$not": bson.M{"$gt": 0}}, "stock": bson.M{"$lte": threshold}},
+	}}
+}
+
 func (r *DashboardRepositoryImpl) FindLowStockProducts(branchUUIDs []string, threshold int) ([]dao.Product, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
 	defer cancel()
 
-	filter := bson.M{
-		"branch_uuid": bson.M{"$in": branchUUIDs},
-		"is_active":   true,
-		"stock":       bson.M{"$lte": threshold},
-	}
+	filter := lowStockFilter(int64(threshold))
+	filter["branch_uuid"] = bson.M{"$in": branchUUIDs}
+	filter["is_active"] = true
 
 	cur, err := r.productCol.Find(ctx, filter)
 	if err != nil {
@@ -168,10 +175,8 @@ func (r *DashboardRepositoryImpl) CountLowStockSKU(branchUUIDs []string, thresho
 	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
 	defer cancel()
 
-	filter := bson.M{
-		"is_active": true,
-		"stock":     bson.M{"$lte": threshold},
-	}
+	filter := lowStockFilter(threshold)
+	filter["is_active"] = true
 	if len(branchUUIDs) > 0 {
 		filter["branch_uuid"] = bson.M{"$in": branchUUIDs}
 	}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/helpers"
+)
+
+type EmailOutboxRepository interface {
+	Insert(o *dao.EmailOutbox) (dao.EmailOutbox, error)
+	ListByRef(ref string) ([]dao.EmailOutbox, error)
+
+	// ClaimNext: ambil 1 email yang siap dikirim (PENDING & jatuh waktu, atau SENDING yang lock-nya basi)
+	ClaimNext(now int64, staleBefore int64) (dao.EmailOutbox, error)
+	MarkSent(uuid string) (dao.EmailOutbox, error)
+	MarkRetry(uuid string, errMsg string, nextAttemptAt int64, failed bool) (dao.EmailOutbox, error)
+}
+
+type EmailOutboxRepositoryImpl struct {
+	col *mongo.Collection
+}
+
+func EmailOutboxRepositoryInit(mongoClient *mongo.Client) *EmailOutboxRepositoryImpl {
+	dbName := helpers.ProvideDBName()
+	return &EmailOutboxRepositoryImpl{
+		col: mongoClient.Database(dbName).Collection("email_outbox"),
+	}
+}
+
+func (r *EmailOutboxRepositoryImpl) Insert(o *dao.EmailOutbox) (dao.EmailOutbox, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	if o.To == "" {
+		return dao.EmailOutbox{}, errors.New("recipient required")
+	}
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+
+	if o.UUID == "" {
+		o.UUID = helpers.GenerateUUID()
+	}
+	o.CreatedAt = now
+	o.CreatedAtStr = nowStr
+	o.UpdatedAt = now.Unix()
+	o.UpdatedAtStr = nowStr
+
+	o.Status = dao.EmailPending
+	o.Attempts = 0
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = 5
+	}
+	if o.NextAttemptAt == 0 {
+		o.NextAttemptAt = now.Unix()
+	}
+	if o.Attachments == nil {
+		o.Attachments = []dao.EmailOutboxAttachment{}
+	}
+
+	if _, err := r.col.InsertOne(ctx, o); err != nil {
+		return dao.EmailOutbox{}, err
+	}
+	return *o, nil
+}
+
+func (r *EmailOutboxRepositoryImpl) ListByRef(ref string) ([]dao.EmailOutbox, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cur, err := r.col.Find(ctx, bson.M{"ref": ref},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetProjection(bson.M{"body": 0, "attachments.data": 0}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var out []dao.EmailOutbox
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *EmailOutboxRepositoryImpl) ClaimNext(now int64, staleBefore int64) (dao.EmailOutbox, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.EmailOutbox
+	err := r.col.FindOneAndUpdate(ctx, bson.M{
+		"$or": bson.A{
+			bson.M{"status": dao.EmailPending, "next_attempt_at": bson.M{"$lte": now}},
+			bson.M{"status": dao.EmailSending, "locked_at": bson.M{"$lt": staleBefore}},
+		},
+	}, bson.M{
+		"$set": bson.M{"status": dao.EmailSending, "locked_at": now},
+		"$inc": bson.M{"attempts": 1},
+	}, options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)).Decode(&out)
+	return out, err
+}
+
+func (r *EmailOutboxRepositoryImpl) MarkSent(uuid string) (dao.EmailOutbox, error) {
+	now := time.Now()
+	return r.update(uuid, bson.M{
+		"status":         dao.EmailSent,
+		"last_error":     "",
+		"sent_at":        now.Unix(),
+		"sent_at_str":    now.Format(time.RFC3339),
+		"updated_at":     now.Unix(),
+		"updated_at_str": now.Format(time.RFC3339),
+	})
+}
+
+// MarkRetry: gagal kirim. failed=true => berhenti retry (FAILED)
+func (r *EmailOutboxRepositoryImpl) MarkRetry(uuid string, errMsg string, nextAttemptAt int64, failed bool) (dao.EmailOutbox, error) {
+	now := time.Now()
+	status := dao.EmailPending
+	if failed {
+		status = dao.EmailFailed
+	}
+	return r.update(uuid, bson.M{
+		"status":          status,
+		"last_error":      errMsg,
+		"next_attempt_at": nextAttemptAt,
+		"locked_at":       0,
+		"updated_at":      now.Unix(),
+		"updated_at_str":  now.Format(time.RFC3339),
+	})
+}
+
+func (r *EmailOutboxRepositoryImpl) update(uuid string, set bson.M) (dao.EmailOutbox, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.EmailOutbox
+	err := r.col.FindOneAndUpdate(ctx, bson.M{"uuid": uuid}, bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After).SetProjection(bson.M{"body": 0, "attachments.data": 0})).Decode(&out)
+	return out, err
+}
@@ -19,6 +19,8 @@ type POSTransactionRepository interface {
 	Detail(uuid string) (dao.POSTransaction, error)
 	List(req *dto.FilterRequest) ([]dao.POSTransaction, error)
 	UpdateStatus(uuid string, status string, extra bson.M) (dao.POSTransaction, error)
+	MarkReprinted(uuid string, by string) (dao.POSTransaction, error)
+	SetReceiptEmail(uuid string, d dao.ReceiptEmailDelivery) error
 }
 
 type POSTransactionRepositoryImpl struct {
@@ -181,3 +183,30 @@ func (r *POSTransactionRepositoryImpl) UpdateStatus(uuid string, status string,
 	}
 	return out, nil
 }
+
+// MarkReprinted: catat cetak ulang struk (counter + siapa/kapan terakhir)
+func (r *POSTransactionRepositoryImpl) MarkReprinted(uuid string, by string) (dao.POSTransaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	var out dao.POSTransaction
+	err := r.col.FindOneAndUpdate(ctx, bson.M{"uuid": uuid}, bson.M{
+		"$inc": bson.M{"reprint_count": 1},
+		"$set": bson.M{
+			"last_reprinted_by":     by,
+			"last_reprinted_at":     now.Unix(),
+			"last_reprinted_at_str": now.Format(time.RFC3339),
+		},
+	}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&out)
+	return out, err
+}
+
+func (r *POSTransactionRepositoryImpl) SetReceiptEmail(uuid string, d dao.ReceiptEmailDelivery) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	d.UpdatedAt = time.Now().Unix()
+	_, err := r.col.UpdateOne(ctx, bson.M{"uuid": uuid}, bson.M{"$set": bson.M{"receipt_email": d}})
+	return err
+}
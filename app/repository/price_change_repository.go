@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+)
+
+type PriceChangeRepository interface {
+	Insert(pc *dao.PriceChange) (dao.PriceChange, error)
+	Detail(uuid string) (dao.PriceChange, error)
+	List(req *dto.FilterRequest) ([]dao.PriceChange, error)
+	// Decide: PENDING_APPROVAL -> SCHEDULED/REJECTED (atomic, sekali saja)
+	Decide(uuid string, status dao.PriceChangeStatus, by, byName, note string) (dao.PriceChange, error)
+	// Cancel: PENDING_APPROVAL / SCHEDULED yang belum diproses -> CANCELLED
+	Cancel(uuid string, note string) (dao.PriceChange, error)
+
+	// ClaimDue: 1 change SCHEDULED yang effective_from sudah lewat (lock basi diulang), paling awal dulu.
+	// catalogUUID kosong = semua produk (worker), isi = 1 produk (baru di-approve)
+	ClaimDue(now int64, staleBefore int64, catalogUUID string) (dao.PriceChange, error)
+	// LatestDue: change SCHEDULED / APPLIED terakhir yang effective_from <= now (read-only, untuk POS).
+	// branchUUID kosong = scope CLIENT, isi = scope BRANCH branch tsb
+	LatestDue(catalogUUID, branchUUID string, now int64) (dao.PriceChange, error)
+	// Finish: APPLIED (old_price = harga tepat sebelum diterapkan) / FAILED
+	Finish(uuid string, status dao.PriceChangeStatus, oldPrice float64, lastErr string) (dao.PriceChange, error)
+
+	InsertHistory(h *dao.PriceHistory) error
+	ListHistory(req *dto.FilterRequest) ([]dao.PriceHistory, error)
+}
+
+type PriceChangeRepositoryImpl struct {
+	col        *mongo.Collection
+	historyCol *mongo.Collection
+}
+
+func PriceChangeRepositoryInit(mongoClient *mongo.Client) *PriceChangeRepositoryImpl {
+	dbName := helpers.ProvideDBName()
+	db := mongoClient.Database(dbName)
+	return &PriceChangeRepositoryImpl{
+		col:        db.Collection("price_changes"),
+		historyCol: db.Collection("price_history"),
+	}
+}
+
+func (r *PriceChangeRepositoryImpl) Insert(pc *dao.PriceChange) (dao.PriceChange, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+	if pc.UUID == "" {
+		pc.UUID = helpers.GenerateUUID()
+	}
+	pc.CreatedAt = now
+	pc.CreatedAtStr = nowStr
+	pc.UpdatedAt = now.Unix()
+	pc.UpdatedAtStr = nowStr
+	pc.EffectiveFromStr = time.Unix(pc.EffectiveFrom, 0).Format(time.RFC3339)
+
+	if _, err := r.col.InsertOne(ctx, pc); err != nil {
+		return dao.PriceChange{}, err
+	}
+	return *pc, nil
+}
+
+func (r *PriceChangeRepositoryImpl) Detail(uuid string) (dao.PriceChange, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.PriceChange
+	err := r.col.FindOne(ctx, bson.M{"uuid": uuid}).Decode(&out)
+	return out, err
+}
+
+func (r *PriceChangeRepositoryImpl) List(req *dto.FilterRequest) ([]dao.PriceChange, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	filter := buildListFilter(req, "sku", "name", "requested_by_name", "approved_by_name")
+	cur, err := r.col.Find(ctx, filter, buildListOptions(req))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	out := []dao.PriceChange{}
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *PriceChangeRepositoryImpl) Decide(uuid string, status dao.PriceChangeStatus, by, byName, note string) (dao.PriceChange, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	var out dao.PriceChange
+	err := r.col.FindOneAndUpdate(ctx, bson.M{
+		"uuid":   uuid,
+		"status": dao.PriceChangePending,
+	}, bson.M{"$set": bson.M{
+		"status":           status,
+		"approved_by":      by,
+		"approved_by_name": byName,
+		"decision_note":    note,
+		"decided_at":       now.Unix(),
+		"decided_at_str":   now.Format(time.RFC3339),
+		"updated_at":       now.Unix(),
+		"updated_at_str":   now.Format(time.RFC3339),
+	}}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&out)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return dao.PriceChange{}, errors.New("price change not pending approval")
+	}
+	return out, err
+}
+
+func (r *PriceChangeRepositoryImpl) Cancel(uuid string, note string) (dao.PriceChange, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	var out dao.PriceChange
+	err := r.col.FindOneAndUpdate(ctx, bson.M{
+		"uuid":      uuid,
+		"status":    bson.M{"$in": bson.A{dao.PriceChangePending, dao.PriceChangeScheduled}},
+		"locked_at": bson.M{"$in": bson.A{0, nil}},
+	}, bson.M{"$set": bson.M{
+		"status":         dao.PriceChangeCancelled,
+		"decision_note":  note,
+		"updated_at":     now.Unix(),
+		"updated_at_str": now.Format(time.RFC3339),
+	}}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&out)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return dao.PriceChange{}, errors.New("price change already applied or closed")
+	}
+	return out, err
+}
+
+func (r *PriceChangeRepositoryImpl) ClaimDue(now int64, staleBefore int64, catalogUUID string) (dao.PriceChange, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"status":         dao.PriceChangeScheduled,
+		"effective_from": bson.M{"$lte": now},
+		"$or": bson.A{
+			bson.M{"locked_at": bson.M{"$in": bson.A{0, nil}}},
+			bson.M{"locked_at": bson.M{"$lt": staleBefore}},
+		},
+	}
+	if catalogUUID != "" {
+		filter["catalog_uuid"] = catalogUUID
+	}
+
+	var out dao.PriceChange
+	err := r.col.FindOneAndUpdate(ctx, filter, bson.M{
+		"$set": bson.M{"locked_at": now},
+	}, options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "effective_from", Value: 1}, {Key: "decided_at", Value: 1}}).
+		SetReturnDocument(options.After)).Decode(&out)
+	return out, err
+}
+
+func (r *PriceChangeRepositoryImpl) LatestDue(catalogUUID, branchUUID string, now int64) (dao.PriceChange, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"catalog_uuid":   catalogUUID,
+		"scope":          dao.PriceScopeClient,
+		"status":         bson.M{"$in": bson.A{dao.PriceChangeScheduled, dao.PriceChangeApplied}},
+		"effective_from": bson.M{"$lte": now},
+	}
+	if branchUUID != "" {
+		filter["scope"] = dao.PriceScopeBranch
+		filter["branch_uuid"] = branchUUID
+	}
+
+	// urutan kebalikan ClaimDue: yang diterapkan terakhir = yang berlaku
+	var out dao.PriceChange
+	err := r.col.FindOne(ctx, filter, options.FindOne().
+		SetSort(bson.D{{Key: "effective_from", Value: -1}, {Key: "decided_at", Value: -1}})).Decode(&out)
+	return out, err
+}
+
+func (r *PriceChangeRepositoryImpl) Finish(uuid string, status dao.PriceChangeStatus, oldPrice float64, lastErr string) (dao.PriceChange, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	set := bson.M{
+		"status":         status,
+		"last_error":     lastErr,
+		"locked_at":      0,
+		"updated_at":     now.Unix(),
+		"updated_at_str": now.Format(time.RFC3339),
+	}
+	if status == dao.PriceChangeApplied {
+		set["old_price"] = oldPrice
+		set["applied_at"] = now.Unix()
+		set["applied_at_str"] = now.Format(time.RFC3339)
+	}
+
+	var out dao.PriceChange
+	err := r.col.FindOneAndUpdate(ctx, bson.M{"uuid": uuid}, bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&out)
+	return out, err
+}
+
+func (r *PriceChangeRepositoryImpl) InsertHistory(h *dao.PriceHistory) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+	if h.UUID == "" {
+		h.UUID = helpers.GenerateUUID()
+	}
+	h.CreatedAt = now
+	h.CreatedAtStr = nowStr
+	h.UpdatedAt = now.Unix()
+	h.UpdatedAtStr = nowStr
+
+	_, err := r.historyCol.InsertOne(ctx, h)
+	return err
+}
+
+func (r *PriceChangeRepositoryImpl) ListHistory(req *dto.FilterRequest) ([]dao.PriceHistory, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	filter := buildListFilter(req)
+	cur, err := r.historyCol.Find(ctx, filter, buildListOptions(req))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	out := []dao.PriceHistory{}
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+)
+
+type ProductImportJobRepository interface {
+	Insert(j *dao.ProductImportJob) (dao.ProductImportJob, error)
+	// Detail: tanpa isi file
+	Detail(uuid string) (dao.ProductImportJob, error)
+	// DetailWithFile: untuk generate laporan error
+	DetailWithFile(uuid string) (dao.ProductImportJob, error)
+	List(clientUUID string, req *dto.FilterRequest) ([]dao.ProductImportJob, error)
+
+	// ClaimNext: ambil 1 job PENDING (atau RUNNING yang lock-nya basi => diulang dari awal)
+	ClaimNext(now int64, staleBefore int64) (dao.ProductImportJob, error)
+	// Progress: update counter selama berjalan (sekaligus perpanjang lock)
+	Progress(j dao.ProductImportJob) error
+	// Finish: status DONE / FAILED + hasil akhir
+	Finish(j dao.ProductImportJob) (dao.ProductImportJob, error)
+}
+
+type ProductImportJobRepositoryImpl struct {
+	col *mongo.Collection
+}
+
+func ProductImportJobRepositoryInit(mongoClient *mongo.Client) *ProductImportJobRepositoryImpl {
+	dbName := helpers.ProvideDBName()
+	return &ProductImportJobRepositoryImpl{
+		col: mongoClient.Database(dbName).Collection("product_import_jobs"),
+	}
+}
+
+var importJobNoFile = bson.M{"file": 0}
+
+func (r *ProductImportJobRepositoryImpl) Insert(j *dao.ProductImportJob) (dao.ProductImportJob, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	if len(j.File) == 0 {
+		return dao.ProductImportJob{}, errors.New("file required")
+	}
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+	if j.UUID == "" {
+		j.UUID = helpers.GenerateUUID()
+	}
+	j.CreatedAt = now
+	j.CreatedAtStr = nowStr
+	j.UpdatedAt = now.Unix()
+	j.UpdatedAtStr = nowStr
+	j.Status = dao.ImportPending
+	j.Errors = []dao.ProductImportError{}
+
+	if _, err := r.col.InsertOne(ctx, j); err != nil {
+		return dao.ProductImportJob{}, err
+	}
+	out := *j
+	out.File = nil
+	return out, nil
+}
+
+func (r *ProductImportJobRepositoryImpl) Detail(uuid string) (dao.ProductImportJob, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.ProductImportJob
+	err := r.col.FindOne(ctx, bson.M{"uuid": uuid}, options.FindOne().SetProjection(importJobNoFile)).Decode(&out)
+	return out, err
+}
+
+func (r *ProductImportJobRepositoryImpl) DetailWithFile(uuid string) (dao.ProductImportJob, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	var out dao.ProductImportJob
+	err := r.col.FindOne(ctx, bson.M{"uuid": uuid}).Decode(&out)
+	return out, err
+}
+
+// List: tanpa file, errors & diffs (lihat detail)
+func (r *ProductImportJobRepositoryImpl) List(clientUUID string, req *dto.FilterRequest) ([]dao.ProductImportJob, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := buildListFilter(req, "file_name")
+	filter["client_uuid"] = clientUUID
+
+	opts := buildListOptions(req).SetProjection(bson.M{"file": 0, "errors": 0, "diffs": 0})
+	cur, err := r.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	out := []dao.ProductImportJob{}
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *ProductImportJobRepositoryImpl) ClaimNext(now int64, staleBefore int64) (dao.ProductImportJob, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	var out dao.ProductImportJob
+	err := r.col.FindOneAndUpdate(ctx, bson.M{
+		"$or": bson.A{
+			bson.M{"status": dao.ImportPending},
+			bson.M{"status": dao.ImportRunning, "locked_at": bson.M{"$lt": staleBefore}},
+		},
+	}, bson.M{
+		"$set": bson.M{
+			"status":         dao.ImportRunning,
+			"locked_at":      now,
+			"started_at":     now,
+			"started_at_str": time.Unix(now, 0).Format(time.RFC3339),
+		},
+		"$inc": bson.M{"attempts": 1},
+	}, options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetReturnDocument(options.After)).Decode(&out)
+	return out, err
+}
+
+func (r *ProductImportJobRepositoryImpl) Progress(j dao.ProductImportJob) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	set := importJobCounters(j)
+	set["locked_at"] = now.Unix()
+	set["updated_at"] = now.Unix()
+	set["updated_at_str"] = now.Format(time.RFC3339)
+	_, err := r.col.UpdateOne(ctx, bson.M{"uuid": j.UUID}, bson.M{"$set": set})
+	return err
+}
+
+func (r *ProductImportJobRepositoryImpl) Finish(j dao.ProductImportJob) (dao.ProductImportJob, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	set := importJobCounters(j)
+	set["status"] = j.Status
+	set["last_error"] = j.LastError
+	set["locked_at"] = 0
+	set["finished_at"] = now.Unix()
+	set["finished_at_str"] = now.Format(time.RFC3339)
+	set["updated_at"] = now.Unix()
+	set["updated_at_str"] = now.Format(time.RFC3339)
+
+	var out dao.ProductImportJob
+	err := r.col.FindOneAndUpdate(ctx, bson.M{"uuid": j.UUID}, bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After).SetProjection(importJobNoFile)).Decode(&out)
+	return out, err
+}
+
+func importJobCounters(j dao.ProductImportJob) bson.M {
+	errs := j.Errors
+	if errs == nil {
+		errs = []dao.ProductImportError{}
+	}
+	return bson.M{
+		"total_rows":     j.TotalRows,
+		"processed_rows": j.ProcessedRows,
+		"created":        j.Created,
+		"updated":        j.Updated,
+		"unchanged":      j.Unchanged,
+		"failed":         j.Failed,
+		"errors":         errs,
+		"diffs":          j.Diffs,
+	}
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -16,28 +17,61 @@ import (
 
 type ProductRepository interface {
 	SaveProduct(data *dao.Product) (dao.Product, error)
-	BulkUpsertProducts(list []dao.Product) (int, int, []error)
+	// MatchExisting: produk yang sudah ada untuk tiap baris (kunci sama dengan SaveProduct), UUID kosong = belum ada
+	MatchExisting(list []dao.Product) ([]dao.Product, error)
+	// ImportProducts: upsert 1 batch dengan 1 bulk write. failed: index baris => error, baris lain tetap tersimpan.
+	ImportProducts(list []dao.Product) (created int, updated int, failed map[int]error, err error)
 	DetailProduct(uuid string) (dao.Product, error)
 	ListProduct(req *dto.FilterRequest) ([]dao.Product, error)
+	// ListGrouped: seperti ListProduct, varian dikelompokkan per parent (pagination per grup, urut nama)
+	ListGrouped(req *dto.FilterRequest) ([]dto.VariantGroup, error)
+	// EachProduct: semua produk sesuai filter (tanpa pagination) lewat cursor, untuk export
+	EachProduct(req *dto.FilterRequest, fn func(dao.Product) error) error
 	DeleteProduct(uuid string) error
-
-	DecreaseStockIfEnough(productUUID string, qty int64) error
-	IncreaseStock(productUUID string, qty int64) error
+	FindByPLU(branchUUID string, plu string) (dao.Product, error)
+
+	// perubahan stock selalu tercatat di stock_movements
+	// DecreaseStockIfEnough: return produk setelah dipotong (cost saat itu dipakai untuk COGS)
+	DecreaseStockIfEnough(productUUID string, qty int64, ref dao.StockRef) (dao.Product, error)
+	IncreaseStock(productUUID string, qty int64, ref dao.StockRef) error
+	// AdjustStock: delta +/- (opname, adjustment manual). Delta minus tidak boleh bikin stock < 0.
+	// Delta plus dengan unitCost > 0 ikut menghitung ulang cost (weighted-average).
+	// Return produk setelah update + stock/cost sebelumnya (untuk RevertStock).
+	AdjustStock(productUUID string, delta int64, unitCost float64, ref dao.StockRef) (dao.Product, dao.StockBefore, error)
+	// ReceivePurchase: stock masuk dari supplier (GRN), cost dihitung ulang weighted-average
+	ReceivePurchase(productUUID string, qty int64, costPerBase float64, ref dao.StockRef) (dao.Product, dao.StockBefore, error)
+	// RevertStock: rollback AdjustStock / ReceivePurchase, stock + weighted-average cost kembali ke before
+	RevertStock(after dao.Product, delta int64, before dao.StockBefore, ref dao.StockRef) error
+
+	// SetStockLevels: min (reorder point) & max stock per produk per branch
+	SetStockLevels(productUUID string, minStock, maxStock int64) (dao.Product, error)
 }
 
 type ProductRepositoryImpl struct {
 	productCollection *mongo.Collection
+	ledger            stockLedger
+	lots              lotLedger
+	catalog           catalogLink
 }
 
 func ProductRepositoryInit(mongoClient *mongo.Client) *ProductRepositoryImpl {
 	dbName := helpers.ProvideDBName()
-	collection := mongoClient.Database(dbName).Collection("products")
+	db := mongoClient.Database(dbName)
 	return &ProductRepositoryImpl{
-		productCollection: collection,
+		productCollection: db.Collection("products"),
+		ledger:            newStockLedger(db),
+		lots:              newLotLedger(db),
+		catalog:           newCatalogLink(db),
 	}
 }
 
+// SaveProduct: upsert master produk. Field stock & cost hanya dipakai saat insert (stock awal dicatat ADJUSTMENT);
+// setelah itu stock berubah lewat ledger ($inc) dan cost lewat weighted-average saat barang masuk.
 func (r *ProductRepositoryImpl) SaveProduct(data *dao.Product) (dao.Product, error) {
+	return r.saveProduct(data, dao.StockMoveAdjustment)
+}
+
+func (r *ProductRepositoryImpl) saveProduct(data *dao.Product, reason dao.StockMovementReason) (dao.Product, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -50,20 +84,73 @@ func (r *ProductRepositoryImpl) SaveProduct(data *dao.Product) (dao.Product, err
 	if data.BaseUnit == "" {
 		return dao.Product{}, errors.New("base_unit required")
 	}
+	if err := validateStockLevels(data.MinStock, data.MaxStock); err != nil {
+		return dao.Product{}, err
+	}
 
-	filter := bson.M{}
+	filter := productKey(data)
+
+	// master (nama, SKU, units, dst) disimpan di catalog client, produk ini record inventory branch:
+	// master hanya dibuat dari sini kalau belum ada, perubahan master lewat /catalog/upsert
+	if data.CatalogUUID == "" {
+		var existing dao.Product
+		if err := r.productCollection.FindOne(ctx, filter).Decode(&existing); err == nil {
+			data.CatalogUUID = existing.CatalogUUID
+		}
+	}
+	cat, err := r.catalog.fromProduct(ctx, data)
+	if err != nil {
+		return dao.Product{}, err
+	}
+
+	// produk + movement stock awal + lot OPENING dalam 1 transaction
+	var out dao.Product
+	err = r.ledger.tx(ctx, func(sc context.Context) error {
+		opts := options.Update().SetUpsert(true)
+		res, err := r.productCollection.UpdateOne(sc, filter, productUpsert(data, reason, time.Now()), opts)
+		if err != nil {
+			return err
+		}
+		if err := r.catalog.link(sc, filter, cat); err != nil {
+			return err
+		}
+		if err := r.productCollection.FindOne(sc, filter).Decode(&out); err != nil {
+			return err
+		}
+
+		if res.UpsertedCount > 0 && out.Stock != 0 {
+			if err := r.ledger.record(sc, out, out.Stock, out.Stock, dao.StockRef{
+				Reason:    reason,
+				RefUUID:   out.UUID,
+				Note:      "initial stock",
+				CreatedBy: data.CreatedBy,
+			}); err != nil {
+				return err
+			}
+		}
+		// tracking lot baru aktif / stock awal: stock yang belum ber-lot masuk lot OPENING
+		return r.lots.sync(sc, out)
+	})
+	if err != nil {
+		return dao.Product{}, err
+	}
+	return out, nil
+}
+
+// productKey: uuid, kalau kosong SKU per branch, kalau kosong nama per branch
+func productKey(data *dao.Product) bson.M {
 	switch {
 	case data.UUID != "":
-		filter["uuid"] = data.UUID
+		return bson.M{"uuid": data.UUID}
 	case data.SKU != "":
-		filter["branch_uuid"] = data.BranchUUID
-		filter["sku"] = data.SKU
+		return bson.M{"branch_uuid": data.BranchUUID, "sku": data.SKU}
 	default:
-		filter["branch_uuid"] = data.BranchUUID
-		filter["name"] = data.Name
+		return bson.M{"branch_uuid": data.BranchUUID, "name": data.Name}
 	}
+}
 
-	now := time.Now()
+// productUpsert: dokumen update SaveProduct / import (catalog_uuid & price_override sudah di-resolve)
+func productUpsert(data *dao.Product, reason dao.StockMovementReason, now time.Time) bson.M {
 	nowStr := now.Format(time.RFC3339)
 
 	newUUID := data.UUID
@@ -74,15 +161,15 @@ func (r *ProductRepositoryImpl) SaveProduct(data *dao.Product) (dao.Product, err
 	update := bson.M{
 		"$set": bson.M{
 			"branch_uuid":    data.BranchUUID,
+			"catalog_uuid":   data.CatalogUUID,
 			"image":          data.Image,
 			"sku":            data.SKU,
 			"barcode":        data.Barcode,
+			"plu":            data.PLU,
 			"name":           data.Name,
 			"description":    data.Description,
 			"base_unit":      data.BaseUnit,
 			"units":          data.Units,
-			"cost":           data.Cost,
-			"stock":          data.Stock,
 			"price":          data.Price,
 			"is_active":      data.IsActive,
 			"created_by":     data.CreatedBy,
@@ -90,7 +177,9 @@ func (r *ProductRepositoryImpl) SaveProduct(data *dao.Product) (dao.Product, err
 			"updated_at_str": nowStr,
 		},
 		"$setOnInsert": bson.M{
-			"uuid": newUUID,
+			"uuid":  newUUID,
+			"stock": data.Stock,
+			"cost":  data.Cost,
 			"created_at": func() time.Time {
 				if data.CreatedAt.IsZero() {
 					return now
@@ -106,34 +195,124 @@ func (r *ProductRepositoryImpl) SaveProduct(data *dao.Product) (dao.Product, err
 		},
 	}
 
-	opts := options.Update().SetUpsert(true)
-	if _, err := r.productCollection.UpdateOne(ctx, filter, update, opts); err != nil {
-		return dao.Product{}, err
+	if data.PriceOverride != nil {
+		update["$set"].(bson.M)["price_override"] = *data.PriceOverride
+	} else {
+		update["$unset"] = bson.M{"price_override": ""}
 	}
 
-	var out dao.Product
-	if err := r.productCollection.FindOne(ctx, filter).Decode(&out); err != nil {
-		return dao.Product{}, err
+	// import tidak punya kolom track_lots / min-max: jangan timpa setting produk yang sudah ada
+	settings := update["$set"].(bson.M)
+	if reason == dao.StockMoveImport {
+		settings = update["$setOnInsert"].(bson.M)
+	}
+	settings["track_lots"] = data.TrackLots
+	settings["min_stock"] = data.MinStock
+	settings["max_stock"] = data.MaxStock
+	return update
+}
+
+func (r *ProductRepositoryImpl) MatchExisting(list []dao.Product) ([]dao.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	out := make([]dao.Product, len(list))
+	if len(list) == 0 {
+		return out, nil
+	}
+	keys := make(bson.A, 0, len(list))
+	for i := range list {
+		keys = append(keys, productKey(&list[i]))
+	}
+	cur, err := r.productCollection.Find(ctx, bson.M{"$or": keys})
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var found []dao.Product
+	if err := cur.All(ctx, &found); err != nil {
+		return nil, err
+	}
+	for i, p := range list {
+		for _, f := range found {
+			if f.BranchUUID != p.BranchUUID {
+				continue
+			}
+			if (p.SKU != "" && f.SKU == p.SKU) || (p.SKU == "" && f.Name == p.Name) {
+				out[i] = f
+				break
+			}
+		}
 	}
 	return out, nil
 }
 
-// Bulk: per-row upsert, return (successCount, failCount, errors)
-func (r *ProductRepositoryImpl) BulkUpsertProducts(list []dao.Product) (int, int, []error) {
-	ok := 0
-	fail := 0
-	var errs []error
+// import tidak membawa stock: produk baru stock 0 (stock awal lewat adjustment / PO), jadi tanpa ledger & lot
+func (r *ProductRepositoryImpl) ImportProducts(list []dao.Product) (int, int, map[int]error, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	failed := map[int]error{}
+	existing, err := r.MatchExisting(list)
+	if err != nil {
+		return 0, 0, nil, err
+	}
 
+	// master catalog per baris (SKU unik per client dicek di sini)
+	now := time.Now()
+	models := []mongo.WriteModel{}
+	rows := []int{}
+	cats := map[int]dao.CatalogProduct{}
 	for i := range list {
-		_, err := r.SaveProduct(&list[i])
+		data := &list[i]
+		data.Stock = 0
+		if data.CatalogUUID == "" {
+			data.CatalogUUID = existing[i].CatalogUUID
+		}
+		cat, err := r.catalog.fromProduct(ctx, data)
 		if err != nil {
-			fail++
-			errs = append(errs, err)
+			failed[i] = err
+			continue
+		}
+		cats[i] = cat
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(productKey(data)).
+			SetUpdate(productUpsert(data, dao.StockMoveImport, now)).
+			SetUpsert(true))
+		rows = append(rows, i)
+	}
+	if len(models) == 0 {
+		return 0, 0, failed, nil
+	}
+
+	res, err := r.productCollection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	var bwe mongo.BulkWriteException
+	switch {
+	case errors.As(err, &bwe):
+		for _, we := range bwe.WriteErrors {
+			failed[rows[we.Index]] = errors.New(we.Message)
+		}
+	case err != nil:
+		return 0, 0, nil, err
+	}
+
+	created, updated := 0, 0
+	if res != nil {
+		created = int(res.UpsertedCount)
+		updated = len(models) - len(bwe.WriteErrors) - created
+	}
+
+	// field master (kategori, brand, varian) produk yang baru terhubung ikut catalog
+	for _, i := range rows {
+		if _, bad := failed[i]; bad {
 			continue
 		}
-		ok++
+		if err := r.catalog.link(ctx, productKey(&list[i]), cats[i]); err != nil {
+			return created, updated, failed, err
+		}
 	}
-	return ok, fail, errs
+	return created, updated, failed, nil
 }
 
 func (r *ProductRepositoryImpl) DetailProduct(uuid string) (dao.Product, error) {
@@ -145,10 +324,7 @@ func (r *ProductRepositoryImpl) DetailProduct(uuid string) (dao.Product, error)
 	return result, err
 }
 
-func (r *ProductRepositoryImpl) ListProduct(req *dto.FilterRequest) ([]dao.Product, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
+func productListFilter(req *dto.FilterRequest) bson.M {
 	filter := bson.M{}
 	if req.Search != "" {
 		filter["$or"] = []bson.M{
@@ -156,6 +332,9 @@ func (r *ProductRepositoryImpl) ListProduct(req *dto.FilterRequest) ([]dao.Produ
 			{"sku": bson.M{"$regex": req.Search, "$options": "i"}},
 			{"barcode": bson.M{"$regex": req.Search, "$options": "i"}},
 			{"base_unit": bson.M{"$regex": req.Search, "$options": "i"}},
+			{"category_path": bson.M{"$regex": req.Search, "$options": "i"}},
+			{"brand_name": bson.M{"$regex": req.Search, "$options": "i"}},
+			{"tags": bson.M{"$regex": req.Search, "$options": "i"}},
 		}
 	}
 
@@ -165,6 +344,14 @@ func (r *ProductRepositoryImpl) ListProduct(req *dto.FilterRequest) ([]dao.Produ
 		}
 		filter[k] = v
 	}
+	return filter
+}
+
+func (r *ProductRepositoryImpl) ListProduct(req *dto.FilterRequest) ([]dao.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := productListFilter(req)
 
 	sort := bson.D{}
 	for k, v := range req.SortBy {
@@ -214,6 +401,127 @@ func (r *ProductRepositoryImpl) ListProduct(req *dto.FilterRequest) ([]dao.Produ
 	return listOut, nil
 }
 
+func (r *ProductRepositoryImpl) ListGrouped(req *dto.FilterRequest) ([]dto.VariantGroup, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	page := req.Pagination.Page
+	size := req.Pagination.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	if size <= 0 || size > 200 {
+		size = 20
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: productListFilter(req)}},
+		{{Key: "$sort", Value: bson.D{{Key: "sku", Value: 1}, {Key: "name", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{"$cond": bson.A{
+				bson.M{"$gt": bson.A{"$parent_uuid", ""}}, "$parent_uuid", "$uuid",
+			}},
+			"parent_uuid": bson.M{"$max": "$parent_uuid"},
+			"name":        bson.M{"$first": "$name"},
+			"variants":    bson.M{"$push": "$$ROOT"},
+		}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "catalog_products",
+			"localField":   "parent_uuid",
+			"foreignField": "uuid",
+			"as":           "_parent",
+		}}},
+		{{Key: "$addFields", Value: bson.M{
+			"_sort_name": bson.M{"$ifNull": bson.A{bson.M{"$arrayElemAt": bson.A{"$_parent.name", 0}}, "$name"}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_sort_name", Value: 1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$skip", Value: int64((page - 1) * size)}},
+		{{Key: "$limit", Value: int64(size)}},
+	}
+
+	cur, err := r.productCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var rows []struct {
+		ParentUUID string               `bson:"parent_uuid"`
+		Variants   []dao.Product        `bson:"variants"`
+		Parent     []dao.CatalogProduct `bson:"_parent"`
+	}
+	if err := cur.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	out := make([]dto.VariantGroup, 0, len(rows))
+	for _, row := range rows {
+		var parent *dao.CatalogProduct
+		if row.ParentUUID != "" && len(row.Parent) > 0 {
+			parent = &row.Parent[0]
+		}
+		out = append(out, variantGroup(parent, row.Variants))
+	}
+	return out, nil
+}
+
+// variantGroup: info grup dari parent catalog, produk tanpa varian pakai datanya sendiri
+func variantGroup(parent *dao.CatalogProduct, variants []dao.Product) dto.VariantGroup {
+	g := dto.VariantGroup{Variants: variants}
+	switch {
+	case parent != nil:
+		g.ParentUUID = parent.UUID
+		g.SKU = parent.SKU
+		g.Name = parent.Name
+		g.Image = parent.Image
+		g.CategoryPath = parent.CategoryPath
+		g.BrandName = parent.BrandName
+		g.Attributes = parent.VariantAttributes
+	case len(variants) > 0:
+		g.ParentUUID = variants[0].ParentUUID
+		g.SKU = variants[0].SKU
+		g.Name = variants[0].Name
+		g.Image = variants[0].Image
+		g.CategoryPath = variants[0].CategoryPath
+		g.BrandName = variants[0].BrandName
+	}
+	for i, v := range variants {
+		if i == 0 || v.Price < g.PriceMin {
+			g.PriceMin = v.Price
+		}
+		if v.Price > g.PriceMax {
+			g.PriceMax = v.Price
+		}
+		g.TotalStock += v.Stock
+	}
+	return g
+}
+
+func (r *ProductRepositoryImpl) EachProduct(req *dto.FilterRequest, fn func(dao.Product) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "branch_uuid", Value: 1}, {Key: "name", Value: 1}}).
+		SetBatchSize(500)
+	cur, err := r.productCollection.Find(ctx, productListFilter(req), opts)
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var p dao.Product
+		if err := cur.Decode(&p); err != nil {
+			return err
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return cur.Err()
+}
+
 func (r *ProductRepositoryImpl) DeleteProduct(uuid string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -222,15 +530,34 @@ func (r *ProductRepositoryImpl) DeleteProduct(uuid string) error {
 	return err
 }
 
-func (r *ProductRepositoryImpl) DecreaseStockIfEnough(productUUID string, qty int64) error {
+// FindByPLU: PLU dari label timbangan biasanya zero-padded ("00123"), match dua-duanya
+func (r *ProductRepositoryImpl) FindByPLU(branchUUID string, plu string) (dao.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	candidates := bson.A{plu}
+	if trimmed := strings.TrimLeft(plu, "0"); trimmed != "" && trimmed != plu {
+		candidates = append(candidates, trimmed)
+	}
+
+	var out dao.Product
+	err := r.productCollection.FindOne(ctx, bson.M{
+		"branch_uuid": branchUUID,
+		"plu":         bson.M{"$in": candidates},
+		"is_active":   true,
+	}).Decode(&out)
+	return out, err
+}
+
+func (r *ProductRepositoryImpl) DecreaseStockIfEnough(productUUID string, qty int64, ref dao.StockRef) (dao.Product, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	if productUUID == "" {
-		return errors.New("product_uuid required")
+		return dao.Product{}, errors.New("product_uuid required")
 	}
 	if qty <= 0 {
-		return errors.New("qty must be > 0")
+		return dao.Product{}, errors.New("qty must be > 0")
 	}
 
 	filter := bson.M{
@@ -238,27 +565,10 @@ func (r *ProductRepositoryImpl) DecreaseStockIfEnough(productUUID string, qty in
 		"is_active": true,
 		"stock":     bson.M{"$gte": qty},
 	}
-
-	now := time.Now()
-	update := bson.M{
-		"$inc": bson.M{"stock": -qty},
-		"$set": bson.M{
-			"updated_at":     now.Unix(),
-			"updated_at_str": now.Format(time.RFC3339),
-		},
-	}
-
-	res, err := r.productCollection.UpdateOne(ctx, filter, update)
-	if err != nil {
-		return err
-	}
-	if res.MatchedCount == 0 {
-		return errors.New("stock not enough or product not found")
-	}
-	return nil
+	return r.ledger.apply(ctx, filter, -qty, ref, nil)
 }
 
-func (r *ProductRepositoryImpl) IncreaseStock(productUUID string, qty int64) error {
+func (r *ProductRepositoryImpl) IncreaseStock(productUUID string, qty int64, ref dao.StockRef) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -273,15 +583,80 @@ func (r *ProductRepositoryImpl) IncreaseStock(productUUID string, qty int64) err
 		"uuid":      productUUID,
 		"is_active": true,
 	}
-	now := time.Now()
-	update := bson.M{
-		"$inc": bson.M{"stock": qty},
-		"$set": bson.M{
-			"updated_at":     now.Unix(),
-			"updated_at_str": now.Format(time.RFC3339),
-		},
+	_, err := r.ledger.apply(ctx, filter, qty, ref, nil)
+	if errors.Is(err, errStockNotApplied) {
+		// produk sudah dihapus / non-aktif: sama seperti sebelumnya, tidak dianggap error
+		return nil
 	}
-
-	_, err := r.productCollection.UpdateOne(ctx, filter, update)
 	return err
 }
+
+func (r *ProductRepositoryImpl) AdjustStock(productUUID string, delta int64, unitCost float64, ref dao.StockRef) (dao.Product, dao.StockBefore, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if productUUID == "" {
+		return dao.Product{}, dao.StockBefore{}, errors.New("product_uuid required")
+	}
+
+	filter := bson.M{"uuid": productUUID}
+	if delta < 0 {
+		filter["stock"] = bson.M{"$gte": -delta}
+		p, err := r.ledger.apply(ctx, filter, delta, ref, nil)
+		return p, dao.StockBefore{Stock: p.Stock - delta, Cost: p.Cost}, err
+	}
+	return r.ledger.applyIn(ctx, filter, delta, unitCost, ref, nil)
+}
+
+func (r *ProductRepositoryImpl) ReceivePurchase(productUUID string, qty int64, costPerBase float64, ref dao.StockRef) (dao.Product, dao.StockBefore, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if productUUID == "" {
+		return dao.Product{}, dao.StockBefore{}, errors.New("product_uuid required")
+	}
+	if qty <= 0 {
+		return dao.Product{}, dao.StockBefore{}, errors.New("qty must be > 0")
+	}
+
+	return r.ledger.applyIn(ctx, bson.M{"uuid": productUUID}, qty, costPerBase, ref, nil)
+}
+
+func (r *ProductRepositoryImpl) RevertStock(after dao.Product, delta int64, before dao.StockBefore, ref dao.StockRef) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if after.UUID == "" || delta == 0 {
+		return errors.New("nothing to revert")
+	}
+	return r.ledger.revert(ctx, after, delta, before, ref)
+}
+
+func validateStockLevels(minStock, maxStock int64) error {
+	if minStock < 0 || maxStock < 0 {
+		return errors.New("min_stock & max_stock must be >= 0")
+	}
+	if maxStock > 0 && maxStock < minStock {
+		return errors.New("max_stock must be >= min_stock")
+	}
+	return nil
+}
+
+func (r *ProductRepositoryImpl) SetStockLevels(productUUID string, minStock, maxStock int64) (dao.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := validateStockLevels(minStock, maxStock); err != nil {
+		return dao.Product{}, err
+	}
+
+	now := time.Now()
+	var out dao.Product
+	err := r.productCollection.FindOneAndUpdate(ctx, bson.M{"uuid": productUUID}, bson.M{"$set": bson.M{
+		"min_stock":      minStock,
+		"max_stock":      maxStock,
+		"updated_at":     now.Unix(),
+		"updated_at_str": now.Format(time.RFC3339),
+	}}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&out)
+	return out, err
+}
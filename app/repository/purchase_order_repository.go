@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+)
+
+type PurchaseOrderRepository interface {
+	// Save: insert baru atau update PO yang masih DRAFT
+	Save(po *dao.PurchaseOrder) (dao.PurchaseOrder, error)
+	Detail(uuid string) (dao.PurchaseOrder, error)
+	List(req *dto.FilterRequest) ([]dao.PurchaseOrder, error)
+	SetStatus(uuid string, from []dao.PurchaseOrderStatus, to dao.PurchaseOrderStatus, extra bson.M) (dao.PurchaseOrder, error)
+
+	// ApplyReceipt: simpan received_base + status baru, guard rev (optimistic lock)
+	ApplyReceipt(uuid string, rev int64, items []dao.PurchaseOrderItem, status dao.PurchaseOrderStatus) error
+
+	InsertReceipt(grn *dao.GoodsReceipt) (dao.GoodsReceipt, error)
+	ListReceipts(poUUID string) ([]dao.GoodsReceipt, error)
+}
+
+type PurchaseOrderRepositoryImpl struct {
+	poCol      *mongo.Collection
+	receiptCol *mongo.Collection
+}
+
+func PurchaseOrderRepositoryInit(mongoClient *mongo.Client) *PurchaseOrderRepositoryImpl {
+	dbName := helpers.ProvideDBName()
+	db := mongoClient.Database(dbName)
+	return &PurchaseOrderRepositoryImpl{
+		poCol:      db.Collection("purchase_orders"),
+		receiptCol: db.Collection("goods_receipts"),
+	}
+}
+
+var errPOConflict = errors.New("purchase order changed by another request, please retry")
+
+func (r *PurchaseOrderRepositoryImpl) Save(po *dao.PurchaseOrder) (dao.PurchaseOrder, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if po.ClientUUID == "" || po.BranchUUID == "" || po.SupplierUUID == "" {
+		return dao.PurchaseOrder{}, errors.New("client_uuid, branch_uuid & supplier_uuid required")
+	}
+	if len(po.Items) == 0 {
+		return dao.PurchaseOrder{}, errors.New("items required")
+	}
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+
+	if po.UUID == "" {
+		po.UUID = helpers.GenerateUUID()
+		po.CreatedAt = now
+		po.CreatedAtStr = nowStr
+		po.UpdatedAt = now.Unix()
+		po.UpdatedAtStr = nowStr
+		po.Status = dao.PODraft
+		if po.PONo == "" {
+			po.PONo = "PO-" + now.Format("20060102-150405")
+		}
+		if _, err := r.poCol.InsertOne(ctx, po); err != nil {
+			return dao.PurchaseOrder{}, err
+		}
+		return *po, nil
+	}
+
+	var out dao.PurchaseOrder
+	err := r.poCol.FindOneAndUpdate(ctx, bson.M{"uuid": po.UUID, "status": dao.PODraft}, bson.M{"$set": bson.M{
+		"branch_uuid":    po.BranchUUID,
+		"supplier_uuid":  po.SupplierUUID,
+		"supplier_name":  po.SupplierName,
+		"expected_date":  po.ExpectedDate,
+		"note":           po.Note,
+		"items":          po.Items,
+		"sub_total":      po.SubTotal,
+		"discount":       po.Discount,
+		"tax":            po.Tax,
+		"total":          po.Total,
+		"updated_at":     now.Unix(),
+		"updated_at_str": nowStr,
+	}}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&out)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return dao.PurchaseOrder{}, errors.New("only DRAFT purchase order can be edited")
+	}
+	return out, err
+}
+
+func (r *PurchaseOrderRepositoryImpl) Detail(uuid string) (dao.PurchaseOrder, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.PurchaseOrder
+	err := r.poCol.FindOne(ctx, bson.M{"uuid": uuid}).Decode(&out)
+	return out, err
+}
+
+func (r *PurchaseOrderRepositoryImpl) List(req *dto.FilterRequest) ([]dao.PurchaseOrder, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	filter := buildListFilter(req, "po_no", "supplier_name", "note")
+	cur, err := r.poCol.Find(ctx, filter, buildListOptions(req))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var out []dao.PurchaseOrder
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *PurchaseOrderRepositoryImpl) SetStatus(uuid string, from []dao.PurchaseOrderStatus, to dao.PurchaseOrderStatus, extra bson.M) (dao.PurchaseOrder, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	set := bson.M{
+		"status":         to,
+		"updated_at":     now.Unix(),
+		"updated_at_str": now.Format(time.RFC3339),
+	}
+	for k, v := range extra {
+		set[k] = v
+	}
+
+	var out dao.PurchaseOrder
+	err := r.poCol.FindOneAndUpdate(ctx, bson.M{"uuid": uuid, "status": bson.M{"$in": from}}, bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&out)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return dao.PurchaseOrder{}, errors.New("invalid purchase order status for this action")
+	}
+	return out, err
+}
+
+func (r *PurchaseOrderRepositoryImpl) ApplyReceipt(uuid string, rev int64, items []dao.PurchaseOrderItem, status dao.PurchaseOrderStatus) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	set := bson.M{
+		"items":          items,
+		"status":         status,
+		"updated_at":     now.Unix(),
+		"updated_at_str": now.Format(time.RFC3339),
+	}
+	if status == dao.POReceived {
+		set["received_at"] = now.Unix()
+	}
+
+	res, err := r.poCol.UpdateOne(ctx, bson.M{"uuid": uuid, "rev": rev}, bson.M{
+		"$set": set,
+		"$inc": bson.M{"rev": 1},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errPOConflict
+	}
+	return nil
+}
+
+func (r *PurchaseOrderRepositoryImpl) InsertReceipt(grn *dao.GoodsReceipt) (dao.GoodsReceipt, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+	if grn.UUID == "" {
+		grn.UUID = helpers.GenerateUUID()
+	}
+	grn.CreatedAt = now
+	grn.CreatedAtStr = nowStr
+	grn.UpdatedAt = now.Unix()
+	grn.UpdatedAtStr = nowStr
+	if grn.GRNNo == "" {
+		grn.GRNNo = "GRN-" + now.Format("20060102-150405")
+	}
+
+	if _, err := r.receiptCol.InsertOne(ctx, grn); err != nil {
+		return dao.GoodsReceipt{}, err
+	}
+	return *grn, nil
+}
+
+func (r *PurchaseOrderRepositoryImpl) ListReceipts(poUUID string) ([]dao.GoodsReceipt, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	cur, err := r.receiptCol.Find(ctx, bson.M{"po_uuid": poUUID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	out := make([]dao.GoodsReceipt, 0)
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
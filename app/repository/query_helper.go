@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"harjonan.id/user-service/app/domain/dto"
+)
+
+// buildListFilter: search regex (OR ke searchFields) + filter_by langsung.
+// Value slice/array di filter_by otomatis jadi $in (sama seperti stock transfer list).
+func buildListFilter(req *dto.FilterRequest, searchFields ...string) bson.M {
+	filter := bson.M{}
+	if req == nil {
+		return filter
+	}
+
+	if req.Search != "" && len(searchFields) > 0 {
+		or := make([]bson.M, 0, len(searchFields))
+		for _, f := range searchFields {
+			or = append(or, bson.M{f: bson.M{"$regex": req.Search, "$options": "i"}})
+		}
+		filter["$or"] = or
+	}
+
+	for k, v := range req.FilterBy {
+		if k == "" || v == nil {
+			continue
+		}
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+			if rv.Len() == 0 {
+				continue
+			}
+			inVals := make([]any, 0, rv.Len())
+			for i := 0; i < rv.Len(); i++ {
+				inVals = append(inVals, rv.Index(i).Interface())
+			}
+			filter[k] = bson.M{"$in": inVals}
+			continue
+		}
+		filter[k] = v
+	}
+	return filter
+}
+
+// buildListOptions: sort_by + pagination (default created_at desc, page_size max 200).
+func buildListOptions(req *dto.FilterRequest) *options.FindOptions {
+	sort := bson.D{}
+	page, size := 1, 20
+	if req != nil {
+		for k, v := range req.SortBy {
+			switch tv := v.(type) {
+			case string:
+				if tv == "asc" || tv == "ASC" || tv == "1" {
+					sort = append(sort, bson.E{Key: k, Value: 1})
+				} else {
+					sort = append(sort, bson.E{Key: k, Value: -1})
+				}
+			case float64:
+				if int(tv) >= 0 {
+					sort = append(sort, bson.E{Key: k, Value: 1})
+				} else {
+					sort = append(sort, bson.E{Key: k, Value: -1})
+				}
+			default:
+				sort = append(sort, bson.E{Key: k, Value: -1})
+			}
+		}
+		page = req.Pagination.Page
+		size = req.Pagination.PageSize
+	}
+	if len(sort) == 0 {
+		sort = bson.D{{Key: "created_at", Value: -1}}
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if size <= 0 || size > 200 {
+		size = 20
+	}
+	skip := int64((page - 1) * size)
+
+	return options.Find().SetSort(sort).SetSkip(skip).SetLimit(int64(size))
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/helpers"
+)
+
+type ReceiptTemplateRepository interface {
+	// Get: template client, default kalau belum ada
+	Get(clientUUID string) (dao.ReceiptTemplate, error)
+	Save(t *dao.ReceiptTemplate) (dao.ReceiptTemplate, error)
+}
+
+type ReceiptTemplateRepositoryImpl struct {
+	col *mongo.Collection
+}
+
+func ReceiptTemplateRepositoryInit(mongoClient *mongo.Client) *ReceiptTemplateRepositoryImpl {
+	dbName := helpers.ProvideDBName()
+	return &ReceiptTemplateRepositoryImpl{
+		col: mongoClient.Database(dbName).Collection("receipt_templates"),
+	}
+}
+
+func (r *ReceiptTemplateRepositoryImpl) Get(clientUUID string) (dao.ReceiptTemplate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.ReceiptTemplate
+	err := r.col.FindOne(ctx, bson.M{"client_uuid": clientUUID}).Decode(&out)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return dao.DefaultReceiptTemplate(clientUUID), nil
+	}
+	return out, err
+}
+
+func (r *ReceiptTemplateRepositoryImpl) Save(t *dao.ReceiptTemplate) (dao.ReceiptTemplate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if t.ClientUUID == "" {
+		return dao.ReceiptTemplate{}, errors.New("client_uuid required")
+	}
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+
+	update := bson.M{
+		"$set": bson.M{
+			"paper_width":    t.PaperWidth,
+			"header_text":    t.HeaderText,
+			"footer_text":    t.FooterText,
+			"show_logo":      t.ShowLogo,
+			"logo_url":       t.LogoURL,
+			"show_address":   t.ShowAddress,
+			"show_customer":  t.ShowCustomer,
+			"show_points":    t.ShowPoints,
+			"open_drawer":    t.OpenDrawer,
+			"updated_by":     t.UpdatedBy,
+			"updated_at":     now.Unix(),
+			"updated_at_str": nowStr,
+		},
+		"$setOnInsert": bson.M{
+			"uuid":           helpers.GenerateUUID(),
+			"created_at":     now,
+			"created_at_str": nowStr,
+		},
+	}
+	if _, err := r.col.UpdateOne(ctx, bson.M{"client_uuid": t.ClientUUID}, update, options.Update().SetUpsert(true)); err != nil {
+		return dao.ReceiptTemplate{}, err
+	}
+	return r.Get(t.ClientUUID)
+}
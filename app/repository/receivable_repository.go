@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+)
+
+type ReceivableRepository interface {
+	Insert(rc *dao.Receivable) (dao.Receivable, error)
+	Delete(uuid string) error
+	Detail(uuid string) (dao.Receivable, error)
+	List(req *dto.FilterRequest) ([]dao.Receivable, error)
+
+	// open = OPEN/PARTIAL, urut created_at asc (paling lama duluan)
+	ListOpen(filter bson.M) ([]dao.Receivable, error)
+	AddPayment(uuid string, pay dao.ReceivablePayment) (dao.Receivable, error)
+	Void(uuid string, voidedBy string) (dao.Receivable, error)
+
+	OutstandingByBranch(clientUUID string) ([]dto.ReceivableBranchOutstanding, error)
+
+	ListDueForReminder(dueBefore int64, remindedBefore int64, limit int64) ([]dao.Receivable, error)
+	MarkReminded(uuid string, at int64) error
+}
+
+type ReceivableRepositoryImpl struct {
+	col *mongo.Collection
+}
+
+func ReceivableRepositoryInit(mongoClient *mongo.Client) *ReceivableRepositoryImpl {
+	dbName := helpers.ProvideDBName()
+	return &ReceivableRepositoryImpl{
+		col: mongoClient.Database(dbName).Collection("receivables"),
+	}
+}
+
+var openReceivableStatus = bson.A{dao.ReceivableOpen, dao.ReceivablePartial}
+
+func (r *ReceivableRepositoryImpl) Insert(rc *dao.Receivable) (dao.Receivable, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if rc.ClientUUID == "" || rc.CustomerUUID == "" {
+		return dao.Receivable{}, errors.New("client_uuid & customer_uuid required")
+	}
+	if rc.Amount <= 0 {
+		return dao.Receivable{}, errors.New("amount must be > 0")
+	}
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+
+	if rc.UUID == "" {
+		rc.UUID = helpers.GenerateUUID()
+	}
+	rc.CreatedAt = now
+	rc.CreatedAtStr = nowStr
+	rc.UpdatedAt = now.Unix()
+	rc.UpdatedAtStr = nowStr
+
+	rc.PaidAmount = 0
+	rc.Outstanding = rc.Amount
+	rc.Status = dao.ReceivableOpen
+	if rc.Payments == nil {
+		rc.Payments = []dao.ReceivablePayment{}
+	}
+
+	if _, err := r.col.InsertOne(ctx, rc); err != nil {
+		return dao.Receivable{}, err
+	}
+	return *rc, nil
+}
+
+// Delete: dipakai untuk rollback checkout (insert trx gagal)
+func (r *ReceivableRepositoryImpl) Delete(uuid string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.col.DeleteOne(ctx, bson.M{"uuid": uuid})
+	return err
+}
+
+func (r *ReceivableRepositoryImpl) Detail(uuid string) (dao.Receivable, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.Receivable
+	err := r.col.FindOne(ctx, bson.M{"uuid": uuid}).Decode(&out)
+	return out, err
+}
+
+func (r *ReceivableRepositoryImpl) List(req *dto.FilterRequest) ([]dao.Receivable, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	filter := buildListFilter(req, "receipt_no", "customer_name")
+	cur, err := r.col.Find(ctx, filter, buildListOptions(req))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var out []dao.Receivable
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *ReceivableRepositoryImpl) ListOpen(filter bson.M) ([]dao.Receivable, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	f := bson.M{}
+	for k, v := range filter {
+		f[k] = v
+	}
+	f["status"] = bson.M{"$in": openReceivableStatus}
+
+	cur, err := r.col.Find(ctx, f, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var out []dao.Receivable
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AddPayment: atomic, guard outstanding >= amount supaya tidak lebih bayar
+func (r *ReceivableRepositoryImpl) AddPayment(uuid string, pay dao.ReceivablePayment) (dao.Receivable, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if pay.Amount <= 0 {
+		return dao.Receivable{}, errors.New("amount must be > 0")
+	}
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+	if pay.UUID == "" {
+		pay.UUID = helpers.GenerateUUID()
+	}
+	pay.PaidAt = now.Unix()
+	pay.PaidAtStr = nowStr
+
+	var out dao.Receivable
+	err := r.col.FindOneAndUpdate(ctx, bson.M{
+		"uuid":        uuid,
+		"status":      bson.M{"$in": openReceivableStatus},
+		"outstanding": bson.M{"$gte": pay.Amount},
+	}, bson.M{
+		"$inc":  bson.M{"paid_amount": pay.Amount, "outstanding": -pay.Amount},
+		"$push": bson.M{"payments": pay},
+		"$set":  bson.M{"updated_at": now.Unix(), "updated_at_str": nowStr},
+	}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&out)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return dao.Receivable{}, errors.New("receivable not open or amount exceeds outstanding")
+		}
+		return dao.Receivable{}, err
+	}
+
+	status := dao.ReceivablePartial
+	if out.Outstanding <= 0.000001 {
+		status = dao.ReceivablePaid
+	}
+	if _, err := r.col.UpdateOne(ctx, bson.M{"uuid": uuid}, bson.M{"$set": bson.M{"status": status}}); err != nil {
+		return dao.Receivable{}, err
+	}
+	out.Status = status
+	return out, nil
+}
+
+func (r *ReceivableRepositoryImpl) Void(uuid string, voidedBy string) (dao.Receivable, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	var before dao.Receivable
+	err := r.col.FindOneAndUpdate(ctx, bson.M{
+		"uuid":   uuid,
+		"status": bson.M{"$ne": dao.ReceivableVoid},
+	}, bson.M{
+		"$set": bson.M{
+			"status":         dao.ReceivableVoid,
+			"voided_by":      voidedBy,
+			"updated_at":     now.Unix(),
+			"updated_at_str": now.Format(time.RFC3339),
+		},
+	}).Decode(&before)
+	// return dokumen SEBELUM void, biar caller tahu outstanding yang harus dilepas dari credit_balance
+	return before, err
+}
+
+func (r *ReceivableRepositoryImpl) OutstandingByBranch(clientUUID string) ([]dto.ReceivableBranchOutstanding, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"client_uuid": clientUUID,
+			"status":      bson.M{"$in": openReceivableStatus},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         "$branch_uuid",
+			"outstanding": bson.M{"$sum": "$outstanding"},
+			"count":       bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.M{"outstanding": -1}}},
+	}
+
+	cur, err := r.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var out []dto.ReceivableBranchOutstanding
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListDueForReminder: piutang open yang jatuh tempo <= dueBefore dan belum diingatkan sejak remindedBefore
+func (r *ReceivableRepositoryImpl) ListDueForReminder(dueBefore int64, remindedBefore int64, limit int64) ([]dao.Receivable, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cur, err := r.col.Find(ctx, bson.M{
+		"status":           bson.M{"$in": openReceivableStatus},
+		"due_date":         bson.M{"$gt": 0, "$lte": dueBefore},
+		"last_reminded_at": bson.M{"$lt": remindedBefore},
+	}, options.Find().SetSort(bson.D{{Key: "due_date", Value: 1}}).SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var out []dao.Receivable
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *ReceivableRepositoryImpl) MarkReminded(uuid string, at int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.col.UpdateOne(ctx, bson.M{"uuid": uuid}, bson.M{"$set": bson.M{"last_reminded_at": at}})
+	return err
+}
@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+)
+
+type ReplenishmentRepository interface {
+	// SalesVelocity: total qty_base terjual (trx PAID) per product_uuid sejak since
+	SalesVelocity(branchUUID string, since time.Time) (map[string]int64, error)
+	ActiveProducts(branchUUID string) ([]dao.Product, error)
+	FindProductBySKU(branchUUID, sku string) (dao.Product, error)
+
+	Insert(p *dao.ReplenishmentPlan) (dao.ReplenishmentPlan, error)
+	Detail(uuid string) (dao.ReplenishmentPlan, error)
+	List(req *dto.FilterRequest) ([]dao.ReplenishmentPlan, error)
+	SetStatus(uuid string, from, to dao.ReplenishmentStatus, extra bson.M) (dao.ReplenishmentPlan, error)
+	// DiscardDrafts: plan DRAFT lama di branch yang sama diganti plan baru
+	DiscardDrafts(branchUUID, by string) error
+
+	// low stock alert (worker): produk menipis yang belum diingatkan, dan reset flag yang stock-nya sudah naik
+	ListLowStockUnalerted(threshold int64, limit int64) ([]dao.Product, error)
+	SetLowStockAlert(productUUID string, alerted bool) error
+	ResetLowStockAlerts(threshold int64) (int64, error)
+}
+
+type ReplenishmentRepositoryImpl struct {
+	planCol    *mongo.Collection
+	productCol *mongo.Collection
+	posTrxCol  *mongo.Collection
+}
+
+func ReplenishmentRepositoryInit(mongoClient *mongo.Client) *ReplenishmentRepositoryImpl {
+	dbName := helpers.ProvideDBName()
+	db := mongoClient.Database(dbName)
+	return &ReplenishmentRepositoryImpl{
+		planCol:    db.Collection("replenishment_plans"),
+		productCol: db.Collection("products"),
+		posTrxCol:  db.Collection("pos_transactions"),
+	}
+}
+
+func (r *ReplenishmentRepositoryImpl) SalesVelocity(branchUUID string, since time.Time) (map[string]int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	cur, err := r.posTrxCol.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"status":      "PAID",
+			"branch_uuid": branchUUID,
+			"created_at":  bson.M{"$gte": since},
+		}}},
+		{{Key: "$unwind", Value: "$items"}},
+		{{Key: "$group", Value: bson.M{
+			"_id": "$items.product_uuid",
+			// trx lama belum punya qty_base => pakai qty
+			"qty": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$gt": bson.A{"$items.qty_base", 0}}, "$items.qty_base", "$items.qty",
+			}}},
+		}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var rows []struct {
+		ProductUUID string `bson:"_id"`
+		Qty         int64  `bson:"qty"`
+	}
+	if err := cur.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	out := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		out[row.ProductUUID] = row.Qty
+	}
+	return out, nil
+}
+
+func (r *ReplenishmentRepositoryImpl) ActiveProducts(branchUUID string) ([]dao.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	cur, err := r.productCol.Find(ctx, bson.M{"branch_uuid": branchUUID, "is_active": true},
+		options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var out []dao.Product
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *ReplenishmentRepositoryImpl) FindProductBySKU(branchUUID, sku string) (dao.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.Product
+	err := r.productCol.FindOne(ctx, bson.M{"branch_uuid": branchUUID, "sku": sku, "is_active": true}).Decode(&out)
+	return out, err
+}
+
+func (r *ReplenishmentRepositoryImpl) Insert(p *dao.ReplenishmentPlan) (dao.ReplenishmentPlan, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if p.ClientUUID == "" || p.BranchUUID == "" {
+		return dao.ReplenishmentPlan{}, errors.New("client_uuid & branch_uuid required")
+	}
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+	p.UUID = helpers.GenerateUUID()
+	p.CreatedAt = now
+	p.CreatedAtStr = nowStr
+	p.UpdatedAt = now.Unix()
+	p.UpdatedAtStr = nowStr
+	p.Status = dao.ReplenishmentDraft
+	if p.Code == "" {
+		p.Code = "RPL-" + now.Format("20060102-150405")
+	}
+
+	if _, err := r.planCol.InsertOne(ctx, p); err != nil {
+		return dao.ReplenishmentPlan{}, err
+	}
+	return *p, nil
+}
+
+func (r *ReplenishmentRepositoryImpl) Detail(uuid string) (dao.ReplenishmentPlan, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.ReplenishmentPlan
+	err := r.planCol.FindOne(ctx, bson.M{"uuid": uuid}).Decode(&out)
+	return out, err
+}
+
+func (r *ReplenishmentRepositoryImpl) List(req *dto.FilterRequest) ([]dao.ReplenishmentPlan, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	filter := buildListFilter(req, "code", "lines.name", "lines.sku")
+	cur, err := r.planCol.Find(ctx, filter, buildListOptions(req))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var out []dao.ReplenishmentPlan
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *ReplenishmentRepositoryImpl) SetStatus(uuid string, from, to dao.ReplenishmentStatus, extra bson.M) (dao.ReplenishmentPlan, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	set := bson.M{
+		"status":         to,
+		"updated_at":     now.Unix(),
+		"updated_at_str": now.Format(time.RFC3339),
+	}
+	for k, v := range extra {
+		set[k] = v
+	}
+
+	var out dao.ReplenishmentPlan
+	err := r.planCol.FindOneAndUpdate(ctx, bson.M{"uuid": uuid, "status": from}, bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&out)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return dao.ReplenishmentPlan{}, errors.New("invalid status: replenishment plan is not " + string(from))
+	}
+	return out, err
+}
+
+func (r *ReplenishmentRepositoryImpl) DiscardDrafts(branchUUID, by string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	_, err := r.planCol.UpdateMany(ctx, bson.M{"branch_uuid": branchUUID, "status": dao.ReplenishmentDraft}, bson.M{"$set": bson.M{
+		"status":         dao.ReplenishmentDiscarded,
+		"discarded_by":   by,
+		"updated_at":     now.Unix(),
+		"updated_at_str": now.Format(time.RFC3339),
+	}})
+	return err
+}
+
+func (r *ReplenishmentRepositoryImpl) ListLowStockUnalerted(threshold int64, limit int64) ([]dao.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := lowStockFilter(threshold)
+	filter["is_active"] = true
+	filter["low_stock_alert"] = bson.M{"$ne": true}
+
+	cur, err := r.productCol.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "branch_uuid", Value: 1}}).SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var out []dao.Product
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *ReplenishmentRepositoryImpl) SetLowStockAlert(productUUID string, alerted bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.productCol.UpdateOne(ctx, bson.M{"uuid": productUUID}, bson.M{"$set": bson.M{"low_stock_alert": alerted}})
+	return err
+}
+
+func (r *ReplenishmentRepositoryImpl) ResetLowStockAlerts(threshold int64) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := r.productCol.UpdateMany(ctx, bson.M{
+		"low_stock_alert": true,
+		"$nor":            bson.A{lowStockFilter(threshold)},
+	}, bson.M{"$set": bson.M{"low_stock_alert": false}})
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+)
+
+// ReportRepository: agregasi laporan dari pos_transactions (hanya trx PAID)
+type ReportRepository interface {
+	MarginByTransaction(branchUUIDs []string, from, to time.Time, limit int64) ([]dto.MarginRow, error)
+	MarginByProduct(branchUUIDs []string, from, to time.Time, limit int64) ([]dto.MarginRow, error)
+	MarginByBranch(branchUUIDs []string, from, to time.Time) ([]dto.MarginRow, error)
+	// MarginByCategory: per kategori produk saat ini (key = category_uuid, "" = tanpa kategori), roll-up di service
+	MarginByCategory(branchUUIDs []string, from, to time.Time) ([]dto.MarginRow, error)
+}
+
+type ReportRepositoryImpl struct {
+	posTrxCol *mongo.Collection
+}
+
+func ReportRepositoryInit(mongoClient *mongo.Client) *ReportRepositoryImpl {
+	dbName := helpers.ProvideDBName()
+	return &ReportRepositoryImpl{
+		posTrxCol: mongoClient.Database(dbName).Collection("pos_transactions"),
+	}
+}
+
+func paidTrxMatch(branchUUIDs []string, from, to time.Time) bson.D {
+	return bson.D{{Key: "$match", Value: bson.M{
+		"status":      "PAID",
+		"branch_uuid": bson.M{"$in": branchUUIDs},
+		"created_at":  bson.M{"$gte": from, "$lt": to},
+	}}}
+}
+
+// item tanpa cost (trx sebelum costing) dihitung terpisah
+var missingCostExpr = bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$items.cost", 0}}, 0, 1}}
+
+func (r *ReportRepositoryImpl) aggregateMargin(pipeline mongo.Pipeline) ([]dto.MarginRow, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	cur, err := r.posTrxCol.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	out := []dto.MarginRow{}
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *ReportRepositoryImpl) MarginByTransaction(branchUUIDs []string, from, to time.Time, limit int64) ([]dto.MarginRow, error) {
+	pipeline := mongo.Pipeline{
+		paidTrxMatch(branchUUIDs, from, to),
+		{{Key: "$sort", Value: bson.D{{Key: "created_at", Value: -1}}}},
+		{{Key: "$limit", Value: limit}},
+		{{Key: "$project", Value: bson.M{
+			"_id":          "$uuid",
+			"label":        "$receipt_no",
+			"transactions": bson.M{"$literal": 1},
+			"qty_base":     bson.M{"$sum": "$items.qty_base"},
+			"revenue":      "$total",
+			"cogs":         "$cogs",
+			"gross_profit": bson.M{"$subtract": bson.A{"$total", "$cogs"}},
+			"missing_cost_items": bson.M{"$size": bson.M{"$filter": bson.M{
+				"input": "$items",
+				"as":    "it",
+				"cond":  bson.M{"$lte": bson.A{"$$it.cost", 0}},
+			}}},
+		}}},
+	}
+	return r.aggregateMargin(pipeline)
+}
+
+// productMarginStages: 1 baris per produk, diskon transaksi dialokasikan proporsional ke line (line_total x total / sub_total)
+func productMarginStages(branchUUIDs []string, from, to time.Time) mongo.Pipeline {
+	return mongo.Pipeline{
+		paidTrxMatch(branchUUIDs, from, to),
+		{{Key: "$addFields", Value: bson.M{
+			"_ratio": bson.M{"$cond": bson.A{
+				bson.M{"$gt": bson.A{"$sub_total", 0}},
+				bson.M{"$divide": bson.A{"$total", "$sub_total"}},
+				1,
+			}},
+		}}},
+		{{Key: "$unwind", Value: "$items"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":                "$items.product_uuid",
+			"label":              bson.M{"$last": "$items.name"},
+			"sku":                bson.M{"$last": "$items.sku"},
+			"transactions":       bson.M{"$sum": 1},
+			"qty_base":           bson.M{"$sum": "$items.qty_base"},
+			"revenue":            bson.M{"$sum": bson.M{"$multiply": bson.A{"$items.line_total", "$_ratio"}}},
+			"cogs":               bson.M{"$sum": "$items.cost_total"},
+			"missing_cost_items": bson.M{"$sum": missingCostExpr},
+		}}},
+	}
+}
+
+func (r *ReportRepositoryImpl) MarginByProduct(branchUUIDs []string, from, to time.Time, limit int64) ([]dto.MarginRow, error) {
+	pipeline := append(productMarginStages(branchUUIDs, from, to),
+		bson.D{{Key: "$addFields", Value: bson.M{
+			"gross_profit": bson.M{"$subtract": bson.A{"$revenue", "$cogs"}},
+		}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "gross_profit", Value: -1}}}},
+		bson.D{{Key: "$limit", Value: limit}},
+	)
+	return r.aggregateMargin(pipeline)
+}
+
+func (r *ReportRepositoryImpl) MarginByCategory(branchUUIDs []string, from, to time.Time) ([]dto.MarginRow, error) {
+	pipeline := append(productMarginStages(branchUUIDs, from, to),
+		bson.D{{Key: "$lookup", Value: bson.M{
+			"from":         "products",
+			"localField":   "_id",
+			"foreignField": "uuid",
+			"as":           "_product",
+		}}},
+		bson.D{{Key: "$group", Value: bson.M{
+			"_id":                bson.M{"$ifNull": bson.A{bson.M{"$first": "$_product.category_uuid"}, ""}},
+			"label":              bson.M{"$first": bson.M{"$ifNull": bson.A{bson.M{"$first": "$_product.category_path"}, ""}}},
+			"transactions":       bson.M{"$sum": "$transactions"},
+			"qty_base":           bson.M{"$sum": "$qty_base"},
+			"revenue":            bson.M{"$sum": "$revenue"},
+			"cogs":               bson.M{"$sum": "$cogs"},
+			"missing_cost_items": bson.M{"$sum": "$missing_cost_items"},
+		}}},
+	)
+	return r.aggregateMargin(pipeline)
+}
+
+func (r *ReportRepositoryImpl) MarginByBranch(branchUUIDs []string, from, to time.Time) ([]dto.MarginRow, error) {
+	pipeline := mongo.Pipeline{
+		paidTrxMatch(branchUUIDs, from, to),
+		{{Key: "$group", Value: bson.M{
+			"_id":          "$branch_uuid",
+			"transactions": bson.M{"$sum": 1},
+			"qty_base":     bson.M{"$sum": bson.M{"$sum": "$items.qty_base"}},
+			"revenue":      bson.M{"$sum": "$total"},
+			"cogs":         bson.M{"$sum": "$cogs"},
+			"missing_cost_items": bson.M{"$sum": bson.M{"$size": bson.M{"$filter": bson.M{
+				"input": "$items",
+				"as":    "it",
+				"cond":  bson.M{"$lte": bson.A{"$$it.cost", 0}},
+			}}}},
+		}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "client_branches",
+			"localField":   "_id",
+			"foreignField": "uuid",
+			"as":           "_branch",
+		}}},
+		{{Key: "$addFields", Value: bson.M{
+			"label":        bson.M{"$ifNull": bson.A{bson.M{"$first": "$_branch.name"}, ""}},
+			"gross_profit": bson.M{"$subtract": bson.A{"$revenue", "$cogs"}},
+		}}},
+		{{Key: "$project", Value: bson.M{"_branch": 0}}},
+		{{Key: "$sort", Value: bson.D{{Key: "revenue", Value: -1}}}},
+	}
+	return r.aggregateMargin(pipeline)
+}
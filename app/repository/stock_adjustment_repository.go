@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+)
+
+type StockAdjustmentRepository interface {
+	Insert(a *dao.StockAdjustment) (dao.StockAdjustment, error)
+	Detail(uuid string) (dao.StockAdjustment, error)
+	List(req *dto.FilterRequest) ([]dao.StockAdjustment, error)
+
+	// SetStatus: transisi dengan guard status asal, dipakai juga sebagai "claim" sebelum apply
+	SetStatus(uuid string, from, to dao.StockAdjustmentStatus, extra bson.M) (dao.StockAdjustment, error)
+}
+
+type StockAdjustmentRepositoryImpl struct {
+	col *mongo.Collection
+}
+
+func StockAdjustmentRepositoryInit(mongoClient *mongo.Client) *StockAdjustmentRepositoryImpl {
+	dbName := helpers.ProvideDBName()
+	return &StockAdjustmentRepositoryImpl{
+		col: mongoClient.Database(dbName).Collection("stock_adjustments"),
+	}
+}
+
+func (r *StockAdjustmentRepositoryImpl) Insert(a *dao.StockAdjustment) (dao.StockAdjustment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if a.BranchUUID == "" {
+		return dao.StockAdjustment{}, errors.New("branch_uuid required")
+	}
+	if len(a.Items) == 0 {
+		return dao.StockAdjustment{}, errors.New("items required")
+	}
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+
+	a.UUID = helpers.GenerateUUID()
+	a.CreatedAt = now
+	a.CreatedAtStr = nowStr
+	a.UpdatedAt = now.Unix()
+	a.UpdatedAtStr = nowStr
+	if a.Code == "" {
+		a.Code = "ADJ-" + now.Format("20060102-150405")
+	}
+
+	if _, err := r.col.InsertOne(ctx, a); err != nil {
+		return dao.StockAdjustment{}, err
+	}
+	return *a, nil
+}
+
+func (r *StockAdjustmentRepositoryImpl) Detail(uuid string) (dao.StockAdjustment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.StockAdjustment
+	err := r.col.FindOne(ctx, bson.M{"uuid": uuid}).Decode(&out)
+	return out, err
+}
+
+func (r *StockAdjustmentRepositoryImpl) List(req *dto.FilterRequest) ([]dao.StockAdjustment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	filter := buildListFilter(req, "code", "note", "items.name", "items.sku")
+	cur, err := r.col.Find(ctx, filter, buildListOptions(req))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var out []dao.StockAdjustment
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *StockAdjustmentRepositoryImpl) SetStatus(uuid string, from, to dao.StockAdjustmentStatus, extra bson.M) (dao.StockAdjustment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	set := bson.M{
+		"status":         to,
+		"updated_at":     now.Unix(),
+		"updated_at_str": now.Format(time.RFC3339),
+	}
+	for k, v := range extra {
+		set[k] = v
+	}
+
+	var out dao.StockAdjustment
+	err := r.col.FindOneAndUpdate(ctx, bson.M{"uuid": uuid, "status": from}, bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&out)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return dao.StockAdjustment{}, errors.New("invalid status: stock adjustment is not " + string(from))
+	}
+	return out, err
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/helpers"
+)
+
+// stockLedger: semua perubahan products.stock lewat sini supaya selalu ada stock_movements.
+// Update stock ($inc + guard di filter) dan insert movement dengan balance hasil update
+// dalam 1 transaction mongo: gagal salah satu => dua-duanya batal.
+// Transaction butuh replica set / mongos (MONGO_URI standalone tidak bisa).
+type stockLedger struct {
+	client      *mongo.Client
+	productCol  *mongo.Collection
+	movementCol *mongo.Collection
+}
+
+func newStockLedger(db *mongo.Database) stockLedger {
+	return stockLedger{
+		client:      db.Client(),
+		productCol:  db.Collection("products"),
+		movementCol: db.Collection("stock_movements"),
+	}
+}
+
+// tx: jalankan fn dalam transaction. ctx yang sudah membawa session (dari tx caller) dipakai apa adanya,
+// jadi apply/applyIn di dalam tx caller ikut transaction caller (stock, lot, status transfer sekaligus).
+// fn bisa dijalankan ulang oleh driver (TransientTransactionError), jangan simpan state di luar fn.
+func (l stockLedger) tx(ctx context.Context, fn func(sc context.Context) error) error {
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+	sess, err := l.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer sess.EndSession(context.Background())
+
+	_, err = sess.WithTransaction(ctx, func(sc mongo.SessionContext) (any, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
+
+var errStockNotApplied = errors.New("stock not enough or product not found")
+
+// apply: $inc stock sebesar delta pada produk yang match filter.
+// setOnInsert != nil => upsert (produk dibuat kalau belum ada).
+func (l stockLedger) apply(ctx context.Context, filter bson.M, delta int64, ref dao.StockRef, setOnInsert bson.M) (dao.Product, error) {
+	if delta == 0 {
+		return dao.Product{}, errors.New("delta must not be 0")
+	}
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+
+	update := bson.M{
+		"$inc": bson.M{"stock": delta},
+		"$set": bson.M{"updated_at": now.Unix(), "updated_at_str": nowStr},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	if setOnInsert != nil {
+		update["$setOnInsert"] = setOnInsert
+		opts.SetUpsert(true)
+	}
+
+	var p dao.Product
+	err := l.tx(ctx, func(sc context.Context) error {
+		if err := l.productCol.FindOneAndUpdate(sc, filter, update, opts).Decode(&p); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return errStockNotApplied
+			}
+			return err
+		}
+		return l.record(sc, p, delta, p.Stock, ref)
+	})
+	if err != nil {
+		return dao.Product{}, err
+	}
+	return p, nil
+}
+
+// applyIn: stock masuk dengan harga (purchase, transfer in, adjustment plus).
+// Cost produk dihitung ulang moving weighted-average dalam 1 update pipeline (atomic):
+// cost baru = (max(stock,0) x cost lama + delta x unitCost) / (max(stock,0) + delta).
+// unitCost <= 0 => cost tidak berubah (sama dengan apply biasa).
+// Return juga stock + cost sebelum update untuk rollback (revert).
+func (l stockLedger) applyIn(ctx context.Context, filter bson.M, delta int64, unitCost float64, ref dao.StockRef, setOnInsert bson.M) (dao.Product, dao.StockBefore, error) {
+	if unitCost <= 0 {
+		p, err := l.apply(ctx, filter, delta, ref, setOnInsert)
+		return p, dao.StockBefore{Stock: p.Stock - delta, Cost: p.Cost}, err
+	}
+	if delta <= 0 {
+		return dao.Product{}, dao.StockBefore{}, errors.New("delta must be > 0")
+	}
+
+	now := time.Now()
+	oldStock := bson.M{"$max": bson.A{bson.M{"$ifNull": bson.A{"$stock", 0}}, 0}}
+	oldCost := bson.M{"$ifNull": bson.A{"$cost", 0}}
+	set := bson.M{
+		"cost": bson.M{"$round": bson.A{
+			bson.M{"$divide": bson.A{
+				bson.M{"$add": bson.A{bson.M{"$multiply": bson.A{oldStock, oldCost}}, float64(delta) * unitCost}},
+				bson.M{"$add": bson.A{oldStock, delta}},
+			}},
+			4,
+		}},
+		"stock":          bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$stock", 0}}, delta}},
+		"updated_at":     now.Unix(),
+		"updated_at_str": now.Format(time.RFC3339),
+	}
+	// dokumen sebelum update: hasil update dihitung ulang dari stock + cost lama
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+	if setOnInsert != nil {
+		// pipeline update tidak kenal $setOnInsert: field yang belum ada diisi via $ifNull
+		for k, v := range setOnInsert {
+			if _, computed := set[k]; computed {
+				continue
+			}
+			set[k] = bson.M{"$ifNull": bson.A{"$" + k, bson.M{"$literal": v}}}
+		}
+		opts.SetUpsert(true)
+	}
+
+	var p dao.Product
+	var prev dao.StockBefore
+	err := l.tx(ctx, func(sc context.Context) error {
+		var before dao.Product
+		err := l.productCol.FindOneAndUpdate(sc, filter, mongo.Pipeline{{{Key: "$set", Value: set}}}, opts).Decode(&before)
+		switch {
+		case errors.Is(err, mongo.ErrNoDocuments) && setOnInsert != nil:
+			// upsert membuat produk baru: tidak ada dokumen lama, ambil hasil insert
+			if err := l.productCol.FindOne(sc, filter).Decode(&p); err != nil {
+				return err
+			}
+			prev = dao.StockBefore{Stock: 0, Cost: p.Cost}
+		case errors.Is(err, mongo.ErrNoDocuments):
+			return errStockNotApplied
+		case err != nil:
+			return err
+		default:
+			// hasil update = rumus pipeline di atas (stock dari dokumen lama)
+			p = before
+			p.Stock = before.Stock + delta
+			p.Cost = weightedCost(before.Stock, before.Cost, delta, unitCost)
+			prev = dao.StockBefore{Stock: before.Stock, Cost: before.Cost}
+		}
+		return l.record(sc, p, delta, p.Stock, ref)
+	})
+	if err != nil {
+		return dao.Product{}, dao.StockBefore{}, err
+	}
+	return p, prev, nil
+}
+
+// revert: batalkan apply / applyIn (delta yang sama, hasil after). Stock dibalik dengan guard tidak minus,
+// cost dikembalikan ke cost sebelum stock masuk selama belum ada stock masuk lain (cost masih = after.Cost).
+func (l stockLedger) revert(ctx context.Context, after dao.Product, delta int64, before dao.StockBefore, ref dao.StockRef) error {
+	filter := bson.M{"uuid": after.UUID}
+	if delta > 0 {
+		filter["stock"] = bson.M{"$gte": delta}
+	}
+	return l.tx(ctx, func(sc context.Context) error {
+		if _, err := l.apply(sc, filter, -delta, ref, nil); err != nil {
+			return err
+		}
+		if before.Cost == after.Cost {
+			return nil
+		}
+		_, err := l.productCol.UpdateOne(sc,
+			bson.M{"uuid": after.UUID, "cost": after.Cost},
+			bson.M{"$set": bson.M{"cost": before.Cost}})
+		return err
+	})
+}
+
+// weightedCost: sama dengan pipeline applyIn ($round 4 desimal, half-to-even)
+func weightedCost(stock int64, cost float64, delta int64, unitCost float64) float64 {
+	base := float64(max(stock, 0))
+	return math.RoundToEven((base*cost+float64(delta)*unitCost)/(base+float64(delta))*1e4) / 1e4
+}
+
+// record: insert movement untuk perubahan yang sudah terjadi (mis. stock di-set langsung)
+func (l stockLedger) record(ctx context.Context, p dao.Product, delta int64, balanceAfter int64, ref dao.StockRef) error {
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+
+	mv := dao.StockMovement{
+		BaseModel: dao.BaseModel{
+			UUID:         helpers.GenerateUUID(),
+			CreatedAt:    now,
+			CreatedAtStr: nowStr,
+			UpdatedAt:    now.Unix(),
+			UpdatedAtStr: nowStr,
+		},
+		ProductUUID:  p.UUID,
+		BranchUUID:   p.BranchUUID,
+		SKU:          p.SKU,
+		Name:         p.Name,
+		Delta:        delta,
+		BalanceAfter: balanceAfter,
+		Reason:       ref.Reason,
+		RefUUID:      ref.RefUUID,
+		RefNo:        ref.RefNo,
+		Note:         ref.Note,
+		CreatedBy:    ref.CreatedBy,
+	}
+	_, err := l.movementCol.InsertOne(ctx, mv)
+	return err
+}
@@ -0,0 +1,28 @@
+package repository
+
+import "testing"
+
+func TestWeightedCost(t *testing.T) {
+	cases := []struct {
+		name     string
+		stock    int64
+		cost     float64
+		delta    int64
+		unitCost float64
+		want     float64
+	}{
+		{"first receipt", 0, 0, 10, 1000, 1000},
+		{"zero stock keeps no old cost", 0, 900, 10, 1000, 1000},
+		{"negative stock counts as zero", -5, 900, 10, 1000, 1000},
+		{"blend equal qty", 10, 1000, 10, 2000, 1500},
+		{"blend weighted", 30, 1000, 10, 2000, 1250},
+		{"same cost", 7, 1200, 3, 1200, 1200},
+		{"rounded to 4 decimals", 1, 0, 2, 1, 0.6667},
+		{"rounded down", 2, 0, 1, 1, 0.3333},
+	}
+	for _, c := range cases {
+		if got := weightedCost(c.stock, c.cost, c.delta, c.unitCost); got != c.want {
+			t.Errorf("%s: want %v, got %v", c.name, c.want, got)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+)
+
+type StockLotRepository interface {
+	// Receive: qty masuk ke lot (upsert per branch + produk + lot_no)
+	Receive(p dao.Product, lotNo string, expiryDate int64, qty int64) (dao.LotAllocation, error)
+	// Allocate: ambil qty dari lot FEFO (expiry paling dekat dulu). allowExpired=false => lot kedaluwarsa dilewati.
+	Allocate(p dao.Product, qty int64, allowExpired bool) ([]dao.LotAllocation, error)
+	// Restore: kembalikan qty ke lot asal (void / rollback)
+	Restore(allocs []dao.LotAllocation) error
+	// Revert: batalkan Receive (rollback)
+	Revert(allocs []dao.LotAllocation) error
+	// SyncProduct: stock yang belum punya lot (produk baru di-track, stock awal) dimasukkan ke lot OPENING
+	SyncProduct(p dao.Product) error
+
+	List(req *dto.FilterRequest) ([]dao.StockLot, error)
+	ListExpiring(branchUUIDs []string, before int64, limit int64) ([]dao.StockLot, error)
+	// ListToNotify: lot yang masuk window expiry / sudah kedaluwarsa tapi belum diingatkan untuk stage tsb
+	ListToNotify(soonBefore int64, now int64, limit int64) ([]dao.StockLot, error)
+	MarkNotified(uuid string, stage int) error
+}
+
+type StockLotRepositoryImpl struct {
+	lots lotLedger
+}
+
+func StockLotRepositoryInit(mongoClient *mongo.Client) *StockLotRepositoryImpl {
+	dbName := helpers.ProvideDBName()
+	return &StockLotRepositoryImpl{lots: newLotLedger(mongoClient.Database(dbName))}
+}
+
+// lotLedger: operasi stock_lots yang juga dipakai repository lain (transfer) tanpa lewat interface
+type lotLedger struct {
+	col *mongo.Collection
+}
+
+func newLotLedger(db *mongo.Database) lotLedger {
+	return lotLedger{col: db.Collection("stock_lots")}
+}
+
+const openingLotNo = "OPENING"
+
+func (l lotLedger) receive(ctx context.Context, p dao.Product, lotNo string, expiryDate int64, qty int64) (dao.LotAllocation, error) {
+	if p.UUID == "" || p.BranchUUID == "" {
+		return dao.LotAllocation{}, errors.New("product required")
+	}
+	if lotNo == "" {
+		return dao.LotAllocation{}, errors.New("lot_no required")
+	}
+	if qty <= 0 {
+		return dao.LotAllocation{}, errors.New("qty must be > 0")
+	}
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+	var lot dao.StockLot
+	err := l.col.FindOneAndUpdate(ctx, bson.M{
+		"branch_uuid":  p.BranchUUID,
+		"product_uuid": p.UUID,
+		"lot_no":       lotNo,
+	}, bson.M{
+		"$inc": bson.M{"qty": qty},
+		"$set": bson.M{
+			"sku":            p.SKU,
+			"name":           p.Name,
+			"updated_at":     now.Unix(),
+			"updated_at_str": nowStr,
+		},
+		"$setOnInsert": bson.M{
+			"uuid":            helpers.GenerateUUID(),
+			"expiry_date":     expiryDate,
+			"expiry_date_str": helpers.FormatDateISO(expiryDate),
+			"notified_stage":  0,
+			"created_at":      now,
+			"created_at_str":  nowStr,
+		},
+	}, options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)).Decode(&lot)
+	if err != nil {
+		return dao.LotAllocation{}, err
+	}
+	if expiryDate > 0 && lot.ExpiryDate != expiryDate {
+		// lot_no sama tapi expiry beda => kemungkinan salah input, batalkan
+		_, _ = l.col.UpdateOne(context.Background(), bson.M{"uuid": lot.UUID}, bson.M{"$inc": bson.M{"qty": -qty}})
+		return dao.LotAllocation{}, fmt.Errorf("lot %s already exists with expiry %s", lotNo, lot.ExpiryDateStr)
+	}
+	return lotAllocation(lot, qty), nil
+}
+
+func lotAllocation(lot dao.StockLot, qty int64) dao.LotAllocation {
+	return dao.LotAllocation{
+		LotUUID:       lot.UUID,
+		LotNo:         lot.LotNo,
+		ExpiryDate:    lot.ExpiryDate,
+		ExpiryDateStr: lot.ExpiryDateStr,
+		Qty:           qty,
+	}
+}
+
+// sortFEFO: expiry terdekat dulu, lot tanpa expiry paling akhir
+func sortFEFO(lots []dao.StockLot) {
+	sort.SliceStable(lots, func(i, j int) bool {
+		a, b := lots[i].ExpiryDate, lots[j].ExpiryDate
+		if a == 0 || b == 0 {
+			return b == 0 && a != 0
+		}
+		return a < b
+	})
+}
+
+func (l lotLedger) allocate(ctx context.Context, p dao.Product, qty int64, allowExpired bool) ([]dao.LotAllocation, error) {
+	if qty <= 0 {
+		return nil, errors.New("qty must be > 0")
+	}
+
+	cur, err := l.col.Find(ctx, bson.M{
+		"branch_uuid":  p.BranchUUID,
+		"product_uuid": p.UUID,
+		"qty":          bson.M{"$gt": 0},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var lots []dao.StockLot
+	if err := cur.All(ctx, &lots); err != nil {
+		return nil, err
+	}
+	sortFEFO(lots)
+
+	now := time.Now().Unix()
+	remaining := qty
+	var expiredQty int64
+	var out []dao.LotAllocation
+	for _, lot := range lots {
+		if remaining == 0 {
+			break
+		}
+		if !allowExpired && lot.Expired(now) {
+			expiredQty += lot.Qty
+			continue
+		}
+		take := min(lot.Qty, remaining)
+		// guard qty >= take: lot bisa keburu dipakai proses lain
+		res, err := l.col.UpdateOne(ctx, bson.M{"uuid": lot.UUID, "qty": bson.M{"$gte": take}},
+			bson.M{"$inc": bson.M{"qty": -take}, "$set": bson.M{"updated_at": now}})
+		if err != nil {
+			_ = l.shift(context.Background(), out, 1)
+			return nil, err
+		}
+		if res.MatchedCount == 0 {
+			continue
+		}
+		out = append(out, lotAllocation(lot, take))
+		remaining -= take
+	}
+
+	if remaining > 0 {
+		_ = l.shift(context.Background(), out, 1)
+		if expiredQty > 0 {
+			return nil, fmt.Errorf("%s: only expired lots left (need %d, expired %d)", p.Name, qty, expiredQty)
+		}
+		return nil, fmt.Errorf("%s: insufficient lot stock", p.Name)
+	}
+	return out, nil
+}
+
+// shift: $inc qty tiap alokasi x sign (+1 kembalikan, -1 tarik lagi)
+func (l lotLedger) shift(ctx context.Context, allocs []dao.LotAllocation, sign int64) error {
+	var firstErr error
+	for _, a := range allocs {
+		if a.LotUUID == "" || a.Qty == 0 {
+			continue
+		}
+		_, err := l.col.UpdateOne(ctx, bson.M{"uuid": a.LotUUID}, bson.M{"$inc": bson.M{"qty": a.Qty * sign}})
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (r *StockLotRepositoryImpl) Receive(p dao.Product, lotNo string, expiryDate int64, qty int64) (dao.LotAllocation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return r.lots.receive(ctx, p, lotNo, expiryDate, qty)
+}
+
+func (r *StockLotRepositoryImpl) Allocate(p dao.Product, qty int64, allowExpired bool) ([]dao.LotAllocation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+	return r.lots.allocate(ctx, p, qty, allowExpired)
+}
+
+func (r *StockLotRepositoryImpl) Restore(allocs []dao.LotAllocation) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+	return r.lots.shift(ctx, allocs, 1)
+}
+
+func (r *StockLotRepositoryImpl) Revert(allocs []dao.LotAllocation) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+	return r.lots.shift(ctx, allocs, -1)
+}
+
+func (r *StockLotRepositoryImpl) SyncProduct(p dao.Product) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+	return r.lots.sync(ctx, p)
+}
+
+func (l lotLedger) sync(ctx context.Context, p dao.Product) error {
+	if !p.TrackLots || p.Stock <= 0 {
+		return nil
+	}
+	cur, err := l.col.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"branch_uuid": p.BranchUUID, "product_uuid": p.UUID}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "qty": bson.M{"$sum": "$qty"}}}},
+	})
+	if err != nil {
+		return err
+	}
+	var rows []struct {
+		Qty int64 `bson:"qty"`
+	}
+	if err := cur.All(ctx, &rows); err != nil {
+		return err
+	}
+	var lotted int64
+	if len(rows) > 0 {
+		lotted = rows[0].Qty
+	}
+	if gap := p.Stock - lotted; gap > 0 {
+		_, err = l.receive(ctx, p, openingLotNo, 0, gap)
+	}
+	return err
+}
+
+func (r *StockLotRepositoryImpl) List(req *dto.FilterRequest) ([]dao.StockLot, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	filter := buildListFilter(req, "lot_no", "sku", "name")
+	cur, err := r.lots.col.Find(ctx, filter, buildListOptions(req))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var out []dao.StockLot
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *StockLotRepositoryImpl) ListExpiring(branchUUIDs []string, before int64, limit int64) ([]dao.StockLot, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	cur, err := r.lots.col.Find(ctx, bson.M{
+		"branch_uuid": bson.M{"$in": branchUUIDs},
+		"qty":         bson.M{"$gt": 0},
+		"expiry_date": bson.M{"$gt": 0, "$lt": before},
+	}, options.Find().SetSort(bson.D{{Key: "expiry_date", Value: 1}}).SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var out []dao.StockLot
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *StockLotRepositoryImpl) ListToNotify(soonBefore int64, now int64, limit int64) ([]dao.StockLot, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cur, err := r.lots.col.Find(ctx, bson.M{
+		"qty": bson.M{"$gt": 0},
+		"$or": []bson.M{
+			{"expiry_date": bson.M{"$gt": 0, "$lt": soonBefore}, "notified_stage": bson.M{"$lt": 1}},
+			{"expiry_date": bson.M{"$gt": 0, "$lte": now}, "notified_stage": bson.M{"$lt": 2}},
+		},
+	}, options.Find().SetSort(bson.D{{Key: "branch_uuid", Value: 1}, {Key: "expiry_date", Value: 1}}).SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var out []dao.StockLot
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *StockLotRepositoryImpl) MarkNotified(uuid string, stage int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.lots.col.UpdateOne(ctx, bson.M{"uuid": uuid}, bson.M{"$max": bson.M{"notified_stage": stage}})
+	return err
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"slices"
+	"testing"
+
+	"harjonan.id/user-service/app/domain/dao"
+)
+
+func TestSortFEFO(t *testing.T) {
+	cases := []struct {
+		name string
+		lots []dao.StockLot
+		want []string
+	}{
+		{"nearest expiry first", []dao.StockLot{
+			{LotNo: "C", ExpiryDate: 300},
+			{LotNo: "A", ExpiryDate: 100},
+			{LotNo: "B", ExpiryDate: 200},
+		}, []string{"A", "B", "C"}},
+		{"no expiry last", []dao.StockLot{
+			{LotNo: "N1"},
+			{LotNo: "A", ExpiryDate: 100},
+			{LotNo: "N2"},
+			{LotNo: "B", ExpiryDate: 200},
+		}, []string{"A", "B", "N1", "N2"}},
+		{"same expiry keeps order", []dao.StockLot{
+			{LotNo: "B", ExpiryDate: 100},
+			{LotNo: "A", ExpiryDate: 100},
+		}, []string{"B", "A"}},
+		{"all without expiry", []dao.StockLot{
+			{LotNo: "N2"},
+			{LotNo: "N1"},
+		}, []string{"N2", "N1"}},
+		{"empty", nil, []string{}},
+	}
+	for _, c := range cases {
+		sortFEFO(c.lots)
+		got := make([]string, 0, len(c.lots))
+		for _, l := range c.lots {
+			got = append(got, l.LotNo)
+		}
+		if !slices.Equal(got, c.want) {
+			t.Errorf("%s: want %v, got %v", c.name, c.want, got)
+		}
+	}
+}
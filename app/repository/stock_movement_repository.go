@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+)
+
+// StockMovementRepository: read-only, movement ditulis lewat stockLedger
+type StockMovementRepository interface {
+	List(req *dto.FilterRequest) ([]dao.StockMovement, error)
+	// ListSince: movement branch sejak waktu tertentu (urut created_at asc), excludeRef di-skip
+	ListSince(branchUUID string, since time.Time, excludeRef string) ([]dao.StockMovement, error)
+}
+
+type StockMovementRepositoryImpl struct {
+	col *mongo.Collection
+}
+
+func StockMovementRepositoryInit(mongoClient *mongo.Client) *StockMovementRepositoryImpl {
+	dbName := helpers.ProvideDBName()
+	return &StockMovementRepositoryImpl{
+		col: mongoClient.Database(dbName).Collection("stock_movements"),
+	}
+}
+
+func (r *StockMovementRepositoryImpl) List(req *dto.FilterRequest) ([]dao.StockMovement, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	filter := buildListFilter(req, "ref_no", "note")
+	cur, err := r.col.Find(ctx, filter, buildListOptions(req))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	out := make([]dao.StockMovement, 0)
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *StockMovementRepositoryImpl) ListSince(branchUUID string, since time.Time, excludeRef string) ([]dao.StockMovement, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"branch_uuid": branchUUID,
+		"created_at":  bson.M{"$gte": since},
+	}
+	if excludeRef != "" {
+		filter["ref_uuid"] = bson.M{"$ne": excludeRef}
+	}
+	cur, err := r.col.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	out := make([]dao.StockMovement, 0)
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
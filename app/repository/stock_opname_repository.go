@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+)
+
+type StockOpnameRepository interface {
+	// Start: buat sesi + snapshot stock semua produk aktif di branch
+	Start(op *dao.StockOpname) (dao.StockOpname, error)
+	Detail(uuid string) (dao.StockOpname, error)
+	List(req *dto.FilterRequest) ([]dao.StockOpname, error)
+	FindOpen(branchUUID string) (dao.StockOpname, error)
+
+	ListItems(opnameUUID string) ([]dao.StockOpnameItem, error)
+	FindItem(opnameUUID string, code string) (dao.StockOpnameItem, error)
+	AddCount(itemUUID string, c dao.StockOpnameCount) (dao.StockOpnameItem, error)
+	SaveItemResult(it dao.StockOpnameItem) error
+	// ClaimItemPost: posted false -> true (atomic), supaya approve paralel tidak double posting
+	ClaimItemPost(itemUUID string) error
+	UnclaimItemPost(itemUUID string) error
+
+	// SetStatus: transisi status dengan guard status asal (anti double approve)
+	SetStatus(uuid string, from, to dao.StockOpnameStatus, extra bson.M) (dao.StockOpname, error)
+}
+
+type StockOpnameRepositoryImpl struct {
+	col        *mongo.Collection
+	itemCol    *mongo.Collection
+	productCol *mongo.Collection
+}
+
+func StockOpnameRepositoryInit(mongoClient *mongo.Client) *StockOpnameRepositoryImpl {
+	dbName := helpers.ProvideDBName()
+	db := mongoClient.Database(dbName)
+	return &StockOpnameRepositoryImpl{
+		col:        db.Collection("stock_opnames"),
+		itemCol:    db.Collection("stock_opname_items"),
+		productCol: db.Collection("products"),
+	}
+}
+
+func (r *StockOpnameRepositoryImpl) Start(op *dao.StockOpname) (dao.StockOpname, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if op.BranchUUID == "" {
+		return dao.StockOpname{}, errors.New("branch_uuid required")
+	}
+	if _, err := r.FindOpen(op.BranchUUID); err == nil {
+		return dao.StockOpname{}, errors.New("branch already has an open stock opname")
+	}
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+
+	op.UUID = helpers.GenerateUUID()
+	op.CreatedAt = now
+	op.CreatedAtStr = nowStr
+	op.UpdatedAt = now.Unix()
+	op.UpdatedAtStr = nowStr
+	op.Status = dao.StockOpnameOpen
+	op.SnapshotAt = now
+	if op.Code == "" {
+		op.Code = "SO-" + now.Format("20060102-150405")
+	}
+
+	// snapshot
+	cur, err := r.productCol.Find(ctx, bson.M{"branch_uuid": op.BranchUUID, "is_active": true})
+	if err != nil {
+		return dao.StockOpname{}, err
+	}
+	var products []dao.Product
+	if err := cur.All(ctx, &products); err != nil {
+		return dao.StockOpname{}, err
+	}
+
+	docs := make([]any, 0, len(products))
+	for _, p := range products {
+		docs = append(docs, dao.StockOpnameItem{
+			BaseModel: dao.BaseModel{
+				UUID:         helpers.GenerateUUID(),
+				CreatedAt:    now,
+				CreatedAtStr: nowStr,
+				UpdatedAt:    now.Unix(),
+				UpdatedAtStr: nowStr,
+			},
+			OpnameUUID:  op.UUID,
+			ProductUUID: p.UUID,
+			SKU:         p.SKU,
+			Barcode:     p.Barcode,
+			Name:        p.Name,
+			BaseUnit:    p.BaseUnit,
+			Cost:        p.Cost,
+			SnapshotQty: p.Stock,
+			Counts:      []dao.StockOpnameCount{},
+		})
+	}
+	op.TotalItems = len(docs)
+
+	if len(docs) > 0 {
+		if _, err := r.itemCol.InsertMany(ctx, docs); err != nil {
+			_, _ = r.itemCol.DeleteMany(context.Background(), bson.M{"opname_uuid": op.UUID})
+			return dao.StockOpname{}, err
+		}
+	}
+	if _, err := r.col.InsertOne(ctx, op); err != nil {
+		_, _ = r.itemCol.DeleteMany(context.Background(), bson.M{"opname_uuid": op.UUID})
+		return dao.StockOpname{}, err
+	}
+	return *op, nil
+}
+
+func (r *StockOpnameRepositoryImpl) Detail(uuid string) (dao.StockOpname, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.StockOpname
+	err := r.col.FindOne(ctx, bson.M{"uuid": uuid}).Decode(&out)
+	return out, err
+}
+
+func (r *StockOpnameRepositoryImpl) List(req *dto.FilterRequest) ([]dao.StockOpname, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	filter := buildListFilter(req, "code", "note")
+	cur, err := r.col.Find(ctx, filter, buildListOptions(req))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var out []dao.StockOpname
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *StockOpnameRepositoryImpl) FindOpen(branchUUID string) (dao.StockOpname, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.StockOpname
+	err := r.col.FindOne(ctx, bson.M{"branch_uuid": branchUUID, "status": dao.StockOpnameOpen}).Decode(&out)
+	return out, err
+}
+
+func (r *StockOpnameRepositoryImpl) ListItems(opnameUUID string) ([]dao.StockOpnameItem, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	cur, err := r.itemCol.Find(ctx, bson.M{"opname_uuid": opnameUUID}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	out := make([]dao.StockOpnameItem, 0)
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FindItem: code = barcode / sku / product_uuid
+func (r *StockOpnameRepositoryImpl) FindItem(opnameUUID string, code string) (dao.StockOpnameItem, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.StockOpnameItem
+	err := r.itemCol.FindOne(ctx, bson.M{
+		"opname_uuid": opnameUUID,
+		"$or": bson.A{
+			bson.M{"barcode": code},
+			bson.M{"sku": code},
+			bson.M{"product_uuid": code},
+		},
+	}).Decode(&out)
+	return out, err
+}
+
+// AddCount: hasil scan counter dijumlah (atomic, aman untuk beberapa counter paralel)
+func (r *StockOpnameRepositoryImpl) AddCount(itemUUID string, c dao.StockOpnameCount) (dao.StockOpnameItem, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	c.At = now.Unix()
+
+	var out dao.StockOpnameItem
+	err := r.itemCol.FindOneAndUpdate(ctx, bson.M{"uuid": itemUUID}, bson.M{
+		"$inc":  bson.M{"counted_qty": c.Qty},
+		"$push": bson.M{"counts": c},
+		"$set": bson.M{
+			"counted":         true,
+			"last_counted_at": now,
+			"updated_at":      now.Unix(),
+			"updated_at_str":  now.Format(time.RFC3339),
+		},
+	}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&out)
+	if err != nil {
+		return dao.StockOpnameItem{}, err
+	}
+	if out.CountedQty < 0 {
+		// koreksi scan tidak boleh bikin hitungan minus
+		_, _ = r.itemCol.UpdateOne(context.Background(), bson.M{"uuid": itemUUID}, bson.M{
+			"$inc":  bson.M{"counted_qty": -c.Qty},
+			"$pull": bson.M{"counts": bson.M{"at": c.At, "counter_uuid": c.CounterUUID, "qty": c.Qty}},
+		})
+		return dao.StockOpnameItem{}, errors.New("counted qty cannot be negative")
+	}
+	return out, nil
+}
+
+func (r *StockOpnameRepositoryImpl) SaveItemResult(it dao.StockOpnameItem) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.itemCol.UpdateOne(ctx, bson.M{"uuid": it.UUID}, bson.M{"$set": bson.M{
+		"movement_qty":   it.MovementQty,
+		"expected_qty":   it.ExpectedQty,
+		"variance_qty":   it.VarianceQty,
+		"variance_value": it.VarianceValue,
+	}})
+	return err
+}
+
+func (r *StockOpnameRepositoryImpl) ClaimItemPost(itemUUID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := r.itemCol.UpdateOne(ctx, bson.M{"uuid": itemUUID, "posted": false}, bson.M{"$set": bson.M{"posted": true}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errors.New("item already posted")
+	}
+	return nil
+}
+
+func (r *StockOpnameRepositoryImpl) UnclaimItemPost(itemUUID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.itemCol.UpdateOne(ctx, bson.M{"uuid": itemUUID}, bson.M{"$set": bson.M{"posted": false}})
+	return err
+}
+
+func (r *StockOpnameRepositoryImpl) SetStatus(uuid string, from, to dao.StockOpnameStatus, extra bson.M) (dao.StockOpname, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	set := bson.M{
+		"status":         to,
+		"updated_at":     now.Unix(),
+		"updated_at_str": now.Format(time.RFC3339),
+	}
+	for k, v := range extra {
+		set[k] = v
+	}
+
+	var out dao.StockOpname
+	err := r.col.FindOneAndUpdate(ctx, bson.M{"uuid": uuid, "status": from}, bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&out)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return dao.StockOpname{}, errors.New("invalid status: stock opname is not " + string(from))
+	}
+	return out, err
+}
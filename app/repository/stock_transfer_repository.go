@@ -3,8 +3,11 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 	"reflect"
+	"slices"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -17,21 +20,42 @@ import (
 )
 
 type StockTransferRepository interface {
-	CreateDraft(data *dao.StockTransfer) (dao.StockTransfer, error)
+	// CreateDraft: event REQUESTED oleh actor (user token), validasi item => ErrStockTransferInvalid
+	CreateDraft(data *dao.StockTransfer, actor dao.StockTransferActor) (dao.StockTransfer, error)
 	Detail(uuid string) (dao.StockTransfer, error)
 	List(req *dto.FilterRequest) ([]dao.StockTransfer, error)
 
-	WarehouseApprove(uuid, notes, approvedBy string) (dao.StockTransfer, error)
-	DriverAccept(uuid, notes, acceptedBy string) (dao.StockTransfer, error)
-	ReceiveDone(uuid, notes, receivedBy string) (dao.StockTransfer, error)
+	// role + guard tiap transisi dicek dao.FireStockTransfer, error ErrStockTransferState / ErrStockTransferForbidden;
+	// data item / qty salah => ErrStockTransferInvalid
+	WarehouseApprove(uuid, notes string, actor dao.StockTransferActor) (dao.StockTransfer, error)
+	DriverAccept(uuid, notes string, actor dao.StockTransferActor) (dao.StockTransfer, error)
+	ReceiveDone(uuid string, req dto.StockTransferReceiveReq, clientUUID string, actor dao.StockTransferActor) (dao.StockTransfer, error)
+
+	// Close: REJECTED / CANCELLED / DRIVER_DECLINED, transfer berhenti sebelum barang jalan
+	Close(uuid string, ev dao.StockTransferEvent, reason string, actor dao.StockTransferActor) (dao.StockTransfer, error)
+	// ExpireStale: transfer open yang tidak bergerak sejak before (updated_at) -> EXPIRED
+	ExpireStale(before int64, reason string, limit int64) ([]dao.StockTransfer, error)
+	// RestorePending: ulangi pengembalian stock transfer tertutup yang sempat gagal di tengah
+	RestorePending(limit int64) (int, error)
+
+	ListDiscrepancies(req *dto.FilterRequest) ([]dao.StockTransferDiscrepancy, error)
+	DetailDiscrepancy(uuid string) (dao.StockTransferDiscrepancy, error)
+	// ResolveDiscrepancy: OPEN -> RESOLVED (atomic). RETURN: stock + lot asal dikembalikan ke branch asal.
+	ResolveDiscrepancy(uuid string, resolution dao.StockDiscrepancyResolution, note, resolvedBy, reshipUUID string) (dao.StockTransferDiscrepancy, error)
+	// DiscrepancyReport: per driver + branch asal + branch tujuan, created_at [from, to)
+	DiscrepancyReport(clientUUID string, from, to time.Time, limit int64) ([]dto.StockDiscrepancyReportRow, error)
 }
 
 type StockTransferRepositoryImpl struct {
 	db          *mongo.Database
 	transferCol *mongo.Collection
 	productCol  *mongo.Collection
-	userCol     *mongo.Collection
-	roleCol     *mongo.Collection
+	ledger      stockLedger
+	lots        lotLedger
+
+	discrepancyCol *mongo.Collection
+	branchCol      *mongo.Collection
+	usersCol       *mongo.Collection
 }
 
 func StockTransferRepositoryInit(mongoClient *mongo.Client) *StockTransferRepositoryImpl {
@@ -41,33 +65,44 @@ func StockTransferRepositoryInit(mongoClient *mongo.Client) *StockTransferReposi
 		db:          db,
 		transferCol: db.Collection("stock_transfers"),
 		productCol:  db.Collection("products"),
-		userCol:     db.Collection("client_users"),
-		roleCol:     db.Collection("roles"),
+		ledger:      newStockLedger(db),
+		lots:        newLotLedger(db),
+
+		discrepancyCol: db.Collection("stock_transfer_discrepancies"),
+		branchCol:      db.Collection("client_branches"),
+		usersCol:       db.Collection("users"),
 	}
 }
 
-func (r *StockTransferRepositoryImpl) CreateDraft(data *dao.StockTransfer) (dao.StockTransfer, error) {
+func (r *StockTransferRepositoryImpl) CreateDraft(data *dao.StockTransfer, actor dao.StockTransferActor) (dao.StockTransfer, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 7*time.Second)
 	defer cancel()
 
 	log.Print("Creating stock transfer with driver: ", data.DriverUUID)
 
+	data.Status = ""
+	t, err := dao.FireStockTransfer(*data, dao.StockTransferEventRequest, actor)
+	if err != nil {
+		return dao.StockTransfer{}, err
+	}
+
 	for i := range data.Items {
 		it := &data.Items[i] // ✅ pointer ke item asli di slice
 
 		if it.ProductUUID == "" || it.Qty <= 0 {
-			return dao.StockTransfer{}, errors.New("invalid item qty")
+			return dao.StockTransfer{}, fmt.Errorf("%w: invalid item qty", dao.ErrStockTransferInvalid)
 		}
 
 		var product dao.Product
 		err := r.productCol.FindOne(ctx, bson.M{"uuid": it.ProductUUID}).Decode(&product)
 		if err != nil {
 			if errors.Is(err, mongo.ErrNoDocuments) {
-				return dao.StockTransfer{}, errors.New("product not found: " + it.ProductUUID)
+				return dao.StockTransfer{}, fmt.Errorf("%w: product not found: %s", dao.ErrStockTransferInvalid, it.ProductUUID)
 			}
 			return dao.StockTransfer{}, err
 		}
 
+		it.CatalogUUID = product.CatalogUUID
 		it.SKU = product.SKU
 		it.Barcode = product.Barcode
 		it.Name = product.Name
@@ -77,6 +112,7 @@ func (r *StockTransferRepositoryImpl) CreateDraft(data *dao.StockTransfer) (dao.
 		it.Cost = product.Cost
 		it.Price = product.Price
 		it.Image = product.Image
+		it.Lots = nil // diisi saat approve gudang
 	}
 
 	log.Print("Product items: ", data.Items)
@@ -89,7 +125,8 @@ func (r *StockTransferRepositoryImpl) CreateDraft(data *dao.StockTransfer) (dao.
 	data.CreatedAtStr = nowStr
 	data.UpdatedAt = now.Unix()
 	data.UpdatedAtStr = nowStr
-	data.Status = dao.StockTransferPendingWarehouse
+	data.Status = t.To
+	data.History = []dao.StockTransferHistory{t.Record("", actor, data.RequesterNote, now)}
 
 	if _, err := r.transferCol.InsertOne(ctx, data); err != nil {
 		return dao.StockTransfer{}, err
@@ -107,6 +144,7 @@ func (r *StockTransferRepositoryImpl) Detail(uuid string) (dao.StockTransfer, er
 
 	var out dao.StockTransfer
 	err := r.transferCol.FindOne(ctx, bson.M{"uuid": uuid}).Decode(&out)
+	out.NormalizeNotes()
 	return out, err
 }
 
@@ -260,217 +298,137 @@ func (r *StockTransferRepositoryImpl) List(req *dto.FilterRequest) ([]dao.StockT
 	if err := cur.All(ctx, &list); err != nil {
 		return nil, err
 	}
+	for i := range list {
+		list[i].NormalizeNotes()
+	}
 
 	return list, nil
 }
 
 /*
-WarehouseApprove:
-- Validasi status harus PENDING_WAREHOUSE
-- Validasi driver_uuid adalah role DRIVER (client_users.role_value == "DRIVER")
+WarehouseApprove (event WAREHOUSE_APPROVED, role GUDANG):
 - Untuk setiap item: cek stock gudang cukup (products.branch_uuid=from_branch AND uuid=item.product_uuid)
 - Potong stock gudang (stock -= qty)
 - Update transfer: status WAITING_DRIVER, driver_uuid, approved_at
 ALL IN TRANSACTION
 */
-func (r *StockTransferRepositoryImpl) WarehouseApprove(uuid, notes, approvedBy string) (dao.StockTransfer, error) {
+func (r *StockTransferRepositoryImpl) WarehouseApprove(uuid, notes string, actor dao.StockTransferActor) (dao.StockTransfer, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
 	defer cancel()
 
-	// validate warehouse role
-	var approver bson.M
-	if err := r.userCol.FindOne(ctx, bson.M{"user_uuid": approvedBy}).Decode(&approver); err != nil {
-		return dao.StockTransfer{}, errors.New("approver not found / not WAREHOUSE role")
-	}
-	var role bson.M
-	if err := r.roleCol.FindOne(ctx, bson.M{"uuid": approver["role_uuid"]}).Decode(&role); err != nil {
-		return dao.StockTransfer{}, errors.New("approver not found / not WAREHOUSE role")
-	}
-	if role["value"] != "GUDANG" {
-		return dao.StockTransfer{}, errors.New("approver not found / not WAREHOUSE role")
-	}
-
+	approvedBy := actor.UserUUID
 	now := time.Now()
 	nowStr := now.Format(time.RFC3339)
 
-	// 1) ambil transfer (no txn) + validasi status
+	// 1) ambil transfer (no txn) + validasi transisi
 	var tr dao.StockTransfer
 	if err := r.transferCol.FindOne(ctx, bson.M{"uuid": uuid}).Decode(&tr); err != nil {
 		return dao.StockTransfer{}, err
 	}
-	if tr.Status != dao.StockTransferPendingWarehouse {
-		return dao.StockTransfer{}, errors.New("invalid status: must be PENDING_WAREHOUSE")
-	}
-
-	// 2) potong stock per item dengan update atomic (filter stock >= qty)
-	// simpan list yang sudah berhasil dipotong, untuk rollback kalau ada error
-	type cutItem struct {
-		ProductUUID string
-		Qty         int64
+	t, err := dao.FireStockTransfer(tr, dao.StockTransferEventApprove, actor)
+	if err != nil {
+		return dao.StockTransfer{}, err
 	}
-	cut := make([]cutItem, 0, len(tr.Items))
 
-	rollback := func() {
-		// best-effort rollback
-		for _, it := range cut {
-			filter := bson.M{"uuid": it.ProductUUID, "branch_uuid": tr.FromBranchUUID}
-			_, _ = r.productCol.UpdateOne(context.Background(), filter, bson.M{
-				"$inc": bson.M{"stock": it.Qty},
-			})
-		}
-	}
+	// 2) potong stock per item (filter stock >= qty) + update status dalam 1 transaction:
+	// error di item mana pun / status keburu berubah => semua potongan batal
+	outRef := dao.StockRef{Reason: dao.StockMoveTransferOut, RefUUID: tr.UUID, Note: notes, CreatedBy: approvedBy}
+	var out dao.StockTransfer
+	err = r.ledger.tx(ctx, func(sc context.Context) error {
+		items := slices.Clone(tr.Items)
+		for i, it := range items {
+			if it.ProductUUID == "" || it.Qty <= 0 {
+				return fmt.Errorf("%w: invalid item qty", dao.ErrStockTransferInvalid)
+			}
 
-	for _, it := range tr.Items {
-		if it.ProductUUID == "" || it.Qty <= 0 {
-			rollback()
-			return dao.StockTransfer{}, errors.New("invalid item qty")
-		}
+			filter := bson.M{
+				"uuid":        it.ProductUUID,
+				"branch_uuid": tr.FromBranchUUID,
+				"stock":       bson.M{"$gte": it.Qty}, // IMPORTANT: cegah minus secara atomic
+			}
 
-		filter := bson.M{
-			"uuid":        it.ProductUUID,
-			"branch_uuid": tr.FromBranchUUID,
-			"stock":       bson.M{"$gte": it.Qty}, // IMPORTANT: cegah minus secara atomic
-		}
+			p, err := r.ledger.apply(sc, filter, -it.Qty, outRef, nil)
+			if errors.Is(err, errStockNotApplied) {
+				// bisa karena product tidak ada di branch tsb, atau stok kurang
+				return fmt.Errorf("%w: product not found in from_branch or insufficient stock", dao.ErrStockTransferInvalid)
+			}
+			if err != nil {
+				return err
+			}
 
-		res, err := r.productCol.UpdateOne(ctx, filter, bson.M{
-			"$inc": bson.M{"stock": -it.Qty},
-			"$set": bson.M{"updated_at": now.Unix(), "updated_at_str": nowStr},
-		})
-		if err != nil {
-			rollback()
-			return dao.StockTransfer{}, err
-		}
-		if res.MatchedCount == 0 {
-			// bisa karena product tidak ada di branch tsb, atau stok kurang
-			// optional: fetch sku untuk error message (extra query)
-			rollback()
-			return dao.StockTransfer{}, errors.New("product not found in from_branch or insufficient stock")
+			// produk track_lots: lot dikirim FEFO, lot kedaluwarsa tidak ikut dikirim
+			if p.TrackLots {
+				lots, err := r.lots.allocate(sc, p, it.Qty, false)
+				if err != nil {
+					return fmt.Errorf("%w: %v", dao.ErrStockTransferInvalid, err)
+				}
+				items[i].Lots = lots
+			}
+			// cost asal saat barang keluar, dipakai weighted-average di branch tujuan
+			items[i].Cost = p.Cost
 		}
 
-		cut = append(cut, cutItem{ProductUUID: it.ProductUUID, Qty: it.Qty})
-	}
-
-	// 3) update transfer dengan guard status masih PENDING_WAREHOUSE (hindari double-approve race)
-	updFilter := bson.M{"uuid": uuid, "status": dao.StockTransferPendingWarehouse}
-	upd := bson.M{
-		"$set": bson.M{
-			"status":          dao.StockTransferWaitingDriver,
+		// 3) update transfer dengan guard status asal (hindari double-approve race)
+		var err error
+		out, err = r.transition(sc, bson.M{"uuid": uuid}, tr.Status, t, actor, notes, now, bson.M{
+			"items":           items,
 			"approved_by":     approvedBy,
-			"approver_notes":  notes,
+			"approver_note":   notes,
 			"approved_at":     now.Unix(),
 			"approved_at_str": nowStr,
-			"updated_at":      now.Unix(),
-			"updated_at_str":  nowStr,
-		},
-	}
-
-	updRes, err := r.transferCol.UpdateOne(ctx, updFilter, upd)
+		})
+		return err
+	})
 	if err != nil {
-		rollback()
 		return dao.StockTransfer{}, err
 	}
-	if updRes.MatchedCount == 0 {
-		// status keburu berubah oleh proses lain -> rollback potongan stock
-		rollback()
-		return dao.StockTransfer{}, errors.New("approve failed: transfer status already changed")
-	}
-
-	return r.Detail(uuid)
+	return out, nil
 }
 
 /*
-DriverAccept:
-- status harus WAITING_DRIVER
-- driverUUID must match transfer.driver_uuid
+DriverAccept (event DRIVER_ACCEPTED, role DRIVER yang ditugaskan):
 - update status IN_PROGRESS
 */
-func (r *StockTransferRepositoryImpl) DriverAccept(uuid, notes, acceptedBy string) (dao.StockTransfer, error) {
+func (r *StockTransferRepositoryImpl) DriverAccept(uuid, notes string, actor dao.StockTransferActor) (dao.StockTransfer, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 7*time.Second)
 	defer cancel()
 
 	now := time.Now()
-	nowStr := now.Format(time.RFC3339)
-
-	// validate driver role
-	var driver bson.M
-	if err := r.userCol.FindOne(ctx, bson.M{"user_uuid": acceptedBy}).Decode(&driver); err != nil {
-		return dao.StockTransfer{}, errors.New("driver not found / not DRIVER role")
-	}
-	var role bson.M
-	if err := r.roleCol.FindOne(ctx, bson.M{"uuid": driver["role_uuid"]}).Decode(&role); err != nil {
-		return dao.StockTransfer{}, errors.New("driver not found / not DRIVER role")
-	}
-	if role["value"] != "DRIVER" {
-		return dao.StockTransfer{}, errors.New("driver not found / not DRIVER role")
-	}
 
-	// ambil transfer untuk validasi driver assigned (biar error message jelas)
 	var tr dao.StockTransfer
 	if err := r.transferCol.FindOne(ctx, bson.M{"uuid": uuid}).Decode(&tr); err != nil {
 		return dao.StockTransfer{}, err
 	}
-	if tr.DriverUUID != acceptedBy {
-		return dao.StockTransfer{}, errors.New("forbidden: driver not assigned")
-	}
-	if tr.Status != dao.StockTransferWaitingDriver {
-		return dao.StockTransfer{}, errors.New("invalid status: must be WAITING_DRIVER")
-	}
-
-	// update dengan guard status + driver_uuid (anti race / double accept)
-	filter := bson.M{
-		"uuid":        uuid,
-		"status":      dao.StockTransferWaitingDriver,
-		"driver_uuid": acceptedBy,
-	}
-
-	res, err := r.transferCol.UpdateOne(ctx, filter, bson.M{
-		"$set": bson.M{
-			"status":          dao.StockTransferInProgress,
-			"accepted_by":     acceptedBy,
-			"acceptor_notes":  notes,
-			"accepted_at":     now.Unix(),
-			"accepted_at_str": nowStr,
-			"notes":           notes,
-			"updated_at":      now.Unix(),
-			"updated_at_str":  nowStr,
-		},
-	})
+	t, err := dao.FireStockTransfer(tr, dao.StockTransferEventDriverAccept, actor)
 	if err != nil {
 		return dao.StockTransfer{}, err
 	}
-	if res.MatchedCount == 0 {
-		return dao.StockTransfer{}, errors.New("accept failed: transfer status already changed or driver mismatch")
-	}
 
-	return r.Detail(uuid)
+	// guard status + driver_uuid (anti race / double accept)
+	return r.transition(ctx, bson.M{"uuid": uuid, "driver_uuid": actor.UserUUID}, tr.Status, t, actor, notes, now, bson.M{
+		"accepted_by":     actor.UserUUID,
+		"accepter_note":   notes,
+		"accepted_at":     now.Unix(),
+		"accepted_at_str": now.Format(time.RFC3339),
+	})
 }
 
 /*
-ReceiveDone:
-- status harus IN_PROGRESS
+ReceiveDone (event RECEIVE_DONE, role KASIR / ADMIN):
+- qty per item: received masuk branch tujuan, damaged + missing jadi discrepancy (item tidak disebut = utuh)
 - stock masuk ke branch tujuan (products branch_uuid=to_branch + sku)
-  - kalau product tujuan belum ada: create copy minimal
-  - lalu stock += qty
+  - kalau product tujuan belum ada: create record inventory dari item (terhubung ke catalog_uuid yang sama)
+  - lalu stock += received
 
 - update transfer status DONE
 ALL IN TRANSACTION
 */
-func (r *StockTransferRepositoryImpl) ReceiveDone(uuid, notes, receivedBy string) (dao.StockTransfer, error) {
+func (r *StockTransferRepositoryImpl) ReceiveDone(uuid string, req dto.StockTransferReceiveReq, clientUUID string, actor dao.StockTransferActor) (dao.StockTransfer, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
 	defer cancel()
 
-	// validate kasir role
-	var receiver bson.M
-	if err := r.userCol.FindOne(ctx, bson.M{"user_uuid": receivedBy}).Decode(&receiver); err != nil {
-		return dao.StockTransfer{}, errors.New("receiver not found / not KASIR role")
-	}
-	var role bson.M
-	if err := r.roleCol.FindOne(ctx, bson.M{"uuid": receiver["role_uuid"]}).Decode(&role); err != nil {
-		return dao.StockTransfer{}, errors.New("receiver not found / not KASIR role")
-	}
-	if role["value"] != "KASIR" && role["value"] != "ADMIN" {
-		return dao.StockTransfer{}, errors.New("receiver not found / not KASIR role")
-	}
+	notes := req.Notes
+	receivedBy := actor.UserUUID
 
 	now := time.Now()
 	nowStr := now.Format(time.RFC3339)
@@ -480,42 +438,35 @@ func (r *StockTransferRepositoryImpl) ReceiveDone(uuid, notes, receivedBy string
 	if err := r.transferCol.FindOne(ctx, bson.M{"uuid": uuid}).Decode(&tr); err != nil {
 		return dao.StockTransfer{}, err
 	}
-	if tr.Status != dao.StockTransferInProgress {
-		return dao.StockTransfer{}, errors.New("invalid status: must be IN_PROGRESS")
-	}
-
-	// track increments for rollback
-	type incItem struct {
-		SKU string
-		Qty int64
+	t, err := dao.FireStockTransfer(tr, dao.StockTransferEventReceive, actor)
+	if err != nil {
+		return dao.StockTransfer{}, err
 	}
-	applied := make([]incItem, 0, len(tr.Items))
 
-	rollback := func() {
-		// best-effort rollback
-		for _, it := range applied {
-			filter := bson.M{"branch_uuid": tr.ToBranchUUID, "sku": it.SKU}
-			_, _ = r.productCol.UpdateOne(context.Background(), filter, bson.M{
-				"$inc": bson.M{"stock": -it.Qty},
-			})
-		}
+	discrepancies, err := applyReceivedQty(&tr, req.Items)
+	if err != nil {
+		return dao.StockTransfer{}, err
 	}
 
-	// stock to destination (atomic per item)
-	for _, it := range tr.Items {
-		if it.SKU == "" || it.Qty <= 0 {
-			rollback()
-			return dao.StockTransfer{}, errors.New("invalid item qty")
-		}
+	// stock masuk, lot, discrepancy + status DONE dalam 1 transaction (gagal di mana pun => semua batal)
+	inRef := dao.StockRef{Reason: dao.StockMoveTransferIn, RefUUID: tr.UUID, Note: notes, CreatedBy: receivedBy}
+	var out dao.StockTransfer
+	err = r.ledger.tx(ctx, func(sc context.Context) error {
+		for _, it := range tr.Items {
+			if it.SKU == "" || it.Qty <= 0 {
+				return fmt.Errorf("%w: invalid item qty", dao.ErrStockTransferInvalid)
+			}
+			if it.ReceivedQty == 0 {
+				continue
+			}
 
-		destFilter := bson.M{"branch_uuid": tr.ToBranchUUID, "sku": it.SKU}
+			destFilter := bson.M{"branch_uuid": tr.ToBranchUUID, "sku": it.SKU}
 
-		// gunakan $setOnInsert supaya kalau belum ada product, dibuat otomatis saat upsert
-		update := bson.M{
-			"$inc": bson.M{"stock": it.Qty},
-			"$setOnInsert": bson.M{
+			// gunakan $setOnInsert supaya kalau belum ada product, dibuat otomatis saat upsert
+			setOnInsert := bson.M{
 				"uuid":           helpers.GenerateUUID(),
 				"branch_uuid":    tr.ToBranchUUID,
+				"catalog_uuid":   it.CatalogUUID,
 				"sku":            it.SKU,
 				"barcode":        it.Barcode,
 				"name":           it.Name,
@@ -526,47 +477,481 @@ func (r *StockTransferRepositoryImpl) ReceiveDone(uuid, notes, receivedBy string
 				"price":          it.Price,
 				"image":          it.Image,
 				"is_active":      true,
+				"track_lots":     len(it.Lots) > 0,
 				"created_by":     receivedBy,
 				"created_at":     now, // sesuaikan type field kamu (time.Time vs unix)
 				"created_at_str": nowStr,
-				"updated_at":     now.Unix(),
-				"updated_at_str": nowStr,
-			},
-		}
+			}
 
-		_, err := r.productCol.UpdateOne(ctx, destFilter, update, options.Update().SetUpsert(true))
-		if err != nil {
-			rollback()
-			return dao.StockTransfer{}, err
+			dest, _, err := r.ledger.applyIn(sc, destFilter, it.ReceivedQty, it.Cost, inRef, setOnInsert)
+			if err != nil {
+				return err
+			}
+
+			// lot asal dipindah apa adanya (lot_no + expiry), hanya porsi yang diterima
+			recvLots, _ := splitLots(it.Lots, it.ReceivedQty)
+			for _, lot := range recvLots {
+				if _, err := r.lots.receive(sc, dest, lot.LotNo, lot.ExpiryDate, lot.Qty); err != nil {
+					return err
+				}
+			}
 		}
 
-		applied = append(applied, incItem{SKU: it.SKU, Qty: it.Qty})
-	}
+		// selisih in-transit, diputuskan gudang (return / write-off / kirim ulang)
+		if len(discrepancies) > 0 {
+			docs := make([]any, 0, len(discrepancies))
+			for i := range discrepancies {
+				d := &discrepancies[i]
+				d.UUID = helpers.GenerateUUID()
+				d.CreatedAt = now
+				d.CreatedAtStr = nowStr
+				d.UpdatedAt = now.Unix()
+				d.UpdatedAtStr = nowStr
+				d.ClientUUID = clientUUID
+				d.ReportedBy = receivedBy
+				docs = append(docs, d)
+			}
+			if _, err := r.discrepancyCol.InsertMany(sc, docs); err != nil {
+				return err
+			}
+		}
 
-	// update transfer DONE dengan guard status (anti race)
-	updFilter := bson.M{"uuid": uuid, "status": dao.StockTransferInProgress}
-	upd := bson.M{
-		"$set": bson.M{
-			"status":          dao.StockTransferDone,
+		// update transfer DONE dengan guard status (anti race)
+		var err error
+		out, err = r.transition(sc, bson.M{"uuid": uuid}, tr.Status, t, actor, notes, now, bson.M{
+			"items":           tr.Items,
+			"has_discrepancy": len(discrepancies) > 0,
 			"received_by":     receivedBy,
-			"receiver_notes":  notes,
+			"receiver_note":   notes,
 			"received_at":     now.Unix(),
 			"received_at_str": nowStr,
-			"notes":           notes,
-			"updated_at":      now.Unix(),
-			"updated_at_str":  nowStr,
-		},
+		})
+		return err
+	})
+	if err != nil {
+		return dao.StockTransfer{}, err
+	}
+	return out, nil
+}
+
+// applyReceivedQty: isi received/damaged/missing per item, return discrepancy (belum punya uuid)
+func applyReceivedQty(tr *dao.StockTransfer, input []dto.StockTransferReceiveItem) ([]dao.StockTransferDiscrepancy, error) {
+	byProduct := map[string]dto.StockTransferReceiveItem{}
+	for _, in := range input {
+		if _, dup := byProduct[in.ProductUUID]; dup {
+			return nil, fmt.Errorf("%w: duplicate receive item %s", dao.ErrStockTransferInvalid, in.ProductUUID)
+		}
+		byProduct[in.ProductUUID] = in
 	}
 
-	res, err := r.transferCol.UpdateOne(ctx, updFilter, upd)
+	out := []dao.StockTransferDiscrepancy{}
+	for i := range tr.Items {
+		it := &tr.Items[i]
+		in, ok := byProduct[it.ProductUUID]
+		if !ok {
+			it.ReceivedQty, it.DamagedQty, it.MissingQty = it.Qty, 0, 0
+			continue
+		}
+		delete(byProduct, it.ProductUUID)
+
+		missing := it.Qty - in.Received - in.Damaged
+		if in.Missing != nil {
+			missing = *in.Missing
+		}
+		if in.Received < 0 || in.Damaged < 0 || missing < 0 || in.Received+in.Damaged+missing != it.Qty {
+			return nil, fmt.Errorf("%w: %s: received + damaged + missing must equal shipped qty %d", dao.ErrStockTransferInvalid, it.Name, it.Qty)
+		}
+		it.ReceivedQty, it.DamagedQty, it.MissingQty = in.Received, in.Damaged, missing
+		if in.Damaged+missing == 0 {
+			continue
+		}
+
+		_, lots := splitLots(it.Lots, in.Received)
+		out = append(out, dao.StockTransferDiscrepancy{
+			TransferUUID:   tr.UUID,
+			FromBranchUUID: tr.FromBranchUUID,
+			ToBranchUUID:   tr.ToBranchUUID,
+			DriverUUID:     tr.DriverUUID,
+			ProductUUID:    it.ProductUUID,
+			CatalogUUID:    it.CatalogUUID,
+			SKU:            it.SKU,
+			Name:           it.Name,
+			BaseUnit:       it.BaseUnit,
+			Cost:           it.Cost,
+			Shipped:        it.Qty,
+			Received:       in.Received,
+			Damaged:        in.Damaged,
+			Missing:        missing,
+			Qty:            in.Damaged + missing,
+			Lots:           lots,
+			Note:           strings.TrimSpace(in.Note),
+			Status:         dao.DiscrepancyOpen,
+		})
+	}
+	for productUUID := range byProduct {
+		return nil, fmt.Errorf("%w: product not in transfer: %s", dao.ErrStockTransferInvalid, productUUID)
+	}
+	return out, nil
+}
+
+// splitLots: n unit pertama (urutan FEFO saat dikirim) dan sisanya
+func splitLots(lots []dao.LotAllocation, n int64) (head, tail []dao.LotAllocation) {
+	for _, l := range lots {
+		take := min(l.Qty, max(n, 0))
+		n -= take
+		if take > 0 {
+			h := l
+			h.Qty = take
+			head = append(head, h)
+		}
+		if l.Qty > take {
+			t := l
+			t.Qty = l.Qty - take
+			tail = append(tail, t)
+		}
+	}
+	return head, tail
+}
+
+func (r *StockTransferRepositoryImpl) Close(uuid string, ev dao.StockTransferEvent, reason string, actor dao.StockTransferActor) (dao.StockTransfer, error) {
+	switch ev {
+	case dao.StockTransferEventReject, dao.StockTransferEventCancel, dao.StockTransferEventDriverDecline:
+	case dao.StockTransferEventExpire:
+		return dao.StockTransfer{}, errors.New("use ExpireStale for EXPIRED")
+	default:
+		return dao.StockTransfer{}, fmt.Errorf("%w: %s is not a close event", dao.ErrStockTransferState, ev)
+	}
+	return r.close(bson.M{"uuid": uuid}, ev, reason, actor)
+}
+
+func (r *StockTransferRepositoryImpl) ExpireStale(before int64, reason string, limit int64) ([]dao.StockTransfer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cur, err := r.transferCol.Find(ctx, bson.M{
+		"status":     bson.M{"$in": dao.StockTransferOpen},
+		"updated_at": bson.M{"$lt": before},
+	}, options.Find().SetSort(bson.D{{Key: "updated_at", Value: 1}}).SetLimit(limit).SetProjection(bson.M{"uuid": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var stale []dao.StockTransfer
+	if err := cur.All(ctx, &stale); err != nil {
+		return nil, err
+	}
+
+	out := []dao.StockTransfer{}
+	for _, tr := range stale {
+		// guard updated_at: transfer yang baru saja diproses tidak ikut kedaluwarsa
+		res, err := r.close(bson.M{"uuid": tr.UUID, "updated_at": bson.M{"$lt": before}}, dao.StockTransferEventExpire, reason, dao.StockTransferSystemActor)
+		if err != nil {
+			continue
+		}
+		out = append(out, res)
+	}
+	return out, nil
+}
+
+func (r *StockTransferRepositoryImpl) RestorePending(limit int64) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cur, err := r.transferCol.Find(ctx, bson.M{
+		"status": bson.M{"$in": bson.A{
+			dao.StockTransferRejected, dao.StockTransferCancelled, dao.StockTransferDriverDeclined, dao.StockTransferExpired,
+		}},
+		"stock_restored": false,
+	}, options.Find().SetLimit(limit))
+	if err != nil {
+		return 0, err
+	}
+	var list []dao.StockTransfer
+	if err := cur.All(ctx, &list); err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, tr := range list {
+		if err := r.restoreStock(ctx, tr); err != nil {
+			log.Printf("stock transfer %s restore: %v", tr.UUID, err)
+			continue
+		}
+		n++
+	}
+	return n, nil
+}
+
+// close: transfer open -> status akhir (guard status atomic), lalu kembalikan stock yang sudah dipotong.
+// Stock gagal dikembalikan tidak membatalkan penutupan: stock_restored tetap false dan diulang worker.
+func (r *StockTransferRepositoryImpl) close(filter bson.M, ev dao.StockTransferEvent, reason string, actor dao.StockTransferActor) (dao.StockTransfer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	defer cancel()
+
+	var cur dao.StockTransfer
+	if err := r.transferCol.FindOne(ctx, bson.M{"uuid": filter["uuid"]}).Decode(&cur); err != nil {
+		return dao.StockTransfer{}, err
+	}
+	t, err := dao.FireStockTransfer(cur, ev, actor)
+	if err != nil {
+		return dao.StockTransfer{}, err
+	}
+
+	now := time.Now()
+	tr, err := r.transition(ctx, filter, cur.Status, t, actor, reason, now, bson.M{
+		"closed_by":      actor.UserUUID,
+		"close_reason":   reason,
+		"closed_at":      now.Unix(),
+		"closed_at_str":  now.Format(time.RFC3339),
+		"stock_restored": false,
+	})
 	if err != nil {
-		rollback()
 		return dao.StockTransfer{}, err
 	}
-	if res.MatchedCount == 0 {
-		rollback()
-		return dao.StockTransfer{}, errors.New("receive failed: transfer status already changed")
+
+	// semua event close mengembalikan stock yang sudah dipotong (belum di-approve gudang = tidak ada)
+	if err := r.restoreStock(ctx, tr); err != nil {
+		log.Printf("stock transfer %s restore: %v", tr.UUID, err)
+		return tr, nil
+	}
+	return r.Detail(tr.UUID)
+}
+
+// transition: simpan hasil FireStockTransfer dengan guard status asal (optimistic lock) + push history
+func (r *StockTransferRepositoryImpl) transition(ctx context.Context, filter bson.M, from dao.StockTransferStatus, t dao.StockTransferTransition, actor dao.StockTransferActor, note string, now time.Time, set bson.M) (dao.StockTransfer, error) {
+	f := bson.M{"status": from}
+	for k, v := range filter {
+		f[k] = v
+	}
+	set["status"] = t.To
+	set["updated_at"] = now.Unix()
+	set["updated_at_str"] = now.Format(time.RFC3339)
+
+	var out dao.StockTransfer
+	err := r.transferCol.FindOneAndUpdate(ctx, f, bson.M{
+		"$set":  set,
+		"$push": bson.M{"history": t.Record(from, actor, note, now)},
+	}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&out)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return dao.StockTransfer{}, fmt.Errorf("%w: transfer status already changed", dao.ErrStockTransferState)
+	}
+	if err != nil {
+		return dao.StockTransfer{}, err
+	}
+	out.NormalizeNotes()
+	return out, nil
+}
+
+// restoreStock: stock + lot yang dipotong saat approve gudang kembali ke branch asal, per item sekali saja
+func (r *StockTransferRepositoryImpl) restoreStock(ctx context.Context, tr dao.StockTransfer) error {
+	if tr.ApprovedAt > 0 {
+		ref := dao.StockRef{
+			Reason:    dao.StockMoveTransferReturn,
+			RefUUID:   tr.UUID,
+			Note:      string(tr.Status) + ": " + tr.CloseReason,
+			CreatedBy: tr.ClosedBy,
+		}
+		for i, it := range tr.Items {
+			if it.Restored || it.Qty <= 0 {
+				continue
+			}
+			// tanda restored (guard: retry / proses paralel tidak mengembalikan 2x) + stock + lot dalam 1 transaction
+			key := fmt.Sprintf("items.%d.restored", i)
+			err := r.ledger.tx(ctx, func(sc context.Context) error {
+				res, err := r.transferCol.UpdateOne(sc,
+					bson.M{"uuid": tr.UUID, key: bson.M{"$ne": true}},
+					bson.M{"$set": bson.M{key: true}})
+				if err != nil || res.ModifiedCount == 0 {
+					return err
+				}
+
+				filter := bson.M{"uuid": it.ProductUUID, "branch_uuid": tr.FromBranchUUID}
+				_, err = r.ledger.apply(sc, filter, it.Qty, ref, nil)
+				if errors.Is(err, errStockNotApplied) {
+					// produk asal sudah dihapus: tidak ada yang bisa dikembalikan, item tetap ditandai
+					log.Printf("stock transfer %s restore: product %s not found in from_branch", tr.UUID, it.ProductUUID)
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				return r.lots.shift(sc, it.Lots, 1)
+			})
+			if err != nil {
+				return err
+			}
+		}
 	}
 
-	return r.Detail(uuid)
+	_, err := r.transferCol.UpdateOne(ctx, bson.M{"uuid": tr.UUID}, bson.M{"$set": bson.M{"stock_restored": true}})
+	return err
+}
+
+func (r *StockTransferRepositoryImpl) ListDiscrepancies(req *dto.FilterRequest) ([]dao.StockTransferDiscrepancy, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 7*time.Second)
+	defer cancel()
+
+	filter := buildListFilter(req, "sku", "name", "transfer_uuid", "note")
+	cur, err := r.discrepancyCol.Find(ctx, filter, buildListOptions(req))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	out := []dao.StockTransferDiscrepancy{}
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *StockTransferRepositoryImpl) DetailDiscrepancy(uuid string) (dao.StockTransferDiscrepancy, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.StockTransferDiscrepancy
+	err := r.discrepancyCol.FindOne(ctx, bson.M{"uuid": uuid}).Decode(&out)
+	return out, err
+}
+
+func (r *StockTransferRepositoryImpl) ResolveDiscrepancy(uuid string, resolution dao.StockDiscrepancyResolution, note, resolvedBy, reshipUUID string) (dao.StockTransferDiscrepancy, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+
+	// status resolved + stock kembali ke gudang (resolusi RETURN) dalam 1 transaction
+	var d dao.StockTransferDiscrepancy
+	err := r.ledger.tx(ctx, func(sc context.Context) error {
+		err := r.discrepancyCol.FindOneAndUpdate(sc, bson.M{"uuid": uuid, "status": dao.DiscrepancyOpen}, bson.M{"$set": bson.M{
+			"status":               dao.DiscrepancyResolved,
+			"resolution":           resolution,
+			"resolve_note":         note,
+			"resolved_by":          resolvedBy,
+			"resolved_at":          now.Unix(),
+			"resolved_at_str":      nowStr,
+			"reship_transfer_uuid": reshipUUID,
+			"updated_at":           now.Unix(),
+			"updated_at_str":       nowStr,
+		}}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&d)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return errors.New("discrepancy already resolved")
+		}
+		if err != nil || resolution != dao.DiscrepancyReturn {
+			return err
+		}
+
+		ref := dao.StockRef{Reason: dao.StockMoveTransferReturn, RefUUID: d.TransferUUID, Note: "discrepancy return: " + note, CreatedBy: resolvedBy}
+		filter := bson.M{"uuid": d.ProductUUID, "branch_uuid": d.FromBranchUUID}
+		if _, err := r.ledger.apply(sc, filter, d.Qty, ref, nil); err != nil {
+			if errors.Is(err, errStockNotApplied) {
+				return errors.New("product not found in from_branch")
+			}
+			return err
+		}
+		return r.lots.shift(sc, d.Lots, 1)
+	})
+	if err != nil {
+		return dao.StockTransferDiscrepancy{}, err
+	}
+	return d, nil
+}
+
+func (r *StockTransferRepositoryImpl) DiscrepancyReport(clientUUID string, from, to time.Time, limit int64) ([]dto.StockDiscrepancyReportRow, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	qtyIf := func(res dao.StockDiscrepancyResolution) bson.M {
+		return bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$resolution", res}}, "$qty", 0}}}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"client_uuid": clientUUID,
+			"created_at":  bson.M{"$gte": from, "$lt": to},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       bson.M{"driver": "$driver_uuid", "from": "$from_branch_uuid", "to": "$to_branch_uuid"},
+			"transfers": bson.M{"$addToSet": "$transfer_uuid"},
+			"items":     bson.M{"$sum": 1},
+			"damaged":   bson.M{"$sum": "$damaged"},
+			"missing":   bson.M{"$sum": "$missing"},
+			"value":     bson.M{"$sum": bson.M{"$multiply": bson.A{"$qty", "$cost"}}},
+			"open":      bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$status", dao.DiscrepancyOpen}}, 1, 0}}},
+			"returned":  qtyIf(dao.DiscrepancyReturn),
+			"write_off": qtyIf(dao.DiscrepancyWriteOff),
+			"reship":    qtyIf(dao.DiscrepancyReship),
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":              0,
+			"driver_uuid":      "$_id.driver",
+			"from_branch_uuid": "$_id.from",
+			"to_branch_uuid":   "$_id.to",
+			"transfers":        bson.M{"$size": "$transfers"},
+			"items":            1,
+			"damaged":          1,
+			"missing":          1,
+			"value":            bson.M{"$round": bson.A{"$value", 2}},
+			"open":             1,
+			"returned":         1,
+			"write_off":        1,
+			"reship":           1,
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "value", Value: -1}, {Key: "missing", Value: -1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+
+	cur, err := r.discrepancyCol.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	rows := []dto.StockDiscrepancyReportRow{}
+	if err := cur.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	// nama driver & branch untuk tampilan
+	driverUUIDs, branchUUIDs := []string{}, []string{}
+	for _, row := range rows {
+		driverUUIDs = append(driverUUIDs, row.DriverUUID)
+		branchUUIDs = append(branchUUIDs, row.FromBranchUUID, row.ToBranchUUID)
+	}
+	drivers, err := r.namesOf(ctx, r.usersCol, driverUUIDs)
+	if err != nil {
+		return nil, err
+	}
+	branches, err := r.namesOf(ctx, r.branchCol, branchUUIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		rows[i].DriverName = drivers[rows[i].DriverUUID]
+		rows[i].FromBranchName = branches[rows[i].FromBranchUUID]
+		rows[i].ToBranchName = branches[rows[i].ToBranchUUID]
+	}
+	return rows, nil
+}
+
+// namesOf: uuid -> name
+func (r *StockTransferRepositoryImpl) namesOf(ctx context.Context, col *mongo.Collection, uuids []string) (map[string]string, error) {
+	out := map[string]string{}
+	if len(uuids) == 0 {
+		return out, nil
+	}
+	cur, err := col.Find(ctx, bson.M{"uuid": bson.M{"$in": uuids}}, options.Find().SetProjection(bson.M{"uuid": 1, "name": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var docs []struct {
+		UUID string `bson:"uuid"`
+		Name string `bson:"name"`
+	}
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	for _, d := range docs {
+		out[d.UUID] = d.Name
+	}
+	return out, nil
 }
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"slices"
+	"testing"
+
+	"harjonan.id/user-service/app/domain/dao"
+)
+
+func TestSplitLots(t *testing.T) {
+	lots := []dao.LotAllocation{
+		{LotNo: "A", ExpiryDate: 100, Qty: 5},
+		{LotNo: "B", ExpiryDate: 200, Qty: 3},
+		{LotNo: "C", Qty: 2},
+	}
+	cases := []struct {
+		name     string
+		n        int64
+		wantHead []dao.LotAllocation
+		wantTail []dao.LotAllocation
+	}{
+		{"all received", 10, lots, nil},
+		{"more than allocated", 12, lots, nil},
+		{"none received", 0, nil, lots},
+		{"negative", -1, nil, lots},
+		{"first lot exact", 5, lots[:1], lots[1:]},
+		{"split inside lot", 7,
+			[]dao.LotAllocation{{LotNo: "A", ExpiryDate: 100, Qty: 5}, {LotNo: "B", ExpiryDate: 200, Qty: 2}},
+			[]dao.LotAllocation{{LotNo: "B", ExpiryDate: 200, Qty: 1}, {LotNo: "C", Qty: 2}}},
+		{"split first lot", 1,
+			[]dao.LotAllocation{{LotNo: "A", ExpiryDate: 100, Qty: 1}},
+			[]dao.LotAllocation{{LotNo: "A", ExpiryDate: 100, Qty: 4}, {LotNo: "B", ExpiryDate: 200, Qty: 3}, {LotNo: "C", Qty: 2}}},
+	}
+	for _, c := range cases {
+		head, tail := splitLots(lots, c.n)
+		if !slices.Equal(head, c.wantHead) || !slices.Equal(tail, c.wantTail) {
+			t.Errorf("%s: want %v | %v, got %v | %v", c.name, c.wantHead, c.wantTail, head, tail)
+		}
+	}
+	if lots[0].Qty != 5 || lots[1].Qty != 3 {
+		t.Errorf("splitLots must not modify the input, got %v", lots)
+	}
+}
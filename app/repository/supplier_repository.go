@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+)
+
+type SupplierRepository interface {
+	SaveSupplier(data *dao.Supplier) (dao.Supplier, error)
+	DetailSupplier(uuid string) (dao.Supplier, error)
+	ListSupplier(req *dto.FilterRequest) ([]dao.Supplier, error)
+	DeleteSupplier(uuid string) error
+
+	// histori harga beli
+	AddPrice(p *dao.SupplierPrice) error
+	ListPrices(req *dto.FilterRequest) ([]dao.SupplierPrice, error)
+	LastPrice(supplierUUID, productUUID string) (dao.SupplierPrice, error)
+	// LastPriceBySKU: pembelian terakhir SKU ini dari supplier mana pun (semua branch client)
+	LastPriceBySKU(clientUUID, sku string) (dao.SupplierPrice, error)
+}
+
+type SupplierRepositoryImpl struct {
+	supplierCol *mongo.Collection
+	priceCol    *mongo.Collection
+}
+
+func SupplierRepositoryInit(mongoClient *mongo.Client) *SupplierRepositoryImpl {
+	dbName := helpers.ProvideDBName()
+	db := mongoClient.Database(dbName)
+	return &SupplierRepositoryImpl{
+		supplierCol: db.Collection("suppliers"),
+		priceCol:    db.Collection("supplier_prices"),
+	}
+}
+
+func (r *SupplierRepositoryImpl) SaveSupplier(data *dao.Supplier) (dao.Supplier, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if data.ClientUUID == "" {
+		return dao.Supplier{}, errors.New("client_uuid required")
+	}
+	if data.Name == "" {
+		return dao.Supplier{}, errors.New("name required")
+	}
+
+	filter := bson.M{}
+	switch {
+	case data.UUID != "":
+		filter["uuid"] = data.UUID
+	case data.Code != "":
+		filter["client_uuid"] = data.ClientUUID
+		filter["code"] = data.Code
+	default:
+		filter["client_uuid"] = data.ClientUUID
+		filter["name"] = data.Name
+	}
+
+	// code harus unik per client (tanpa uuid, filter by code sudah mengarah ke supplier yang sama)
+	if data.Code != "" && data.UUID != "" {
+		n, err := r.supplierCol.CountDocuments(ctx, bson.M{
+			"client_uuid": data.ClientUUID,
+			"code":        data.Code,
+			"uuid":        bson.M{"$ne": data.UUID},
+		})
+		if err != nil {
+			return dao.Supplier{}, err
+		}
+		if n > 0 {
+			return dao.Supplier{}, errors.New("code already used by another supplier")
+		}
+	}
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+
+	newUUID := data.UUID
+	if newUUID == "" {
+		newUUID = helpers.GenerateUUID()
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"client_uuid":       data.ClientUUID,
+			"code":              data.Code,
+			"name":              data.Name,
+			"contact_name":      data.ContactName,
+			"phone":             data.Phone,
+			"email":             data.Email,
+			"address":           data.Address,
+			"tax_id":            data.TaxID,
+			"payment_term_days": data.PaymentTermDays,
+			"note":              data.Note,
+			"is_active":         data.IsActive,
+			"updated_at":        now.Unix(),
+			"updated_at_str":    nowStr,
+		},
+		"$setOnInsert": bson.M{
+			"uuid":           newUUID,
+			"created_by":     data.CreatedBy,
+			"created_at":     now,
+			"created_at_str": nowStr,
+		},
+	}
+
+	opts := options.Update().SetUpsert(true)
+	if _, err := r.supplierCol.UpdateOne(ctx, filter, update, opts); err != nil {
+		return dao.Supplier{}, err
+	}
+
+	var out dao.Supplier
+	if err := r.supplierCol.FindOne(ctx, filter).Decode(&out); err != nil {
+		return dao.Supplier{}, err
+	}
+	return out, nil
+}
+
+func (r *SupplierRepositoryImpl) DetailSupplier(uuid string) (dao.Supplier, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.Supplier
+	err := r.supplierCol.FindOne(ctx, bson.M{"uuid": uuid}).Decode(&out)
+	return out, err
+}
+
+func (r *SupplierRepositoryImpl) ListSupplier(req *dto.FilterRequest) ([]dao.Supplier, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := buildListFilter(req, "name", "code", "contact_name", "phone", "email")
+	cur, err := r.supplierCol.Find(ctx, filter, buildListOptions(req))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var out []dao.Supplier
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *SupplierRepositoryImpl) DeleteSupplier(uuid string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.supplierCol.DeleteOne(ctx, bson.M{"uuid": uuid})
+	return err
+}
+
+func (r *SupplierRepositoryImpl) AddPrice(p *dao.SupplierPrice) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+	p.UUID = helpers.GenerateUUID()
+	p.CreatedAt = now
+	p.CreatedAtStr = nowStr
+	p.UpdatedAt = now.Unix()
+	p.UpdatedAtStr = nowStr
+
+	_, err := r.priceCol.InsertOne(ctx, p)
+	return err
+}
+
+func (r *SupplierRepositoryImpl) ListPrices(req *dto.FilterRequest) ([]dao.SupplierPrice, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	filter := buildListFilter(req, "sku", "name", "ref_no")
+	cur, err := r.priceCol.Find(ctx, filter, buildListOptions(req))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	out := make([]dao.SupplierPrice, 0)
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *SupplierRepositoryImpl) LastPrice(supplierUUID, productUUID string) (dao.SupplierPrice, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.SupplierPrice
+	err := r.priceCol.FindOne(ctx, bson.M{"supplier_uuid": supplierUUID, "product_uuid": productUUID},
+		options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})).Decode(&out)
+	return out, err
+}
+
+func (r *SupplierRepositoryImpl) LastPriceBySKU(clientUUID, sku string) (dao.SupplierPrice, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var out dao.SupplierPrice
+	err := r.priceCol.FindOne(ctx, bson.M{"client_uuid": clientUUID, "sku": sku},
+		options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})).Decode(&out)
+	return out, err
+}
@@ -135,10 +135,44 @@ func Init(init *config.Initialization) *gin.Engine {
 	product := router.Group("/products", middleware.JWTAuthMiddleware())
 	{
 		product.POST("/fetch", init.ProductCtrl.List)
+		product.POST("/grouped", init.ProductCtrl.Grouped)
 		product.GET("/:uuid", init.ProductCtrl.Detail)
 		product.POST("/upsert", init.ProductCtrl.Upsert)
 		product.DELETE("/:uuid", init.ProductCtrl.Delete)
-		product.POST("/bulk-upload", init.ProductCtrl.BulkUpload)
+		product.POST("/bulk-upload", init.ProductImportCtrl.Upload) // lama, sekarang job import
+		product.POST("/import", init.ProductImportCtrl.Upload)
+		product.POST("/export", init.ProductImportCtrl.Export)
+		product.POST("/import/fetch", init.ProductImportCtrl.List)
+		product.GET("/import/:uuid", init.ProductImportCtrl.Detail)
+		product.GET("/import/:uuid/report", init.ProductImportCtrl.Report)
+		product.POST("/:uuid/movements", init.ProductCtrl.Movements)
+		product.POST("/:uuid/price-history", init.PriceChangeCtrl.ProductHistory)
+	}
+
+	catalog := router.Group("/catalog", middleware.JWTAuthMiddleware())
+	{
+		catalog.POST("/fetch", init.CatalogCtrl.List)
+		catalog.GET("/:uuid", init.CatalogCtrl.Detail)
+		catalog.POST("/upsert", init.CatalogCtrl.Upsert)
+		catalog.POST("/:uuid/inventory", init.CatalogCtrl.SetInventory)
+		catalog.POST("/:uuid/variants", init.CatalogCtrl.GenerateVariants)
+		catalog.POST("/migrate", init.CatalogCtrl.Migrate)
+		catalog.GET("/tags", init.CatalogCtrl.Tags)
+		catalog.POST("/:uuid/price-history", init.PriceChangeCtrl.CatalogHistory)
+	}
+
+	category := router.Group("/categories", middleware.JWTAuthMiddleware())
+	{
+		category.POST("/fetch", init.CategoryCtrl.Tree)
+		category.POST("/upsert", init.CategoryCtrl.Upsert)
+		category.DELETE("/:uuid", init.CategoryCtrl.Delete)
+	}
+
+	brand := router.Group("/brands", middleware.JWTAuthMiddleware())
+	{
+		brand.POST("/fetch", init.BrandCtrl.List)
+		brand.POST("/upsert", init.BrandCtrl.Upsert)
+		brand.DELETE("/:uuid", init.BrandCtrl.Delete)
 	}
 
 	stock := router.Group("/stock-transfers", middleware.JWTAuthMiddleware())
@@ -149,6 +183,14 @@ func Init(init *config.Initialization) *gin.Engine {
 		stock.POST("/:uuid/warehouse-approve", init.StockTransferCtrl.WarehouseApprove)
 		stock.POST("/:uuid/driver-accept", init.StockTransferCtrl.DriverAccept)
 		stock.POST("/:uuid/receive", init.StockTransferCtrl.ReceiveDone)
+		stock.POST("/:uuid/reject", init.StockTransferCtrl.Reject)
+		stock.POST("/:uuid/cancel", init.StockTransferCtrl.Cancel)
+		stock.POST("/:uuid/driver-decline", init.StockTransferCtrl.DriverDecline)
+		stock.GET("/:uuid/delivery-note", init.StockTransferCtrl.DeliveryNote)
+		stock.POST("/discrepancies/fetch", init.StockTransferCtrl.ListDiscrepancies)
+		stock.POST("/discrepancies/report", init.StockTransferCtrl.DiscrepancyReport)
+		stock.GET("/discrepancies/:uuid", init.StockTransferCtrl.DetailDiscrepancy)
+		stock.POST("/discrepancies/:uuid/resolve", init.StockTransferCtrl.ResolveDiscrepancy)
 	}
 
 	pos := router.Group("/pos/transactions", middleware.JWTAuthMiddleware())
@@ -157,6 +199,9 @@ func Init(init *config.Initialization) *gin.Engine {
 		pos.POST("/fetch", init.PosTransactionCtrl.List)
 		pos.GET("/:uuid", init.PosTransactionCtrl.Detail)
 		pos.POST("/:uuid/void", init.PosTransactionCtrl.Void)
+		pos.GET("/:uuid/receipt", init.ReceiptCtrl.Render)
+		pos.POST("/:uuid/reprint", init.ReceiptCtrl.Reprint)
+		pos.POST("/:uuid/send-receipt", init.ReceiptCtrl.SendEmail)
 
 		// optional: barcode scan cepat
 		pos.POST("/scan", init.PosTransactionCtrl.ScanByBarcode)
@@ -186,5 +231,142 @@ func Init(init *config.Initialization) *gin.Engine {
 		notification.DELETE("/clear-all", init.NotifCtrl.ClearAll)
 	}
 
+	customer := router.Group("/customers", middleware.JWTAuthMiddleware())
+	{
+		customer.POST("/fetch", init.CustomerCtrl.List)
+		customer.GET("/:uuid", init.CustomerCtrl.Detail)
+		customer.POST("/upsert", init.CustomerCtrl.Upsert)
+		customer.DELETE("/:uuid", init.CustomerCtrl.Delete)
+
+		// kasir: cari by no hp / barcode member
+		customer.POST("/lookup", init.CustomerCtrl.Lookup)
+		customer.POST("/:uuid/history", init.CustomerCtrl.History)
+		customer.POST("/:uuid/points", init.CustomerCtrl.PointLedger)
+	}
+
+	opname := router.Group("/stock-opnames", middleware.JWTAuthMiddleware())
+	{
+		opname.POST("/start", init.StockOpnameCtrl.Start)
+		opname.POST("/fetch", init.StockOpnameCtrl.List)
+		opname.GET("/:uuid", init.StockOpnameCtrl.Detail)
+		opname.POST("/:uuid/count", init.StockOpnameCtrl.Count)
+		opname.GET("/:uuid/variance", init.StockOpnameCtrl.Variance)
+		opname.POST("/:uuid/approve", init.StockOpnameCtrl.Approve)
+		opname.POST("/:uuid/cancel", init.StockOpnameCtrl.Cancel)
+	}
+
+	adjustment := router.Group("/stock-adjustments", middleware.JWTAuthMiddleware())
+	{
+		adjustment.POST("/create", init.StockAdjustmentCtrl.Create)
+		adjustment.POST("/fetch", init.StockAdjustmentCtrl.List)
+		adjustment.GET("/:uuid", init.StockAdjustmentCtrl.Detail)
+		adjustment.POST("/:uuid/approve", init.StockAdjustmentCtrl.Approve)
+		adjustment.POST("/:uuid/reject", init.StockAdjustmentCtrl.Reject)
+	}
+
+	supplier := router.Group("/suppliers", middleware.JWTAuthMiddleware())
+	{
+		supplier.POST("/fetch", init.SupplierCtrl.List)
+		supplier.GET("/:uuid", init.SupplierCtrl.Detail)
+		supplier.POST("/upsert", init.SupplierCtrl.Upsert)
+		supplier.DELETE("/:uuid", init.SupplierCtrl.Delete)
+		supplier.POST("/:uuid/prices", init.SupplierCtrl.Prices)
+	}
+
+	purchaseOrder := router.Group("/purchase-orders", middleware.JWTAuthMiddleware())
+	{
+		purchaseOrder.POST("/fetch", init.PurchaseOrderCtrl.List)
+		purchaseOrder.GET("/:uuid", init.PurchaseOrderCtrl.Detail)
+		purchaseOrder.POST("/upsert", init.PurchaseOrderCtrl.Save)
+		purchaseOrder.POST("/:uuid/send", init.PurchaseOrderCtrl.Send)
+		purchaseOrder.POST("/:uuid/cancel", init.PurchaseOrderCtrl.Cancel)
+		purchaseOrder.POST("/:uuid/receive", init.PurchaseOrderCtrl.Receive)
+		purchaseOrder.GET("/:uuid/receipts", init.PurchaseOrderCtrl.Receipts)
+		purchaseOrder.GET("/:uuid/pdf", init.PurchaseOrderCtrl.PDF)
+	}
+
+	stockLot := router.Group("/stock-lots", middleware.JWTAuthMiddleware())
+	{
+		stockLot.POST("/fetch", init.StockLotCtrl.List)
+		stockLot.POST("/expiring", init.StockLotCtrl.Expiring)
+	}
+
+	replenishment := router.Group("/replenishment", middleware.JWTAuthMiddleware())
+	{
+		replenishment.POST("/levels", init.ReplenishmentCtrl.SetLevels)
+		replenishment.POST("/generate", init.ReplenishmentCtrl.Generate)
+		replenishment.POST("/fetch", init.ReplenishmentCtrl.List)
+		replenishment.GET("/:uuid", init.ReplenishmentCtrl.Detail)
+		replenishment.POST("/:uuid/approve", init.ReplenishmentCtrl.Approve)
+		replenishment.POST("/:uuid/discard", init.ReplenishmentCtrl.Discard)
+	}
+
+	report := router.Group("/reports", middleware.JWTAuthMiddleware())
+	{
+		report.POST("/margin/transactions", init.ReportCtrl.MarginByTransaction)
+		report.POST("/margin/products", init.ReportCtrl.MarginByProduct)
+		report.POST("/margin/branches", init.ReportCtrl.MarginByBranch)
+		report.POST("/margin/categories", init.ReportCtrl.MarginByCategory)
+	}
+
+	receivable := router.Group("/receivables", middleware.JWTAuthMiddleware())
+	{
+		receivable.POST("/fetch", init.ReceivableCtrl.List)
+		receivable.GET("/outstanding", init.ReceivableCtrl.OutstandingByBranch)
+		receivable.POST("/aging", init.ReceivableCtrl.Aging)
+		receivable.GET("/:uuid", init.ReceivableCtrl.Detail)
+		receivable.POST("/:uuid/pay", init.ReceivableCtrl.Pay)
+		receivable.POST("/customer/:uuid/pay", init.ReceivableCtrl.PayCustomer)
+	}
+
+	barcodeTemplate := router.Group("/barcode-templates", middleware.JWTAuthMiddleware())
+	{
+		barcodeTemplate.GET("/", init.BarcodeTemplateCtrl.List)
+		barcodeTemplate.POST("/upsert", init.BarcodeTemplateCtrl.Upsert)
+		barcodeTemplate.DELETE("/:uuid", init.BarcodeTemplateCtrl.Delete)
+	}
+
+	barcode := router.Group("/barcodes", middleware.JWTAuthMiddleware())
+	{
+		barcode.GET("/prefix", init.BarcodeLabelCtrl.Prefix)
+		barcode.POST("/prefix", init.BarcodeLabelCtrl.SetPrefix)
+		barcode.POST("/assign", init.BarcodeLabelCtrl.Assign)
+		barcode.GET("/label-templates", init.BarcodeLabelCtrl.Templates)
+		barcode.POST("/labels", init.BarcodeLabelCtrl.Labels)
+	}
+
+	approval := router.Group("/approvals", middleware.JWTAuthMiddleware())
+	{
+		approval.GET("/policy", init.ApprovalCtrl.GetPolicy)
+		approval.POST("/policy", init.ApprovalCtrl.SavePolicy)
+		approval.POST("/pin", init.ApprovalCtrl.SetPin)
+		approval.POST("/fetch", init.ApprovalCtrl.List)
+		approval.GET("/:uuid", init.ApprovalCtrl.Detail)
+		approval.POST("/:uuid/approve", init.ApprovalCtrl.Approve)
+		approval.POST("/:uuid/reject", init.ApprovalCtrl.Reject)
+	}
+
+	priceChange := router.Group("/price-changes", middleware.JWTAuthMiddleware())
+	{
+		priceChange.POST("/schedule", init.PriceChangeCtrl.Schedule)
+		priceChange.POST("/fetch", init.PriceChangeCtrl.List)
+		priceChange.GET("/:uuid", init.PriceChangeCtrl.Detail)
+		priceChange.POST("/:uuid/approve", init.PriceChangeCtrl.Approve)
+		priceChange.POST("/:uuid/reject", init.PriceChangeCtrl.Reject)
+		priceChange.POST("/:uuid/cancel", init.PriceChangeCtrl.Cancel)
+	}
+
+	receiptTemplate := router.Group("/receipt-templates", middleware.JWTAuthMiddleware())
+	{
+		receiptTemplate.GET("/", init.ReceiptCtrl.GetTemplate)
+		receiptTemplate.POST("/upsert", init.ReceiptCtrl.SaveTemplate)
+	}
+
+	loyalty := router.Group("/loyalty-rules", middleware.JWTAuthMiddleware())
+	{
+		loyalty.GET("/", init.CustomerCtrl.GetLoyaltyRule)
+		loyalty.POST("/upsert", init.CustomerCtrl.SaveLoyaltyRule)
+	}
+
 	return router
 }
@@ -0,0 +1,402 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+	"harjonan.id/user-service/app/repository"
+)
+
+type ApprovalService interface {
+	GetPolicy(ctx *gin.Context)
+	SavePolicy(ctx *gin.Context)
+	SetPin(ctx *gin.Context)
+	List(ctx *gin.Context)
+	Detail(ctx *gin.Context)
+	Approve(ctx *gin.Context)
+	Reject(ctx *gin.Context)
+}
+
+type ApprovalServiceImpl struct {
+	repo      repository.ApprovalRepository
+	authRepo  repository.AuthRepository
+	notifRepo repository.NotificationRepository
+}
+
+func NewApprovalService(repo repository.ApprovalRepository, authRepo repository.AuthRepository, notifRepo repository.NotificationRepository) *ApprovalServiceImpl {
+	return &ApprovalServiceImpl{repo: repo, authRepo: authRepo, notifRepo: notifRepo}
+}
+
+func (s *ApprovalServiceImpl) GetPolicy(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	data, err := s.repo.GetPolicy(profile.Client.UUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load policy", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", data)
+}
+
+func (s *ApprovalServiceImpl) SavePolicy(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	if !isOwnerRole(profile.Role.Value) {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("only OWNER can manage approval policy"))
+		return
+	}
+
+	var req dao.ApprovalPolicy
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if req.VoidMaxAgeMinutes < 0 || req.DiscountMaxPercent < 0 || req.DiscountMaxPercent > 100 || req.AdjustmentMaxQty < 0 || req.AdjustmentMaxValue < 0 {
+		helpers.JsonErr[any](ctx, "invalid policy", http.StatusBadRequest, errors.New("void_max_age_minutes >= 0, discount_max_percent 0-100, adjustment_max_qty/value >= 0"))
+		return
+	}
+	roles := make([]string, 0, len(req.ApproverRoles))
+	for _, r := range req.ApproverRoles {
+		if r = strings.TrimSpace(strings.ToUpper(r)); r != "" {
+			roles = append(roles, r)
+		}
+	}
+	req.ApproverRoles = roles
+	req.ClientUUID = profile.Client.UUID
+	req.UpdatedBy = profile.UUID
+
+	res, err := s.repo.SavePolicy(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to save policy", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", res)
+}
+
+// POST /approvals/pin body: { "pin": "123456" } — set PIN approval milik sendiri
+func (s *ApprovalServiceImpl) SetPin(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	policy, err := s.repo.GetPolicy(profile.Client.UUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load policy", http.StatusInternalServerError, err)
+		return
+	}
+	if !isApproverRole(policy, profile.Role.Value) {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("role is not allowed to approve"))
+		return
+	}
+
+	var req dto.SupervisorPinRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	req.PIN = strings.TrimSpace(req.PIN)
+	if len(req.PIN) < 4 || len(req.PIN) > 8 || !helpers.IsNumeric(req.PIN) {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("pin must be 4-8 digits"))
+		return
+	}
+
+	err = s.repo.SavePin(&dao.SupervisorPin{
+		ClientUUID: profile.Client.UUID,
+		UserUUID:   profile.UUID,
+		Name:       profile.Name,
+		RoleValue:  profile.Role.Value,
+		PinHash:    hashSupervisorPin(profile.Client.UUID, profile.UUID, req.PIN),
+	})
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to save pin", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK[struct{}](ctx, "success", struct{}{})
+}
+
+func (s *ApprovalServiceImpl) List(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	var req dto.FilterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if req.FilterBy == nil {
+		req.FilterBy = map[string]any{}
+	}
+	req.FilterBy["client_uuid"] = profile.Client.UUID
+
+	// non-approver hanya lihat request miliknya
+	policy, _ := s.repo.GetPolicy(profile.Client.UUID)
+	if !isApproverRole(policy, profile.Role.Value) {
+		req.FilterBy["requested_by"] = profile.UUID
+	}
+
+	data, err := s.repo.List(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to list approval", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", data)
+}
+
+func (s *ApprovalServiceImpl) Detail(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	a, err := s.repo.Detail(strings.TrimSpace(ctx.Param("uuid")))
+	if err != nil || a.ClientUUID != profile.Client.UUID {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("approval not found"))
+		return
+	}
+	helpers.JsonOK(ctx, "success", a)
+}
+
+// POST /approvals/:uuid/approve body: { "note": "" }
+func (s *ApprovalServiceImpl) Approve(ctx *gin.Context) {
+	s.decide(ctx, dao.ApprovalApproved)
+}
+
+// POST /approvals/:uuid/reject body: { "note": "" }
+func (s *ApprovalServiceImpl) Reject(ctx *gin.Context) {
+	s.decide(ctx, dao.ApprovalRejected)
+}
+
+func (s *ApprovalServiceImpl) decide(ctx *gin.Context, status dao.ApprovalStatus) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	policy, err := s.repo.GetPolicy(profile.Client.UUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load policy", http.StatusInternalServerError, err)
+		return
+	}
+	if !isApproverRole(policy, profile.Role.Value) {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("role is not allowed to approve"))
+		return
+	}
+
+	var req dto.ApprovalDecisionRequest
+	_ = ctx.ShouldBindJSON(&req)
+
+	uuid := strings.TrimSpace(ctx.Param("uuid"))
+	a, err := s.repo.Detail(uuid)
+	if err != nil || a.ClientUUID != profile.Client.UUID {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("approval not found"))
+		return
+	}
+
+	out, err := s.repo.Decide(uuid, status, profile.UUID, profile.Name, strings.TrimSpace(req.Note))
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to decide approval", http.StatusBadRequest, err)
+		return
+	}
+
+	// kabari kasir yang request
+	title := "Approval Disetujui"
+	icon := "success"
+	if status == dao.ApprovalRejected {
+		title = "Approval Ditolak"
+		icon = "warning"
+	}
+	_, _ = s.notifRepo.Insert(&dao.Notification{
+		ClientUUID: out.ClientUUID,
+		BranchUUID: out.BranchUUID,
+		UserUUID:   out.RequestedBy,
+		Title:      title,
+		Message:    fmt.Sprintf("%s • %s • oleh %s", out.Type, out.Reason, profile.Name),
+		Icon:       icon,
+		Type:       "APPROVAL",
+		Ref:        out.UUID,
+	})
+
+	helpers.JsonOK(ctx, "success", out)
+}
+
+// -------------------------------
+// Guard approval (dipakai POS void/checkout)
+// -------------------------------
+
+type approvalNeed struct {
+	Type            dao.ApprovalType
+	Ref             string
+	BranchUUID      string
+	Reason          string
+	DiscountPercent float64
+	Amount          float64
+}
+
+const (
+	pinMaxFailed = 5
+	pinLockFor   = 15 * time.Minute
+)
+
+func isApproverRole(policy dao.ApprovalPolicy, roleValue string) bool {
+	for _, r := range policy.ApproverRoles {
+		if strings.EqualFold(r, roleValue) {
+			return true
+		}
+	}
+	return false
+}
+
+func hashSupervisorPin(clientUUID, userUUID, pin string) string {
+	hasher := sha256.New()
+	hasher.Write([]byte(clientUUID + ":" + userUUID + ":" + pin))
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// resolveApproval: cek approval untuk aksi yang melewati threshold.
+// Return status http: 200 = boleh lanjut (approval tercatat), 202 = request dibuat & menunggu OWNER,
+// lainnya = ditolak (err berisi alasan).
+func resolveApproval(repo repository.ApprovalRepository, notifRepo repository.NotificationRepository, profile *dto.UserProfile, policy dao.ApprovalPolicy, need approvalNeed, in dto.ApprovalInput) (dao.Approval, int, error) {
+	now := time.Now()
+	base := dao.Approval{
+		ClientUUID:      profile.Client.UUID,
+		BranchUUID:      need.BranchUUID,
+		Type:            need.Type,
+		Ref:             need.Ref,
+		Reason:          need.Reason,
+		DiscountPercent: need.DiscountPercent,
+		Amount:          need.Amount,
+		RequestedBy:     profile.UUID,
+		RequestedByName: profile.Name,
+		RequestNote:     strings.TrimSpace(in.RequestNote),
+	}
+
+	// 1) pelaku sendiri supervisor
+	if isApproverRole(policy, profile.Role.Value) {
+		base.Method = dao.ApprovalBySelf
+		base.Status = dao.ApprovalUsed
+		base.ApprovedBy = profile.UUID
+		base.ApprovedByName = profile.Name
+		base.DecidedAt = now.Unix()
+		base.DecidedAtStr = now.Format(time.RFC3339)
+		base.UsedAt = now.Unix()
+		base.UsedRef = need.Ref
+		out, err := repo.Insert(&base)
+		if err != nil {
+			return dao.Approval{}, http.StatusInternalServerError, err
+		}
+		return out, http.StatusOK, nil
+	}
+
+	// 2) PIN inline
+	if approver := strings.TrimSpace(in.ApproverUUID); approver != "" {
+		pin, err := repo.GetPin(profile.Client.UUID, approver)
+		if err != nil || !isApproverRole(policy, pin.RoleValue) {
+			return dao.Approval{}, http.StatusForbidden, errors.New("invalid approver or pin")
+		}
+		if pin.LockedUntil > now.Unix() {
+			return dao.Approval{}, http.StatusForbidden, errors.New("pin locked, try again later")
+		}
+		if pin.PinHash != hashSupervisorPin(profile.Client.UUID, approver, strings.TrimSpace(in.ApproverPIN)) {
+			var lockUntil int64
+			if pin.FailedAttempts+1 >= pinMaxFailed {
+				lockUntil = now.Add(pinLockFor).Unix()
+			}
+			_, _ = repo.PinFailed(pin.UUID, lockUntil)
+			return dao.Approval{}, http.StatusForbidden, errors.New("invalid approver or pin")
+		}
+		_ = repo.PinSucceeded(pin.UUID)
+
+		base.Method = dao.ApprovalByPIN
+		base.Status = dao.ApprovalUsed
+		base.ApprovedBy = pin.UserUUID
+		base.ApprovedByName = pin.Name
+		base.DecidedAt = now.Unix()
+		base.DecidedAtStr = now.Format(time.RFC3339)
+		base.UsedAt = now.Unix()
+		base.UsedRef = need.Ref
+		out, err := repo.Insert(&base)
+		if err != nil {
+			return dao.Approval{}, http.StatusInternalServerError, err
+		}
+		return out, http.StatusOK, nil
+	}
+
+	// 3) pakai request async yang sudah disetujui
+	if uuid := strings.TrimSpace(in.ApprovalUUID); uuid != "" {
+		a, err := repo.Detail(uuid)
+		if err != nil || a.ClientUUID != profile.Client.UUID || a.Type != need.Type {
+			return dao.Approval{}, http.StatusForbidden, errors.New("approval not found")
+		}
+		switch a.Status {
+		case dao.ApprovalPending:
+			return a, http.StatusAccepted, nil
+		case dao.ApprovalRejected:
+			return dao.Approval{}, http.StatusForbidden, errors.New("approval rejected")
+		}
+		if need.Type == dao.ApprovalVoid && a.Ref != need.Ref {
+			return dao.Approval{}, http.StatusForbidden, errors.New("approval is for another transaction")
+		}
+		if need.Type == dao.ApprovalDiscount {
+			if a.RequestedBy != profile.UUID || a.BranchUUID != need.BranchUUID {
+				return dao.Approval{}, http.StatusForbidden, errors.New("approval is for another cashier/branch")
+			}
+			if need.DiscountPercent > a.DiscountPercent+0.0001 {
+				return dao.Approval{}, http.StatusForbidden, fmt.Errorf("approved discount is %.2f%%", a.DiscountPercent)
+			}
+		}
+		out, err := repo.Consume(uuid, need.Ref)
+		if err != nil {
+			return dao.Approval{}, http.StatusForbidden, err
+		}
+		return out, http.StatusOK, nil
+	}
+
+	// 4) kirim request ke OWNER
+	if in.RequestApproval {
+		base.Method = dao.ApprovalByRequest
+		base.Status = dao.ApprovalPending
+		base.ExpiresAt = now.Add(time.Duration(policy.RequestTTLMinutes) * time.Minute).Unix()
+		out, err := repo.Insert(&base)
+		if err != nil {
+			return dao.Approval{}, http.StatusInternalServerError, err
+		}
+		// BranchUUID kosong => hanya owner yang lihat (owner fetch semua notif client)
+		_, _ = notifRepo.Insert(&dao.Notification{
+			ClientUUID: profile.Client.UUID,
+			Title:      "Butuh Approval " + string(need.Type),
+			Message:    fmt.Sprintf("%s • %s • %s", profile.Name, need.Reason, newIDRCurrency(need.Amount)),
+			Icon:       "warning",
+			Type:       "APPROVAL",
+			Ref:        out.UUID,
+		})
+		return out, http.StatusAccepted, nil
+	}
+
+	return dao.Approval{}, http.StatusForbidden, errors.New(need.Reason)
+}
+
+// respondApproval: tulis response untuk hasil resolveApproval yang bukan 200. Return true kalau caller harus berhenti.
+func respondApproval(ctx *gin.Context, a dao.Approval, code int, err error) bool {
+	switch {
+	case code == http.StatusAccepted:
+		helpers.JsonOK(ctx, "approval pending", gin.H{"approval_required": true, "approval": a})
+		return true
+	case err != nil:
+		helpers.JsonErr[any](ctx, "supervisor approval required", code, err)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+	"harjonan.id/user-service/app/repository"
+)
+
+// requireClientProfile ambil profile dari access token + pastikan ada client_uuid.
+// Kalau gagal, response error sudah ditulis ke ctx (caller cukup return).
+func requireClientProfile(ctx *gin.Context, authRepo repository.AuthRepository) (*dto.UserProfile, bool) {
+	accessToken := ctx.GetString("access_token")
+	if accessToken == "" {
+		helpers.JsonErr[any](ctx, "missing access token", http.StatusBadRequest, errors.New("no bearer token"))
+		return nil, false
+	}
+	profile, err := authRepo.ValidateToken(accessToken)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "validate token failed", http.StatusUnauthorized, err)
+		return nil, false
+	}
+	if profile.Client.UUID == "" {
+		helpers.JsonErr[any](ctx, "client uuid not found", http.StatusUnauthorized, errors.New("missing client_uuid"))
+		return nil, false
+	}
+	return profile, true
+}
+
+func isOwnerRole(roleValue string) bool {
+	return roleValue == "OWNER" || roleValue == "SUPERADMIN"
+}
+
+// requireBranchAccess: branch harus milik client user; selain OWNER hanya boleh branch sendiri
+func requireBranchAccess(ctx *gin.Context, branchRepo repository.ClientBranchRepository, profile *dto.UserProfile, branchUUID string) bool {
+	if branchUUID == "" {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("branch_uuid required"))
+		return false
+	}
+	branch, err := branchRepo.DetailClientBranch(branchUUID)
+	if err != nil || branch.ClientUUID != profile.Client.UUID {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("branch not found"))
+		return false
+	}
+	if !isOwnerRole(profile.Role.Value) && branchUUID != profile.Branch.UUID {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("cannot access another branch"))
+		return false
+	}
+	return true
+}
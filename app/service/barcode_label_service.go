@@ -0,0 +1,271 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+	"harjonan.id/user-service/app/infra/document"
+	"harjonan.id/user-service/app/repository"
+)
+
+// BarcodeLabelService: EAN-13 internal untuk produk tanpa barcode + cetak label rak / stiker harga
+type BarcodeLabelService interface {
+	Prefix(ctx *gin.Context)
+	SetPrefix(ctx *gin.Context)
+	Assign(ctx *gin.Context)
+	Templates(ctx *gin.Context)
+	Labels(ctx *gin.Context)
+}
+
+type BarcodeLabelServiceImpl struct {
+	repo         repository.BarcodeSequenceRepository
+	catalogRepo  repository.CatalogRepository
+	prodRepo     repository.ProductRepository
+	templateRepo repository.BarcodeTemplateRepository
+	branchRepo   repository.ClientBranchRepository
+	authRepo     repository.AuthRepository
+}
+
+func NewBarcodeLabelService(repo repository.BarcodeSequenceRepository, catalogRepo repository.CatalogRepository, prodRepo repository.ProductRepository, templateRepo repository.BarcodeTemplateRepository, branchRepo repository.ClientBranchRepository, authRepo repository.AuthRepository) *BarcodeLabelServiceImpl {
+	return &BarcodeLabelServiceImpl{repo: repo, catalogRepo: catalogRepo, prodRepo: prodRepo, templateRepo: templateRepo, branchRepo: branchRepo, authRepo: authRepo}
+}
+
+const (
+	maxBarcodeAssign = 500
+	maxLabelsPerPDF  = 2000
+)
+
+// GET /barcodes/prefix
+func (s *BarcodeLabelServiceImpl) Prefix(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	seq, err := s.repo.Get(profile.Client.UUID)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		helpers.JsonErr[any](ctx, "failed to load barcode prefix", http.StatusInternalServerError, err)
+		return
+	}
+	seq.ClientUUID = profile.Client.UUID
+	helpers.JsonOK(ctx, "success", seq)
+}
+
+// POST /barcodes/prefix (OWNER) body: { "prefix": "28" }
+// prefix tidak boleh bentrok dengan template timbangan (scale barcode juga EAN-13 prefix 20-29)
+func (s *BarcodeLabelServiceImpl) SetPrefix(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	if !isOwnerRole(profile.Role.Value) {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("only OWNER can change barcode prefix"))
+		return
+	}
+
+	var req dto.BarcodePrefixRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	req.Prefix = strings.TrimSpace(req.Prefix)
+	if _, err := helpers.BuildEAN13(req.Prefix, 1); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+
+	templates, err := s.templateRepo.List(profile.Client.UUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load barcode template", http.StatusInternalServerError, err)
+		return
+	}
+	for _, t := range templates {
+		if strings.HasPrefix(req.Prefix, t.Prefix) || strings.HasPrefix(t.Prefix, req.Prefix) {
+			helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest,
+				fmt.Errorf("prefix conflicts with scale barcode template %s (%s)", t.Name, t.Prefix))
+			return
+		}
+	}
+
+	out, err := s.repo.SetPrefix(profile.Client.UUID, req.Prefix, profile.UUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to save barcode prefix", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", out)
+}
+
+// POST /barcodes/assign (OWNER) body: { "catalog_uuids": [], "limit": 100 }
+// barcode disimpan di catalog lalu ikut ke semua branch
+func (s *BarcodeLabelServiceImpl) Assign(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	if !isOwnerRole(profile.Role.Value) {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("only OWNER can assign barcodes"))
+		return
+	}
+
+	var req dto.BarcodeAssignRequest
+	_ = ctx.ShouldBindJSON(&req)
+	if req.Limit <= 0 || req.Limit > maxBarcodeAssign {
+		req.Limit = maxBarcodeAssign
+	}
+
+	list, err := s.catalogRepo.WithoutBarcode(profile.Client.UUID, req.CatalogUUIDs, req.Limit)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load catalog", http.StatusInternalServerError, err)
+		return
+	}
+	res := dto.BarcodeAssignResult{Assigned: []dto.BarcodeAssigned{}, Failed: []dto.BarcodeAssigned{}}
+	if len(list) == 0 {
+		helpers.JsonOK(ctx, "success", res)
+		return
+	}
+
+	// cadangan nomor untuk kode yang ternyata sudah dipakai (input manual dengan prefix sama)
+	n := int64(len(list))
+	prefix, next, err := s.repo.Reserve(profile.Client.UUID, n+n/10+5)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to reserve barcode", http.StatusBadRequest, err)
+		return
+	}
+	last := next + n + n/10 + 5
+	res.Prefix = prefix
+
+	for _, cat := range list {
+		row := dto.BarcodeAssigned{CatalogUUID: cat.UUID, SKU: cat.SKU, Name: cat.Name}
+		for row.Barcode == "" && row.Error == "" {
+			if next >= last {
+				row.Error = "out of reserved numbers, retry"
+				break
+			}
+			code, err := helpers.BuildEAN13(prefix, next)
+			next++
+			if err != nil {
+				row.Error = err.Error()
+				break
+			}
+			if _, err := s.catalogRepo.FindByBarcode(profile.Client.UUID, code); err == nil {
+				continue
+			}
+			row.Barcode = code
+		}
+		if row.Error != "" {
+			res.Failed = append(res.Failed, row)
+			continue
+		}
+
+		cat.Barcode = row.Barcode
+		if _, err := s.catalogRepo.Save(&cat); err != nil {
+			row.Barcode, row.Error = "", err.Error()
+			res.Failed = append(res.Failed, row)
+			continue
+		}
+		res.Assigned = append(res.Assigned, row)
+	}
+	helpers.JsonOK(ctx, "success", res)
+}
+
+// GET /barcodes/label-templates => preset layout label
+func (s *BarcodeLabelServiceImpl) Templates(ctx *gin.Context) {
+	out := map[string]dto.LabelLayout{}
+	for code, l := range document.LabelPresets {
+		out[code] = dto.LabelLayout(l)
+	}
+	helpers.JsonOK(ctx, "success", out)
+}
+
+// POST /barcodes/labels body: { "branch_uuid": "", "template": "A4_3X8", "items": [{ "product_uuid": "", "copies": 3 }] }
+// => PDF siap cetak
+func (s *BarcodeLabelServiceImpl) Labels(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.LabelPrintRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	req.BranchUUID = strings.TrimSpace(req.BranchUUID)
+	if req.BranchUUID == "" {
+		req.BranchUUID = profile.Branch.UUID
+	}
+	if !requireBranchAccess(ctx, s.branchRepo, profile, req.BranchUUID) {
+		return
+	}
+
+	var layout document.LabelLayout
+	switch {
+	case req.Layout != nil:
+		layout = document.LabelLayout(*req.Layout)
+	case req.Template != "":
+		preset, ok := document.LabelPresets[strings.ToUpper(req.Template)]
+		if !ok {
+			helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("unknown label template "+req.Template))
+			return
+		}
+		layout = preset
+	default:
+		layout = document.LabelPresets["A4_3X8"]
+	}
+	if err := layout.Validate(); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if len(req.Items) == 0 {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("items required"))
+		return
+	}
+
+	labels := []document.LabelItem{}
+	for _, it := range req.Items {
+		p, err := s.prodRepo.DetailProduct(strings.TrimSpace(it.ProductUUID))
+		if err != nil || p.BranchUUID != req.BranchUUID {
+			helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("product "+it.ProductUUID+" not found in branch"))
+			return
+		}
+		unit, _, price, err := resolveSaleUnit(p, it.Unit)
+		if err != nil {
+			helpers.JsonErr[any](ctx, "invalid unit", http.StatusBadRequest, err)
+			return
+		}
+		label := document.LabelItem{Name: p.Name, Barcode: p.Barcode, Price: price}
+		if unit != p.BaseUnit {
+			label.Unit = unit
+		}
+		copies := max(it.Copies, 1)
+		if len(labels)+copies > maxLabelsPerPDF {
+			helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, fmt.Errorf("max %d labels per print", maxLabelsPerPDF))
+			return
+		}
+		for range copies {
+			labels = append(labels, label)
+		}
+	}
+
+	opt := document.LabelOptions{
+		ShowPrice: req.ShowPrice == nil || *req.ShowPrice,
+		Border:    req.Border,
+		SkipSlots: max(req.SkipSlots, 0),
+	}
+	if req.ShowStoreName {
+		opt.StoreName = profile.Client.Name
+	}
+	out, err := document.RenderLabelsPDF(layout, labels, opt)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to render labels", http.StatusInternalServerError, err)
+		return
+	}
+	ctx.Header("Content-Disposition", `inline; filename="labels.pdf"`)
+	ctx.Data(http.StatusOK, "application/pdf", out)
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/helpers"
+	"harjonan.id/user-service/app/repository"
+)
+
+type BarcodeTemplateService interface {
+	Upsert(ctx *gin.Context)
+	List(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+}
+
+type BarcodeTemplateServiceImpl struct {
+	repo     repository.BarcodeTemplateRepository
+	authRepo repository.AuthRepository
+}
+
+func NewBarcodeTemplateService(repo repository.BarcodeTemplateRepository, authRepo repository.AuthRepository) *BarcodeTemplateServiceImpl {
+	return &BarcodeTemplateServiceImpl{repo: repo, authRepo: authRepo}
+}
+
+func (s *BarcodeTemplateServiceImpl) Upsert(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	if !isOwnerRole(profile.Role.Value) {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("only OWNER can manage barcode template"))
+		return
+	}
+
+	var req dao.BarcodeTemplate
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if err := validateBarcodeTemplate(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid template", http.StatusBadRequest, err)
+		return
+	}
+	if req.UUID != "" {
+		existing, err := s.repo.Detail(req.UUID)
+		if err != nil || existing.ClientUUID != profile.Client.UUID {
+			helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("template not found"))
+			return
+		}
+	}
+
+	req.ClientUUID = profile.Client.UUID
+	req.CreatedBy = profile.UUID
+
+	res, err := s.repo.Save(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to save template", http.StatusBadRequest, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", res)
+}
+
+func (s *BarcodeTemplateServiceImpl) List(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	data, err := s.repo.List(profile.Client.UUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to list template", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", data)
+}
+
+func (s *BarcodeTemplateServiceImpl) Delete(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	if !isOwnerRole(profile.Role.Value) {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("only OWNER can manage barcode template"))
+		return
+	}
+	uuid := strings.TrimSpace(ctx.Param("uuid"))
+	existing, err := s.repo.Detail(uuid)
+	if err != nil || existing.ClientUUID != profile.Client.UUID {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("template not found"))
+		return
+	}
+	if err := s.repo.Delete(uuid); err != nil {
+		helpers.JsonErr[any](ctx, "failed to delete template", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK[struct{}](ctx, "success", struct{}{})
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+	"harjonan.id/user-service/app/repository"
+)
+
+type BrandService interface {
+	List(ctx *gin.Context)
+	Upsert(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+}
+
+type BrandServiceImpl struct {
+	repo     repository.BrandRepository
+	authRepo repository.AuthRepository
+}
+
+func NewBrandService(repo repository.BrandRepository, authRepo repository.AuthRepository) *BrandServiceImpl {
+	return &BrandServiceImpl{repo: repo, authRepo: authRepo}
+}
+
+func (s *BrandServiceImpl) List(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.FilterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+
+	data, err := s.repo.List(profile.Client.UUID, &req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to list brand", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", data)
+}
+
+func (s *BrandServiceImpl) Upsert(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	if !isOwnerRole(profile.Role.Value) {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("only OWNER can change brand"))
+		return
+	}
+
+	var req dao.ProductBrand
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if req.UUID != "" {
+		old, err := s.repo.Detail(req.UUID)
+		if err != nil || old.ClientUUID != profile.Client.UUID {
+			helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("brand not found"))
+			return
+		}
+	}
+	req.ClientUUID = profile.Client.UUID
+	req.CreatedBy = profile.UUID
+
+	out, err := s.repo.Save(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to save brand", http.StatusBadRequest, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", out)
+}
+
+func (s *BrandServiceImpl) Delete(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	if !isOwnerRole(profile.Role.Value) {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("only OWNER can change brand"))
+		return
+	}
+	uuid := strings.TrimSpace(ctx.Param("uuid"))
+	b, err := s.repo.Detail(uuid)
+	if err != nil || b.ClientUUID != profile.Client.UUID {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("brand not found"))
+		return
+	}
+	if err := s.repo.Delete(uuid); err != nil {
+		helpers.JsonErr[any](ctx, "failed to delete brand", http.StatusConflict, err)
+		return
+	}
+	helpers.JsonOK[struct{}](ctx, "success", struct{}{})
+}
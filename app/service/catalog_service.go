@@ -0,0 +1,467 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+	"harjonan.id/user-service/app/repository"
+)
+
+// CatalogService: view master produk level client. View per branch (stock, harga, aktif) tetap di /products.
+type CatalogService interface {
+	List(ctx *gin.Context)
+	Detail(ctx *gin.Context)
+	Upsert(ctx *gin.Context)
+	SetInventory(ctx *gin.Context)
+	Migrate(ctx *gin.Context)
+	Tags(ctx *gin.Context)
+	GenerateVariants(ctx *gin.Context)
+}
+
+type CatalogServiceImpl struct {
+	repo         repository.CatalogRepository
+	categoryRepo repository.CategoryRepository
+	brandRepo    repository.BrandRepository
+	branchRepo   repository.ClientBranchRepository
+	authRepo     repository.AuthRepository
+	priceRepo    repository.PriceChangeRepository
+}
+
+func NewCatalogService(repo repository.CatalogRepository, categoryRepo repository.CategoryRepository, brandRepo repository.BrandRepository, branchRepo repository.ClientBranchRepository, authRepo repository.AuthRepository, priceRepo repository.PriceChangeRepository) *CatalogServiceImpl {
+	return &CatalogServiceImpl{repo: repo, categoryRepo: categoryRepo, brandRepo: brandRepo, branchRepo: branchRepo, authRepo: authRepo, priceRepo: priceRepo}
+}
+
+// POST /catalog/fetch body: FilterRequest (search name/sku/barcode/kategori/brand/tag,
+// filter_by.category_uuid = kategori + semua sub-kategori, filter_by.tags = "kopi")
+func (s *CatalogServiceImpl) List(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.FilterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if !withCategorySubtree(ctx, s.categoryRepo, &req) {
+		return
+	}
+
+	data, err := s.repo.List(profile.Client.UUID, &req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to list catalog", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", data)
+}
+
+// GET /catalog/:uuid => master + inventory per branch (non-OWNER hanya branch sendiri)
+func (s *CatalogServiceImpl) Detail(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	cat, ok := s.load(ctx, profile)
+	if !ok {
+		return
+	}
+
+	inv, err := s.repo.Inventories(cat.UUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load inventory", http.StatusInternalServerError, err)
+		return
+	}
+	if !isOwnerRole(profile.Role.Value) {
+		own := []dao.Product{}
+		for _, p := range inv {
+			if p.BranchUUID == profile.Branch.UUID {
+				own = append(own, p)
+			}
+		}
+		inv = own
+	}
+
+	res := dto.CatalogDetailResponse{CatalogProduct: cat, Inventories: inv}
+	if cat.HasVariants {
+		if res.Variants, err = s.repo.Variants(cat.UUID); err != nil {
+			helpers.JsonErr[any](ctx, "failed to load variants", http.StatusInternalServerError, err)
+			return
+		}
+	}
+	helpers.JsonOK(ctx, "success", res)
+}
+
+// POST /catalog/upsert (OWNER) body: CatalogProduct. Perubahan langsung ikut ke semua branch.
+func (s *CatalogServiceImpl) Upsert(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	if !isOwnerRole(profile.Role.Value) {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("only OWNER can change catalog"))
+		return
+	}
+
+	var req dao.CatalogProduct
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	req.SKU = strings.TrimSpace(req.SKU)
+	req.Name = strings.TrimSpace(req.Name)
+	req.BaseUnit = strings.TrimSpace(req.BaseUnit)
+	if req.Name == "" || req.BaseUnit == "" {
+		helpers.JsonErr[any](ctx, "missing identifier", http.StatusBadRequest, errors.New("name & base_unit required"))
+		return
+	}
+	if req.Price < 0 {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("price must be >= 0"))
+		return
+	}
+
+	var oldPrice *float64
+	if req.UUID != "" {
+		old, err := s.repo.Detail(req.UUID)
+		if err != nil || old.ClientUUID != profile.Client.UUID {
+			helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("catalog product not found"))
+			return
+		}
+		oldPrice = &old.Price
+		req.CreatedBy = old.CreatedBy
+		// struktur varian hanya diubah lewat /catalog/:uuid/variants
+		req.HasVariants = old.HasVariants
+		req.VariantAttributes = old.VariantAttributes
+		req.ParentUUID = old.ParentUUID
+		req.VariantOptions = old.VariantOptions
+	} else {
+		req.CreatedBy = profile.UUID
+		req.HasVariants = false
+		req.VariantAttributes = nil
+		req.ParentUUID = ""
+		req.VariantOptions = nil
+	}
+	req.ClientUUID = profile.Client.UUID
+	req.Units = ensureBaseUnit(req.BaseUnit, req.Units)
+	req.Tags = normalizeTags(req.Tags)
+	if req.Tags == nil {
+		req.Tags = []string{}
+	}
+	if err := classifyProduct(s.categoryRepo, s.brandRepo, profile.Client.UUID, profile.UUID,
+		&req.CategoryUUID, &req.CategoryPath, &req.BrandUUID, &req.BrandName); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+
+	out, err := s.repo.Save(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to save catalog product", http.StatusBadRequest, err)
+		return
+	}
+	if oldPrice != nil {
+		recordPriceHistory(s.priceRepo, dao.PriceHistory{
+			ClientUUID:  out.ClientUUID,
+			CatalogUUID: out.UUID,
+			SKU:         out.SKU,
+			Name:        out.Name,
+			OldPrice:    *oldPrice,
+			NewPrice:    out.Price,
+			Source:      dao.PriceSourceManual,
+			ChangedBy:   profile.UUID,
+		})
+	}
+	helpers.JsonOK(ctx, "success", out)
+}
+
+// POST /catalog/:uuid/inventory body: { "branch_uuid": "", "price_override": 12000, "is_active": true }
+// branch belum punya produk ini => dibuat dengan stock 0 (stock masuk lewat PO / transfer / adjustment)
+func (s *CatalogServiceImpl) SetInventory(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	cat, ok := s.load(ctx, profile)
+	if !ok {
+		return
+	}
+
+	var req dto.CatalogInventoryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	req.BranchUUID = strings.TrimSpace(req.BranchUUID)
+	if req.BranchUUID == "" {
+		req.BranchUUID = profile.Branch.UUID
+	}
+	if !requireBranchAccess(ctx, s.branchRepo, profile, req.BranchUUID) {
+		return
+	}
+
+	old, oldErr := branchInventory(s.repo, cat.UUID, req.BranchUUID)
+	out, err := s.repo.SetInventory(cat, req, profile.UUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to save inventory", http.StatusBadRequest, err)
+		return
+	}
+	if oldErr == nil {
+		recordPriceHistory(s.priceRepo, dao.PriceHistory{
+			ClientUUID:  cat.ClientUUID,
+			CatalogUUID: cat.UUID,
+			BranchUUID:  out.BranchUUID,
+			ProductUUID: out.UUID,
+			SKU:         out.SKU,
+			Name:        out.Name,
+			OldPrice:    old.Price,
+			NewPrice:    out.Price,
+			Source:      dao.PriceSourceManual,
+			ChangedBy:   profile.UUID,
+		})
+	}
+	helpers.JsonOK(ctx, "success", out)
+}
+
+// POST /catalog/migrate (OWNER) body: { "dry_run": true }
+// produk lama per branch digabung ke catalog by SKU; aman diulang (yang sudah terhubung dilewati)
+func (s *CatalogServiceImpl) Migrate(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	if !isOwnerRole(profile.Role.Value) {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("only OWNER can migrate catalog"))
+		return
+	}
+
+	var req dto.CatalogMigrateRequest
+	_ = ctx.ShouldBindJSON(&req)
+
+	out, err := s.repo.Migrate(profile.Client.UUID, req.DryRun)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to migrate catalog", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", out)
+}
+
+// GET /catalog/tags
+func (s *CatalogServiceImpl) Tags(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	data, err := s.repo.Tags(profile.Client.UUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to list tags", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", data)
+}
+
+// POST /catalog/:uuid/variants (OWNER) body: { "attributes": [{ "name": "Ukuran", "values": ["S","M"] }], "branch_uuids": [] }
+// varian baru: SKU parent-S, nama "Parent - S / Merah", unit / harga / kategori ikut parent
+func (s *CatalogServiceImpl) GenerateVariants(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	if !isOwnerRole(profile.Role.Value) {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("only OWNER can change catalog"))
+		return
+	}
+	parent, ok := s.load(ctx, profile)
+	if !ok {
+		return
+	}
+	if parent.ParentUUID != "" {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("variant cannot have variants"))
+		return
+	}
+
+	var req dto.CatalogVariantRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	attrs, err := normalizeVariantAttributes(req.Attributes)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	for _, b := range req.BranchUUIDs {
+		if !requireBranchAccess(ctx, s.branchRepo, profile, b) {
+			return
+		}
+	}
+
+	existing, err := s.repo.Variants(parent.UUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load variants", http.StatusInternalServerError, err)
+		return
+	}
+	have := map[string]bool{}
+	for _, v := range existing {
+		have[variantKey(v.VariantOptions)] = true
+	}
+
+	// atribut lama yang tidak dikirim tetap dipertahankan, kombinasi dihitung dari gabungannya
+	parent, err = s.repo.SetVariantAttributes(parent.UUID, mergeVariantAttributes(parent.VariantAttributes, attrs))
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to save variants", http.StatusBadRequest, err)
+		return
+	}
+
+	created := []dao.CatalogProduct{}
+	for _, opts := range variantCombinations(parent.VariantAttributes) {
+		if have[variantKey(opts)] {
+			continue
+		}
+		values := make([]string, 0, len(opts))
+		for _, o := range opts {
+			values = append(values, o.Value)
+		}
+		v := dao.CatalogProduct{
+			ClientUUID:     parent.ClientUUID,
+			Name:           parent.Name + " - " + strings.Join(values, " / "),
+			Description:    parent.Description,
+			BaseUnit:       parent.BaseUnit,
+			Units:          parent.Units,
+			Image:          parent.Image,
+			CategoryUUID:   parent.CategoryUUID,
+			CategoryPath:   parent.CategoryPath,
+			BrandUUID:      parent.BrandUUID,
+			BrandName:      parent.BrandName,
+			Tags:           parent.Tags,
+			Price:          parent.Price,
+			ParentUUID:     parent.UUID,
+			VariantOptions: opts,
+			CreatedBy:      profile.UUID,
+		}
+		if parent.SKU != "" {
+			v.SKU = parent.SKU + "-" + strings.ToUpper(strings.Join(values, "-"))
+		}
+		out, err := s.repo.Save(&v)
+		if err != nil {
+			helpers.JsonErr[any](ctx, "failed to save variant "+v.Name, http.StatusBadRequest, err)
+			return
+		}
+		for _, b := range req.BranchUUIDs {
+			if _, err := s.repo.SetInventory(out, dto.CatalogInventoryRequest{BranchUUID: b}, profile.UUID); err != nil {
+				helpers.JsonErr[any](ctx, "failed to save inventory", http.StatusBadRequest, err)
+				return
+			}
+		}
+		created = append(created, out)
+	}
+
+	variants, err := s.repo.Variants(parent.UUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load variants", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", gin.H{"parent": parent, "created": created, "variants": variants})
+}
+
+const maxVariantCombinations = 200
+
+// normalizeVariantAttributes: trim, buang value kosong / dobel, nama atribut unik
+func normalizeVariantAttributes(in []dao.VariantAttribute) ([]dao.VariantAttribute, error) {
+	out := []dao.VariantAttribute{}
+	seen := map[string]bool{}
+	for _, a := range in {
+		name := strings.TrimSpace(a.Name)
+		if name == "" {
+			return nil, errors.New("attribute name required")
+		}
+		if seen[strings.ToLower(name)] {
+			return nil, errors.New("duplicate attribute " + name)
+		}
+		seen[strings.ToLower(name)] = true
+
+		values := []string{}
+		for _, v := range a.Values {
+			v = strings.TrimSpace(v)
+			if v != "" && !slices.ContainsFunc(values, func(x string) bool { return strings.EqualFold(x, v) }) {
+				values = append(values, v)
+			}
+		}
+		if len(values) == 0 {
+			return nil, errors.New("attribute " + name + " has no values")
+		}
+		out = append(out, dao.VariantAttribute{Name: name, Values: values})
+	}
+	if len(out) == 0 {
+		return nil, errors.New("attributes required")
+	}
+	combos := 1
+	for _, a := range out {
+		combos *= len(a.Values)
+	}
+	if combos > maxVariantCombinations {
+		return nil, fmt.Errorf("too many variants (%d), max %d", combos, maxVariantCombinations)
+	}
+	return out, nil
+}
+
+// mergeVariantAttributes: value baru ditambahkan ke atribut lama dengan nama sama
+func mergeVariantAttributes(old, in []dao.VariantAttribute) []dao.VariantAttribute {
+	out := append([]dao.VariantAttribute{}, old...)
+	for _, a := range in {
+		i := slices.IndexFunc(out, func(x dao.VariantAttribute) bool { return strings.EqualFold(x.Name, a.Name) })
+		if i < 0 {
+			out = append(out, a)
+			continue
+		}
+		values := append([]string{}, out[i].Values...)
+		for _, v := range a.Values {
+			if !slices.ContainsFunc(values, func(x string) bool { return strings.EqualFold(x, v) }) {
+				values = append(values, v)
+			}
+		}
+		out[i] = dao.VariantAttribute{Name: out[i].Name, Values: values}
+	}
+	return out
+}
+
+// variantCombinations: cartesian product value atribut, urut sesuai input
+func variantCombinations(attrs []dao.VariantAttribute) [][]dao.VariantOption {
+	combos := [][]dao.VariantOption{{}}
+	for _, a := range attrs {
+		next := make([][]dao.VariantOption, 0, len(combos)*len(a.Values))
+		for _, c := range combos {
+			for _, v := range a.Values {
+				opt := append(append([]dao.VariantOption{}, c...), dao.VariantOption{Name: a.Name, Value: v})
+				next = append(next, opt)
+			}
+		}
+		combos = next
+	}
+	return combos
+}
+
+// variantKey: kunci kombinasi tanpa peduli urutan atribut / huruf besar
+func variantKey(opts []dao.VariantOption) string {
+	parts := make([]string, 0, len(opts))
+	for _, o := range opts {
+		parts = append(parts, strings.ToLower(o.Name)+"="+strings.ToLower(o.Value))
+	}
+	slices.Sort(parts)
+	return strings.Join(parts, "|")
+}
+
+func (s *CatalogServiceImpl) load(ctx *gin.Context, profile *dto.UserProfile) (dao.CatalogProduct, bool) {
+	cat, err := s.repo.Detail(strings.TrimSpace(ctx.Param("uuid")))
+	if err != nil || cat.ClientUUID != profile.Client.UUID {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("catalog product not found"))
+		return dao.CatalogProduct{}, false
+	}
+	return cat, true
+}
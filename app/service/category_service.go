@@ -0,0 +1,254 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+	"harjonan.id/user-service/app/repository"
+)
+
+type CategoryService interface {
+	Tree(ctx *gin.Context)
+	Upsert(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+}
+
+type CategoryServiceImpl struct {
+	repo     repository.CategoryRepository
+	authRepo repository.AuthRepository
+}
+
+func NewCategoryService(repo repository.CategoryRepository, authRepo repository.AuthRepository) *CategoryServiceImpl {
+	return &CategoryServiceImpl{repo: repo, authRepo: authRepo}
+}
+
+// POST /categories/fetch => pohon kategori client (children bersarang)
+func (s *CategoryServiceImpl) Tree(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	list, err := s.repo.List(profile.Client.UUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to list category", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", buildCategoryTree(list))
+}
+
+// POST /categories/upsert (OWNER) body: { "uuid": "", "parent_uuid": "", "name": "Kopi", "sort_order": 1 }
+func (s *CategoryServiceImpl) Upsert(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	if !isOwnerRole(profile.Role.Value) {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("only OWNER can change category"))
+		return
+	}
+
+	var req dao.ProductCategory
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if req.UUID != "" {
+		old, err := s.repo.Detail(req.UUID)
+		if err != nil || old.ClientUUID != profile.Client.UUID {
+			helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("category not found"))
+			return
+		}
+		req.CreatedBy = old.CreatedBy
+	} else {
+		req.CreatedBy = profile.UUID
+	}
+	req.ClientUUID = profile.Client.UUID
+	req.ParentUUID = strings.TrimSpace(req.ParentUUID)
+
+	out, err := s.repo.Save(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to save category", http.StatusBadRequest, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", out)
+}
+
+func (s *CategoryServiceImpl) Delete(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	if !isOwnerRole(profile.Role.Value) {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("only OWNER can change category"))
+		return
+	}
+	uuid := strings.TrimSpace(ctx.Param("uuid"))
+	c, err := s.repo.Detail(uuid)
+	if err != nil || c.ClientUUID != profile.Client.UUID {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("category not found"))
+		return
+	}
+	if err := s.repo.Delete(uuid); err != nil {
+		helpers.JsonErr[any](ctx, "failed to delete category", http.StatusConflict, err)
+		return
+	}
+	helpers.JsonOK[struct{}](ctx, "success", struct{}{})
+}
+
+// buildCategoryTree: list (urut level) => root dengan children bersarang
+func buildCategoryTree(list []dao.ProductCategory) []dao.ProductCategory {
+	children := map[string][]dao.ProductCategory{}
+	for _, c := range list {
+		children[c.ParentUUID] = append(children[c.ParentUUID], c)
+	}
+	var attach func(parent string) []dao.ProductCategory
+	attach = func(parent string) []dao.ProductCategory {
+		nodes := children[parent]
+		for i := range nodes {
+			nodes[i].Children = attach(nodes[i].UUID)
+		}
+		return nodes
+	}
+	out := attach("")
+	if out == nil {
+		out = []dao.ProductCategory{}
+	}
+	return out
+}
+
+// rollUpByCategory: baris per kategori leaf digabung ke anak langsung parentUUID ("" = kategori root).
+// Produk tanpa kategori masuk baris "Tanpa Kategori" (hanya di level root).
+func rollUpByCategory(rows []dto.MarginRow, cats []dao.ProductCategory, parentUUID string) []dto.MarginRow {
+	byUUID := make(map[string]dao.ProductCategory, len(cats))
+	for _, c := range cats {
+		byUUID[c.UUID] = c
+	}
+	depth := 0
+	if parent, ok := byUUID[parentUUID]; ok {
+		depth = parent.Level + 1
+	}
+
+	acc := map[string]*dto.MarginRow{}
+	var order []string
+	for _, row := range rows {
+		c, ok := byUUID[row.Key]
+		key, label := "", "Tanpa Kategori"
+		switch {
+		case !ok:
+			if parentUUID != "" {
+				continue
+			}
+		case parentUUID != "" && c.UUID != parentUUID && !slices.Contains(c.Ancestors, parentUUID):
+			continue
+		case c.UUID == parentUUID || c.Level == depth:
+			key, label = c.UUID, c.Path
+		default:
+			key = c.Ancestors[depth]
+			label = byUUID[key].Path
+		}
+
+		r, seen := acc[key]
+		if !seen {
+			r = &dto.MarginRow{Key: key, Label: label}
+			acc[key] = r
+			order = append(order, key)
+		}
+		r.Transactions += row.Transactions
+		r.QtyBase += row.QtyBase
+		r.Revenue += row.Revenue
+		r.COGS += row.COGS
+		r.MissingCostItems += row.MissingCostItems
+	}
+
+	out := make([]dto.MarginRow, 0, len(order))
+	for _, k := range order {
+		out = append(out, *acc[k])
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Revenue > out[j].Revenue })
+	return out
+}
+
+// withCategorySubtree: filter_by.category_uuid diganti kategori tsb + semua turunannya
+func withCategorySubtree(ctx *gin.Context, categoryRepo repository.CategoryRepository, req *dto.FilterRequest) bool {
+	uuid, _ := req.FilterBy["category_uuid"].(string)
+	if uuid == "" {
+		return true
+	}
+	uuids, err := categoryRepo.SubtreeUUIDs(uuid)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load category", http.StatusInternalServerError, err)
+		return false
+	}
+	if len(uuids) == 0 {
+		uuids = []string{uuid}
+	}
+	req.FilterBy["category_uuid"] = bson.M{"$in": uuids}
+	return true
+}
+
+// classifyProduct: category_uuid / brand_uuid dicek milik client lalu path & nama diisi (denormalisasi);
+// tanpa uuid, category_path ("Minuman > Kopi") & brand_name dibuat kalau belum ada (bulk upload)
+func classifyProduct(categoryRepo repository.CategoryRepository, brandRepo repository.BrandRepository, clientUUID, createdBy string,
+	categoryUUID, categoryPath, brandUUID, brandName *string) error {
+	switch {
+	case *categoryUUID != "":
+		c, err := categoryRepo.Detail(*categoryUUID)
+		if err != nil || c.ClientUUID != clientUUID {
+			return errors.New("category not found")
+		}
+		*categoryPath = c.Path
+	case strings.TrimSpace(*categoryPath) != "":
+		c, err := categoryRepo.EnsurePath(clientUUID, *categoryPath, createdBy)
+		if err != nil {
+			return err
+		}
+		*categoryUUID, *categoryPath = c.UUID, c.Path
+	default:
+		*categoryPath = ""
+	}
+
+	switch {
+	case *brandUUID != "":
+		b, err := brandRepo.Detail(*brandUUID)
+		if err != nil || b.ClientUUID != clientUUID {
+			return errors.New("brand not found")
+		}
+		*brandName = b.Name
+	case strings.TrimSpace(*brandName) != "":
+		b, err := brandRepo.EnsureByName(clientUUID, *brandName, createdBy)
+		if err != nil {
+			return err
+		}
+		*brandUUID, *brandName = b.UUID, b.Name
+	default:
+		*brandName = ""
+	}
+	return nil
+}
+
+// normalizeTags: trim + lowercase + unik. nil tetap nil (= tag tidak diubah)
+func normalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	out := make([]string, 0, len(tags))
+	seen := map[string]bool{}
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	return out
+}
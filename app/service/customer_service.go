@@ -0,0 +1,302 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+	"harjonan.id/user-service/app/repository"
+)
+
+type CustomerService interface {
+	Upsert(ctx *gin.Context)
+	Detail(ctx *gin.Context)
+	List(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+	Lookup(ctx *gin.Context)
+	History(ctx *gin.Context)
+	PointLedger(ctx *gin.Context)
+
+	GetLoyaltyRule(ctx *gin.Context)
+	SaveLoyaltyRule(ctx *gin.Context)
+}
+
+type CustomerServiceImpl struct {
+	repo     repository.CustomerRepository
+	trxRepo  repository.POSTransactionRepository
+	authRepo repository.AuthRepository
+}
+
+func NewCustomerService(repo repository.CustomerRepository, trxRepo repository.POSTransactionRepository, authRepo repository.AuthRepository) *CustomerServiceImpl {
+	return &CustomerServiceImpl{repo: repo, trxRepo: trxRepo, authRepo: authRepo}
+}
+
+// detailScoped: ambil customer + pastikan milik client yang login
+func (s *CustomerServiceImpl) detailScoped(ctx *gin.Context, clientUUID string) (dao.Customer, bool) {
+	uuid := strings.TrimSpace(ctx.Param("uuid"))
+	if uuid == "" {
+		helpers.JsonErr[any](ctx, "missing uuid", http.StatusBadRequest, errors.New("uuid required"))
+		return dao.Customer{}, false
+	}
+	c, err := s.repo.DetailCustomer(uuid)
+	if err != nil || c.ClientUUID != clientUUID {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("customer not found"))
+		return dao.Customer{}, false
+	}
+	return c, true
+}
+
+func (s *CustomerServiceImpl) Upsert(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dao.Customer
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	req.Phone = strings.TrimSpace(req.Phone)
+	req.Email = strings.TrimSpace(req.Email)
+	req.MemberCode = strings.TrimSpace(req.MemberCode)
+	req.Tier = dao.CustomerTier(strings.ToUpper(strings.TrimSpace(string(req.Tier))))
+
+	if req.Name == "" {
+		helpers.JsonErr[any](ctx, "missing identifier", http.StatusBadRequest, errors.New("name required"))
+		return
+	}
+	switch req.Tier {
+	case "", dao.CustomerTierRegular, dao.CustomerTierSilver, dao.CustomerTierGold, dao.CustomerTierPlatinum:
+	default:
+		helpers.JsonErr[any](ctx, "invalid tier", http.StatusBadRequest, errors.New("tier must be REGULAR/SILVER/GOLD/PLATINUM"))
+		return
+	}
+
+	if req.CreditLimit < 0 || req.CreditTermDays < 0 {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("credit_limit & credit_term_days must be >= 0"))
+		return
+	}
+
+	var existing dao.Customer
+	if req.UUID != "" {
+		var err error
+		existing, err = s.repo.DetailCustomer(req.UUID)
+		if err != nil || existing.ClientUUID != profile.Client.UUID {
+			helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("customer not found"))
+			return
+		}
+	}
+
+	// limit kasbon hanya boleh diubah OWNER
+	if !isOwnerRole(profile.Role.Value) {
+		req.CreditLimit = existing.CreditLimit
+		req.CreditTermDays = existing.CreditTermDays
+	}
+
+	req.ClientUUID = profile.Client.UUID
+	req.CreatedBy = profile.UUID
+
+	res, err := s.repo.SaveCustomer(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to save customer", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", res)
+}
+
+func (s *CustomerServiceImpl) Detail(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	c, ok := s.detailScoped(ctx, profile.Client.UUID)
+	if !ok {
+		return
+	}
+	helpers.JsonOK(ctx, "success", c)
+}
+
+func (s *CustomerServiceImpl) List(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.FilterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if req.FilterBy == nil {
+		req.FilterBy = map[string]any{}
+	}
+	req.FilterBy["client_uuid"] = profile.Client.UUID
+
+	data, err := s.repo.ListCustomer(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to list customer", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", data)
+}
+
+func (s *CustomerServiceImpl) Delete(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	c, ok := s.detailScoped(ctx, profile.Client.UUID)
+	if !ok {
+		return
+	}
+	if err := s.repo.DeleteCustomer(c.UUID); err != nil {
+		helpers.JsonErr[any](ctx, "failed to delete customer", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK[struct{}](ctx, "success", struct{}{})
+}
+
+// POST /customers/lookup body: { "phone": "...", "member_code": "..." }
+// dipakai kasir sebelum checkout (ketik no hp / scan kartu member)
+func (s *CustomerServiceImpl) Lookup(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.CustomerLookupRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	req.Phone = strings.TrimSpace(req.Phone)
+	req.MemberCode = strings.TrimSpace(req.MemberCode)
+	if req.Phone == "" && req.MemberCode == "" {
+		helpers.JsonErr[any](ctx, "missing identifier", http.StatusBadRequest, errors.New("phone or member_code required"))
+		return
+	}
+
+	c, err := s.repo.FindCustomer(profile.Client.UUID, req.Phone, req.MemberCode)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("customer not found"))
+		return
+	}
+	helpers.JsonOK(ctx, "success", c)
+}
+
+// POST /customers/:uuid/history body: dto.FilterRequest (pagination)
+func (s *CustomerServiceImpl) History(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	c, ok := s.detailScoped(ctx, profile.Client.UUID)
+	if !ok {
+		return
+	}
+
+	var req dto.FilterRequest
+	_ = ctx.ShouldBindJSON(&req)
+	req.FilterBy = map[string]any{"customer_uuid": c.UUID}
+	if req.SortBy == nil {
+		req.SortBy = map[string]any{"created_at": "desc"}
+	}
+
+	data, err := s.trxRepo.List(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to list transaction", http.StatusInternalServerError, err)
+		return
+	}
+
+	out := make([]dto.POSHistoryItem, 0, len(data))
+	for _, tr := range data {
+		out = append(out, toPOSHistoryItem(tr))
+	}
+
+	helpers.JsonOK(ctx, "success", gin.H{
+		"customer":     c,
+		"transactions": out,
+	})
+}
+
+// POST /customers/:uuid/points body: dto.FilterRequest (pagination)
+func (s *CustomerServiceImpl) PointLedger(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	c, ok := s.detailScoped(ctx, profile.Client.UUID)
+	if !ok {
+		return
+	}
+
+	var req dto.FilterRequest
+	_ = ctx.ShouldBindJSON(&req)
+	req.FilterBy = map[string]any{"customer_uuid": c.UUID}
+
+	data, err := s.repo.ListPointLedger(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to list point ledger", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", gin.H{
+		"points": c.Points,
+		"ledger": data,
+	})
+}
+
+func (s *CustomerServiceImpl) GetLoyaltyRule(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	rule, err := s.repo.GetLoyaltyRule(profile.Client.UUID)
+	if err != nil {
+		// belum di-setup: balikin default non-aktif
+		helpers.JsonOK(ctx, "success", dao.LoyaltyRule{ClientUUID: profile.Client.UUID})
+		return
+	}
+	helpers.JsonOK(ctx, "success", rule)
+}
+
+func (s *CustomerServiceImpl) SaveLoyaltyRule(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	if !isOwnerRole(profile.Role.Value) {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("only OWNER can change loyalty rule"))
+		return
+	}
+
+	var req dao.LoyaltyRule
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if req.EarnSpendAmount < 0 || req.EarnPoints < 0 || req.RedeemPointValue < 0 || req.MinRedeemPoints < 0 {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("loyalty values must be >= 0"))
+		return
+	}
+	if req.MaxRedeemPercent < 0 || req.MaxRedeemPercent > 100 {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("max_redeem_percent must be 0..100"))
+		return
+	}
+
+	req.ClientUUID = profile.Client.UUID
+	req.UpdatedBy = profile.UUID
+
+	res, err := s.repo.SaveLoyaltyRule(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to save loyalty rule", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", res)
+}
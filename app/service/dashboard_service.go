@@ -3,12 +3,10 @@ package service
 import (
 	"errors"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
-	"harjonan.id/user-service/app/domain/dao"
 	"harjonan.id/user-service/app/domain/dto"
 	"harjonan.id/user-service/app/helpers"
 	"harjonan.id/user-service/app/repository"
@@ -22,15 +20,20 @@ type DashboardService interface {
 }
 
 type DashboardServiceImpl struct {
-	repo      repository.DashboardRepository
-	authRepo  repository.AuthRepository
-	notifRepo repository.NotificationRepository
+	repo         repository.DashboardRepository
+	reportRepo   repository.ReportRepository
+	categoryRepo repository.CategoryRepository
+	authRepo     repository.AuthRepository
 }
 
-func NewDashboardService(repo repository.DashboardRepository, authRepo repository.AuthRepository, notifRepo repository.NotificationRepository) *DashboardServiceImpl {
-	return &DashboardServiceImpl{repo: repo, authRepo: authRepo, notifRepo: notifRepo}
+func NewDashboardService(repo repository.DashboardRepository, reportRepo repository.ReportRepository, categoryRepo repository.CategoryRepository, authRepo repository.AuthRepository) *DashboardServiceImpl {
+	return &DashboardServiceImpl{repo: repo, reportRepo: reportRepo, categoryRepo: categoryRepo, authRepo: authRepo}
 }
 
+// produk tanpa min_stock dianggap menipis kalau stock <= threshold ini.
+// Notifikasi stock menipis dikirim worker (ReplenishmentService.NotifyLowStock), bukan saat dashboard dibuka.
+const defaultLowStockThreshold int64 = 10
+
 func startOfDay(t time.Time) time.Time {
 	y, m, d := t.Date()
 	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
@@ -42,28 +45,6 @@ func startOfMonth(t time.Time) time.Time {
 }
 func endOfMonth(t time.Time) time.Time { return startOfMonth(t).AddDate(0, 1, 0) }
 
-func (s *DashboardServiceImpl) pushLowStockNotification(
-	clientUUID string,
-	branchUUID string,
-	product dao.Product,
-) {
-
-	title := "Low Stock Alert"
-	message := product.Name + " stock tersisa " + strconv.FormatInt(product.Stock, 10)
-
-	notif := &dao.Notification{
-		ClientUUID: clientUUID,
-		BranchUUID: branchUUID,
-		Title:      title,
-		Message:    message,
-		Type:       "STOCK",
-		Icon:       "warning",
-		Ref:        product.UUID,
-	}
-
-	_, _ = s.notifRepo.Insert(notif)
-}
-
 // =========================
 // OWNER (existing)
 // =========================
@@ -91,7 +72,7 @@ func (s *DashboardServiceImpl) OwnerSummary(ctx *gin.Context) {
 
 	threshold := req.LowStockThreshold
 	if threshold <= 0 {
-		threshold = 10
+		threshold = defaultLowStockThreshold
 	}
 
 	loc, _ := time.LoadLocation("Asia/Jakarta")
@@ -156,13 +137,19 @@ func (s *DashboardServiceImpl) OwnerSummary(ctx *gin.Context) {
 		return
 	}
 
-	if lowStockSKU > 0 {
-		products, err := s.repo.FindLowStockProducts(branchUUIDs, int(threshold))
-		if err == nil {
-			for _, p := range products {
-				s.pushLowStockNotification(clientUUID, p.BranchUUID, p)
-			}
-		}
+	categorySales, err := s.reportRepo.MarginByCategory(branchUUIDs, monthStart, monthEnd)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to sum revenue by category", http.StatusInternalServerError, err)
+		return
+	}
+	categories, err := s.categoryRepo.List(clientUUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load category", http.StatusInternalServerError, err)
+		return
+	}
+	revenueByCategory := rollUpByCategory(categorySales, categories, "")
+	for i := range revenueByCategory {
+		withMarginPct(&revenueByCategory[i])
 	}
 
 	resp := dto.OwnerDashboardSummary{
@@ -174,6 +161,7 @@ func (s *DashboardServiceImpl) OwnerSummary(ctx *gin.Context) {
 		TransactionToday:    txToday,
 		RevenueMonth:        revenueMonth,
 		LowStockSKU:         lowStockSKU,
+		RevenueByCategory:   revenueByCategory,
 	}
 
 	helpers.JsonOK(ctx, "success", resp)
@@ -234,7 +222,7 @@ func (s *DashboardServiceImpl) KasirSummary(ctx *gin.Context) {
 		return
 	}
 
-	threshold := int64(10)
+	threshold := defaultLowStockThreshold
 
 	lowStockSKU, err := s.repo.CountLowStockSKU([]string{branchUUID}, threshold)
 	if err != nil {
@@ -242,20 +230,12 @@ func (s *DashboardServiceImpl) KasirSummary(ctx *gin.Context) {
 		return
 	}
 
-	if lowStockSKU > 0 {
-		products, err := s.repo.FindLowStockProducts([]string{branchUUID}, int(threshold))
-		if err == nil {
-			for _, p := range products {
-				s.pushLowStockNotification(clientUUID, p.BranchUUID, p)
-			}
-		}
-	}
-
 	resp := dto.KasirDashboardSummary{
 		TransactionToday:      txToday,
 		TransactionMonth:      txMonth,
 		ProductInBranch:       productInBranch,
 		StockRequestInProcess: stockReqProcess,
+		LowStockSKU:           lowStockSKU,
 	}
 
 	helpers.JsonOK(ctx, "success", resp)
@@ -326,7 +306,7 @@ func (s *DashboardServiceImpl) GudangSummary(ctx *gin.Context) {
 		return
 	}
 
-	threshold := int64(10)
+	threshold := defaultLowStockThreshold
 
 	lowStockSKU, err := s.repo.CountLowStockSKU([]string{gudangBranchUUID}, threshold)
 	if err != nil {
@@ -334,21 +314,13 @@ func (s *DashboardServiceImpl) GudangSummary(ctx *gin.Context) {
 		return
 	}
 
-	if lowStockSKU > 0 {
-		products, err := s.repo.FindLowStockProducts([]string{gudangBranchUUID}, int(threshold))
-		if err == nil {
-			for _, p := range products {
-				s.pushLowStockNotification(clientUUID, p.BranchUUID, p)
-			}
-		}
-	}
-
 	resp := dto.GudangDashboardSummary{
 		TotalProduct:           totalProduct,
 		TotalStockRequestMonth: totalStockReq,
 		StockRequestInDriver:   stockReqInDriver,
 		DriverAvailable:        driverAvailable,
 		ProductSentToBranch:    productSent,
+		LowStockSKU:            lowStockSKU,
 	}
 
 	helpers.JsonOK(ctx, "success", resp)
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"harjonan.id/user-service/app/domain/dao"
+)
+
+// ---------------------------------------------
+// Loyalty calculator (pure, dipakai checkout & void)
+// ---------------------------------------------
+
+// earnedPoints: floor(amount / earn_spend_amount) * earn_points * multiplier tier
+func earnedPoints(rule dao.LoyaltyRule, tier dao.CustomerTier, amount float64) int64 {
+	if !rule.IsActive || rule.EarnSpendAmount <= 0 || rule.EarnPoints <= 0 {
+		return 0
+	}
+	if amount <= 0 || amount < rule.MinSpendToEarn {
+		return 0
+	}
+	mult := 1.0
+	if m, ok := rule.TierMultipliers[string(tier)]; ok && m > 0 {
+		mult = m
+	}
+	blocks := math.Floor(amount / rule.EarnSpendAmount)
+	return int64(math.Floor(blocks * float64(rule.EarnPoints) * mult))
+}
+
+// redeemValue: validasi poin yang mau dipakai dan hitung nilai rupiahnya.
+// Kalau melebihi batas (max % atau total), poin dipotong ke jumlah maksimal yang boleh.
+func redeemValue(rule dao.LoyaltyRule, points int64, balance int64, total float64) (int64, float64, error) {
+	if points <= 0 {
+		return 0, 0, nil
+	}
+	if !rule.IsActive || rule.RedeemPointValue <= 0 {
+		return 0, 0, errors.New("loyalty redeem not active")
+	}
+	if points < rule.MinRedeemPoints {
+		return 0, 0, errors.New("redeem points below minimum")
+	}
+	if points > balance {
+		return 0, 0, errors.New("points not enough")
+	}
+
+	maxValue := total
+	if rule.MaxRedeemPercent > 0 {
+		maxValue = math.Min(maxValue, total*rule.MaxRedeemPercent/100)
+	}
+	maxPoints := int64(math.Floor(maxValue / rule.RedeemPointValue))
+	if points > maxPoints {
+		points = maxPoints
+	}
+	if points <= 0 {
+		return 0, 0, nil
+	}
+	return points, float64(points) * rule.RedeemPointValue, nil
+}
+
+// tierForSpent: tier tertinggi yang threshold-nya sudah dilewati (tidak pernah turun otomatis)
+func tierForSpent(rule dao.LoyaltyRule, current dao.CustomerTier, totalSpent float64) dao.CustomerTier {
+	if len(rule.TierThresholds) == 0 {
+		return current
+	}
+	type tierMin struct {
+		tier string
+		min  float64
+	}
+	list := make([]tierMin, 0, len(rule.TierThresholds))
+	for t, min := range rule.TierThresholds {
+		list = append(list, tierMin{tier: t, min: min})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].min > list[j].min })
+
+	currentMin := rule.TierThresholds[string(current)]
+	for _, tm := range list {
+		if totalSpent >= tm.min && tm.min > currentMin {
+			return dao.CustomerTier(tm.tier)
+		}
+	}
+	return current
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 
 	"harjonan.id/user-service/app/domain/dao"
 	"harjonan.id/user-service/app/domain/dto"
@@ -26,14 +28,21 @@ type POSTransactionService interface {
 }
 
 type POSTransactionServiceImpl struct {
-	trxRepo   repository.POSTransactionRepository
-	prodRepo  repository.ProductRepository
-	authRepo  repository.AuthRepository
-	notifRepo repository.NotificationRepository
+	trxRepo        repository.POSTransactionRepository
+	prodRepo       repository.ProductRepository
+	authRepo       repository.AuthRepository
+	notifRepo      repository.NotificationRepository
+	customerRepo   repository.CustomerRepository
+	receivableRepo repository.ReceivableRepository
+	templateRepo   repository.BarcodeTemplateRepository
+	approvalRepo   repository.ApprovalRepository
+	lotRepo        repository.StockLotRepository
+	catalogRepo    repository.CatalogRepository
+	priceRepo      repository.PriceChangeRepository
 }
 
-func NewPOSTransactionService(trxRepo repository.POSTransactionRepository, prodRepo repository.ProductRepository, authRepo repository.AuthRepository, notifRepo repository.NotificationRepository) *POSTransactionServiceImpl {
-	return &POSTransactionServiceImpl{trxRepo: trxRepo, prodRepo: prodRepo, authRepo: authRepo, notifRepo: notifRepo}
+func NewPOSTransactionService(trxRepo repository.POSTransactionRepository, prodRepo repository.ProductRepository, authRepo repository.AuthRepository, notifRepo repository.NotificationRepository, customerRepo repository.CustomerRepository, receivableRepo repository.ReceivableRepository, templateRepo repository.BarcodeTemplateRepository, approvalRepo repository.ApprovalRepository, lotRepo repository.StockLotRepository, catalogRepo repository.CatalogRepository, priceRepo repository.PriceChangeRepository) *POSTransactionServiceImpl {
+	return &POSTransactionServiceImpl{trxRepo: trxRepo, prodRepo: prodRepo, authRepo: authRepo, notifRepo: notifRepo, customerRepo: customerRepo, receivableRepo: receivableRepo, templateRepo: templateRepo, approvalRepo: approvalRepo, lotRepo: lotRepo, catalogRepo: catalogRepo, priceRepo: priceRepo}
 }
 
 // -------------------------------
@@ -71,12 +80,14 @@ func (s *POSTransactionServiceImpl) createPOSNotif(clientUUID string, branchUUID
 	}
 }
 
-// body: { "branch_uuid": "...", "barcode": "..." }
+// body: { "branch_uuid": "...", "barcode": "...", "unit": "box" }
 func (s *POSTransactionServiceImpl) ScanByBarcode(ctx *gin.Context) {
-	var req struct {
-		BranchUUID string `json:"branch_uuid"`
-		Barcode    string `json:"barcode"`
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
 	}
+
+	var req dto.POSScanRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
 		return
@@ -102,10 +113,175 @@ func (s *POSTransactionServiceImpl) ScanByBarcode(ctx *gin.Context) {
 
 	list, err := s.prodRepo.ListProduct(&fr)
 	if err != nil || len(list) == 0 {
+		// barcode parent varian: tidak ada record branch, kasir pilih varian dari grup
+		if cat, err := s.catalogRepo.FindByBarcode(profile.Client.UUID, req.Barcode); err == nil && cat.HasVariants {
+			group, err := s.variantGroup(req.BranchUUID, cat.UUID)
+			if err != nil {
+				helpers.JsonErr[any](ctx, "failed to load variants", http.StatusInternalServerError, err)
+				return
+			}
+			if group == nil {
+				helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("no active variant in this branch"))
+				return
+			}
+			helpers.JsonOK(ctx, "success", dto.POSScanResult{VariantGroup: group})
+			return
+		}
+
+		// bukan barcode produk: coba label timbangan (prefix 20-29)
+		if isScaleBarcode(req.Barcode) {
+			sl, err := s.resolveScaleBarcode(profile.Client.UUID, req.BranchUUID, req.Barcode)
+			if err != nil {
+				helpers.JsonErr[any](ctx, "invalid scale barcode", http.StatusBadRequest, err)
+				return
+			}
+			helpers.JsonOK(ctx, "success", dto.POSScanResult{
+				Product:      sl.Product,
+				Unit:         sl.Product.BaseUnit,
+				Conversion:   1,
+				UnitPrice:    sl.UnitPrice,
+				Qty:          sl.Qty,
+				LineTotal:    sl.LineTotal,
+				ScaleBarcode: req.Barcode,
+			})
+			return
+		}
+		if helpers.IsEANLike(req.Barcode) && !helpers.ValidEAN(req.Barcode) {
+			helpers.JsonErr[any](ctx, "invalid barcode", http.StatusBadRequest, errors.New("invalid check digit"))
+			return
+		}
 		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("barcode not found"))
 		return
 	}
-	helpers.JsonOK(ctx, "success", list[0])
+
+	p := s.effectiveProduct(list[0])
+	unit, conv, price, err := resolveSaleUnit(p, req.Unit)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "invalid unit", http.StatusBadRequest, err)
+		return
+	}
+	res := dto.POSScanResult{
+		Product:    p,
+		Unit:       unit,
+		Conversion: conv,
+		UnitPrice:  price,
+		Qty:        1,
+		LineTotal:  price,
+	}
+	if p.ParentUUID != "" {
+		if res.VariantGroup, err = s.variantGroup(req.BranchUUID, p.ParentUUID); err != nil {
+			helpers.JsonErr[any](ctx, "failed to load variants", http.StatusInternalServerError, err)
+			return
+		}
+	}
+	helpers.JsonOK(ctx, "success", res)
+}
+
+// effectiveProduct: scan & checkout pakai harga yang berlaku saat itu walau price change jatuh tempo
+// belum disapu worker. Read-only: menerapkan (simpan harga, history, notif) tetap tugas worker.
+// Produk tanpa catalog_uuid tidak bisa punya price change (ditolak saat schedule).
+func (s *POSTransactionServiceImpl) effectiveProduct(p dao.Product) dao.Product {
+	if p.CatalogUUID == "" {
+		return p
+	}
+	now := time.Now().Unix()
+	branch, err := s.priceRepo.LatestDue(p.CatalogUUID, p.BranchUUID, now)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return p
+	}
+	client, err := s.priceRepo.LatestDue(p.CatalogUUID, "", now)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return p
+	}
+	p.Price = effectivePrice(p, branch, client)
+	return p
+}
+
+// effectivePrice: change terakhir yang jatuh tempo per scope (zero value = tidak ada).
+// SCHEDULED = belum diterapkan, pakai new_price; APPLIED = sudah ada di produk, harga produk dipakai.
+// Change BRANCH (jadi price_override) menang dari CLIENT; CLIENT hanya untuk branch tanpa price_override.
+func effectivePrice(p dao.Product, branch, client dao.PriceChange) float64 {
+	if branch.Status == dao.PriceChangeScheduled {
+		return branch.NewPrice
+	}
+	if client.Status == dao.PriceChangeScheduled && p.PriceOverride == nil {
+		return client.NewPrice
+	}
+	return p.Price
+}
+
+// variantGroup: varian aktif parent di branch; nil = tidak ada
+func (s *POSTransactionServiceImpl) variantGroup(branchUUID, parentUUID string) (*dto.VariantGroup, error) {
+	fr := dto.FilterRequest{FilterBy: map[string]any{
+		"branch_uuid": branchUUID,
+		"parent_uuid": parentUUID,
+		"is_active":   true,
+	}}
+	fr.Pagination.Page = 1
+	fr.Pagination.PageSize = 1
+
+	groups, err := s.prodRepo.ListGrouped(&fr)
+	if err != nil || len(groups) == 0 {
+		return nil, err
+	}
+	return &groups[0], nil
+}
+
+// resolveScaleBarcode: template client (prefix) -> PLU + value -> product branch -> qty & harga
+func (s *POSTransactionServiceImpl) resolveScaleBarcode(clientUUID, branchUUID, code string) (scaleLineResult, error) {
+	if !helpers.ValidEAN(code) {
+		return scaleLineResult{}, errors.New("invalid check digit")
+	}
+	templates, err := s.templateRepo.ListActive(clientUUID)
+	if err != nil {
+		return scaleLineResult{}, err
+	}
+	tpl, ok := matchBarcodeTemplate(code, templates)
+	if !ok {
+		return scaleLineResult{}, errors.New("no barcode template for prefix " + code[:2])
+	}
+	plu, value, err := decodeScaleBarcode(code, tpl)
+	if err != nil {
+		return scaleLineResult{}, err
+	}
+	p, err := s.prodRepo.FindByPLU(branchUUID, plu)
+	if err != nil {
+		return scaleLineResult{}, errors.New("PLU " + plu + " not found")
+	}
+	p = s.effectiveProduct(p)
+	qty, unitPrice, line, err := scaleLine(p, tpl, value)
+	if err != nil {
+		return scaleLineResult{}, err
+	}
+	return scaleLineResult{Product: p, Qty: qty, UnitPrice: unitPrice, LineTotal: line}, nil
+}
+
+// resolveSaleUnit: cari unit jual di product.Units (case-insensitive).
+// Kosong / base unit => conversion 1, harga = Price.
+// Unit lain tanpa harga khusus => Price x conversion.
+func resolveSaleUnit(p dao.Product, unit string) (string, float64, float64, error) {
+	unit = strings.TrimSpace(unit)
+	if unit == "" || strings.EqualFold(unit, p.BaseUnit) {
+		return p.BaseUnit, 1, p.Price, nil
+	}
+	for _, u := range p.Units {
+		if !strings.EqualFold(u.Name, unit) {
+			continue
+		}
+		conv := u.ConversionToBase
+		if conv <= 0 {
+			conv = 1
+		}
+		if conv != math.Trunc(conv) {
+			return "", 0, 0, fmt.Errorf("unit %s conversion must be whole number", u.Name)
+		}
+		price := u.Price
+		if price <= 0 {
+			price = p.Price * conv
+		}
+		return u.Name, conv, price, nil
+	}
+	return "", 0, 0, fmt.Errorf("unit %s not found for %s", unit, p.Name)
 }
 
 func (s *POSTransactionServiceImpl) Checkout(ctx *gin.Context) {
@@ -149,9 +325,9 @@ func (s *POSTransactionServiceImpl) Checkout(ctx *gin.Context) {
 		req.PaymentMethod = "CASH" // default
 	}
 	switch req.PaymentMethod {
-	case "CASH", "TRANSFER", "QRIS":
+	case "CASH", "TRANSFER", "QRIS", "CREDIT":
 	default:
-		helpers.JsonErr[any](ctx, "invalid payment_method", http.StatusBadRequest, errors.New("payment_method must be CASH/TRANSFER/QRIS"))
+		helpers.JsonErr[any](ctx, "invalid payment_method", http.StatusBadRequest, errors.New("payment_method must be CASH/TRANSFER/QRIS/CREDIT"))
 		return
 	}
 
@@ -162,6 +338,58 @@ func (s *POSTransactionServiceImpl) Checkout(ctx *gin.Context) {
 		req.Paid = 0
 	}
 
+	// customer (optional): by uuid / no hp / barcode member
+	var customer *dao.Customer
+	req.CustomerUUID = strings.TrimSpace(req.CustomerUUID)
+	req.CustomerPhone = strings.TrimSpace(req.CustomerPhone)
+	req.MemberCode = strings.TrimSpace(req.MemberCode)
+	if req.CustomerUUID != "" || req.CustomerPhone != "" || req.MemberCode != "" {
+		var c dao.Customer
+		if req.CustomerUUID != "" {
+			c, err = s.customerRepo.DetailCustomer(req.CustomerUUID)
+		} else {
+			c, err = s.customerRepo.FindCustomer(clientUUID, req.CustomerPhone, req.MemberCode)
+		}
+		if err != nil || c.ClientUUID != clientUUID || !c.IsActive {
+			helpers.JsonErr[any](ctx, "customer not found", http.StatusNotFound, errors.New("customer not found"))
+			return
+		}
+		customer = &c
+	}
+
+	// kasbon: wajib customer dengan credit_limit
+	if req.PaymentMethod == "CREDIT" {
+		if customer == nil {
+			helpers.JsonErr[any](ctx, "missing customer", http.StatusBadRequest, errors.New("customer required for CREDIT"))
+			return
+		}
+		if customer.CreditLimit <= 0 {
+			helpers.JsonErr[any](ctx, "credit not allowed", http.StatusBadRequest, errors.New("customer has no credit limit"))
+			return
+		}
+	}
+
+	req.RedeemAs = strings.TrimSpace(strings.ToUpper(req.RedeemAs))
+	if req.RedeemAs == "" {
+		req.RedeemAs = "DISCOUNT"
+	}
+	if req.RedeemPoints > 0 {
+		if customer == nil {
+			helpers.JsonErr[any](ctx, "missing customer", http.StatusBadRequest, errors.New("customer required to redeem points"))
+			return
+		}
+		if req.RedeemAs != "DISCOUNT" && req.RedeemAs != "TENDER" {
+			helpers.JsonErr[any](ctx, "invalid redeem_as", http.StatusBadRequest, errors.New("redeem_as must be DISCOUNT/TENDER"))
+			return
+		}
+	}
+
+	var rule dao.LoyaltyRule
+	if customer != nil {
+		// belum ada rule => loyalty non-aktif (earn/redeem 0)
+		rule, _ = s.customerRepo.GetLoyaltyRule(clientUUID)
+	}
+
 	// build items from DB (server-side pricing + validate stock)
 	items := make([]dao.POSTransactionItem, 0, len(req.Items))
 	var subTotal float64
@@ -173,16 +401,47 @@ func (s *POSTransactionServiceImpl) Checkout(ctx *gin.Context) {
 	var plans []decPlan
 
 	for _, it := range req.Items {
-		if strings.TrimSpace(it.ProductUUID) == "" || it.Qty <= 0 {
+		it.ScaleBarcode = strings.TrimSpace(it.ScaleBarcode)
+		if it.ScaleBarcode == "" && (strings.TrimSpace(it.ProductUUID) == "" || it.Qty <= 0) {
 			helpers.JsonErr[any](ctx, "invalid item", http.StatusBadRequest, errors.New("product_uuid & qty required"))
 			return
 		}
 
-		p, err := s.prodRepo.DetailProduct(it.ProductUUID)
-		if err != nil {
-			helpers.JsonErr[any](ctx, "product not found", http.StatusNotFound, err)
-			return
+		var p dao.Product
+		var unit string
+		var conv, unitPrice, line float64
+		var qty int64
+
+		if it.ScaleBarcode != "" {
+			// label timbangan: qty & harga di-decode ulang di server (jangan percaya client)
+			sl, err := s.resolveScaleBarcode(clientUUID, req.BranchUUID, it.ScaleBarcode)
+			if err != nil {
+				helpers.JsonErr[any](ctx, "invalid scale barcode", http.StatusBadRequest, err)
+				return
+			}
+			if it.ProductUUID != "" && it.ProductUUID != sl.Product.UUID {
+				helpers.JsonErr[any](ctx, "invalid item", http.StatusBadRequest, errors.New("scale barcode does not match product_uuid"))
+				return
+			}
+			p = sl.Product
+			unit, conv = p.BaseUnit, 1
+			qty, unitPrice, line = sl.Qty, sl.UnitPrice, sl.LineTotal
+		} else {
+			p, err = s.prodRepo.DetailProduct(it.ProductUUID)
+			if err != nil {
+				helpers.JsonErr[any](ctx, "product not found", http.StatusNotFound, err)
+				return
+			}
+			p = s.effectiveProduct(p)
+			unit, conv, unitPrice, err = resolveSaleUnit(p, it.Unit)
+			if err != nil {
+				helpers.JsonErr[any](ctx, "invalid unit", http.StatusBadRequest, err)
+				return
+			}
+			qty = it.Qty
+			line = float64(qty) * unitPrice
 		}
+
 		if p.BranchUUID != req.BranchUUID {
 			helpers.JsonErr[any](ctx, "branch mismatch", http.StatusBadRequest, errors.New("product branch_uuid mismatch"))
 			return
@@ -191,60 +450,216 @@ func (s *POSTransactionServiceImpl) Checkout(ctx *gin.Context) {
 			helpers.JsonErr[any](ctx, "inactive product", http.StatusBadRequest, errors.New("product inactive"))
 			return
 		}
-		if p.Stock < it.Qty {
+
+		qtyBase := qty * int64(conv)
+		if p.Stock < qtyBase {
 			helpers.JsonErr[any](ctx, "stock not enough", http.StatusBadRequest, errors.New(p.Name+" stock not enough"))
 			return
 		}
 
-		line := float64(it.Qty) * p.Price
 		subTotal += line
 
 		items = append(items, dao.POSTransactionItem{
-			ProductUUID: p.UUID,
-			SKU:         p.SKU,
-			Barcode:     p.Barcode,
-			Name:        p.Name,
-			Description: p.Description,
-			BaseUnit:    p.BaseUnit,
-			Units:       p.Units,
-			Price:       p.Price,
-			Qty:         it.Qty,
-			LineTotal:   line,
+			ProductUUID:  p.UUID,
+			SKU:          p.SKU,
+			Barcode:      p.Barcode,
+			Name:         p.Name,
+			Description:  p.Description,
+			BaseUnit:     p.BaseUnit,
+			Units:        p.Units,
+			Price:        unitPrice,
+			Qty:          qty,
+			LineTotal:    line,
+			Unit:         unit,
+			Conversion:   conv,
+			QtyBase:      qtyBase,
+			ScaleBarcode: it.ScaleBarcode,
 		})
-		plans = append(plans, decPlan{ProductUUID: p.UUID, Qty: it.Qty})
+		plans = append(plans, decPlan{ProductUUID: p.UUID, Qty: qtyBase})
 	}
 
 	total := subTotal - req.Discount
 	if total < 0 {
 		total = 0
 	}
-	if req.Paid < total {
-		helpers.JsonErr[any](ctx, "payment not enough", http.StatusBadRequest, errors.New("paid < total"))
-		return
+
+	// poin: DISCOUNT mengurangi total, TENDER dihitung sebagai pembayaran
+	var redeemPts int64
+	var redeemVal, tenderVal float64
+	if req.RedeemPoints > 0 {
+		redeemPts, redeemVal, err = redeemValue(rule, req.RedeemPoints, customer.Points, total)
+		if err != nil {
+			helpers.JsonErr[any](ctx, "invalid redeem", http.StatusBadRequest, err)
+			return
+		}
+		if req.RedeemAs == "DISCOUNT" {
+			total -= redeemVal
+		} else {
+			tenderVal = redeemVal
+		}
 	}
-	change := req.Paid - total
 
-	// 1) decrement stock per item (atomic per document)
-	decOK := make([]decPlan, 0, len(plans))
-	for _, pl := range plans {
-		err := s.prodRepo.DecreaseStockIfEnough(pl.ProductUUID, pl.Qty)
+	// CREDIT: paid = uang muka (DP), sisanya jadi piutang
+	var creditAmount, change float64
+	if req.PaymentMethod == "CREDIT" {
+		creditAmount = total - tenderVal - req.Paid
+		if creditAmount <= 0 {
+			helpers.JsonErr[any](ctx, "nothing to credit", http.StatusBadRequest, errors.New("paid covers total, use CASH/TRANSFER/QRIS"))
+			return
+		}
+		if customer.CreditBalance+creditAmount > customer.CreditLimit {
+			helpers.JsonErr[any](ctx, "credit limit exceeded", http.StatusBadRequest, fmt.Errorf("available credit %s", newIDRCurrency(customer.CreditLimit-customer.CreditBalance)))
+			return
+		}
+	} else {
+		if req.Paid+tenderVal < total {
+			helpers.JsonErr[any](ctx, "payment not enough", http.StatusBadRequest, errors.New("paid < total"))
+			return
+		}
+		change = req.Paid + tenderVal - total
+	}
+
+	// diskon besar: butuh approval supervisor sesuai policy client
+	var discountApproval dao.Approval
+	if req.Discount > 0 && subTotal > 0 {
+		policy, err := s.approvalRepo.GetPolicy(clientUUID)
 		if err != nil {
-			for i := len(decOK) - 1; i >= 0; i-- {
-				_ = s.prodRepo.IncreaseStock(decOK[i].ProductUUID, decOK[i].Qty)
+			helpers.JsonErr[any](ctx, "failed to load approval policy", http.StatusInternalServerError, err)
+			return
+		}
+		pct := req.Discount / subTotal * 100
+		if policy.DiscountMaxPercent > 0 && pct > policy.DiscountMaxPercent+0.0001 {
+			a, code, err := resolveApproval(s.approvalRepo, s.notifRepo, profile, policy, approvalNeed{
+				Type:            dao.ApprovalDiscount,
+				BranchUUID:      req.BranchUUID,
+				Reason:          fmt.Sprintf("discount %.2f%% above %.2f%%", pct, policy.DiscountMaxPercent),
+				DiscountPercent: math.Round(pct*100) / 100,
+				Amount:          req.Discount,
+			}, req.ApprovalInput)
+			if respondApproval(ctx, a, code, err) {
+				return
+			}
+			discountApproval = a
+		}
+	}
+	releaseApproval := func() {
+		if discountApproval.Method == dao.ApprovalByRequest {
+			_ = s.approvalRepo.Release(discountApproval.UUID)
+		}
+	}
+
+	now := time.Now()
+	ms := now.UnixNano() / 1e6
+	trxUUID := helpers.GenerateUUID()
+	receiptNo := "TRX-" + strconv.FormatInt(ms, 10)
+
+	saleRef := dao.StockRef{Reason: dao.StockMoveSale, RefUUID: trxUUID, RefNo: receiptNo, CreatedBy: userUUID}
+	rollbackRef := saleRef
+	rollbackRef.Note = "rollback checkout"
+
+	// 1) decrement stock per item (atomic per document, tercatat di stock_movements)
+	// cost (weighted-average) diambil dari produk saat dipotong => COGS per item
+	// produk track_lots: lot diambil FEFO, lot yang sudah kedaluwarsa tidak boleh dijual
+	decOK := make([]decPlan, 0, len(plans))
+	rollbackStock := func() {
+		for i := len(decOK) - 1; i >= 0; i-- {
+			_ = s.prodRepo.IncreaseStock(decOK[i].ProductUUID, decOK[i].Qty, rollbackRef)
+			if len(items[i].Lots) > 0 {
+				_ = s.lotRepo.Restore(items[i].Lots)
 			}
+		}
+	}
+	var cogs float64
+	for i, pl := range plans {
+		p, err := s.prodRepo.DecreaseStockIfEnough(pl.ProductUUID, pl.Qty, saleRef)
+		if err != nil {
+			rollbackStock()
+			releaseApproval()
 			helpers.JsonErr[any](ctx, "failed to update stock", http.StatusInternalServerError, err)
 			return
 		}
+		if p.TrackLots {
+			items[i].Lots, err = s.lotRepo.Allocate(p, pl.Qty, false)
+			if err != nil {
+				_ = s.prodRepo.IncreaseStock(pl.ProductUUID, pl.Qty, rollbackRef)
+				rollbackStock()
+				releaseApproval()
+				helpers.JsonErr[any](ctx, "item cannot be sold", http.StatusConflict, err)
+				return
+			}
+		}
 		decOK = append(decOK, pl)
+		items[i].Cost = p.Cost
+		items[i].CostTotal = p.Cost * float64(pl.Qty)
+		cogs += items[i].CostTotal
 	}
 
-	// 2) insert transaction
-	now := time.Now()
-	ms := now.UnixNano() / 1e6
+	// 2) potong poin (atomic, guard saldo >= poin)
+	var redeemBal int64
+	if redeemPts > 0 {
+		redeemBal, err = s.customerRepo.AdjustPoints(customer.UUID, -redeemPts, false)
+		if err != nil {
+			rollbackStock()
+			releaseApproval()
+			helpers.JsonErr[any](ctx, "failed to redeem points", http.StatusBadRequest, err)
+			return
+		}
+	}
+	rollbackPoints := func() {
+		if redeemPts > 0 {
+			_, _ = s.customerRepo.AdjustPoints(customer.UUID, redeemPts, true)
+		}
+	}
+
+	// 3) kasbon: reservasi limit (atomic) + buat piutang
+	var receivable dao.Receivable
+	rollbackCredit := func() {}
+	if creditAmount > 0 {
+		if _, err := s.customerRepo.AdjustCredit(customer.UUID, creditAmount); err != nil {
+			rollbackPoints()
+			rollbackStock()
+			releaseApproval()
+			helpers.JsonErr[any](ctx, "credit limit exceeded", http.StatusBadRequest, err)
+			return
+		}
+
+		termDays := customer.CreditTermDays
+		if termDays <= 0 {
+			termDays = 30
+		}
+		due := now.AddDate(0, 0, termDays)
+
+		receivable, err = s.receivableRepo.Insert(&dao.Receivable{
+			ClientUUID:      clientUUID,
+			BranchUUID:      req.BranchUUID,
+			CustomerUUID:    customer.UUID,
+			CustomerName:    customer.Name,
+			TransactionUUID: trxUUID,
+			ReceiptNo:       receiptNo,
+			Amount:          creditAmount,
+			DueDate:         due.Unix(),
+			DueDateStr:      due.Format(time.RFC3339),
+			CreatedBy:       userUUID,
+		})
+		if err != nil {
+			_, _ = s.customerRepo.AdjustCredit(customer.UUID, -creditAmount)
+			rollbackPoints()
+			rollbackStock()
+			releaseApproval()
+			helpers.JsonErr[any](ctx, "failed to create receivable", http.StatusInternalServerError, err)
+			return
+		}
+		rollbackCredit = func() {
+			_ = s.receivableRepo.Delete(receivable.UUID)
+			_, _ = s.customerRepo.AdjustCredit(customer.UUID, -creditAmount)
+		}
+	}
 
+	// 4) insert transaction
 	trx := dao.POSTransaction{
+		BaseModel:     dao.BaseModel{UUID: trxUUID},
 		BranchUUID:    req.BranchUUID,
-		ReceiptNo:     "TRX-" + strconv.FormatInt(ms, 10),
+		ReceiptNo:     receiptNo,
 		PaymentMethod: req.PaymentMethod,
 		Items:         items,
 		SubTotal:      subTotal,
@@ -255,17 +670,46 @@ func (s *POSTransactionServiceImpl) Checkout(ctx *gin.Context) {
 		Status:        "PAID",
 		CreatedBy:     req.CreatedBy,
 		Note:          req.Note,
+
+		COGS:        cogs,
+		GrossProfit: total - cogs,
+
+		CreditAmount:   creditAmount,
+		ReceivableUUID: receivable.UUID,
+
+		DiscountApprovalUUID: discountApproval.UUID,
+	}
+	if customer != nil {
+		trx.CustomerUUID = customer.UUID
+		trx.CustomerName = customer.Name
+		trx.CustomerPhone = customer.Phone
+		trx.PointsRedeemed = redeemPts
+		trx.PointsValue = redeemVal
+		if redeemPts > 0 {
+			trx.PointsRedeemAs = req.RedeemAs
+		}
+		// poin dihitung dari nominal yang benar-benar dibayar (tanpa tender poin)
+		trx.PointsEarned = earnedPoints(rule, customer.Tier, total-tenderVal)
 	}
 
 	out, err := s.trxRepo.Insert(&trx)
 	if err != nil {
-		// rollback stock kalau insert gagal
-		for i := len(decOK) - 1; i >= 0; i-- {
-			_ = s.prodRepo.IncreaseStock(decOK[i].ProductUUID, decOK[i].Qty)
-		}
+		// rollback stock + poin + kasbon kalau insert gagal
+		rollbackCredit()
+		rollbackPoints()
+		rollbackStock()
+		releaseApproval()
 		helpers.JsonErr[any](ctx, "failed to checkout", http.StatusInternalServerError, err)
 		return
 	}
+	if discountApproval.UUID != "" {
+		_ = s.approvalRepo.SetUsedRef(discountApproval.UUID, out.UUID)
+	}
+
+	// 5) loyalty (best-effort, trx sudah tersimpan)
+	if customer != nil {
+		s.applyCheckoutLoyalty(clientUUID, userUUID, rule, *customer, out, redeemBal)
+	}
 
 	// ✅ NOTIF: POS Paid (branch + personal)
 	title := "POS Transaction Paid"
@@ -308,33 +752,13 @@ func (s *POSTransactionServiceImpl) List(ctx *gin.Context) {
 
 	out := make([]dto.POSHistoryItem, 0, len(data))
 	for _, tr := range data {
-		out = append(out, dto.POSHistoryItem{
-			UUID:          tr.UUID,
-			BranchUUID:    tr.BranchUUID,
-			ReceiptNo:     tr.ReceiptNo,
-			Status:        tr.Status,
-			PaymentMethod: tr.PaymentMethod,
-
-			SubTotal: tr.SubTotal,
-			Discount: tr.Discount,
-			Total:    tr.Total,
-			Paid:     tr.Paid,
-			Change:   tr.Change,
-
-			CreatedBy: tr.CreatedBy,
-			VoidedBy:  tr.VoidedBy,
-			Note:      tr.Note,
-
-			// ✅ tanggal trx/void format yang kamu minta
-			TrxAt:  helpers.FormatPOSDateTime(tr.CreatedAt),
-			VoidAt: helpers.FormatPOSUnix(tr.VoidedAt),
-		})
+		out = append(out, toPOSHistoryItem(tr))
 	}
 
 	helpers.JsonOK(ctx, "success", out)
 }
 
-// endpoint: POST /pos/transactions/:uuid/void  body: { "voided_by":"...", "note":"..." }
+// endpoint: POST /pos/transactions/:uuid/void  body: { "note":"...", approval (optional): "approver_uuid"+"approver_pin" / "approval_uuid" / "request_approval":true }
 func (s *POSTransactionServiceImpl) Void(ctx *gin.Context) {
 	accessToken := ctx.GetString("access_token")
 	if accessToken == "" {
@@ -360,8 +784,8 @@ func (s *POSTransactionServiceImpl) Void(ctx *gin.Context) {
 	}
 
 	var req struct {
-		VoidedBy string `json:"voided_by"`
-		Note     string `json:"note"`
+		Note string `json:"note"`
+		dto.ApprovalInput
 	}
 	_ = ctx.ShouldBindJSON(&req)
 
@@ -375,32 +799,91 @@ func (s *POSTransactionServiceImpl) Void(ctx *gin.Context) {
 		return
 	}
 
+	// void lewat batas policy: butuh approval supervisor
+	policy, err := s.approvalRepo.GetPolicy(clientUUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load approval policy", http.StatusInternalServerError, err)
+		return
+	}
+	var voidApproval dao.Approval
+	ageMin := int(time.Since(trx.CreatedAt).Minutes())
+	reason := ""
+	switch {
+	case policy.VoidAlwaysRequire:
+		reason = "void requires supervisor approval"
+	case policy.VoidMaxAgeMinutes > 0 && ageMin > policy.VoidMaxAgeMinutes:
+		reason = fmt.Sprintf("transaction is %d minutes old (max %d)", ageMin, policy.VoidMaxAgeMinutes)
+	}
+	if reason != "" {
+		a, code, err := resolveApproval(s.approvalRepo, s.notifRepo, profile, policy, approvalNeed{
+			Type:       dao.ApprovalVoid,
+			Ref:        trx.UUID,
+			BranchUUID: trx.BranchUUID,
+			Reason:     reason,
+			Amount:     trx.Total,
+		}, req.ApprovalInput)
+		if respondApproval(ctx, a, code, err) {
+			return
+		}
+		voidApproval = a
+	}
+	releaseApproval := func() {
+		if voidApproval.Method == dao.ApprovalByRequest {
+			_ = s.approvalRepo.Release(voidApproval.UUID)
+		}
+	}
+
 	// return stock
 	for _, it := range trx.Items {
-		if strings.TrimSpace(it.ProductUUID) == "" || it.Qty <= 0 {
+		if strings.TrimSpace(it.ProductUUID) == "" || it.BaseQty() <= 0 {
 			continue
 		}
-		err = s.prodRepo.IncreaseStock(it.ProductUUID, it.Qty)
+		err = s.prodRepo.IncreaseStock(it.ProductUUID, it.BaseQty(), dao.StockRef{
+			Reason:    dao.StockMoveVoid,
+			RefUUID:   trx.UUID,
+			RefNo:     trx.ReceiptNo,
+			Note:      req.Note,
+			CreatedBy: userUUID,
+		})
 		if err != nil {
+			releaseApproval()
 			helpers.JsonErr[any](ctx, "failed to return stock", http.StatusInternalServerError, err)
 			return
 		}
+		// qty kembali ke lot asal (expiry tetap tercatat)
+		_ = s.lotRepo.Restore(it.Lots)
 	}
 
 	now := time.Now()
 	nowStr := now.Format(time.RFC3339)
 
+	// voided_by selalu dari token, bukan body
 	out, err := s.trxRepo.UpdateStatus(uuid, "VOID", bson.M{
-		"voided_by":     req.VoidedBy,
-		"voided_at":     now.Unix(),
-		"voided_at_str": nowStr,
-		"note":          req.Note,
+		"voided_by":          userUUID,
+		"voided_by_name":     profile.Name,
+		"voided_at":          now.Unix(),
+		"voided_at_str":      nowStr,
+		"note":               req.Note,
+		"void_approval_uuid": voidApproval.UUID,
 	})
 	if err != nil {
 		helpers.JsonErr[any](ctx, "failed to void", http.StatusInternalServerError, err)
 		return
 	}
 
+	// kasbon: piutang di-void, limit customer dilepas sebesar sisa outstanding
+	// (pembayaran yang sudah masuk tetap tercatat di receivable, refund manual)
+	if trx.ReceivableUUID != "" {
+		if rc, err := s.receivableRepo.Void(trx.ReceivableUUID, userUUID); err == nil && rc.Outstanding > 0 {
+			_, _ = s.customerRepo.AdjustCredit(rc.CustomerUUID, -rc.Outstanding)
+		}
+	}
+
+	// reverse poin customer (earn & redeem)
+	if trx.CustomerUUID != "" {
+		s.reverseLoyalty(clientUUID, userUUID, trx)
+	}
+
 	// ✅ NOTIF: POS voided
 	title := "POS Transaction Voided"
 	msg := fmt.Sprintf("Receipt %s dibatalkan • Total %s", out.ReceiptNo, newIDRCurrency(out.Total))
@@ -409,23 +892,109 @@ func (s *POSTransactionServiceImpl) Void(ctx *gin.Context) {
 	helpers.JsonOK(ctx, "success", out)
 }
 
+func toPOSHistoryItem(tr dao.POSTransaction) dto.POSHistoryItem {
+	return dto.POSHistoryItem{
+		UUID:          tr.UUID,
+		BranchUUID:    tr.BranchUUID,
+		ReceiptNo:     tr.ReceiptNo,
+		Status:        tr.Status,
+		PaymentMethod: tr.PaymentMethod,
+
+		SubTotal: tr.SubTotal,
+		Discount: tr.Discount,
+		Total:    tr.Total,
+		Paid:     tr.Paid,
+		Change:   tr.Change,
+
+		CreatedBy: tr.CreatedBy,
+		VoidedBy:  tr.VoidedBy,
+		Note:      tr.Note,
+
+		CustomerUUID:   tr.CustomerUUID,
+		CustomerName:   tr.CustomerName,
+		PointsEarned:   tr.PointsEarned,
+		PointsRedeemed: tr.PointsRedeemed,
+
+		// ✅ tanggal trx/void format yang kamu minta
+		TrxAt:  helpers.FormatPOSDateTime(tr.CreatedAt),
+		VoidAt: helpers.FormatPOSUnix(tr.VoidedAt),
+	}
+}
+
+// -------------------------------
+// Loyalty helper (POS)
+// -------------------------------
+func (s *POSTransactionServiceImpl) applyCheckoutLoyalty(clientUUID, userUUID string, rule dao.LoyaltyRule, c dao.Customer, trx dao.POSTransaction, redeemBal int64) {
+	if trx.PointsRedeemed > 0 {
+		_, _ = s.customerRepo.InsertPointLedger(&dao.CustomerPointLedger{
+			ClientUUID:      clientUUID,
+			CustomerUUID:    c.UUID,
+			TransactionUUID: trx.UUID,
+			ReceiptNo:       trx.ReceiptNo,
+			Type:            dao.PointRedeem,
+			Points:          -trx.PointsRedeemed,
+			BalanceAfter:    redeemBal,
+			Note:            "redeem as " + trx.PointsRedeemAs,
+			CreatedBy:       userUUID,
+		})
+	}
+
+	if trx.PointsEarned > 0 {
+		bal, err := s.customerRepo.AdjustPoints(c.UUID, trx.PointsEarned, true)
+		if err == nil {
+			_, _ = s.customerRepo.InsertPointLedger(&dao.CustomerPointLedger{
+				ClientUUID:      clientUUID,
+				CustomerUUID:    c.UUID,
+				TransactionUUID: trx.UUID,
+				ReceiptNo:       trx.ReceiptNo,
+				Type:            dao.PointEarn,
+				Points:          trx.PointsEarned,
+				BalanceAfter:    bal,
+				CreatedBy:       userUUID,
+			})
+		}
+	}
+
+	updated, err := s.customerRepo.RecordVisit(c.UUID, trx.Total, 1)
+	if err != nil {
+		return
+	}
+	if tier := tierForSpent(rule, updated.Tier, updated.TotalSpent); tier != updated.Tier {
+		_ = s.customerRepo.SetTier(c.UUID, tier)
+	}
+}
+
+// reverseLoyalty: void => tarik poin earn, kembalikan poin redeem, kurangi visit.
+// Earn boleh bikin saldo minus (poin sudah terpakai di trx lain).
+func (s *POSTransactionServiceImpl) reverseLoyalty(clientUUID, userUUID string, trx dao.POSTransaction) {
+	ledgers, err := s.customerRepo.ListPointLedgerByTransaction(trx.UUID)
+	if err != nil {
+		return
+	}
+	for _, l := range ledgers {
+		if l.Type == dao.PointReversal || l.Points == 0 {
+			continue
+		}
+		bal, err := s.customerRepo.AdjustPoints(l.CustomerUUID, -l.Points, true)
+		if err != nil {
+			continue
+		}
+		_, _ = s.customerRepo.InsertPointLedger(&dao.CustomerPointLedger{
+			ClientUUID:      clientUUID,
+			CustomerUUID:    l.CustomerUUID,
+			TransactionUUID: trx.UUID,
+			ReceiptNo:       trx.ReceiptNo,
+			Type:            dao.PointReversal,
+			Points:          -l.Points,
+			BalanceAfter:    bal,
+			Note:            "void " + string(l.Type),
+			CreatedBy:       userUUID,
+		})
+	}
+	_, _ = s.customerRepo.RecordVisit(trx.CustomerUUID, -trx.Total, -1)
+}
+
 func newIDRCurrency(v float64) string {
-	// format: 123,456 (tanpa Rp)
-	// kamu bisa ganti ke helper format money kamu kalau ada
-	s := strconv.FormatInt(int64(v+0.5), 10)
-	n := len(s)
-	if n <= 3 {
-		return s
-	}
-	var b strings.Builder
-	pre := n % 3
-	if pre == 0 {
-		pre = 3
-	}
-	b.WriteString(s[:pre])
-	for i := pre; i < n; i += 3 {
-		b.WriteString(".")
-		b.WriteString(s[i : i+3])
-	}
-	return b.String()
+	// format: 123.456 (tanpa Rp)
+	return helpers.FormatIDR(v)
 }
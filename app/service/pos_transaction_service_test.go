@@ -0,0 +1,56 @@
+package service
+
+import (
+	"testing"
+
+	"harjonan.id/user-service/app/domain/dao"
+)
+
+func TestResolveSaleUnit(t *testing.T) {
+	p := dao.Product{
+		Name:     "Indomie Goreng",
+		BaseUnit: "PCS",
+		Price:    3500,
+		Units: []dao.ProductUnit{
+			{Name: "DUS", ConversionToBase: 40, Price: 130000}, // harga grosir khusus
+			{Name: "PAK", ConversionToBase: 5},                 // tanpa harga => 5 x 3500
+			{Name: "BIJI", ConversionToBase: 0},                // conversion kosong => 1
+			{Name: "KG", ConversionToBase: 1.5},
+		},
+	}
+
+	cases := []struct {
+		name      string
+		unit      string
+		wantUnit  string
+		wantConv  float64
+		wantPrice float64
+		wantErr   bool
+	}{
+		{"empty unit is base", "", "PCS", 1, 3500, false},
+		{"base unit", "PCS", "PCS", 1, 3500, false},
+		{"base unit case-insensitive", " pcs ", "PCS", 1, 3500, false},
+		{"unit with own price", "DUS", "DUS", 40, 130000, false},
+		{"unit case-insensitive", "dus", "DUS", 40, 130000, false},
+		{"unit price from conversion", "PAK", "PAK", 5, 17500, false},
+		{"zero conversion counts as 1", "BIJI", "BIJI", 1, 3500, false},
+		{"fractional conversion", "KG", "", 0, 0, true},
+		{"unknown unit", "LUSIN", "", 0, 0, true},
+	}
+	for _, c := range cases {
+		unit, conv, price, err := resolveSaleUnit(p, c.unit)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("%s: want error, got %s x%v @%v", c.name, unit, conv, price)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", c.name, err)
+			continue
+		}
+		if unit != c.wantUnit || conv != c.wantConv || price != c.wantPrice {
+			t.Errorf("%s: want %s x%v @%v, got %s x%v @%v", c.name, c.wantUnit, c.wantConv, c.wantPrice, unit, conv, price)
+		}
+	}
+}
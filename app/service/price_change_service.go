@@ -0,0 +1,533 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+	"harjonan.id/user-service/app/repository"
+)
+
+// PriceChangeService: perubahan harga terjadwal (approval) + riwayat harga per produk
+type PriceChangeService interface {
+	Schedule(ctx *gin.Context)
+	List(ctx *gin.Context)
+	Detail(ctx *gin.Context)
+	Approve(ctx *gin.Context)
+	Reject(ctx *gin.Context)
+	Cancel(ctx *gin.Context)
+	ProductHistory(ctx *gin.Context)
+	CatalogHistory(ctx *gin.Context)
+
+	// ApplyDue: dipanggil worker, return jumlah price change yang diterapkan
+	ApplyDue() (int, error)
+}
+
+type PriceChangeServiceImpl struct {
+	repo         repository.PriceChangeRepository
+	catalogRepo  repository.CatalogRepository
+	prodRepo     repository.ProductRepository
+	approvalRepo repository.ApprovalRepository
+	notifRepo    repository.NotificationRepository
+	branchRepo   repository.ClientBranchRepository
+	authRepo     repository.AuthRepository
+	prices       priceApplier
+}
+
+func NewPriceChangeService(repo repository.PriceChangeRepository, catalogRepo repository.CatalogRepository, prodRepo repository.ProductRepository, approvalRepo repository.ApprovalRepository, notifRepo repository.NotificationRepository, branchRepo repository.ClientBranchRepository, authRepo repository.AuthRepository) *PriceChangeServiceImpl {
+	return &PriceChangeServiceImpl{
+		repo:         repo,
+		catalogRepo:  catalogRepo,
+		prodRepo:     prodRepo,
+		approvalRepo: approvalRepo,
+		notifRepo:    notifRepo,
+		branchRepo:   branchRepo,
+		authRepo:     authRepo,
+		prices:       priceApplier{repo: repo, catalogRepo: catalogRepo, notifRepo: notifRepo},
+	}
+}
+
+// POST /price-changes/schedule body: PriceChangeRequest
+// requester dengan role approver (approval policy) => langsung SCHEDULED, selain itu menunggu approval
+func (s *PriceChangeServiceImpl) Schedule(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.PriceChangeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	req.CatalogUUID = strings.TrimSpace(req.CatalogUUID)
+	req.ProductUUID = strings.TrimSpace(req.ProductUUID)
+	req.BranchUUID = strings.TrimSpace(req.BranchUUID)
+	req.Scope = strings.TrimSpace(strings.ToUpper(req.Scope))
+	if req.NewPrice < 0 {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("new_price must be >= 0"))
+		return
+	}
+
+	if req.ProductUUID != "" {
+		p, err := s.prodRepo.DetailProduct(req.ProductUUID)
+		if err != nil {
+			helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("product not found"))
+			return
+		}
+		if p.CatalogUUID == "" {
+			helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("product not linked to catalog, run catalog migrate first"))
+			return
+		}
+		req.CatalogUUID = p.CatalogUUID
+		if req.BranchUUID == "" {
+			req.BranchUUID = p.BranchUUID
+		}
+		if req.Scope == "" {
+			req.Scope = string(dao.PriceScopeBranch)
+		}
+	}
+	if req.CatalogUUID == "" {
+		helpers.JsonErr[any](ctx, "missing identifier", http.StatusBadRequest, errors.New("catalog_uuid or product_uuid required"))
+		return
+	}
+	if req.Scope == "" {
+		req.Scope = string(dao.PriceScopeClient)
+	}
+
+	cat, err := s.catalogRepo.Detail(req.CatalogUUID)
+	if err != nil || cat.ClientUUID != profile.Client.UUID {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("catalog product not found"))
+		return
+	}
+	if cat.HasVariants {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("product has variants, schedule price per variant"))
+		return
+	}
+
+	pc := dao.PriceChange{
+		ClientUUID:      profile.Client.UUID,
+		CatalogUUID:     cat.UUID,
+		Scope:           dao.PriceChangeScope(req.Scope),
+		SKU:             cat.SKU,
+		Name:            cat.Name,
+		OldPrice:        cat.Price,
+		NewPrice:        req.NewPrice,
+		Note:            strings.TrimSpace(req.Note),
+		RequestedBy:     profile.UUID,
+		RequestedByName: profile.Name,
+	}
+	switch pc.Scope {
+	case dao.PriceScopeClient:
+		if !isOwnerRole(profile.Role.Value) {
+			helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("only OWNER can schedule client-wide price"))
+			return
+		}
+	case dao.PriceScopeBranch:
+		if req.BranchUUID == "" {
+			req.BranchUUID = profile.Branch.UUID
+		}
+		if !requireBranchAccess(ctx, s.branchRepo, profile, req.BranchUUID) {
+			return
+		}
+		inv, err := branchInventory(s.catalogRepo, cat.UUID, req.BranchUUID)
+		if err != nil {
+			helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+			return
+		}
+		pc.BranchUUID = req.BranchUUID
+		pc.OldPrice = inv.Price
+	default:
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("scope must be CLIENT/BRANCH"))
+		return
+	}
+
+	now := time.Now().Unix()
+	pc.EffectiveFrom = now
+	if req.EffectiveFrom = strings.TrimSpace(req.EffectiveFrom); req.EffectiveFrom != "" {
+		if pc.EffectiveFrom, err = helpers.ParseDateTimeWIB(req.EffectiveFrom); err != nil {
+			helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("invalid effective_from"))
+			return
+		}
+		pc.EffectiveFrom = max(pc.EffectiveFrom, now)
+	}
+
+	policy, err := s.approvalRepo.GetPolicy(profile.Client.UUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load policy", http.StatusInternalServerError, err)
+		return
+	}
+	pc.Status = dao.PriceChangePending
+	if isApproverRole(policy, profile.Role.Value) {
+		pc.Status = dao.PriceChangeScheduled
+		pc.ApprovedBy = profile.UUID
+		pc.ApprovedByName = profile.Name
+		pc.DecidedAt = now
+		pc.DecidedAtStr = time.Unix(now, 0).Format(time.RFC3339)
+	}
+
+	out, err := s.repo.Insert(&pc)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to save price change", http.StatusInternalServerError, err)
+		return
+	}
+
+	if out.Status == dao.PriceChangePending {
+		// BranchUUID kosong => hanya owner yang lihat
+		_, _ = s.notifRepo.Insert(&dao.Notification{
+			ClientUUID: out.ClientUUID,
+			Title:      "Butuh Approval Harga",
+			Message: fmt.Sprintf("%s • %s • Rp %s -> Rp %s mulai %s", profile.Name, out.Name,
+				helpers.FormatIDR(out.OldPrice), helpers.FormatIDR(out.NewPrice), helpers.FormatPOSUnix(out.EffectiveFrom)),
+			Icon: "warning",
+			Type: "PRICE_CHANGE",
+			Ref:  out.UUID,
+		})
+	} else if out.EffectiveFrom <= now {
+		go func() { _, _ = s.prices.applyDue(out.CatalogUUID) }()
+	}
+	helpers.JsonOK(ctx, "success", out)
+}
+
+// POST /price-changes/fetch: selain OWNER hanya price change branch sendiri + harga catalog
+func (s *PriceChangeServiceImpl) List(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	var req dto.FilterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if req.FilterBy == nil {
+		req.FilterBy = map[string]any{}
+	}
+	req.FilterBy["client_uuid"] = profile.Client.UUID
+	if !isOwnerRole(profile.Role.Value) {
+		req.FilterBy["branch_uuid"] = []string{"", profile.Branch.UUID}
+	}
+
+	data, err := s.repo.List(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to list price change", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", data)
+}
+
+func (s *PriceChangeServiceImpl) Detail(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	pc, ok := s.load(ctx, profile)
+	if !ok {
+		return
+	}
+	helpers.JsonOK(ctx, "success", pc)
+}
+
+// POST /price-changes/:uuid/approve body: { "note": "" }
+func (s *PriceChangeServiceImpl) Approve(ctx *gin.Context) {
+	s.decide(ctx, dao.PriceChangeScheduled)
+}
+
+// POST /price-changes/:uuid/reject body: { "note": "" }
+func (s *PriceChangeServiceImpl) Reject(ctx *gin.Context) {
+	s.decide(ctx, dao.PriceChangeRejected)
+}
+
+func (s *PriceChangeServiceImpl) decide(ctx *gin.Context, status dao.PriceChangeStatus) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	policy, err := s.approvalRepo.GetPolicy(profile.Client.UUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load policy", http.StatusInternalServerError, err)
+		return
+	}
+	if !isApproverRole(policy, profile.Role.Value) {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("role is not allowed to approve"))
+		return
+	}
+	pc, ok := s.load(ctx, profile)
+	if !ok {
+		return
+	}
+
+	var req dto.ApprovalDecisionRequest
+	_ = ctx.ShouldBindJSON(&req)
+
+	out, err := s.repo.Decide(pc.UUID, status, profile.UUID, profile.Name, strings.TrimSpace(req.Note))
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to decide price change", http.StatusBadRequest, err)
+		return
+	}
+
+	title, icon := "Perubahan Harga Disetujui", "success"
+	if status == dao.PriceChangeRejected {
+		title, icon = "Perubahan Harga Ditolak", "warning"
+	}
+	_, _ = s.notifRepo.Insert(&dao.Notification{
+		ClientUUID: out.ClientUUID,
+		BranchUUID: out.BranchUUID,
+		UserUUID:   out.RequestedBy,
+		Title:      title,
+		Message: fmt.Sprintf("%s • Rp %s mulai %s • oleh %s", out.Name,
+			helpers.FormatIDR(out.NewPrice), helpers.FormatPOSUnix(out.EffectiveFrom), profile.Name),
+		Icon: icon,
+		Type: "PRICE_CHANGE",
+		Ref:  out.UUID,
+	})
+
+	if status == dao.PriceChangeScheduled && out.EffectiveFrom <= time.Now().Unix() {
+		go func() { _, _ = s.prices.applyDue(out.CatalogUUID) }()
+	}
+	helpers.JsonOK(ctx, "success", out)
+}
+
+// POST /price-changes/:uuid/cancel body: { "note": "" } (requester / OWNER, sebelum diterapkan)
+func (s *PriceChangeServiceImpl) Cancel(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	pc, ok := s.load(ctx, profile)
+	if !ok {
+		return
+	}
+	if pc.RequestedBy != profile.UUID && !isOwnerRole(profile.Role.Value) {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("only requester or OWNER can cancel"))
+		return
+	}
+
+	var req dto.ApprovalDecisionRequest
+	_ = ctx.ShouldBindJSON(&req)
+
+	out, err := s.repo.Cancel(pc.UUID, strings.TrimSpace(req.Note))
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to cancel price change", http.StatusBadRequest, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", out)
+}
+
+// POST /products/:uuid/price-history body: FilterRequest
+// harga branch ini + harga catalog (berlaku selama branch tidak punya price_override)
+func (s *PriceChangeServiceImpl) ProductHistory(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	p, err := s.prodRepo.DetailProduct(strings.TrimSpace(ctx.Param("uuid")))
+	if err != nil {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("product not found"))
+		return
+	}
+	if !requireBranchAccess(ctx, s.branchRepo, profile, p.BranchUUID) {
+		return
+	}
+
+	var req dto.FilterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	req.FilterBy = map[string]any{"client_uuid": profile.Client.UUID}
+	if p.CatalogUUID != "" {
+		req.FilterBy["catalog_uuid"] = p.CatalogUUID
+		req.FilterBy["branch_uuid"] = []string{"", p.BranchUUID}
+	} else {
+		req.FilterBy["product_uuid"] = p.UUID
+	}
+
+	data, err := s.repo.ListHistory(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to list price history", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", data)
+}
+
+// POST /catalog/:uuid/price-history body: FilterRequest (filter_by.branch_uuid optional)
+func (s *PriceChangeServiceImpl) CatalogHistory(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	cat, err := s.catalogRepo.Detail(strings.TrimSpace(ctx.Param("uuid")))
+	if err != nil || cat.ClientUUID != profile.Client.UUID {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("catalog product not found"))
+		return
+	}
+
+	var req dto.FilterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if req.FilterBy == nil {
+		req.FilterBy = map[string]any{}
+	}
+	req.FilterBy["client_uuid"] = profile.Client.UUID
+	req.FilterBy["catalog_uuid"] = cat.UUID
+	if !isOwnerRole(profile.Role.Value) {
+		req.FilterBy["branch_uuid"] = []string{"", profile.Branch.UUID}
+	}
+
+	data, err := s.repo.ListHistory(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to list price history", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", data)
+}
+
+func (s *PriceChangeServiceImpl) ApplyDue() (int, error) {
+	return s.prices.applyDue("")
+}
+
+func (s *PriceChangeServiceImpl) load(ctx *gin.Context, profile *dto.UserProfile) (dao.PriceChange, bool) {
+	pc, err := s.repo.Detail(strings.TrimSpace(ctx.Param("uuid")))
+	if err != nil || pc.ClientUUID != profile.Client.UUID ||
+		(pc.BranchUUID != "" && !isOwnerRole(profile.Role.Value) && pc.BranchUUID != profile.Branch.UUID) {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("price change not found"))
+		return dao.PriceChange{}, false
+	}
+	return pc, true
+}
+
+// -------------------------------
+// Penerapan harga terjadwal (worker + approve yang sudah jatuh tempo)
+// -------------------------------
+
+// lock basi (proses mati di tengah jalan) diambil ulang setelah ini
+const priceChangeLockTTL = 5 * time.Minute
+
+type priceApplier struct {
+	repo        repository.PriceChangeRepository
+	catalogRepo repository.CatalogRepository
+	notifRepo   repository.NotificationRepository
+}
+
+// applyDue: terapkan semua price change jatuh tempo, urut effective_from (yang terakhir menang).
+// catalogUUID kosong = semua produk.
+func (a priceApplier) applyDue(catalogUUID string) (int, error) {
+	n := 0
+	for {
+		now := time.Now().Unix()
+		pc, err := a.repo.ClaimDue(now, now-int64(priceChangeLockTTL.Seconds()), catalogUUID)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+
+		old, err := a.apply(pc)
+		status, lastErr := dao.PriceChangeApplied, ""
+		if err != nil {
+			status, lastErr = dao.PriceChangeFailed, err.Error()
+		}
+		out, ferr := a.repo.Finish(pc.UUID, status, old, lastErr)
+		if ferr != nil {
+			return n, ferr
+		}
+		a.notify(out)
+		if err == nil {
+			n++
+		}
+	}
+}
+
+// apply: return harga tepat sebelum diubah
+func (a priceApplier) apply(pc dao.PriceChange) (float64, error) {
+	cat, err := a.catalogRepo.Detail(pc.CatalogUUID)
+	if err != nil {
+		return 0, errors.New("catalog product not found")
+	}
+
+	h := dao.PriceHistory{
+		ClientUUID:  pc.ClientUUID,
+		CatalogUUID: cat.UUID,
+		SKU:         cat.SKU,
+		Name:        cat.Name,
+		NewPrice:    pc.NewPrice,
+		Source:      dao.PriceSourceScheduled,
+		Ref:         pc.UUID,
+		ChangedBy:   pc.ApprovedBy,
+	}
+	switch pc.Scope {
+	case dao.PriceScopeBranch:
+		inv, err := branchInventory(a.catalogRepo, cat.UUID, pc.BranchUUID)
+		if err != nil {
+			return 0, err
+		}
+		price := pc.NewPrice
+		if _, err := a.catalogRepo.SetInventory(cat, dto.CatalogInventoryRequest{BranchUUID: pc.BranchUUID, PriceOverride: &price}, pc.RequestedBy); err != nil {
+			return 0, err
+		}
+		h.BranchUUID = pc.BranchUUID
+		h.ProductUUID = inv.UUID
+		h.OldPrice = inv.Price
+	default:
+		h.OldPrice = cat.Price
+		cat.Price = pc.NewPrice
+		if _, err := a.catalogRepo.Save(&cat); err != nil {
+			return 0, err
+		}
+	}
+	recordPriceHistory(a.repo, h)
+	return h.OldPrice, nil
+}
+
+func (a priceApplier) notify(pc dao.PriceChange) {
+	title, icon := "Harga Baru Berlaku", "success"
+	msg := fmt.Sprintf("%s • Rp %s -> Rp %s", pc.Name, helpers.FormatIDR(pc.OldPrice), helpers.FormatIDR(pc.NewPrice))
+	if pc.Status == dao.PriceChangeFailed {
+		title, icon = "Perubahan Harga Gagal", "warning"
+		msg = fmt.Sprintf("%s • %s", pc.Name, pc.LastError)
+	}
+	_, _ = a.notifRepo.Insert(&dao.Notification{
+		ClientUUID: pc.ClientUUID,
+		BranchUUID: pc.BranchUUID,
+		UserUUID:   pc.RequestedBy,
+		Title:      title,
+		Message:    msg,
+		Icon:       icon,
+		Type:       "PRICE_CHANGE",
+		Ref:        pc.UUID,
+	})
+}
+
+// branchInventory: record products catalog di 1 branch
+func branchInventory(catalogRepo repository.CatalogRepository, catalogUUID, branchUUID string) (dao.Product, error) {
+	list, err := catalogRepo.Inventories(catalogUUID)
+	if err != nil {
+		return dao.Product{}, err
+	}
+	for _, p := range list {
+		if p.BranchUUID == branchUUID {
+			return p, nil
+		}
+	}
+	return dao.Product{}, errors.New("product not available in branch")
+}
+
+// recordPriceHistory: best-effort, harga tidak berubah = tidak dicatat
+func recordPriceHistory(repo repository.PriceChangeRepository, h dao.PriceHistory) {
+	if h.OldPrice == h.NewPrice {
+		return
+	}
+	_ = repo.InsertHistory(&h)
+}
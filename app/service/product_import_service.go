@@ -0,0 +1,767 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+	"harjonan.id/user-service/app/repository"
+)
+
+// ---------- IMPORT / EXPORT PRODUK ----------
+//
+// Template kolom (CSV/XLSX) (header WAJIB: branch_uuid, name, base_unit):
+// branch_uuid, sku, barcode, name, description, base_unit, units, cost, price, is_active
+// (optional) plu, category ("Minuman > Kopi", dibuat kalau belum ada), brand, tags ("kopi|susu")
+//
+// Format units:
+// pcs:1|box:12|karton:240
+// (artinya: base_unit misal pcs, box=12 pcs, karton=240 pcs), optional harga per unit: box:12:110000
+//
+// is_active: true/false/1/0/yes/no
+// Produk dicocokkan per branch by SKU (kalau kosong by nama). cost hanya dipakai untuk produk baru.
+type ProductImportService interface {
+	Upload(ctx *gin.Context)
+	List(ctx *gin.Context)
+	Detail(ctx *gin.Context)
+	Report(ctx *gin.Context)
+	Export(ctx *gin.Context)
+
+	// ProcessJobs: dipanggil worker, jalankan job import yang antre
+	ProcessJobs() (int, error)
+}
+
+type ProductImportServiceImpl struct {
+	repo         repository.ProductImportJobRepository
+	prodRepo     repository.ProductRepository
+	categoryRepo repository.CategoryRepository
+	brandRepo    repository.BrandRepository
+	branchRepo   repository.ClientBranchRepository
+	authRepo     repository.AuthRepository
+	notifRepo    repository.NotificationRepository
+	priceRepo    repository.PriceChangeRepository
+}
+
+func NewProductImportService(repo repository.ProductImportJobRepository, prodRepo repository.ProductRepository, categoryRepo repository.CategoryRepository, brandRepo repository.BrandRepository, branchRepo repository.ClientBranchRepository, authRepo repository.AuthRepository, notifRepo repository.NotificationRepository, priceRepo repository.PriceChangeRepository) *ProductImportServiceImpl {
+	return &ProductImportServiceImpl{repo: repo, prodRepo: prodRepo, categoryRepo: categoryRepo, brandRepo: brandRepo, branchRepo: branchRepo, authRepo: authRepo, notifRepo: notifRepo, priceRepo: priceRepo}
+}
+
+const (
+	maxImportFileSize  = 8 << 20 // file disimpan di dokumen job (limit dokumen mongo 16MB)
+	maxImportRows      = 10000
+	defaultImportBatch = 200
+	maxImportBatch     = 1000
+)
+
+const (
+	importCreate    = "CREATE"
+	importUpdate    = "UPDATE"
+	importUnchanged = "UNCHANGED"
+)
+
+// POST /products/import (multipart) file, mode=UPSERT|CREATE_ONLY, dry_run=true, batch_size=200
+// => job PENDING, cek progress di GET /products/import/:uuid
+func (s *ProductImportServiceImpl) Upload(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		helpers.JsonErr[any](ctx, "missing file", http.StatusBadRequest, err)
+		return
+	}
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	if ext != ".csv" && ext != ".xlsx" {
+		helpers.JsonErr[any](ctx, "unsupported file", http.StatusBadRequest, errors.New("only .csv or .xlsx supported"))
+		return
+	}
+	if file.Size > maxImportFileSize {
+		helpers.JsonErr[any](ctx, "file too large", http.StatusBadRequest, fmt.Errorf("max %d MB", maxImportFileSize>>20))
+		return
+	}
+
+	mode := dao.ProductImportMode(strings.ToUpper(strings.TrimSpace(ctx.PostForm("mode"))))
+	switch mode {
+	case "":
+		mode = dao.ImportModeUpsert
+	case dao.ImportModeUpsert, dao.ImportModeCreateOnly:
+	default:
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("mode must be UPSERT or CREATE_ONLY"))
+		return
+	}
+	batch := defaultImportBatch
+	if v := strings.TrimSpace(ctx.PostForm("batch_size")); v != "" {
+		batch, err = strconv.Atoi(v)
+		if err != nil || batch <= 0 || batch > maxImportBatch {
+			helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, fmt.Errorf("batch_size must be 1-%d", maxImportBatch))
+			return
+		}
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to open file", http.StatusBadRequest, err)
+		return
+	}
+	defer f.Close()
+	raw, err := io.ReadAll(f)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to read file", http.StatusBadRequest, err)
+		return
+	}
+
+	job := dao.ProductImportJob{
+		ClientUUID: profile.Client.UUID,
+		FileName:   filepath.Base(file.Filename),
+		File:       raw,
+		Mode:       mode,
+		DryRun:     parseBool(ctx.PostForm("dry_run"), false),
+		BatchSize:  batch,
+		CreatedBy:  profile.UUID,
+	}
+	if !isOwnerRole(profile.Role.Value) {
+		job.BranchUUID = profile.Branch.UUID
+	}
+	out, err := s.repo.Insert(&job)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to queue import", http.StatusInternalServerError, err)
+		return
+	}
+
+	// proses secepatnya tanpa menunggu tick worker
+	go func() {
+		if _, err := s.ProcessJobs(); err != nil {
+			log.Printf("product import: %v", err)
+		}
+	}()
+
+	helpers.JsonOK(ctx, "success", out)
+}
+
+// POST /products/import/fetch body: FilterRequest (filter_by.status, filter_by.dry_run)
+func (s *ProductImportServiceImpl) List(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	var req dto.FilterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if !isOwnerRole(profile.Role.Value) {
+		if req.FilterBy == nil {
+			req.FilterBy = map[string]any{}
+		}
+		req.FilterBy["created_by"] = profile.UUID
+	}
+	data, err := s.repo.List(profile.Client.UUID, &req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to list import", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", data)
+}
+
+// GET /products/import/:uuid => status, counter, errors per baris/kolom, diff (dry_run)
+func (s *ProductImportServiceImpl) Detail(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	job, err := s.repo.Detail(strings.TrimSpace(ctx.Param("uuid")))
+	if err != nil || !canSeeImport(profile, job) {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("import job not found"))
+		return
+	}
+	helpers.JsonOK(ctx, "success", job)
+}
+
+// GET /products/import/:uuid/report => XLSX baris yang gagal (format sama dengan file import),
+// sel bermasalah diberi warna + comment, kolom import_error berisi semua pesan. Perbaiki lalu upload ulang.
+func (s *ProductImportServiceImpl) Report(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	job, err := s.repo.DetailWithFile(strings.TrimSpace(ctx.Param("uuid")))
+	if err != nil || !canSeeImport(profile, job) {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("import job not found"))
+		return
+	}
+	if job.Status != dao.ImportDone {
+		helpers.JsonErr[any](ctx, "invalid state", http.StatusConflict, errors.New("import job is "+string(job.Status)))
+		return
+	}
+
+	rows, err := readProductSheet(strings.ToLower(filepath.Ext(job.FileName)), job.File)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to read file", http.StatusInternalServerError, err)
+		return
+	}
+	out, err := renderImportErrorReport(rows, job.Errors)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to render report", http.StatusInternalServerError, err)
+		return
+	}
+
+	name := strings.TrimSuffix(job.FileName, filepath.Ext(job.FileName)) + "-errors.xlsx"
+	ctx.Header("Content-Disposition", `attachment; filename="`+name+`"`)
+	ctx.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", out)
+}
+
+// productExportHeader: layout kolom import (bisa langsung di-upload ulang), branch_name & stock hanya info
+var productExportHeader = []string{
+	"branch_uuid", "sku", "barcode", "plu", "name", "description", "base_unit", "units",
+	"cost", "price", "is_active", "category", "brand", "tags", "branch_name", "stock",
+}
+
+// POST /products/export body: { "format": "xlsx" | "csv", "search": "", "filter_by": { "branch_uuid": "", "category_uuid": "" } }
+// tanpa branch_uuid: OWNER semua branch client, selain OWNER branch sendiri. Ditulis langsung ke response per baris.
+func (s *ProductImportServiceImpl) Export(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.ProductExportRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	format := strings.ToLower(strings.TrimSpace(req.Format))
+	if format == "" {
+		format = "xlsx"
+	}
+	if format != "xlsx" && format != "csv" {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("format must be xlsx or csv"))
+		return
+	}
+
+	fr := req.FilterRequest
+	if fr.FilterBy == nil {
+		fr.FilterBy = map[string]any{}
+	}
+	if !withCategorySubtree(ctx, s.categoryRepo, &fr) {
+		return
+	}
+
+	// nama branch untuk kolom info + batas branch yang boleh di-export
+	branches := map[string]string{}
+	var bf dto.FilterRequest
+	bf.FilterBy = map[string]any{"client_uuid": profile.Client.UUID}
+	bf.Pagination.PageSize = 200
+	list, err := s.branchRepo.ListClientBranch(&bf)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load branches", http.StatusInternalServerError, err)
+		return
+	}
+	uuids := make([]string, 0, len(list))
+	for _, b := range list {
+		branches[b.UUID] = b.Name
+		uuids = append(uuids, b.UUID)
+	}
+	switch branchUUID, _ := fr.FilterBy["branch_uuid"].(string); {
+	case branchUUID != "":
+		if !requireBranchAccess(ctx, s.branchRepo, profile, branchUUID) {
+			return
+		}
+	case isOwnerRole(profile.Role.Value):
+		fr.FilterBy["branch_uuid"] = bson.M{"$in": uuids}
+	default:
+		fr.FilterBy["branch_uuid"] = profile.Branch.UUID
+	}
+
+	row := func(p dao.Product) []string {
+		return []string{
+			p.BranchUUID, p.SKU, p.Barcode, p.PLU, p.Name, p.Description, p.BaseUnit, formatUnits(p.Units),
+			formatNumber(p.Cost), formatNumber(p.Price), strconv.FormatBool(p.IsActive),
+			p.CategoryPath, p.BrandName, strings.Join(p.Tags, "|"), branches[p.BranchUUID], strconv.FormatInt(p.Stock, 10),
+		}
+	}
+
+	name := "products-" + helpers.FormatDateISO(time.Now().Unix())
+	if format == "csv" {
+		ctx.Header("Content-Disposition", `attachment; filename="`+name+`.csv"`)
+		ctx.Header("Content-Type", "text/csv; charset=utf-8")
+		ctx.Status(http.StatusOK)
+
+		w := csv.NewWriter(ctx.Writer)
+		_ = w.Write(productExportHeader)
+		n := 0
+		err = s.prodRepo.EachProduct(&fr, func(p dao.Product) error {
+			if err := w.Write(row(p)); err != nil {
+				return err
+			}
+			if n++; n%500 == 0 {
+				w.Flush()
+				ctx.Writer.Flush()
+			}
+			return w.Error()
+		})
+		w.Flush()
+		if err != nil {
+			// header sudah terkirim, response terpotong
+			log.Printf("product export csv: %v", err)
+		}
+		return
+	}
+
+	// stream writer excelize: baris yang sudah ditulis dipindah ke file sementara, tidak ditahan di memory
+	f := excelize.NewFile()
+	defer f.Close()
+	sw, err := f.NewStreamWriter(f.GetSheetName(0))
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to export", http.StatusInternalServerError, err)
+		return
+	}
+	cells := func(vals []string) []any {
+		out := make([]any, len(vals))
+		for i, v := range vals {
+			out[i] = v
+		}
+		return out
+	}
+	if err := sw.SetRow("A1", cells(productExportHeader)); err != nil {
+		helpers.JsonErr[any](ctx, "failed to export", http.StatusInternalServerError, err)
+		return
+	}
+	n := 1
+	err = s.prodRepo.EachProduct(&fr, func(p dao.Product) error {
+		n++
+		vals := cells(row(p))
+		vals[8], vals[9], vals[15] = p.Cost, p.Price, p.Stock // angka tetap angka di Excel
+		cell, err := excelize.CoordinatesToCellName(1, n)
+		if err != nil {
+			return err
+		}
+		return sw.SetRow(cell, vals)
+	})
+	if err == nil {
+		err = sw.Flush()
+	}
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to export", http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", `attachment; filename="`+name+`.xlsx"`)
+	ctx.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	ctx.Status(http.StatusOK)
+	if err := f.Write(ctx.Writer); err != nil {
+		log.Printf("product export xlsx: %v", err)
+	}
+}
+
+func canSeeImport(profile *dto.UserProfile, job dao.ProductImportJob) bool {
+	if job.ClientUUID != profile.Client.UUID {
+		return false
+	}
+	return isOwnerRole(profile.Role.Value) || job.CreatedBy == profile.UUID
+}
+
+// ProcessJobs: max 5 job per run, job RUNNING yang basi (> 10 menit tanpa progress) diulang dari awal
+func (s *ProductImportServiceImpl) ProcessJobs() (int, error) {
+	done := 0
+	for i := 0; i < 5; i++ {
+		now := time.Now()
+		job, err := s.repo.ClaimNext(now.Unix(), now.Add(-10*time.Minute).Unix())
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return done, nil
+			}
+			return done, err
+		}
+
+		job.TotalRows, job.ProcessedRows = 0, 0
+		job.Created, job.Updated, job.Unchanged, job.Failed = 0, 0, 0, 0
+		job.Errors, job.Diffs = nil, nil
+		job.Status, job.LastError = dao.ImportDone, ""
+		if err := s.run(&job); err != nil {
+			job.Status, job.LastError = dao.ImportFailed, err.Error()
+		}
+
+		out, err := s.repo.Finish(job)
+		if err != nil {
+			return done, err
+		}
+		s.notifyImport(out)
+		done++
+	}
+	return done, nil
+}
+
+type importLine struct {
+	row     int
+	product dao.Product
+}
+
+// run: error = job gagal total (file / sistem); error data dicatat per baris di job.Errors
+func (s *ProductImportServiceImpl) run(job *dao.ProductImportJob) error {
+	rows, err := readProductSheet(strings.ToLower(filepath.Ext(job.FileName)), job.File)
+	if err != nil {
+		return err
+	}
+	if len(rows) < 2 {
+		return errors.New("no rows found")
+	}
+	idx := indexMap(normalizeHeader(rows[0]))
+	for _, col := range []string{"branch_uuid", "name", "base_unit"} {
+		if _, ok := idx[col]; !ok {
+			return errors.New("missing column " + col)
+		}
+	}
+
+	failed := map[int]bool{}
+	fail := func(row int, e dao.ProductImportError) {
+		e.Row = row
+		job.Errors = append(job.Errors, e)
+		failed[row] = true
+		job.Failed = len(failed)
+	}
+
+	// parse + validasi branch dulu, yang lolos diproses per batch
+	branches := map[string]string{} // branch_uuid => pesan error ("" = ok)
+	var lines []importLine
+	for i := 1; i < len(rows); i++ {
+		row := rows[i]
+		if isRowEmpty(row) {
+			continue
+		}
+		job.TotalRows++
+		if job.TotalRows > maxImportRows {
+			return fmt.Errorf("too many rows, max %d", maxImportRows)
+		}
+
+		p, errs := mapRowToProduct(func(col string) string {
+			j, ok := idx[col]
+			if !ok || j >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[j])
+		})
+		for _, e := range errs {
+			fail(i+1, e)
+		}
+		if len(errs) > 0 {
+			continue
+		}
+
+		msg, cached := branches[p.BranchUUID]
+		if !cached {
+			msg = s.checkImportBranch(job, p.BranchUUID)
+			branches[p.BranchUUID] = msg
+		}
+		if msg != "" {
+			fail(i+1, dao.ProductImportError{Column: "branch_uuid", Value: p.BranchUUID, Message: msg})
+			continue
+		}
+		p.CreatedBy = job.CreatedBy
+		lines = append(lines, importLine{row: i + 1, product: p})
+	}
+	job.ProcessedRows = job.TotalRows - len(lines)
+	if err := s.repo.Progress(*job); err != nil {
+		return err
+	}
+
+	for start := 0; start < len(lines); start += job.BatchSize {
+		batch := lines[start:min(start+job.BatchSize, len(lines))]
+		if err := s.importBatch(job, batch, fail); err != nil {
+			return err
+		}
+		job.ProcessedRows += len(batch)
+		if err := s.repo.Progress(*job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ProductImportServiceImpl) importBatch(job *dao.ProductImportJob, batch []importLine, fail func(int, dao.ProductImportError)) error {
+	list := make([]dao.Product, 0, len(batch))
+	for _, ln := range batch {
+		list = append(list, ln.product)
+	}
+	existing, err := s.prodRepo.MatchExisting(list)
+	if err != nil {
+		return err
+	}
+
+	var write, writeOld []dao.Product
+	var writeRows []int
+	for k, ln := range batch {
+		p := ln.product
+		if existing[k].UUID != "" && job.Mode == dao.ImportModeCreateOnly {
+			col, val := "sku", p.SKU
+			if p.SKU == "" {
+				col, val = "name", p.Name
+			}
+			fail(ln.row, dao.ProductImportError{Column: col, Value: val, Message: "product already exists"})
+			continue
+		}
+
+		diff := diffImportProduct(ln.row, p, existing[k])
+		if job.DryRun {
+			job.Diffs = append(job.Diffs, diff)
+			switch diff.Action {
+			case importCreate:
+				job.Created++
+			case importUpdate:
+				job.Updated++
+			default:
+				job.Unchanged++
+			}
+			continue
+		}
+		if diff.Action == importUnchanged {
+			job.Unchanged++
+			continue
+		}
+
+		if err := classifyProduct(s.categoryRepo, s.brandRepo, job.ClientUUID, job.CreatedBy,
+			&p.CategoryUUID, &p.CategoryPath, &p.BrandUUID, &p.BrandName); err != nil {
+			col, val := "category", p.CategoryPath
+			if strings.Contains(err.Error(), "brand") {
+				col, val = "brand", p.BrandName
+			}
+			fail(ln.row, dao.ProductImportError{Column: col, Value: val, Message: err.Error()})
+			continue
+		}
+		write = append(write, p)
+		writeOld = append(writeOld, existing[k])
+		writeRows = append(writeRows, ln.row)
+	}
+	if len(write) == 0 {
+		return nil
+	}
+
+	created, updated, rowErrs, err := s.prodRepo.ImportProducts(write)
+	if err != nil {
+		return err
+	}
+	job.Created += created
+	job.Updated += updated
+	for i, e := range rowErrs {
+		fail(writeRows[i], dao.ProductImportError{Message: e.Error()})
+	}
+
+	// jejak harga untuk baris UPDATE yang harganya berubah (dry-run tidak sampai sini)
+	for i, p := range write {
+		old := writeOld[i]
+		if _, failed := rowErrs[i]; failed || old.UUID == "" {
+			continue
+		}
+		recordPriceHistory(s.priceRepo, dao.PriceHistory{
+			ClientUUID:  job.ClientUUID,
+			CatalogUUID: old.CatalogUUID,
+			BranchUUID:  old.BranchUUID,
+			ProductUUID: old.UUID,
+			SKU:         old.SKU,
+			Name:        p.Name,
+			OldPrice:    old.Price,
+			NewPrice:    p.Price,
+			Source:      dao.PriceSourceImport,
+			Ref:         job.UUID,
+			ChangedBy:   job.CreatedBy,
+		})
+	}
+	return nil
+}
+
+// checkImportBranch: "" = boleh import ke branch ini
+func (s *ProductImportServiceImpl) checkImportBranch(job *dao.ProductImportJob, branchUUID string) string {
+	b, err := s.branchRepo.DetailClientBranch(branchUUID)
+	if err != nil || b.ClientUUID != job.ClientUUID {
+		return "branch not found"
+	}
+	if job.BranchUUID != "" && branchUUID != job.BranchUUID {
+		return "no access to this branch"
+	}
+	return ""
+}
+
+// diffImportProduct: bandingkan baris import dengan produk yang ada (field yang memang ditulis import)
+func diffImportProduct(row int, p, existing dao.Product) dao.ProductImportDiff {
+	d := dao.ProductImportDiff{Row: row, BranchUUID: p.BranchUUID, SKU: p.SKU, Name: p.Name, Action: importCreate}
+	if existing.UUID == "" {
+		return d
+	}
+	d.ProductUUID = existing.UUID
+
+	add := func(field, old, new string) {
+		if old != new {
+			d.Changes = append(d.Changes, dao.ProductImportChange{Field: field, Old: old, New: new})
+		}
+	}
+	add("name", existing.Name, p.Name)
+	add("barcode", existing.Barcode, p.Barcode)
+	add("plu", existing.PLU, p.PLU)
+	add("description", existing.Description, p.Description)
+	add("base_unit", existing.BaseUnit, p.BaseUnit)
+	add("units", formatUnits(existing.Units), formatUnits(p.Units))
+	add("price", formatNumber(existing.Price), formatNumber(p.Price))
+	add("is_active", strconv.FormatBool(existing.IsActive), strconv.FormatBool(p.IsActive))
+	// kategori / brand / tag kosong = tidak diubah
+	if p.CategoryPath != "" {
+		add("category", existing.CategoryPath, importCategoryPath(p.CategoryPath))
+	}
+	if p.BrandName != "" && !strings.EqualFold(existing.BrandName, p.BrandName) {
+		add("brand", existing.BrandName, p.BrandName)
+	}
+	if p.Tags != nil {
+		add("tags", strings.Join(existing.Tags, "|"), strings.Join(p.Tags, "|"))
+	}
+
+	d.Action = importUnchanged
+	if len(d.Changes) > 0 {
+		d.Action = importUpdate
+	}
+	return d
+}
+
+// importCategoryPath: "minuman>kopi" => "minuman > kopi" (format path kategori)
+func importCategoryPath(s string) string {
+	parts := []string{}
+	for _, p := range strings.Split(s, ">") {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, dao.CategoryPathSep)
+}
+
+func (s *ProductImportServiceImpl) notifyImport(job dao.ProductImportJob) {
+	title, icon := "Import Produk Selesai", "success"
+	msg := fmt.Sprintf("%s • %d baru • %d diupdate • %d sama • %d gagal", job.FileName, job.Created, job.Updated, job.Unchanged, job.Failed)
+	switch {
+	case job.Status == dao.ImportFailed:
+		title, icon, msg = "Import Produk Gagal", "warning", job.FileName+" • "+job.LastError
+	case job.DryRun:
+		title = "Cek Import Produk Selesai"
+	}
+	if job.Status == dao.ImportDone && job.Failed > 0 {
+		icon = "warning"
+	}
+	_, _ = s.notifRepo.Insert(&dao.Notification{
+		ClientUUID: job.ClientUUID,
+		BranchUUID: job.BranchUUID,
+		UserUUID:   job.CreatedBy,
+		Title:      title,
+		Message:    msg,
+		Icon:       icon,
+		Type:       "PRODUCT_IMPORT",
+		Ref:        job.UUID,
+	})
+}
+
+// renderImportErrorReport: header file asli + import_row + import_error, hanya baris yang gagal
+func renderImportErrorReport(rows [][]string, errs []dao.ProductImportError) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+	sheet := f.GetSheetName(0)
+
+	style, err := f.NewStyle(&excelize.Style{Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"#FFC7CE"}}})
+	if err != nil {
+		return nil, err
+	}
+
+	header := []string{}
+	if len(rows) > 0 {
+		header = rows[0]
+	}
+	idx := indexMap(normalizeHeader(header))
+	errCol := len(header) + 2
+
+	put := func(col, row int, v any) error {
+		cell, err := excelize.CoordinatesToCellName(col, row)
+		if err != nil {
+			return err
+		}
+		return f.SetCellValue(sheet, cell, v)
+	}
+	for i, h := range header {
+		if err := put(i+1, 1, h); err != nil {
+			return nil, err
+		}
+	}
+	if err := put(errCol-1, 1, "import_row"); err != nil {
+		return nil, err
+	}
+	if err := put(errCol, 1, "import_error"); err != nil {
+		return nil, err
+	}
+
+	byRow := map[int][]dao.ProductImportError{}
+	var order []int
+	for _, e := range errs {
+		if _, ok := byRow[e.Row]; !ok {
+			order = append(order, e.Row)
+		}
+		byRow[e.Row] = append(byRow[e.Row], e)
+	}
+	slices.Sort(order)
+
+	for n, rowNo := range order {
+		out := n + 2
+		if rowNo-1 < len(rows) {
+			for c, v := range rows[rowNo-1] {
+				if err := put(c+1, out, v); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		msgs := []string{}
+		for _, e := range byRow[rowNo] {
+			if e.Column == "" {
+				msgs = append(msgs, e.Message)
+				continue
+			}
+			msgs = append(msgs, e.Column+": "+e.Message)
+			c, ok := idx[e.Column]
+			if !ok {
+				continue
+			}
+			cell, err := excelize.CoordinatesToCellName(c+1, out)
+			if err != nil {
+				return nil, err
+			}
+			if err := f.SetCellStyle(sheet, cell, cell, style); err != nil {
+				return nil, err
+			}
+			if err := f.AddComment(sheet, excelize.Comment{Author: "import", Cell: cell, Text: e.Message}); err != nil {
+				return nil, err
+			}
+		}
+		if err := put(errCol-1, out, rowNo); err != nil {
+			return nil, err
+		}
+		if err := put(errCol, out, strings.Join(msgs, "; ")); err != nil {
+			return nil, err
+		}
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -4,9 +4,7 @@ import (
 	"bytes"
 	"encoding/csv"
 	"errors"
-	"io"
 	"net/http"
-	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -23,19 +21,36 @@ type ProductService interface {
 	Upsert(ctx *gin.Context)
 	Detail(ctx *gin.Context)
 	List(ctx *gin.Context)
+	Grouped(ctx *gin.Context)
 	Delete(ctx *gin.Context)
-	BulkUpload(ctx *gin.Context)
+	Movements(ctx *gin.Context)
 }
 
 type ProductServiceImpl struct {
-	repo repository.ProductRepository
+	repo         repository.ProductRepository
+	movementRepo repository.StockMovementRepository
+	categoryRepo repository.CategoryRepository
+	brandRepo    repository.BrandRepository
+	branchRepo   repository.ClientBranchRepository
+	priceRepo    repository.PriceChangeRepository
+	authRepo     repository.AuthRepository
+	approvalRepo repository.ApprovalRepository
 }
 
-func NewProductService(repo repository.ProductRepository) *ProductServiceImpl {
-	return &ProductServiceImpl{repo: repo}
+func NewProductService(repo repository.ProductRepository, movementRepo repository.StockMovementRepository, categoryRepo repository.CategoryRepository, brandRepo repository.BrandRepository, branchRepo repository.ClientBranchRepository, priceRepo repository.PriceChangeRepository, authRepo repository.AuthRepository, approvalRepo repository.ApprovalRepository) *ProductServiceImpl {
+	return &ProductServiceImpl{repo: repo, movementRepo: movementRepo, categoryRepo: categoryRepo, brandRepo: brandRepo, branchRepo: branchRepo, priceRepo: priceRepo, authRepo: authRepo, approvalRepo: approvalRepo}
 }
 
+// POST /products/upsert body: Product. Menyimpan inventory branch; produk baru membuat entry catalog,
+// field master produk yang sudah ada di catalog tidak bisa diubah di sini (400, lewat /catalog/upsert).
+// Harga produk yang sudah ada berubah = perubahan harga langsung tanpa approval (tercatat MANUAL di price history),
+// jadi hanya role approver (approval policy); role lain lewat /price-changes.
 func (s *ProductServiceImpl) Upsert(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
 	var req dao.Product
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
@@ -54,11 +69,62 @@ func (s *ProductServiceImpl) Upsert(ctx *gin.Context) {
 		return
 	}
 
+	// kategori / brand kosong = tidak diubah (hapus kategori lewat /catalog/upsert)
+	branch, err := s.branchRepo.DetailClientBranch(req.BranchUUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("branch not found"))
+		return
+	}
+	if err := classifyProduct(s.categoryRepo, s.brandRepo, branch.ClientUUID, req.CreatedBy,
+		&req.CategoryUUID, &req.CategoryPath, &req.BrandUUID, &req.BrandName); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	req.Tags = normalizeTags(req.Tags)
+
+	var old *dao.Product
+	if req.UUID != "" {
+		if p, err := s.repo.DetailProduct(req.UUID); err == nil {
+			old = &p
+		}
+	} else if found, err := s.repo.MatchExisting([]dao.Product{req}); err == nil && found[0].UUID != "" {
+		old = &found[0]
+	}
+	if old != nil && old.Price != req.Price {
+		policy, err := s.approvalRepo.GetPolicy(profile.Client.UUID)
+		if err != nil {
+			helpers.JsonErr[any](ctx, "failed to load policy", http.StatusInternalServerError, err)
+			return
+		}
+		if !isApproverRole(policy, profile.Role.Value) {
+			helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("price change needs approval, use /price-changes"))
+			return
+		}
+	}
+
 	res, err := s.repo.SaveProduct(&req)
+	if errors.Is(err, dao.ErrCatalogMasterChanged) {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
 	if err != nil {
 		helpers.JsonErr[any](ctx, "failed to save product", http.StatusInternalServerError, err)
 		return
 	}
+	if old != nil {
+		recordPriceHistory(s.priceRepo, dao.PriceHistory{
+			ClientUUID:  branch.ClientUUID,
+			CatalogUUID: res.CatalogUUID,
+			BranchUUID:  res.BranchUUID,
+			ProductUUID: res.UUID,
+			SKU:         res.SKU,
+			Name:        res.Name,
+			OldPrice:    old.Price,
+			NewPrice:    res.Price,
+			Source:      dao.PriceSourceManual,
+			ChangedBy:   profile.UUID,
+		})
+	}
 	helpers.JsonOK(ctx, "success", res)
 }
 
@@ -76,12 +142,16 @@ func (s *ProductServiceImpl) Detail(ctx *gin.Context) {
 	helpers.JsonOK(ctx, "success", data)
 }
 
+// POST /products/fetch: filter_by.category_uuid = kategori + semua sub-kategori, filter_by.tags = "kopi"
 func (s *ProductServiceImpl) List(ctx *gin.Context) {
 	var req dto.FilterRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
 		return
 	}
+	if !withCategorySubtree(ctx, s.categoryRepo, &req) {
+		return
+	}
 	data, err := s.repo.ListProduct(&req)
 	if err != nil {
 		helpers.JsonErr[any](ctx, "failed to list product", http.StatusInternalServerError, err)
@@ -90,6 +160,24 @@ func (s *ProductServiceImpl) List(ctx *gin.Context) {
 	helpers.JsonOK(ctx, "success", data)
 }
 
+// POST /products/grouped: seperti /products/fetch (filter_by.branch_uuid), varian dikelompokkan per parent untuk grid POS
+func (s *ProductServiceImpl) Grouped(ctx *gin.Context) {
+	var req dto.FilterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if !withCategorySubtree(ctx, s.categoryRepo, &req) {
+		return
+	}
+	data, err := s.repo.ListGrouped(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to list product", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", data)
+}
+
 func (s *ProductServiceImpl) Delete(ctx *gin.Context) {
 	uuid := ctx.Param("uuid")
 	if uuid == "" {
@@ -103,177 +191,115 @@ func (s *ProductServiceImpl) Delete(ctx *gin.Context) {
 	helpers.JsonOK[struct{}](ctx, "success", struct{}{})
 }
 
-// ---------- BULK UPLOAD ----------
-//
-// Template kolom (CSV/XLSX) (header WAJIB match salah satu):
-// branch_uuid, sku, barcode, name, description, base_unit, units, cost, price, is_active
-//
-// Format units:
-// pcs:1|box:12|karton:240
-// (artinya: base_unit misal pcs, box=12 pcs, karton=240 pcs)
-//
-// is_active: true/false/1/0/yes/no
-func (s *ProductServiceImpl) BulkUpload(ctx *gin.Context) {
-	file, err := ctx.FormFile("file")
-	if err != nil {
-		helpers.JsonErr[any](ctx, "missing file", http.StatusBadRequest, err)
-		return
-	}
-
-	ext := strings.ToLower(filepath.Ext(file.Filename))
-	f, err := file.Open()
-	if err != nil {
-		helpers.JsonErr[any](ctx, "failed to open file", http.StatusBadRequest, err)
+// POST /products/:uuid/movements body: FilterRequest (filter_by.reason, pagination, sort_by)
+// riwayat perubahan stock produk (stock_movements), default terbaru dulu
+func (s *ProductServiceImpl) Movements(ctx *gin.Context) {
+	uuid := ctx.Param("uuid")
+	if uuid == "" {
+		helpers.JsonErr[any](ctx, "missing uuid", http.StatusBadRequest, errors.New("uuid required"))
 		return
 	}
-	defer f.Close()
-
-	raw, err := io.ReadAll(f)
-	if err != nil {
-		helpers.JsonErr[any](ctx, "failed to read file", http.StatusBadRequest, err)
+	var req dto.FilterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
 		return
 	}
-
-	var products []dao.Product
-	switch ext {
-	case ".csv":
-		products, err = parseProductsCSV(bytes.NewReader(raw))
-	case ".xlsx":
-		products, err = parseProductsXLSX(bytes.NewReader(raw))
-	default:
-		helpers.JsonErr[any](ctx, "unsupported file", http.StatusBadRequest, errors.New("only .csv or .xlsx supported"))
-		return
+	if req.FilterBy == nil {
+		req.FilterBy = map[string]any{}
 	}
+	req.FilterBy["product_uuid"] = uuid
 
+	data, err := s.movementRepo.List(&req)
 	if err != nil {
-		helpers.JsonErr[any](ctx, "invalid file content", http.StatusBadRequest, err)
+		helpers.JsonErr[any](ctx, "failed to list movement", http.StatusInternalServerError, err)
 		return
 	}
-	if len(products) == 0 {
-		helpers.JsonErr[any](ctx, "empty data", http.StatusBadRequest, errors.New("no rows found"))
-		return
-	}
-
-	ok, fail, errs := s.repo.BulkUpsertProducts(products)
-
-	resp := gin.H{
-		"success_count": ok,
-		"fail_count":    fail,
-		"errors": func() []string {
-			out := make([]string, 0, len(errs))
-			for _, e := range errs {
-				out = append(out, e.Error())
-			}
-			return out
-		}(),
-	}
-	helpers.JsonOK(ctx, "success", resp)
+	helpers.JsonOK(ctx, "success", data)
 }
 
-// ---------- helpers parse ----------
+// ---------- helpers parse (import / export) ----------
 
-func parseProductsCSV(r io.Reader) ([]dao.Product, error) {
-	cr := csv.NewReader(r)
-	cr.TrimLeadingSpace = true
-
-	rows, err := cr.ReadAll()
-	if err != nil {
-		return nil, err
-	}
-	if len(rows) < 2 {
-		return nil, nil
-	}
-
-	header := normalizeHeader(rows[0])
-	idx := indexMap(header)
-
-	var out []dao.Product
-	for i := 1; i < len(rows); i++ {
-		row := rows[i]
-		if isRowEmpty(row) {
-			continue
-		}
-		p, err := mapRowToProduct(func(col string) string {
-			j, ok := idx[col]
-			if !ok || j >= len(row) {
-				return ""
-			}
-			return strings.TrimSpace(row[j])
-		})
+// readProductSheet: semua baris file (baris pertama header), sheet pertama untuk XLSX
+func readProductSheet(ext string, raw []byte) ([][]string, error) {
+	switch ext {
+	case ".csv":
+		cr := csv.NewReader(bytes.NewReader(raw))
+		cr.TrimLeadingSpace = true
+		cr.FieldsPerRecord = -1
+		return cr.ReadAll()
+	case ".xlsx":
+		f, err := excelize.OpenReader(bytes.NewReader(raw))
 		if err != nil {
-			return nil, errors.New("row " + strconv.Itoa(i+1) + ": " + err.Error())
+			return nil, err
 		}
-		out = append(out, p)
+		defer f.Close()
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, errors.New("no sheet found")
+		}
+		return f.GetRows(sheets[0])
+	default:
+		return nil, errors.New("only .csv or .xlsx supported")
 	}
-	return out, nil
 }
 
-func parseProductsXLSX(r io.Reader) ([]dao.Product, error) {
-	f, err := excelize.OpenReader(r)
-	if err != nil {
-		return nil, err
+// mapRowToProduct: error per kolom (kolom kosong = error baris)
+func mapRowToProduct(get func(col string) string) (dao.Product, []dao.ProductImportError) {
+	var errs []dao.ProductImportError
+	fail := func(col, msg string) {
+		errs = append(errs, dao.ProductImportError{Column: col, Value: get(col), Message: msg})
 	}
-
-	sheets := f.GetSheetList()
-	if len(sheets) == 0 {
-		return nil, errors.New("no sheet found")
-	}
-	sheet := sheets[0]
-
-	rows, err := f.GetRows(sheet)
-	if err != nil {
-		return nil, err
-	}
-	if len(rows) < 2 {
-		return nil, nil
-	}
-
-	header := normalizeHeader(rows[0])
-	idx := indexMap(header)
-
-	var out []dao.Product
-	for i := 1; i < len(rows); i++ {
-		row := rows[i]
-		if isRowEmpty(row) {
-			continue
+	number := func(col string) float64 {
+		v := get(col)
+		if v == "" {
+			return 0
 		}
-		p, err := mapRowToProduct(func(col string) string {
-			j, ok := idx[col]
-			if !ok || j >= len(row) {
-				return ""
-			}
-			return strings.TrimSpace(row[j])
-		})
-		if err != nil {
-			return nil, errors.New("row " + strconv.Itoa(i+1) + ": " + err.Error())
+		f, err := strconv.ParseFloat(strings.ReplaceAll(v, ",", ""), 64)
+		switch {
+		case err != nil:
+			fail(col, "must be a number")
+		case f < 0:
+			fail(col, "must be >= 0")
 		}
-		out = append(out, p)
+		return f
 	}
-	return out, nil
-}
 
-func mapRowToProduct(get func(col string) string) (dao.Product, error) {
 	p := dao.Product{}
 	p.BranchUUID = get("branch_uuid")
 	p.SKU = get("sku")
 	p.Barcode = get("barcode")
+	p.PLU = get("plu")
 	p.Name = get("name")
 	p.Description = get("description")
 	p.BaseUnit = get("base_unit")
 	p.Units = parseUnits(get("units"))
-	p.Cost = parseFloat(get("cost"))
-	p.Price = parseFloat(get("price"))
+	p.Cost = number("cost")
+	p.Price = number("price")
 	p.IsActive = parseBool(get("is_active"), true)
+	p.CategoryPath = get("category")
+	p.BrandName = get("brand")
+	if tags := get("tags"); tags != "" {
+		p.Tags = normalizeTags(strings.Split(tags, "|"))
+	}
 
 	// validation minimal
 	if p.BranchUUID == "" {
-		return dao.Product{}, errors.New("branch_uuid required")
+		fail("branch_uuid", "branch_uuid required")
 	}
 	if p.Name == "" {
-		return dao.Product{}, errors.New("name required")
+		fail("name", "name required")
 	}
 	if p.BaseUnit == "" {
-		return dao.Product{}, errors.New("base_unit required")
+		fail("base_unit", "base_unit required")
+	}
+	if v := get("is_active"); v != "" && parseBool(v, true) != parseBool(v, false) {
+		fail("is_active", "must be true/false")
+	}
+	if v := get("units"); v != "" && len(p.Units) == 0 {
+		fail("units", "format: pcs:1|box:12")
+	}
+	if len(errs) > 0 {
+		return dao.Product{}, errs
 	}
 
 	// default: selalu pastikan base unit juga ada di Units minimal conversion 1
@@ -318,6 +344,7 @@ func parseUnits(s string) []dao.ProductUnit {
 		return nil
 	}
 	// format: pcs:1|box:12|karton:240
+	// optional harga per unit: box:12:110000
 	parts := strings.Split(s, "|")
 	var out []dao.ProductUnit
 	for _, part := range parts {
@@ -337,11 +364,35 @@ func parseUnits(s string) []dao.ProductUnit {
 				conv = 1
 			}
 		}
-		out = append(out, dao.ProductUnit{Name: name, ConversionToBase: conv})
+		var price float64
+		if len(kv) >= 3 {
+			price = parseFloat(strings.TrimSpace(kv[2]))
+			if price < 0 {
+				price = 0
+			}
+		}
+		out = append(out, dao.ProductUnit{Name: name, ConversionToBase: conv, Price: price})
 	}
 	return out
 }
 
+// formatUnits: kebalikan parseUnits => pcs:1|box:12:110000
+func formatUnits(units []dao.ProductUnit) string {
+	parts := make([]string, 0, len(units))
+	for _, u := range units {
+		part := u.Name + ":" + formatNumber(u.ConversionToBase)
+		if u.Price > 0 {
+			part += ":" + formatNumber(u.Price)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "|")
+}
+
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
 func ensureBaseUnit(base string, units []dao.ProductUnit) []dao.ProductUnit {
 	base = strings.TrimSpace(base)
 	if base == "" {
@@ -0,0 +1,558 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+	"harjonan.id/user-service/app/infra/document"
+	"harjonan.id/user-service/app/repository"
+)
+
+type PurchaseOrderService interface {
+	Save(ctx *gin.Context)
+	List(ctx *gin.Context)
+	Detail(ctx *gin.Context)
+	Send(ctx *gin.Context)
+	Cancel(ctx *gin.Context)
+	Receive(ctx *gin.Context)
+	Receipts(ctx *gin.Context)
+	PDF(ctx *gin.Context)
+}
+
+type PurchaseOrderServiceImpl struct {
+	repo         repository.PurchaseOrderRepository
+	supplierRepo repository.SupplierRepository
+	prodRepo     repository.ProductRepository
+	branchRepo   repository.ClientBranchRepository
+	authRepo     repository.AuthRepository
+	notifRepo    repository.NotificationRepository
+	lotRepo      repository.StockLotRepository
+}
+
+func NewPurchaseOrderService(repo repository.PurchaseOrderRepository, supplierRepo repository.SupplierRepository, prodRepo repository.ProductRepository, branchRepo repository.ClientBranchRepository, authRepo repository.AuthRepository, notifRepo repository.NotificationRepository, lotRepo repository.StockLotRepository) *PurchaseOrderServiceImpl {
+	return &PurchaseOrderServiceImpl{repo: repo, supplierRepo: supplierRepo, prodRepo: prodRepo, branchRepo: branchRepo, authRepo: authRepo, notifRepo: notifRepo, lotRepo: lotRepo}
+}
+
+// POST /purchase-orders/upsert : buat PO baru (DRAFT) atau edit PO yang masih DRAFT
+func (s *PurchaseOrderServiceImpl) Save(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.PurchaseOrderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if len(req.Items) == 0 {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("items required"))
+		return
+	}
+	if req.Discount < 0 || req.Tax < 0 {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("discount & tax must be >= 0"))
+		return
+	}
+
+	if req.UUID != "" {
+		existing, err := s.repo.Detail(req.UUID)
+		if err != nil || existing.ClientUUID != profile.Client.UUID {
+			helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("purchase order not found"))
+			return
+		}
+		if existing.Status != dao.PODraft {
+			helpers.JsonErr[any](ctx, "invalid status", http.StatusConflict, errors.New("only DRAFT purchase order can be edited"))
+			return
+		}
+	}
+
+	branchUUID := strings.TrimSpace(req.BranchUUID)
+	if branchUUID == "" {
+		branchUUID = profile.Branch.UUID
+	}
+	if !requireBranchAccess(ctx, s.branchRepo, profile, branchUUID) {
+		return
+	}
+	supplier, err := s.supplierRepo.DetailSupplier(strings.TrimSpace(req.SupplierUUID))
+	if err != nil || supplier.ClientUUID != profile.Client.UUID {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("supplier not found"))
+		return
+	}
+
+	po := dao.PurchaseOrder{
+		ClientUUID:   profile.Client.UUID,
+		BranchUUID:   branchUUID,
+		SupplierUUID: supplier.UUID,
+		SupplierName: supplier.Name,
+		ExpectedDate: req.ExpectedDate,
+		Note:         strings.TrimSpace(req.Note),
+		Discount:     req.Discount,
+		Tax:          req.Tax,
+		Items:        make([]dao.PurchaseOrderItem, 0, len(req.Items)),
+		CreatedBy:    profile.UUID,
+	}
+	po.UUID = req.UUID
+
+	seen := map[string]bool{}
+	for _, it := range req.Items {
+		productUUID := strings.TrimSpace(it.ProductUUID)
+		if productUUID == "" || seen[productUUID] {
+			helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("product_uuid required and must be unique"))
+			return
+		}
+		seen[productUUID] = true
+		if it.Qty <= 0 || it.UnitCost < 0 {
+			helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("qty must be > 0, unit_cost >= 0"))
+			return
+		}
+
+		p, err := s.prodRepo.DetailProduct(productUUID)
+		if err != nil || p.BranchUUID != branchUUID {
+			helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, fmt.Errorf("product %s not found in branch", productUUID))
+			return
+		}
+		unit, conv, err := resolvePurchaseUnit(p, it.Unit)
+		if err != nil {
+			helpers.JsonErr[any](ctx, "invalid unit", http.StatusBadRequest, err)
+			return
+		}
+		qtyBase := int64(math.Round(it.Qty * conv))
+		if qtyBase <= 0 {
+			helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, fmt.Errorf("qty for %s too small", p.Name))
+			return
+		}
+
+		// default harga: harga terakhir dari supplier ini, fallback cost produk
+		unitCost := it.UnitCost
+		if unitCost == 0 {
+			if last, err := s.supplierRepo.LastPrice(supplier.UUID, p.UUID); err == nil {
+				unitCost = last.CostPerBase * conv
+			} else {
+				unitCost = p.Cost * conv
+			}
+		}
+
+		line := dao.PurchaseOrderItem{
+			ProductUUID:      p.UUID,
+			SKU:              p.SKU,
+			Name:             p.Name,
+			BaseUnit:         p.BaseUnit,
+			Unit:             unit,
+			ConversionToBase: conv,
+			Qty:              it.Qty,
+			QtyBase:          qtyBase,
+			UnitCost:         unitCost,
+			LineTotal:        it.Qty * unitCost,
+		}
+		po.Items = append(po.Items, line)
+		po.SubTotal += line.LineTotal
+	}
+	po.Total = po.SubTotal - po.Discount + po.Tax
+	if po.Total < 0 {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("discount exceeds subtotal"))
+		return
+	}
+
+	out, err := s.repo.Save(&po)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to save purchase order", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", out)
+}
+
+func (s *PurchaseOrderServiceImpl) List(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.FilterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if req.FilterBy == nil {
+		req.FilterBy = map[string]any{}
+	}
+	req.FilterBy["client_uuid"] = profile.Client.UUID
+	if !isOwnerRole(profile.Role.Value) {
+		req.FilterBy["branch_uuid"] = profile.Branch.UUID
+	}
+
+	data, err := s.repo.List(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to list purchase order", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", data)
+}
+
+func (s *PurchaseOrderServiceImpl) Detail(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	po, ok := s.load(ctx, profile)
+	if !ok {
+		return
+	}
+	helpers.JsonOK(ctx, "success", po)
+}
+
+// POST /purchase-orders/:uuid/send : DRAFT -> SENT (PO dikirim ke supplier, tidak bisa diedit lagi)
+func (s *PurchaseOrderServiceImpl) Send(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	po, ok := s.load(ctx, profile)
+	if !ok {
+		return
+	}
+	out, err := s.repo.SetStatus(po.UUID, []dao.PurchaseOrderStatus{dao.PODraft}, dao.POSent, bson.M{
+		"sent_at": time.Now().Unix(),
+		"sent_by": profile.UUID,
+	})
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to send purchase order", http.StatusConflict, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", out)
+}
+
+// POST /purchase-orders/:uuid/cancel body: { "reason": "" }
+// PARTIAL boleh di-cancel: sisa yang belum datang tidak ditunggu lagi, barang yang sudah diterima tetap
+func (s *PurchaseOrderServiceImpl) Cancel(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	var req dto.PurchaseOrderCancelRequest
+	_ = ctx.ShouldBindJSON(&req)
+
+	po, ok := s.load(ctx, profile)
+	if !ok {
+		return
+	}
+	out, err := s.repo.SetStatus(po.UUID, []dao.PurchaseOrderStatus{dao.PODraft, dao.POSent, dao.POPartial}, dao.POCancelled, bson.M{
+		"cancelled_at":  time.Now().Unix(),
+		"cancelled_by":  profile.UUID,
+		"cancel_reason": strings.TrimSpace(req.Reason),
+	})
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to cancel purchase order", http.StatusConflict, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", out)
+}
+
+// POST /purchase-orders/:uuid/receive body:
+// { "supplier_ref": "SJ-001", "note": "", "items": [{ "product_uuid": "", "qty": 5, "unit_cost": 0 }] }
+// qty dalam unit PO, boleh sebagian (PARTIAL). Stock branch naik lewat ledger (PURCHASE) dan cost produk di-update.
+func (s *PurchaseOrderServiceImpl) Receive(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.GoodsReceiptRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if len(req.Items) == 0 {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("items required"))
+		return
+	}
+
+	po, ok := s.load(ctx, profile)
+	if !ok {
+		return
+	}
+	if po.Status != dao.POSent && po.Status != dao.POPartial {
+		helpers.JsonErr[any](ctx, "invalid status", http.StatusConflict, errors.New("purchase order is "+string(po.Status)))
+		return
+	}
+
+	now := time.Now()
+	grn := dao.GoodsReceipt{
+		ClientUUID:   po.ClientUUID,
+		BranchUUID:   po.BranchUUID,
+		POUUID:       po.UUID,
+		PONo:         po.PONo,
+		SupplierUUID: po.SupplierUUID,
+		SupplierName: po.SupplierName,
+		GRNNo:        "GRN-" + now.Format("20060102-150405"),
+		SupplierRef:  strings.TrimSpace(req.SupplierRef),
+		Note:         strings.TrimSpace(req.Note),
+		Items:        make([]dao.GoodsReceiptItem, 0, len(req.Items)),
+		ReceivedBy:   profile.UUID,
+	}
+	grn.UUID = helpers.GenerateUUID()
+
+	lines := make([]dao.PurchaseOrderItem, len(po.Items))
+	copy(lines, po.Items)
+	idx := map[string]int{}
+	for i, it := range lines {
+		idx[it.ProductUUID] = i
+	}
+
+	for _, it := range req.Items {
+		i, found := idx[strings.TrimSpace(it.ProductUUID)]
+		if !found {
+			helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, fmt.Errorf("product %s not in purchase order", it.ProductUUID))
+			return
+		}
+		if it.Qty <= 0 || it.UnitCost < 0 {
+			helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("qty must be > 0, unit_cost >= 0"))
+			return
+		}
+		line := &lines[i]
+		conv := line.ConversionToBase
+		if conv <= 0 {
+			conv = 1
+		}
+		qtyBase := int64(math.Round(it.Qty * conv))
+		if qtyBase <= 0 || qtyBase > line.RemainingBase() {
+			helpers.JsonErr[any](ctx, "invalid qty", http.StatusBadRequest, fmt.Errorf("%s: remaining %d %s", line.Name, line.RemainingBase(), line.BaseUnit))
+			return
+		}
+
+		var expiry int64
+		if d := strings.TrimSpace(it.ExpiryDate); d != "" {
+			var err error
+			if expiry, err = helpers.ParseDateWIB(d); err != nil {
+				helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("expiry_date must be YYYY-MM-DD"))
+				return
+			}
+		}
+
+		unitCost := it.UnitCost
+		if unitCost == 0 {
+			unitCost = line.UnitCost
+		}
+		line.ReceivedBase += qtyBase
+		grn.Items = append(grn.Items, dao.GoodsReceiptItem{
+			ProductUUID:      line.ProductUUID,
+			SKU:              line.SKU,
+			Name:             line.Name,
+			Unit:             line.Unit,
+			ConversionToBase: conv,
+			Qty:              it.Qty,
+			QtyBase:          qtyBase,
+			UnitCost:         unitCost,
+			CostPerBase:      unitCost / conv,
+			LineTotal:        it.Qty * unitCost,
+			LotNo:            strings.TrimSpace(it.LotNo),
+			ExpiryDate:       expiry,
+			ExpiryDateStr:    helpers.FormatDateISO(expiry),
+		})
+		grn.Total += it.Qty * unitCost
+	}
+
+	status := dao.POReceived
+	for _, it := range lines {
+		if it.RemainingBase() > 0 {
+			status = dao.POPartial
+			break
+		}
+	}
+
+	// klaim penerimaan di PO dulu (rev guard), baru stock
+	if err := s.repo.ApplyReceipt(po.UUID, po.Rev, lines, status); err != nil {
+		helpers.JsonErr[any](ctx, "failed to receive purchase order", http.StatusConflict, err)
+		return
+	}
+	rollbackPO := func() {
+		_ = s.repo.ApplyReceipt(po.UUID, po.Rev+1, po.Items, po.Status)
+	}
+
+	ref := dao.StockRef{
+		Reason:    dao.StockMovePurchase,
+		RefUUID:   grn.UUID,
+		RefNo:     grn.GRNNo,
+		Note:      po.PONo + " • " + po.SupplierName,
+		CreatedBy: profile.UUID,
+	}
+	rollbackRef := ref
+	rollbackRef.Note = "rollback goods receipt"
+	var lotsIn []dao.LotAllocation
+	// hasil + stock/cost sebelum tiap item masuk: rollback mengembalikan weighted-average cost juga
+	received := make([]dao.Product, 0, len(grn.Items))
+	befores := make([]dao.StockBefore, 0, len(grn.Items))
+	for i := range grn.Items {
+		gi := &grn.Items[i]
+		p, before, err := s.prodRepo.ReceivePurchase(gi.ProductUUID, gi.QtyBase, gi.CostPerBase, ref)
+		if err == nil && p.TrackLots {
+			if gi.LotNo == "" {
+				gi.LotNo = grn.GRNNo
+			}
+			var lot dao.LotAllocation
+			if lot, err = s.lotRepo.Receive(p, gi.LotNo, gi.ExpiryDate, gi.QtyBase); err == nil {
+				lotsIn = append(lotsIn, lot)
+			} else {
+				_ = s.prodRepo.RevertStock(p, gi.QtyBase, before, rollbackRef)
+			}
+		}
+		if err != nil {
+			for j := range received {
+				_ = s.prodRepo.RevertStock(received[j], grn.Items[j].QtyBase, befores[j], rollbackRef)
+			}
+			_ = s.lotRepo.Revert(lotsIn)
+			rollbackPO()
+			helpers.JsonErr[any](ctx, "failed to update stock", http.StatusInternalServerError, fmt.Errorf("%s: %w", gi.Name, err))
+			return
+		}
+		gi.BalanceAfter = p.Stock
+		received = append(received, p)
+		befores = append(befores, before)
+	}
+
+	out, err := s.repo.InsertReceipt(&grn)
+	if err != nil {
+		// stock sudah masuk; GRN gagal disimpan jangan dibalik, cukup dilaporkan
+		helpers.JsonErr[any](ctx, "stock received but failed to save goods receipt", http.StatusInternalServerError, err)
+		return
+	}
+
+	for _, gi := range out.Items {
+		_ = s.supplierRepo.AddPrice(&dao.SupplierPrice{
+			ClientUUID:   out.ClientUUID,
+			SupplierUUID: out.SupplierUUID,
+			ProductUUID:  gi.ProductUUID,
+			BranchUUID:   out.BranchUUID,
+			SKU:          gi.SKU,
+			Name:         gi.Name,
+			Unit:         gi.Unit,
+			UnitCost:     gi.UnitCost,
+			CostPerBase:  gi.CostPerBase,
+			RefUUID:      out.UUID,
+			RefNo:        out.GRNNo,
+			CreatedBy:    profile.UUID,
+		})
+	}
+
+	title := "Barang Diterima Sebagian"
+	if status == dao.POReceived {
+		title = "Barang Diterima"
+	}
+	_, _ = s.notifRepo.Insert(&dao.Notification{
+		ClientUUID: out.ClientUUID,
+		BranchUUID: out.BranchUUID,
+		Title:      title,
+		Message:    fmt.Sprintf("%s • %s • %d item • %s", po.PONo, po.SupplierName, len(out.Items), newIDRCurrency(out.Total)),
+		Icon:       "success",
+		Type:       "PURCHASE_ORDER",
+		Ref:        po.UUID,
+	})
+
+	updated, _ := s.repo.Detail(po.UUID)
+	helpers.JsonOK(ctx, "success", gin.H{
+		"purchase_order": updated,
+		"goods_receipt":  out,
+	})
+}
+
+// GET /purchase-orders/:uuid/receipts
+func (s *PurchaseOrderServiceImpl) Receipts(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	po, ok := s.load(ctx, profile)
+	if !ok {
+		return
+	}
+	data, err := s.repo.ListReceipts(po.UUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to list goods receipt", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", data)
+}
+
+// GET /purchase-orders/:uuid/pdf
+func (s *PurchaseOrderServiceImpl) PDF(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	po, ok := s.load(ctx, profile)
+	if !ok {
+		return
+	}
+	supplier, _ := s.supplierRepo.DetailSupplier(po.SupplierUUID)
+	if supplier.Name == "" {
+		supplier.Name = po.SupplierName
+	}
+	branch, _ := s.branchRepo.DetailClientBranch(po.BranchUUID)
+
+	out, err := document.RenderPurchaseOrderPDF(document.PurchaseOrderDoc{
+		PO:       po,
+		Supplier: supplier,
+		Branding: clientBranding(profile.Client),
+		ShipTo:   branch,
+		IssuedBy: profile.Name,
+	})
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to render purchase order", http.StatusInternalServerError, err)
+		return
+	}
+	ctx.Header("Content-Disposition", `inline; filename="`+po.PONo+`.pdf"`)
+	ctx.Data(http.StatusOK, "application/pdf", out)
+}
+
+func (s *PurchaseOrderServiceImpl) load(ctx *gin.Context, profile *dto.UserProfile) (dao.PurchaseOrder, bool) {
+	uuid := strings.TrimSpace(ctx.Param("uuid"))
+	po, err := s.repo.Detail(uuid)
+	if err != nil || po.ClientUUID != profile.Client.UUID {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("purchase order not found"))
+		return dao.PurchaseOrder{}, false
+	}
+	if !isOwnerRole(profile.Role.Value) && po.BranchUUID != profile.Branch.UUID {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("purchase order belongs to another branch"))
+		return dao.PurchaseOrder{}, false
+	}
+	return po, true
+}
+
+// resolvePurchaseUnit: "" / base unit => konversi 1, selain itu harus ada di Units produk
+func resolvePurchaseUnit(p dao.Product, unit string) (string, float64, error) {
+	unit = strings.TrimSpace(unit)
+	if unit == "" || strings.EqualFold(unit, p.BaseUnit) {
+		return p.BaseUnit, 1, nil
+	}
+	for _, u := range p.Units {
+		if strings.EqualFold(u.Name, unit) && u.ConversionToBase > 0 {
+			return u.Name, u.ConversionToBase, nil
+		}
+	}
+	return "", 0, fmt.Errorf("unit %s not found on %s", unit, p.Name)
+}
+
+// clientBranding: header dokumen A4 (PO, surat jalan) dari data client
+func clientBranding(client dao.Client) document.ReceiptBranding {
+	b := document.ReceiptBranding{
+		StoreName: client.Name,
+		Phone:     client.PhoneNumber,
+		Website:   client.WebisteUrl,
+	}
+	if logoURL := strings.TrimSpace(client.Logo); isHTTPURL(logoURL) {
+		if img := fetchLogo(logoURL); img != nil {
+			b.Logo = img
+			b.LogoImageType = document.ImageType(img)
+		}
+	}
+	return b
+}
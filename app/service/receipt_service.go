@@ -0,0 +1,419 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+	"harjonan.id/user-service/app/infra/document"
+	"harjonan.id/user-service/app/repository"
+)
+
+type ReceiptService interface {
+	GetTemplate(ctx *gin.Context)
+	SaveTemplate(ctx *gin.Context)
+	Render(ctx *gin.Context)
+	Reprint(ctx *gin.Context)
+	SendEmail(ctx *gin.Context)
+
+	// dipanggil worker (bukan endpoint)
+	ProcessEmailOutbox() (int, error)
+}
+
+type ReceiptServiceImpl struct {
+	trxRepo      repository.POSTransactionRepository
+	branchRepo   repository.ClientBranchRepository
+	templateRepo repository.ReceiptTemplateRepository
+	authRepo     repository.AuthRepository
+	outboxRepo   repository.EmailOutboxRepository
+	customerRepo repository.CustomerRepository
+}
+
+func NewReceiptService(trxRepo repository.POSTransactionRepository, branchRepo repository.ClientBranchRepository, templateRepo repository.ReceiptTemplateRepository, authRepo repository.AuthRepository, outboxRepo repository.EmailOutboxRepository, customerRepo repository.CustomerRepository) *ReceiptServiceImpl {
+	return &ReceiptServiceImpl{trxRepo: trxRepo, branchRepo: branchRepo, templateRepo: templateRepo, authRepo: authRepo, outboxRepo: outboxRepo, customerRepo: customerRepo}
+}
+
+func (s *ReceiptServiceImpl) GetTemplate(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	data, err := s.templateRepo.Get(profile.Client.UUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load template", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", data)
+}
+
+func (s *ReceiptServiceImpl) SaveTemplate(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	if !isOwnerRole(profile.Role.Value) {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("only OWNER can manage receipt template"))
+		return
+	}
+
+	var req dao.ReceiptTemplate
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if req.PaperWidth == 0 {
+		req.PaperWidth = 58
+	}
+	if req.PaperWidth != 58 && req.PaperWidth != 80 {
+		helpers.JsonErr[any](ctx, "invalid template", http.StatusBadRequest, errors.New("paper_width must be 58 or 80"))
+		return
+	}
+	req.LogoURL = strings.TrimSpace(req.LogoURL)
+	req.ClientUUID = profile.Client.UUID
+	req.UpdatedBy = profile.UUID
+
+	res, err := s.templateRepo.Save(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to save template", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", res)
+}
+
+// GET /pos/transactions/:uuid/receipt?format=pdf|escpos&width=58|80
+func (s *ReceiptServiceImpl) Render(ctx *gin.Context) {
+	s.render(ctx, false)
+}
+
+// POST /pos/transactions/:uuid/reprint?format=pdf|escpos&width=58|80
+// struk dicetak dengan tanda "COPY" dan reprint dicatat di transaksi
+func (s *ReceiptServiceImpl) Reprint(ctx *gin.Context) {
+	s.render(ctx, true)
+}
+
+func (s *ReceiptServiceImpl) render(ctx *gin.Context, reprint bool) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	format := strings.ToLower(strings.TrimSpace(ctx.DefaultQuery("format", "pdf")))
+	if format != "pdf" && format != "escpos" {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("format must be pdf or escpos"))
+		return
+	}
+
+	uuid := strings.TrimSpace(ctx.Param("uuid"))
+	trx, err := s.trxRepo.Detail(uuid)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("transaction not found"))
+		return
+	}
+	branch, err := s.branchRepo.DetailClientBranch(trx.BranchUUID)
+	if err != nil || branch.ClientUUID != profile.Client.UUID {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("transaction not found"))
+		return
+	}
+
+	tpl, err := s.templateRepo.Get(profile.Client.UUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load template", http.StatusInternalServerError, err)
+		return
+	}
+	if w, err := strconv.Atoi(ctx.Query("width")); err == nil && (w == 58 || w == 80) {
+		tpl.PaperWidth = w
+	}
+
+	if reprint {
+		if updated, err := s.trxRepo.MarkReprinted(trx.UUID, profile.UUID); err == nil {
+			trx = updated
+		}
+	}
+
+	receipt := buildReceipt(trx, profile.Client, branch, tpl, format == "pdf")
+	receipt.Options.Copy = reprint
+
+	name := "receipt-" + trx.ReceiptNo
+	if reprint {
+		name += "-copy"
+	}
+
+	if format == "escpos" {
+		ctx.Header("Content-Disposition", `attachment; filename="`+name+`.bin"`)
+		ctx.Data(http.StatusOK, "application/octet-stream", document.RenderReceiptEscPos(receipt))
+		return
+	}
+
+	out, err := document.RenderReceiptPDF(receipt)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to render receipt", http.StatusInternalServerError, err)
+		return
+	}
+	ctx.Header("Content-Disposition", `inline; filename="`+name+`.pdf"`)
+	ctx.Data(http.StatusOK, "application/pdf", out)
+}
+
+// buildReceipt: gabung transaksi + branding client/branch + template
+func buildReceipt(trx dao.POSTransaction, client dao.Client, branch dao.ClientBranch, tpl dao.ReceiptTemplate, withLogo bool) document.Receipt {
+	b := document.ReceiptBranding{
+		StoreName:  client.Name,
+		BranchName: branch.Name,
+		Phone:      branch.PhoneNumber,
+		HeaderText: tpl.HeaderText,
+		FooterText: tpl.FooterText,
+	}
+	if b.Phone == "" {
+		b.Phone = client.PhoneNumber
+	}
+	if tpl.ShowAddress {
+		b.Address = branch.Address
+		b.Website = client.WebisteUrl
+	}
+
+	if tpl.ShowLogo {
+		logoURL := strings.TrimSpace(tpl.LogoURL)
+		if logoURL == "" {
+			logoURL = strings.TrimSpace(client.Logo)
+		}
+		if isHTTPURL(logoURL) {
+			b.LogoURL = logoURL
+		}
+		if withLogo {
+			if img := fetchLogo(b.LogoURL); img != nil {
+				b.Logo = img
+				b.LogoImageType = document.ImageType(img)
+			}
+		}
+	}
+
+	return document.Receipt{
+		Trx:      trx,
+		Branding: b,
+		Options: document.ReceiptOptions{
+			PaperWidthMM: tpl.PaperWidth,
+			ShowCustomer: tpl.ShowCustomer,
+			ShowPoints:   tpl.ShowPoints,
+			OpenDrawer:   tpl.OpenDrawer,
+		},
+	}
+}
+
+// fetchLogo: best-effort, logo gagal diambil => struk tetap dicetak tanpa logo
+func fetchLogo(url string) []byte {
+	if !isHTTPURL(url) {
+		return nil
+	}
+
+	c, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(c, http.MethodGet, url, nil)
+	if err != nil {
+		return nil
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+
+	// max 2MB
+	img, err := io.ReadAll(io.LimitReader(resp.Body, 2<<20))
+	if err != nil || document.ImageType(img) == "" {
+		return nil
+	}
+	return img
+}
+
+func isHTTPURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+// -------------------------------
+// Struk digital (email via outbox)
+// -------------------------------
+
+// jeda retry setelah percobaan ke-n gagal
+var emailRetryBackoff = []time.Duration{time.Minute, 5 * time.Minute, 15 * time.Minute, time.Hour, 3 * time.Hour}
+
+// POST /pos/transactions/:uuid/send-receipt body: { "email": "", "customer_uuid": "" }
+// email di-render sekarang lalu masuk outbox; pengiriman & retry oleh worker
+func (s *ReceiptServiceImpl) SendEmail(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.SendReceiptRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	uuid := strings.TrimSpace(ctx.Param("uuid"))
+	trx, err := s.trxRepo.Detail(uuid)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("transaction not found"))
+		return
+	}
+	branch, err := s.branchRepo.DetailClientBranch(trx.BranchUUID)
+	if err != nil || branch.ClientUUID != profile.Client.UUID {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("transaction not found"))
+		return
+	}
+
+	// recipient: email eksplisit, kalau kosong ambil dari profil customer
+	customerUUID := strings.TrimSpace(req.CustomerUUID)
+	if customerUUID == "" {
+		customerUUID = trx.CustomerUUID
+	}
+	to := strings.TrimSpace(req.Email)
+	if customerUUID != "" {
+		c, err := s.customerRepo.DetailCustomer(customerUUID)
+		if err != nil || c.ClientUUID != profile.Client.UUID {
+			helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("customer not found"))
+			return
+		}
+		if to == "" {
+			to = strings.TrimSpace(c.Email)
+		}
+	}
+	if to == "" {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("email required (customer has no email)"))
+		return
+	}
+	addr, err := mail.ParseAddress(to)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("invalid email"))
+		return
+	}
+	to = addr.Address
+
+	tpl, err := s.templateRepo.Get(profile.Client.UUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load template", http.StatusInternalServerError, err)
+		return
+	}
+	receipt := buildReceipt(trx, profile.Client, branch, tpl, true)
+
+	body, err := document.RenderReceiptHTML(receipt)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to render receipt", http.StatusInternalServerError, err)
+		return
+	}
+	pdf, err := document.RenderReceiptPDF(receipt)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to render receipt", http.StatusInternalServerError, err)
+		return
+	}
+
+	out, err := s.outboxRepo.Insert(&dao.EmailOutbox{
+		ClientUUID:   profile.Client.UUID,
+		BranchUUID:   trx.BranchUUID,
+		Type:         dao.EmailTypeReceipt,
+		Ref:          trx.UUID,
+		CustomerUUID: customerUUID,
+		To:           to,
+		Subject:      fmt.Sprintf("Struk Belanja %s - %s", profile.Client.Name, trx.ReceiptNo),
+		Body:         body,
+		Attachments: []dao.EmailOutboxAttachment{{
+			Name:        "struk-" + trx.ReceiptNo + ".pdf",
+			ContentType: "application/pdf",
+			Data:        pdf,
+		}},
+		MaxAttempts: len(emailRetryBackoff) + 1,
+		CreatedBy:   profile.UUID,
+	})
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to queue email", http.StatusInternalServerError, err)
+		return
+	}
+	s.syncReceiptDelivery(out)
+
+	// kirim secepatnya tanpa menunggu tick worker
+	go func() {
+		if _, err := s.ProcessEmailOutbox(); err != nil {
+			log.Printf("email outbox: %v", err)
+		}
+	}()
+
+	out.Body = ""
+	helpers.JsonOK(ctx, "success", out)
+}
+
+// ProcessEmailOutbox: kirim email yang jatuh waktu, max 50 per run
+func (s *ReceiptServiceImpl) ProcessEmailOutbox() (int, error) {
+	sent := 0
+	for i := 0; i < 50; i++ {
+		now := time.Now()
+		o, err := s.outboxRepo.ClaimNext(now.Unix(), now.Add(-10*time.Minute).Unix())
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return sent, nil
+			}
+			return sent, err
+		}
+
+		atts := make([]helpers.EmailAttachment, 0, len(o.Attachments))
+		for _, a := range o.Attachments {
+			atts = append(atts, helpers.EmailAttachment{Name: a.Name, ContentType: a.ContentType, Data: a.Data})
+		}
+
+		if err := helpers.SendEmailWithAttachments(o.To, o.Subject, o.Body, atts); err != nil {
+			failed := o.Attempts >= o.MaxAttempts
+			next := now.Unix()
+			if !failed {
+				next = now.Add(emailRetryBackoff[min(o.Attempts, len(emailRetryBackoff))-1]).Unix()
+			}
+			updated, uerr := s.outboxRepo.MarkRetry(o.UUID, err.Error(), next, failed)
+			if uerr != nil {
+				return sent, uerr
+			}
+			s.syncReceiptDelivery(updated)
+			continue
+		}
+
+		updated, err := s.outboxRepo.MarkSent(o.UUID)
+		if err != nil {
+			return sent, err
+		}
+		s.syncReceiptDelivery(updated)
+		sent++
+	}
+	return sent, nil
+}
+
+// syncReceiptDelivery: status terakhir disalin ke transaksi
+func (s *ReceiptServiceImpl) syncReceiptDelivery(o dao.EmailOutbox) {
+	if o.Type != dao.EmailTypeReceipt || o.Ref == "" {
+		return
+	}
+	_ = s.trxRepo.SetReceiptEmail(o.Ref, dao.ReceiptEmailDelivery{
+		OutboxUUID:   o.UUID,
+		To:           o.To,
+		CustomerUUID: o.CustomerUUID,
+		Status:       o.Status,
+		Attempts:     o.Attempts,
+		LastError:    o.LastError,
+		SentAt:       o.SentAt,
+	})
+}
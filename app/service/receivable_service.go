@@ -0,0 +1,362 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+	"harjonan.id/user-service/app/repository"
+)
+
+type ReceivableService interface {
+	List(ctx *gin.Context)
+	Detail(ctx *gin.Context)
+	Pay(ctx *gin.Context)
+	PayCustomer(ctx *gin.Context)
+	Aging(ctx *gin.Context)
+	OutstandingByBranch(ctx *gin.Context)
+
+	// dipanggil worker (bukan endpoint)
+	SendDueReminders() (int, error)
+}
+
+type ReceivableServiceImpl struct {
+	repo         repository.ReceivableRepository
+	customerRepo repository.CustomerRepository
+	authRepo     repository.AuthRepository
+	notifRepo    repository.NotificationRepository
+}
+
+func NewReceivableService(repo repository.ReceivableRepository, customerRepo repository.CustomerRepository, authRepo repository.AuthRepository, notifRepo repository.NotificationRepository) *ReceivableServiceImpl {
+	return &ReceivableServiceImpl{repo: repo, customerRepo: customerRepo, authRepo: authRepo, notifRepo: notifRepo}
+}
+
+func (s *ReceivableServiceImpl) List(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.FilterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if req.FilterBy == nil {
+		req.FilterBy = map[string]any{}
+	}
+	req.FilterBy["client_uuid"] = profile.Client.UUID
+
+	data, err := s.repo.List(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to list receivable", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", data)
+}
+
+func (s *ReceivableServiceImpl) Detail(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	uuid := strings.TrimSpace(ctx.Param("uuid"))
+	rc, err := s.repo.Detail(uuid)
+	if err != nil || rc.ClientUUID != profile.Client.UUID {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("receivable not found"))
+		return
+	}
+	helpers.JsonOK(ctx, "success", rc)
+}
+
+func normalizePayRequest(req *dto.ReceivablePayRequest) error {
+	req.Method = strings.TrimSpace(strings.ToUpper(req.Method))
+	if req.Method == "" {
+		req.Method = "CASH"
+	}
+	switch req.Method {
+	case "CASH", "TRANSFER", "QRIS":
+	default:
+		return errors.New("method must be CASH/TRANSFER/QRIS")
+	}
+	if req.Amount <= 0 {
+		return errors.New("amount must be > 0")
+	}
+	return nil
+}
+
+// POST /receivables/:uuid/pay body: { "amount": 50000, "method": "CASH", "note": "" }
+func (s *ReceivableServiceImpl) Pay(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.ReceivablePayRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if err := normalizePayRequest(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+
+	uuid := strings.TrimSpace(ctx.Param("uuid"))
+	rc, err := s.repo.Detail(uuid)
+	if err != nil || rc.ClientUUID != profile.Client.UUID {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("receivable not found"))
+		return
+	}
+
+	out, err := s.repo.AddPayment(uuid, dao.ReceivablePayment{
+		Amount:    req.Amount,
+		Method:    req.Method,
+		Note:      req.Note,
+		CreatedBy: profile.UUID,
+	})
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to pay receivable", http.StatusBadRequest, err)
+		return
+	}
+	_, _ = s.customerRepo.AdjustCredit(out.CustomerUUID, -req.Amount)
+
+	s.createPaymentNotif(out, req.Amount)
+	helpers.JsonOK(ctx, "success", out)
+}
+
+// POST /receivables/customer/:uuid/pay
+// bayar sekaligus, dialokasikan ke piutang paling lama dulu
+func (s *ReceivableServiceImpl) PayCustomer(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.ReceivablePayRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if err := normalizePayRequest(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+
+	customerUUID := strings.TrimSpace(ctx.Param("uuid"))
+	c, err := s.customerRepo.DetailCustomer(customerUUID)
+	if err != nil || c.ClientUUID != profile.Client.UUID {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("customer not found"))
+		return
+	}
+
+	open, err := s.repo.ListOpen(bson.M{"customer_uuid": c.UUID})
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load receivable", http.StatusInternalServerError, err)
+		return
+	}
+	var totalOutstanding float64
+	for _, rc := range open {
+		totalOutstanding += rc.Outstanding
+	}
+	if req.Amount > totalOutstanding+0.000001 {
+		helpers.JsonErr[any](ctx, "amount exceeds outstanding", http.StatusBadRequest, fmt.Errorf("outstanding %s", newIDRCurrency(totalOutstanding)))
+		return
+	}
+
+	remaining := req.Amount
+	applied := make([]dao.Receivable, 0)
+	for _, rc := range open {
+		if remaining <= 0 {
+			break
+		}
+		amt := math.Min(remaining, rc.Outstanding)
+		out, err := s.repo.AddPayment(rc.UUID, dao.ReceivablePayment{
+			Amount:    amt,
+			Method:    req.Method,
+			Note:      req.Note,
+			CreatedBy: profile.UUID,
+		})
+		if err != nil {
+			// kemungkinan race (dibayar paralel), lanjut ke piutang berikutnya
+			continue
+		}
+		_, _ = s.customerRepo.AdjustCredit(c.UUID, -amt)
+		remaining -= amt
+		applied = append(applied, out)
+		s.createPaymentNotif(out, amt)
+	}
+
+	helpers.JsonOK(ctx, "success", gin.H{
+		"paid":        req.Amount - remaining,
+		"unallocated": remaining,
+		"receivables": applied,
+	})
+}
+
+// POST /receivables/aging body: { "branch_uuid": "", "customer_uuid": "" }
+// umur dihitung dari tanggal transaksi: 0-30, 31-60, 60+ hari
+func (s *ReceivableServiceImpl) Aging(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.ReceivableAgingRequest
+	_ = ctx.ShouldBindJSON(&req)
+
+	filter := bson.M{"client_uuid": profile.Client.UUID}
+	if b := strings.TrimSpace(req.BranchUUID); b != "" {
+		filter["branch_uuid"] = b
+	}
+	if c := strings.TrimSpace(req.CustomerUUID); c != "" {
+		filter["customer_uuid"] = c
+	}
+
+	open, err := s.repo.ListOpen(filter)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load receivable", http.StatusInternalServerError, err)
+		return
+	}
+
+	helpers.JsonOK(ctx, "success", receivableAging(open, time.Now()))
+}
+
+// receivableAging: umur piutang dihitung dari created_at, dibulatkan ke bawah per hari penuh.
+// 0-30 hari, 31-60 hari, lebih dari 60 hari. Customer diurutkan dari total terbesar.
+func receivableAging(open []dao.Receivable, now time.Time) dto.ReceivableAgingResponse {
+	rows := map[string]*dto.ReceivableAgingRow{}
+	res := dto.ReceivableAgingResponse{}
+
+	for _, rc := range open {
+		row, ok := rows[rc.CustomerUUID]
+		if !ok {
+			row = &dto.ReceivableAgingRow{CustomerUUID: rc.CustomerUUID, CustomerName: rc.CustomerName}
+			rows[rc.CustomerUUID] = row
+		}
+
+		days := int(now.Sub(rc.CreatedAt).Hours() / 24)
+		switch {
+		case days <= 30:
+			row.Bucket0To30 += rc.Outstanding
+			res.Bucket0To30 += rc.Outstanding
+		case days <= 60:
+			row.Bucket31To60 += rc.Outstanding
+			res.Bucket31To60 += rc.Outstanding
+		default:
+			row.Bucket60Plus += rc.Outstanding
+			res.Bucket60Plus += rc.Outstanding
+		}
+		row.Total += rc.Outstanding
+		res.Total += rc.Outstanding
+	}
+
+	res.Customers = make([]dto.ReceivableAgingRow, 0, len(rows))
+	for _, row := range rows {
+		res.Customers = append(res.Customers, *row)
+	}
+	sort.Slice(res.Customers, func(i, j int) bool { return res.Customers[i].Total > res.Customers[j].Total })
+
+	return res
+}
+
+// GET /receivables/outstanding : total piutang berjalan per branch
+func (s *ReceivableServiceImpl) OutstandingByBranch(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	data, err := s.repo.OutstandingByBranch(profile.Client.UUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load outstanding", http.StatusInternalServerError, err)
+		return
+	}
+	var total float64
+	for _, d := range data {
+		total += d.Outstanding
+	}
+	helpers.JsonOK(ctx, "success", gin.H{
+		"total":    total,
+		"branches": data,
+	})
+}
+
+// -------------------------------
+// Reminder jatuh tempo (worker)
+// -------------------------------
+
+// SendDueReminders: piutang yang jatuh tempo <= besok, max 1 reminder per hari per piutang.
+func (s *ReceivableServiceImpl) SendDueReminders() (int, error) {
+	now := time.Now()
+	dueBefore := now.Add(24 * time.Hour).Unix()
+	remindedBefore := now.Add(-20 * time.Hour).Unix()
+
+	list, err := s.repo.ListDueForReminder(dueBefore, remindedBefore, 200)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, rc := range list {
+		title := "Kasbon Jatuh Tempo"
+		icon := "info"
+		if rc.DueDate < now.Unix() {
+			title = "Kasbon Lewat Jatuh Tempo"
+			icon = "warning"
+		}
+		msg := fmt.Sprintf("%s • %s • sisa %s • jatuh tempo %s",
+			rc.CustomerName,
+			rc.ReceiptNo,
+			newIDRCurrency(rc.Outstanding),
+			helpers.FormatPOSUnix(rc.DueDate),
+		)
+
+		_, err := s.notifRepo.Insert(&dao.Notification{
+			ClientUUID: rc.ClientUUID,
+			BranchUUID: rc.BranchUUID,
+			Title:      title,
+			Message:    msg,
+			Icon:       icon,
+			Type:       "RECEIVABLE",
+			Ref:        rc.UUID,
+		})
+		if err != nil {
+			log.Printf("receivable reminder %s: %v", rc.UUID, err)
+			continue
+		}
+		_ = s.repo.MarkReminded(rc.UUID, now.Unix())
+		sent++
+	}
+	return sent, nil
+}
+
+func (s *ReceivableServiceImpl) createPaymentNotif(rc dao.Receivable, amount float64) {
+	title := "Pembayaran Kasbon"
+	msg := fmt.Sprintf("%s • %s • bayar %s • sisa %s",
+		rc.CustomerName,
+		rc.ReceiptNo,
+		newIDRCurrency(amount),
+		newIDRCurrency(rc.Outstanding),
+	)
+	_, _ = s.notifRepo.Insert(&dao.Notification{
+		ClientUUID: rc.ClientUUID,
+		BranchUUID: rc.BranchUUID,
+		Title:      title,
+		Message:    msg,
+		Icon:       "success",
+		Type:       "RECEIVABLE",
+		Ref:        rc.UUID,
+	})
+}
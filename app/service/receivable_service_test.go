@@ -0,0 +1,75 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"harjonan.id/user-service/app/domain/dao"
+)
+
+func TestReceivableAgingBuckets(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	cases := []struct {
+		name string
+		age  time.Duration
+		want int // 0 = 0-30, 1 = 31-60, 2 = 60+
+	}{
+		{"today", 0, 0},
+		{"day 30", 30 * day, 0},
+		{"day 30 almost 31", 31*day - time.Minute, 0},
+		{"day 31", 31 * day, 1},
+		{"day 60", 60 * day, 1},
+		{"day 60 almost 61", 61*day - time.Minute, 1},
+		{"day 61", 61 * day, 2},
+		{"day 365", 365 * day, 2},
+		{"created in future", -day, 0},
+	}
+	for _, c := range cases {
+		rc := dao.Receivable{CustomerUUID: "C1", Outstanding: 100}
+		rc.CreatedAt = now.Add(-c.age)
+		res := receivableAging([]dao.Receivable{rc}, now)
+
+		got := [3]float64{res.Bucket0To30, res.Bucket31To60, res.Bucket60Plus}
+		var want [3]float64
+		want[c.want] = 100
+		if got != want || res.Total != 100 {
+			t.Errorf("%s: want buckets %v, got %v (total %v)", c.name, want, got, res.Total)
+		}
+		if len(res.Customers) != 1 || res.Customers[0].Bucket0To30 != got[0] || res.Customers[0].Bucket31To60 != got[1] || res.Customers[0].Bucket60Plus != got[2] {
+			t.Errorf("%s: customer row does not match totals, got %+v", c.name, res.Customers)
+		}
+	}
+}
+
+func TestReceivableAgingCustomers(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	open := []dao.Receivable{
+		{CustomerUUID: "A", CustomerName: "Toko A", Outstanding: 100},
+		{CustomerUUID: "B", CustomerName: "Toko B", Outstanding: 500},
+		{CustomerUUID: "A", CustomerName: "Toko A", Outstanding: 300},
+	}
+	open[0].CreatedAt = now.AddDate(0, 0, -10)
+	open[1].CreatedAt = now.AddDate(0, 0, -45)
+	open[2].CreatedAt = now.AddDate(0, 0, -90)
+
+	res := receivableAging(open, now)
+	if res.Bucket0To30 != 100 || res.Bucket31To60 != 500 || res.Bucket60Plus != 300 || res.Total != 900 {
+		t.Errorf("want 100/500/300 total 900, got %v/%v/%v total %v", res.Bucket0To30, res.Bucket31To60, res.Bucket60Plus, res.Total)
+	}
+	if len(res.Customers) != 2 {
+		t.Fatalf("want 2 customers, got %d", len(res.Customers))
+	}
+	// urut total terbesar: B 500, A 400
+	if res.Customers[0].CustomerUUID != "B" || res.Customers[1].CustomerUUID != "A" || res.Customers[1].Total != 400 {
+		t.Errorf("want B(500) then A(400), got %+v", res.Customers)
+	}
+	if a := res.Customers[1]; a.Bucket0To30 != 100 || a.Bucket60Plus != 300 {
+		t.Errorf("customer A: want 100 in 0-30 and 300 in 60+, got %+v", a)
+	}
+
+	if empty := receivableAging(nil, now); empty.Customers == nil || empty.Total != 0 {
+		t.Errorf("no receivable: want empty customer list, got %+v", empty)
+	}
+}
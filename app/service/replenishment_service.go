@@ -0,0 +1,529 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+	"harjonan.id/user-service/app/repository"
+)
+
+type ReplenishmentService interface {
+	SetLevels(ctx *gin.Context)
+	Generate(ctx *gin.Context)
+	List(ctx *gin.Context)
+	Detail(ctx *gin.Context)
+	Approve(ctx *gin.Context)
+	Discard(ctx *gin.Context)
+
+	// NotifyLowStock: dipanggil worker, 1 notifikasi per produk sampai stock naik lagi
+	NotifyLowStock() (int, error)
+}
+
+type ReplenishmentServiceImpl struct {
+	repo          repository.ReplenishmentRepository
+	prodRepo      repository.ProductRepository
+	transferRepo  repository.StockTransferRepository
+	poRepo        repository.PurchaseOrderRepository
+	supplierRepo  repository.SupplierRepository
+	dashboardRepo repository.DashboardRepository
+	branchRepo    repository.ClientBranchRepository
+	authRepo      repository.AuthRepository
+	notifRepo     repository.NotificationRepository
+}
+
+func NewReplenishmentService(repo repository.ReplenishmentRepository, prodRepo repository.ProductRepository, transferRepo repository.StockTransferRepository, poRepo repository.PurchaseOrderRepository, supplierRepo repository.SupplierRepository, dashboardRepo repository.DashboardRepository, branchRepo repository.ClientBranchRepository, authRepo repository.AuthRepository, notifRepo repository.NotificationRepository) *ReplenishmentServiceImpl {
+	return &ReplenishmentServiceImpl{repo: repo, prodRepo: prodRepo, transferRepo: transferRepo, poRepo: poRepo, supplierRepo: supplierRepo, dashboardRepo: dashboardRepo, branchRepo: branchRepo, authRepo: authRepo, notifRepo: notifRepo}
+}
+
+// POST /replenishment/levels body: { "items": [{ "product_uuid": "", "min_stock": 10, "max_stock": 50 }] }
+func (s *ReplenishmentServiceImpl) SetLevels(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.StockLevelRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if len(req.Items) == 0 {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("items required"))
+		return
+	}
+
+	checked := map[string]bool{}
+	out := make([]dao.Product, 0, len(req.Items))
+	for _, it := range req.Items {
+		p, err := s.prodRepo.DetailProduct(strings.TrimSpace(it.ProductUUID))
+		if err != nil {
+			helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, fmt.Errorf("product %s not found", it.ProductUUID))
+			return
+		}
+		if !checked[p.BranchUUID] {
+			if !requireBranchAccess(ctx, s.branchRepo, profile, p.BranchUUID) {
+				return
+			}
+			checked[p.BranchUUID] = true
+		}
+		saved, err := s.prodRepo.SetStockLevels(p.UUID, it.MinStock, it.MaxStock)
+		if err != nil {
+			helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, fmt.Errorf("%s: %w", p.Name, err))
+			return
+		}
+		out = append(out, saved)
+	}
+	helpers.JsonOK(ctx, "success", out)
+}
+
+// POST /replenishment/generate body: { "branch_uuid": "", "velocity_days": 28, "lead_days": 7, "cover_days": 14 }
+// usulan (DRAFT) untuk produk yang stock-nya <= reorder point; DRAFT lama di branch yang sama dibuang
+func (s *ReplenishmentServiceImpl) Generate(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.ReplenishmentGenerateRequest
+	_ = ctx.ShouldBindJSON(&req)
+	branchUUID := strings.TrimSpace(req.BranchUUID)
+	if branchUUID == "" {
+		branchUUID = profile.Branch.UUID
+	}
+	if !requireBranchAccess(ctx, s.branchRepo, profile, branchUUID) {
+		return
+	}
+	if req.VelocityDays <= 0 {
+		req.VelocityDays = 28
+	}
+	if req.LeadDays <= 0 {
+		req.LeadDays = 7
+	}
+	if req.CoverDays <= 0 {
+		req.CoverDays = 14
+	}
+
+	products, err := s.repo.ActiveProducts(branchUUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load products", http.StatusInternalServerError, err)
+		return
+	}
+	sold, err := s.repo.SalesVelocity(branchUUID, time.Now().AddDate(0, 0, -req.VelocityDays))
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load sales velocity", http.StatusInternalServerError, err)
+		return
+	}
+	// gudang tidak ketemu => semua usulan jadi PO
+	gudangUUID, _ := s.dashboardRepo.FindGudangBranchUUID(profile.Client.UUID)
+
+	plan := dao.ReplenishmentPlan{
+		ClientUUID:   profile.Client.UUID,
+		BranchUUID:   branchUUID,
+		VelocityDays: req.VelocityDays,
+		LeadDays:     req.LeadDays,
+		CoverDays:    req.CoverDays,
+		Lines:        []dao.ReplenishmentLine{},
+		CreatedBy:    profile.UUID,
+	}
+	suppliers := map[string]dao.Supplier{}
+
+	for _, p := range products {
+		velocity := float64(sold[p.UUID]) / float64(req.VelocityDays)
+		reorderPoint := p.MinStock
+		if reorderPoint <= 0 {
+			reorderPoint = int64(math.Ceil(velocity * float64(req.LeadDays)))
+		}
+		if reorderPoint <= 0 || p.Stock > reorderPoint {
+			continue
+		}
+		target := p.MaxStock
+		if target <= 0 {
+			target = reorderPoint + int64(math.Ceil(velocity*float64(req.CoverDays)))
+		}
+		need := target - max(p.Stock, 0)
+		if need <= 0 {
+			continue
+		}
+
+		base := dao.ReplenishmentLine{
+			ProductUUID:   p.UUID,
+			SKU:           p.SKU,
+			Name:          p.Name,
+			BaseUnit:      p.BaseUnit,
+			Stock:         p.Stock,
+			MinStock:      p.MinStock,
+			MaxStock:      p.MaxStock,
+			SoldQty:       sold[p.UUID],
+			DailyVelocity: math.Round(velocity*100) / 100,
+			ReorderPoint:  reorderPoint,
+			TargetStock:   target,
+		}
+
+		// ambil dari gudang dulu (di luar min stock gudang), sisanya PO
+		if gudangUUID != "" && gudangUUID != branchUUID && p.SKU != "" {
+			if gp, err := s.repo.FindProductBySKU(gudangUUID, p.SKU); err == nil {
+				if avail := gp.Stock - max(gp.MinStock, 0); avail > 0 {
+					line := base
+					line.Source = dao.ReplenishFromTransfer
+					line.Qty = min(need, avail)
+					line.FromBranchUUID = gudangUUID
+					line.FromProductUUID = gp.UUID
+					line.FromAvailable = avail
+					plan.Lines = append(plan.Lines, line)
+					need -= line.Qty
+				}
+			}
+		}
+		if need <= 0 {
+			continue
+		}
+
+		line := base
+		line.Source = dao.ReplenishFromPurchase
+		line.Qty = need
+		line.CostPerBase = p.Cost
+		if p.SKU != "" {
+			if last, err := s.supplierRepo.LastPriceBySKU(profile.Client.UUID, p.SKU); err == nil {
+				sup, cached := suppliers[last.SupplierUUID]
+				if !cached {
+					sup, _ = s.supplierRepo.DetailSupplier(last.SupplierUUID)
+					suppliers[last.SupplierUUID] = sup
+				}
+				if sup.IsActive {
+					line.SupplierUUID = sup.UUID
+					line.SupplierName = sup.Name
+					line.CostPerBase = last.CostPerBase
+				}
+			}
+		}
+		plan.Lines = append(plan.Lines, line)
+	}
+
+	if len(plan.Lines) == 0 {
+		helpers.JsonOK(ctx, "nothing to replenish", plan)
+		return
+	}
+
+	if err := s.repo.DiscardDrafts(branchUUID, profile.UUID); err != nil {
+		helpers.JsonErr[any](ctx, "failed to discard old plan", http.StatusInternalServerError, err)
+		return
+	}
+	out, err := s.repo.Insert(&plan)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to save replenishment plan", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", out)
+}
+
+func (s *ReplenishmentServiceImpl) List(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.FilterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if req.FilterBy == nil {
+		req.FilterBy = map[string]any{}
+	}
+	req.FilterBy["client_uuid"] = profile.Client.UUID
+	if !isOwnerRole(profile.Role.Value) {
+		req.FilterBy["branch_uuid"] = profile.Branch.UUID
+	}
+
+	data, err := s.repo.List(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to list replenishment plan", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", data)
+}
+
+func (s *ReplenishmentServiceImpl) Detail(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	plan, ok := s.load(ctx, profile)
+	if !ok {
+		return
+	}
+	helpers.JsonOK(ctx, "success", plan)
+}
+
+// POST /replenishment/:uuid/approve (OWNER) body:
+// { "driver_uuid": "", "note": "", "lines": [{ "product_uuid": "", "source": "PURCHASE", "qty": 24, "supplier_uuid": "" }] }
+// sekali panggil: line TRANSFER => 1 stock transfer dari gudang (PENDING_WAREHOUSE), line PURCHASE => 1 PO per supplier (SENT)
+func (s *ReplenishmentServiceImpl) Approve(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	if !isOwnerRole(profile.Role.Value) {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("only OWNER can approve replenishment"))
+		return
+	}
+
+	var req dto.ReplenishmentApproveRequest
+	_ = ctx.ShouldBindJSON(&req)
+
+	plan, ok := s.load(ctx, profile)
+	if !ok {
+		return
+	}
+	if plan.Status != dao.ReplenishmentDraft {
+		helpers.JsonErr[any](ctx, "invalid status", http.StatusConflict, errors.New("replenishment plan is "+string(plan.Status)))
+		return
+	}
+
+	lines := make([]dao.ReplenishmentLine, len(plan.Lines))
+	copy(lines, plan.Lines)
+	for _, o := range req.Lines {
+		found := false
+		for i := range lines {
+			if lines[i].ProductUUID != o.ProductUUID || (o.Source != "" && string(lines[i].Source) != strings.ToUpper(o.Source)) {
+				continue
+			}
+			found = true
+			if o.Qty != nil {
+				if *o.Qty < 0 || (lines[i].Source == dao.ReplenishFromTransfer && *o.Qty > lines[i].FromAvailable) {
+					helpers.JsonErr[any](ctx, "invalid qty", http.StatusBadRequest, fmt.Errorf("%s: qty must be 0..%d", lines[i].Name, lines[i].FromAvailable))
+					return
+				}
+				lines[i].Qty = *o.Qty
+			}
+			if o.SupplierUUID != "" && lines[i].Source == dao.ReplenishFromPurchase {
+				sup, err := s.supplierRepo.DetailSupplier(o.SupplierUUID)
+				if err != nil || sup.ClientUUID != profile.Client.UUID {
+					helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("supplier not found"))
+					return
+				}
+				lines[i].SupplierUUID = sup.UUID
+				lines[i].SupplierName = sup.Name
+				if last, err := s.supplierRepo.LastPrice(sup.UUID, lines[i].ProductUUID); err == nil {
+					lines[i].CostPerBase = last.CostPerBase
+				}
+			}
+		}
+		if !found {
+			helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, fmt.Errorf("product %s not in plan", o.ProductUUID))
+			return
+		}
+	}
+
+	transfers := map[string][]dao.ReplenishmentLine{}
+	purchases := map[string][]dao.ReplenishmentLine{}
+	supplierOrder := []string{}
+	var skipped []string
+	for _, l := range lines {
+		switch {
+		case l.Qty <= 0:
+			continue
+		case l.Source == dao.ReplenishFromTransfer:
+			transfers[l.FromBranchUUID] = append(transfers[l.FromBranchUUID], l)
+		case l.SupplierUUID == "":
+			skipped = append(skipped, l.Name+": supplier unknown")
+		default:
+			if _, seen := purchases[l.SupplierUUID]; !seen {
+				supplierOrder = append(supplierOrder, l.SupplierUUID)
+			}
+			purchases[l.SupplierUUID] = append(purchases[l.SupplierUUID], l)
+		}
+	}
+	if len(transfers) > 0 && strings.TrimSpace(req.DriverUUID) == "" {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("driver_uuid required for transfer lines"))
+		return
+	}
+	if len(transfers) == 0 && len(purchases) == 0 {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("no line to approve"))
+		return
+	}
+
+	// claim dulu supaya approve dobel tidak bikin dokumen dobel
+	now := time.Now()
+	if _, err := s.repo.SetStatus(plan.UUID, dao.ReplenishmentDraft, dao.ReplenishmentApproved, bson.M{
+		"approved_by": profile.UUID,
+		"approved_at": now.Unix(),
+		"lines":       lines,
+	}); err != nil {
+		helpers.JsonErr[any](ctx, "failed to approve replenishment plan", http.StatusConflict, err)
+		return
+	}
+
+	note := plan.Code
+	if n := strings.TrimSpace(req.Note); n != "" {
+		note += " • " + n
+	}
+
+	var transferUUIDs, poUUIDs []string
+	for fromBranch, ls := range transfers {
+		tr := dao.StockTransfer{
+			FromBranchUUID: fromBranch,
+			ToBranchUUID:   plan.BranchUUID,
+			DriverUUID:     strings.TrimSpace(req.DriverUUID),
+			RequesterNote:  note,
+			RequestedBy:    profile.UUID,
+		}
+		for _, l := range ls {
+			tr.Items = append(tr.Items, dao.StockTransferItem{ProductUUID: l.FromProductUUID, Qty: l.Qty})
+		}
+		out, err := s.transferRepo.CreateDraft(&tr, transferActor(profile))
+		if err != nil {
+			skipped = append(skipped, "stock transfer: "+err.Error())
+			continue
+		}
+		transferUUIDs = append(transferUUIDs, out.UUID)
+	}
+
+	for n, supplierUUID := range supplierOrder {
+		ls := purchases[supplierUUID]
+		po := dao.PurchaseOrder{
+			ClientUUID:   plan.ClientUUID,
+			BranchUUID:   plan.BranchUUID,
+			SupplierUUID: supplierUUID,
+			SupplierName: ls[0].SupplierName,
+			PONo:         "PO-" + now.Format("20060102-150405") + "-" + strconv.Itoa(n+1),
+			Note:         note,
+			CreatedBy:    profile.UUID,
+		}
+		for _, l := range ls {
+			item := dao.PurchaseOrderItem{
+				ProductUUID:      l.ProductUUID,
+				SKU:              l.SKU,
+				Name:             l.Name,
+				BaseUnit:         l.BaseUnit,
+				Unit:             l.BaseUnit,
+				ConversionToBase: 1,
+				Qty:              float64(l.Qty),
+				QtyBase:          l.Qty,
+				UnitCost:         l.CostPerBase,
+				LineTotal:        float64(l.Qty) * l.CostPerBase,
+			}
+			po.Items = append(po.Items, item)
+			po.SubTotal += item.LineTotal
+		}
+		po.Total = po.SubTotal
+
+		saved, err := s.poRepo.Save(&po)
+		if err == nil {
+			saved, err = s.poRepo.SetStatus(saved.UUID, []dao.PurchaseOrderStatus{dao.PODraft}, dao.POSent, bson.M{
+				"sent_at": now.Unix(),
+				"sent_by": profile.UUID,
+			})
+		}
+		if err != nil {
+			skipped = append(skipped, po.SupplierName+": "+err.Error())
+			continue
+		}
+		poUUIDs = append(poUUIDs, saved.UUID)
+	}
+
+	out, err := s.repo.SetStatus(plan.UUID, dao.ReplenishmentApproved, dao.ReplenishmentApproved, bson.M{
+		"transfer_uuids": transferUUIDs,
+		"po_uuids":       poUUIDs,
+		"skipped":        skipped,
+	})
+	if err != nil {
+		helpers.JsonErr[any](ctx, "documents created but failed to update plan", http.StatusInternalServerError, err)
+		return
+	}
+
+	_, _ = s.notifRepo.Insert(&dao.Notification{
+		ClientUUID: out.ClientUUID,
+		BranchUUID: out.BranchUUID,
+		Title:      "Restock Disetujui",
+		Message:    fmt.Sprintf("%s • %d transfer gudang • %d PO • %d dilewati", out.Code, len(transferUUIDs), len(poUUIDs), len(skipped)),
+		Icon:       "success",
+		Type:       "REPLENISHMENT",
+		Ref:        out.UUID,
+	})
+	helpers.JsonOK(ctx, "success", out)
+}
+
+// POST /replenishment/:uuid/discard
+func (s *ReplenishmentServiceImpl) Discard(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	plan, ok := s.load(ctx, profile)
+	if !ok {
+		return
+	}
+	out, err := s.repo.SetStatus(plan.UUID, dao.ReplenishmentDraft, dao.ReplenishmentDiscarded, bson.M{"discarded_by": profile.UUID})
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to discard replenishment plan", http.StatusConflict, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", out)
+}
+
+func (s *ReplenishmentServiceImpl) NotifyLowStock() (int, error) {
+	if _, err := s.repo.ResetLowStockAlerts(defaultLowStockThreshold); err != nil {
+		return 0, err
+	}
+	products, err := s.repo.ListLowStockUnalerted(defaultLowStockThreshold, 500)
+	if err != nil {
+		return 0, err
+	}
+
+	clients := map[string]string{}
+	sent := 0
+	for _, p := range products {
+		clientUUID, cached := clients[p.BranchUUID]
+		if !cached {
+			if b, err := s.branchRepo.DetailClientBranch(p.BranchUUID); err == nil {
+				clientUUID = b.ClientUUID
+			}
+			clients[p.BranchUUID] = clientUUID
+		}
+		if clientUUID == "" {
+			continue
+		}
+
+		_, err := s.notifRepo.Insert(&dao.Notification{
+			ClientUUID: clientUUID,
+			BranchUUID: p.BranchUUID,
+			Title:      "Low Stock Alert",
+			Message:    p.Name + " stock tersisa " + strconv.FormatInt(p.Stock, 10),
+			Type:       "STOCK",
+			Icon:       "warning",
+			Ref:        p.UUID,
+		})
+		if err != nil {
+			return sent, err
+		}
+		_ = s.repo.SetLowStockAlert(p.UUID, true)
+		sent++
+	}
+	return sent, nil
+}
+
+func (s *ReplenishmentServiceImpl) load(ctx *gin.Context, profile *dto.UserProfile) (dao.ReplenishmentPlan, bool) {
+	uuid := strings.TrimSpace(ctx.Param("uuid"))
+	plan, err := s.repo.Detail(uuid)
+	if err != nil || plan.ClientUUID != profile.Client.UUID {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("replenishment plan not found"))
+		return dao.ReplenishmentPlan{}, false
+	}
+	if !isOwnerRole(profile.Role.Value) && plan.BranchUUID != profile.Branch.UUID {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("replenishment plan not found"))
+		return dao.ReplenishmentPlan{}, false
+	}
+	return plan, true
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+	"harjonan.id/user-service/app/repository"
+)
+
+type ReportService interface {
+	MarginByTransaction(ctx *gin.Context)
+	MarginByProduct(ctx *gin.Context)
+	MarginByBranch(ctx *gin.Context)
+	MarginByCategory(ctx *gin.Context)
+}
+
+type ReportServiceImpl struct {
+	repo          repository.ReportRepository
+	dashboardRepo repository.DashboardRepository
+	categoryRepo  repository.CategoryRepository
+	branchRepo    repository.ClientBranchRepository
+	authRepo      repository.AuthRepository
+}
+
+func NewReportService(repo repository.ReportRepository, dashboardRepo repository.DashboardRepository, categoryRepo repository.CategoryRepository, branchRepo repository.ClientBranchRepository, authRepo repository.AuthRepository) *ReportServiceImpl {
+	return &ReportServiceImpl{repo: repo, dashboardRepo: dashboardRepo, categoryRepo: categoryRepo, branchRepo: branchRepo, authRepo: authRepo}
+}
+
+type marginScope struct {
+	clientUUID  string
+	branchUUIDs []string
+	from, to    time.Time
+	limit       int64
+	parentUUID  string
+}
+
+// scope: branch_uuid diisi => 1 branch (cek akses), kosong => semua branch client (OWNER) / branch sendiri
+func (s *ReportServiceImpl) scope(ctx *gin.Context) (marginScope, bool) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return marginScope{}, false
+	}
+
+	var req dto.ReportRequest
+	_ = ctx.ShouldBindJSON(&req)
+
+	sc := marginScope{clientUUID: profile.Client.UUID, parentUUID: req.ParentUUID}
+	var err error
+	sc.from, sc.to, err = helpers.ParseDateRange(req.DateFrom, req.DateTo, 30)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("date_from/date_to must be YYYY-MM-DD"))
+		return marginScope{}, false
+	}
+	if !sc.to.After(sc.from) {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("date_to must be >= date_from"))
+		return marginScope{}, false
+	}
+
+	sc.limit = req.Limit
+	if sc.limit <= 0 || sc.limit > 500 {
+		sc.limit = 100
+	}
+
+	switch {
+	case req.BranchUUID != "":
+		if !requireBranchAccess(ctx, s.branchRepo, profile, req.BranchUUID) {
+			return marginScope{}, false
+		}
+		sc.branchUUIDs = []string{req.BranchUUID}
+	case isOwnerRole(profile.Role.Value):
+		sc.branchUUIDs, err = s.dashboardRepo.GetCompanyBranchUUIDs(profile.Client.UUID)
+		if err != nil {
+			helpers.JsonErr[any](ctx, "failed to load branches", http.StatusInternalServerError, err)
+			return marginScope{}, false
+		}
+	default:
+		sc.branchUUIDs = []string{profile.Branch.UUID}
+	}
+	return sc, true
+}
+
+func withMarginPct(row *dto.MarginRow) {
+	row.Revenue = math.Round(row.Revenue*100) / 100
+	row.COGS = math.Round(row.COGS*100) / 100
+	row.GrossProfit = math.Round((row.Revenue-row.COGS)*100) / 100
+	if row.Revenue > 0 {
+		row.MarginPct = math.Round(row.GrossProfit/row.Revenue*10000) / 100
+	}
+}
+
+// respond: total selalu dari seluruh trx di scope (bukan hanya baris yang tampil karena limit)
+func (s *ReportServiceImpl) respond(ctx *gin.Context, sc marginScope, rows []dto.MarginRow) {
+	branches, err := s.repo.MarginByBranch(sc.branchUUIDs, sc.from, sc.to)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load report", http.StatusInternalServerError, err)
+		return
+	}
+
+	total := dto.MarginRow{Key: "TOTAL", Label: "Total"}
+	for _, b := range branches {
+		total.Transactions += b.Transactions
+		total.QtyBase += b.QtyBase
+		total.Revenue += b.Revenue
+		total.COGS += b.COGS
+		total.MissingCostItems += b.MissingCostItems
+	}
+	withMarginPct(&total)
+
+	if rows == nil {
+		rows = branches
+	}
+	for i := range rows {
+		withMarginPct(&rows[i])
+	}
+
+	helpers.JsonOK(ctx, "success", dto.MarginReportResponse{
+		DateFrom: sc.from.Format("2006-01-02"),
+		DateTo:   sc.to.AddDate(0, 0, -1).Format("2006-01-02"),
+		Total:    total,
+		Rows:     rows,
+	})
+}
+
+// POST /reports/margin/transactions body: { "branch_uuid": "", "date_from": "2025-01-01", "date_to": "2025-01-31", "limit": 100 }
+func (s *ReportServiceImpl) MarginByTransaction(ctx *gin.Context) {
+	sc, ok := s.scope(ctx)
+	if !ok {
+		return
+	}
+	rows, err := s.repo.MarginByTransaction(sc.branchUUIDs, sc.from, sc.to, sc.limit)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load report", http.StatusInternalServerError, err)
+		return
+	}
+	s.respond(ctx, sc, rows)
+}
+
+// POST /reports/margin/products (urut gross profit terbesar)
+func (s *ReportServiceImpl) MarginByProduct(ctx *gin.Context) {
+	sc, ok := s.scope(ctx)
+	if !ok {
+		return
+	}
+	rows, err := s.repo.MarginByProduct(sc.branchUUIDs, sc.from, sc.to, sc.limit)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load report", http.StatusInternalServerError, err)
+		return
+	}
+	s.respond(ctx, sc, rows)
+}
+
+// POST /reports/margin/branches
+func (s *ReportServiceImpl) MarginByBranch(ctx *gin.Context) {
+	sc, ok := s.scope(ctx)
+	if !ok {
+		return
+	}
+	s.respond(ctx, sc, nil)
+}
+
+// POST /reports/margin/categories body: + "parent_uuid" (kosong = kategori root, isi = drill-down ke sub-kategori)
+func (s *ReportServiceImpl) MarginByCategory(ctx *gin.Context) {
+	sc, ok := s.scope(ctx)
+	if !ok {
+		return
+	}
+	rows, err := s.repo.MarginByCategory(sc.branchUUIDs, sc.from, sc.to)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load report", http.StatusInternalServerError, err)
+		return
+	}
+	cats, err := s.categoryRepo.List(sc.clientUUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load category", http.StatusInternalServerError, err)
+		return
+	}
+	s.respond(ctx, sc, rollUpByCategory(rows, cats, sc.parentUUID))
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/helpers"
+)
+
+// ---------------------------------------------
+// Label timbangan (EAN-13 prefix 20-29)
+// ---------------------------------------------
+
+type scaleLineResult struct {
+	Product   dao.Product
+	Qty       int64 // base unit
+	UnitPrice float64
+	LineTotal float64
+}
+
+// isScaleBarcode: EAN-13 dengan prefix 20..29 (GS1 restricted circulation)
+func isScaleBarcode(code string) bool {
+	return len(code) == 13 && helpers.IsNumeric(code) && code[0] == '2'
+}
+
+func validateBarcodeTemplate(t *dao.BarcodeTemplate) error {
+	t.Prefix = strings.TrimSpace(t.Prefix)
+	t.ValueType = dao.BarcodeValueType(strings.ToUpper(strings.TrimSpace(string(t.ValueType))))
+
+	if !helpers.IsNumeric(t.Prefix) || t.Prefix[0] != '2' {
+		return errors.New("prefix must be numeric and start with 2 (20-29)")
+	}
+	if len(t.Prefix) < 2 {
+		return errors.New("prefix must be at least 2 digit")
+	}
+	if t.PLULength <= 0 || t.ValueLength <= 0 {
+		return errors.New("plu_length & value_length must be > 0")
+	}
+	total := len(t.Prefix) + t.PLULength + t.ValueLength
+	if t.ValueCheckDigit {
+		total++
+	}
+	if total != 12 {
+		return fmt.Errorf("prefix + plu + value must be 12 digit (got %d)", total)
+	}
+	switch t.ValueType {
+	case dao.BarcodeValueWeight:
+		if t.QtyMultiplier <= 0 {
+			t.QtyMultiplier = 1
+		}
+	case dao.BarcodeValuePrice:
+		if t.PriceDecimals < 0 || t.PriceDecimals > 4 {
+			return errors.New("price_decimals must be 0..4")
+		}
+	default:
+		return errors.New("value_type must be WEIGHT/PRICE")
+	}
+	return nil
+}
+
+// matchBarcodeTemplate: prefix terpanjang yang cocok
+func matchBarcodeTemplate(code string, templates []dao.BarcodeTemplate) (dao.BarcodeTemplate, bool) {
+	var best dao.BarcodeTemplate
+	found := false
+	for _, t := range templates {
+		if strings.HasPrefix(code, t.Prefix) && (!found || len(t.Prefix) > len(best.Prefix)) {
+			best = t
+			found = true
+		}
+	}
+	return best, found
+}
+
+// decodeScaleBarcode: pecah label jadi PLU + value mentah
+func decodeScaleBarcode(code string, t dao.BarcodeTemplate) (string, int64, error) {
+	if !isScaleBarcode(code) {
+		return "", 0, errors.New("not a scale barcode")
+	}
+	if !helpers.ValidEAN(code) {
+		return "", 0, errors.New("invalid check digit")
+	}
+	if !strings.HasPrefix(code, t.Prefix) {
+		return "", 0, errors.New("prefix mismatch")
+	}
+
+	pos := len(t.Prefix)
+	plu := code[pos : pos+t.PLULength]
+	pos += t.PLULength
+	if t.ValueCheckDigit {
+		pos++
+	}
+	value, err := strconv.ParseInt(code[pos:pos+t.ValueLength], 10, 64)
+	if err != nil {
+		return "", 0, err
+	}
+	if value <= 0 {
+		return "", 0, errors.New("empty weight/price on label")
+	}
+	return plu, value, nil
+}
+
+// scaleLine: hitung qty (base unit), harga per base unit, dan line total dari value label.
+// WEIGHT => qty = value x multiplier, harga dari master.
+// PRICE  => line total = harga di label, qty dihitung balik dari harga master (min 1).
+func scaleLine(p dao.Product, t dao.BarcodeTemplate, value int64) (int64, float64, float64, error) {
+	switch t.ValueType {
+	case dao.BarcodeValueWeight:
+		mult := t.QtyMultiplier
+		if mult <= 0 {
+			mult = 1
+		}
+		qty := int64(math.Round(float64(value) * mult))
+		if qty <= 0 {
+			return 0, 0, 0, errors.New("weight too small")
+		}
+		return qty, p.Price, float64(qty) * p.Price, nil
+
+	case dao.BarcodeValuePrice:
+		line := float64(value) / math.Pow10(t.PriceDecimals)
+		qty := int64(1)
+		if p.Price > 0 {
+			qty = int64(math.Round(line / p.Price))
+			if qty < 1 {
+				qty = 1
+			}
+		}
+		return qty, line / float64(qty), line, nil
+	}
+	return 0, 0, 0, errors.New("unknown value_type")
+}
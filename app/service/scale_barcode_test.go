@@ -0,0 +1,94 @@
+package service
+
+import (
+	"testing"
+
+	"harjonan.id/user-service/app/domain/dao"
+)
+
+var (
+	weightTemplate = dao.BarcodeTemplate{Prefix: "20", PLULength: 5, ValueLength: 5, ValueType: dao.BarcodeValueWeight, QtyMultiplier: 1}
+	priceTemplate  = dao.BarcodeTemplate{Prefix: "21", PLULength: 5, ValueLength: 5, ValueType: dao.BarcodeValuePrice}
+	// value dengan check digit sendiri: 22 + PLU 4 + cd + value 5
+	checkedTemplate = dao.BarcodeTemplate{Prefix: "22", PLULength: 4, ValueLength: 5, ValueCheckDigit: true, ValueType: dao.BarcodeValueWeight, QtyMultiplier: 1}
+)
+
+func TestDecodeScaleBarcode(t *testing.T) {
+	cases := []struct {
+		name      string
+		code      string
+		tpl       dao.BarcodeTemplate
+		wantPLU   string
+		wantValue int64
+		wantErr   bool
+	}{
+		{"weight label", "2012345012509", weightTemplate, "12345", 1250, false},
+		{"price label", "2112345150000", priceTemplate, "12345", 15000, false},
+		{"value check digit skipped", "2212340125000", checkedTemplate, "1234", 12500, false},
+		{"wrong check digit", "2012345012508", weightTemplate, "", 0, true},
+		{"prefix mismatch", "2112345150000", weightTemplate, "", 0, true},
+		{"zero value", "2012345000001", weightTemplate, "", 0, true},
+		{"not restricted prefix", "4006381333931", weightTemplate, "", 0, true},
+		{"too short", "201234501250", weightTemplate, "", 0, true},
+	}
+	for _, c := range cases {
+		plu, value, err := decodeScaleBarcode(c.code, c.tpl)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("%s: want error, got plu %s value %d", c.name, plu, value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", c.name, err)
+			continue
+		}
+		if plu != c.wantPLU || value != c.wantValue {
+			t.Errorf("%s: want %s/%d, got %s/%d", c.name, c.wantPLU, c.wantValue, plu, value)
+		}
+	}
+}
+
+func TestScaleLine(t *testing.T) {
+	perGram := dao.Product{Name: "Daging", Price: 150} // Rp 150 / gram
+	perKg := weightTemplate
+	perKg.QtyMultiplier = 1000 // label kg (3 desimal = gram), base unit gram
+
+	cases := []struct {
+		name      string
+		p         dao.Product
+		tpl       dao.BarcodeTemplate
+		value     int64
+		wantQty   int64
+		wantPrice float64
+		wantLine  float64
+		wantErr   bool
+	}{
+		{"weight gram", perGram, weightTemplate, 1250, 1250, 150, 187500, false},
+		{"weight multiplier", perGram, perKg, 2, 2000, 150, 300000, false},
+		{"weight multiplier default 1", perGram, dao.BarcodeTemplate{ValueType: dao.BarcodeValueWeight}, 5, 5, 150, 750, false},
+		{"weight rounds to zero", perGram, dao.BarcodeTemplate{ValueType: dao.BarcodeValueWeight, QtyMultiplier: 0.1}, 4, 0, 0, 0, true},
+		// PRICE: total dari label, qty dihitung balik dari harga master
+		{"price label", perGram, priceTemplate, 15000, 100, 150, 15000, false},
+		{"price label decimals", dao.Product{Price: 10}, dao.BarcodeTemplate{ValueType: dao.BarcodeValuePrice, PriceDecimals: 2}, 12345, 12, 123.45 / 12, 123.45, false},
+		{"price below master price", perGram, priceTemplate, 100, 1, 100, 100, false},
+		{"price without master price", dao.Product{}, priceTemplate, 25000, 1, 25000, 25000, false},
+		{"unknown type", perGram, dao.BarcodeTemplate{ValueType: "COUNT"}, 1, 0, 0, 0, true},
+	}
+	for _, c := range cases {
+		qty, price, line, err := scaleLine(c.p, c.tpl, c.value)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("%s: want error, got qty %d", c.name, qty)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", c.name, err)
+			continue
+		}
+		if qty != c.wantQty || price != c.wantPrice || line != c.wantLine {
+			t.Errorf("%s: want %d x %v = %v, got %d x %v = %v", c.name, c.wantQty, c.wantPrice, c.wantLine, qty, price, line)
+		}
+	}
+}
@@ -0,0 +1,372 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+	"harjonan.id/user-service/app/repository"
+)
+
+type StockAdjustmentService interface {
+	Create(ctx *gin.Context)
+	List(ctx *gin.Context)
+	Detail(ctx *gin.Context)
+	Approve(ctx *gin.Context)
+	Reject(ctx *gin.Context)
+}
+
+type StockAdjustmentServiceImpl struct {
+	repo         repository.StockAdjustmentRepository
+	prodRepo     repository.ProductRepository
+	approvalRepo repository.ApprovalRepository
+	branchRepo   repository.ClientBranchRepository
+	authRepo     repository.AuthRepository
+	notifRepo    repository.NotificationRepository
+	lotRepo      repository.StockLotRepository
+}
+
+func NewStockAdjustmentService(repo repository.StockAdjustmentRepository, prodRepo repository.ProductRepository, approvalRepo repository.ApprovalRepository, branchRepo repository.ClientBranchRepository, authRepo repository.AuthRepository, notifRepo repository.NotificationRepository, lotRepo repository.StockLotRepository) *StockAdjustmentServiceImpl {
+	return &StockAdjustmentServiceImpl{repo: repo, prodRepo: prodRepo, approvalRepo: approvalRepo, branchRepo: branchRepo, authRepo: authRepo, notifRepo: notifRepo, lotRepo: lotRepo}
+}
+
+// POST /stock-adjustments/create body:
+// { "branch_uuid": "", "reason": "DAMAGED", "note": "", "items": [{ "product_uuid": "", "delta": -2 }] }
+// di bawah threshold policy (atau pelaku OWNER) langsung diterapkan, di atas itu PENDING sampai OWNER approve
+func (s *StockAdjustmentServiceImpl) Create(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.StockAdjustmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	reason := dao.StockAdjustmentReason(strings.TrimSpace(strings.ToUpper(req.Reason)))
+	if !reason.Valid() {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("reason must be DAMAGED/EXPIRED/LOST/FOUND/SAMPLE"))
+		return
+	}
+	if len(req.Items) == 0 {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("items required"))
+		return
+	}
+	branchUUID := strings.TrimSpace(req.BranchUUID)
+	if branchUUID == "" {
+		branchUUID = profile.Branch.UUID
+	}
+	if !requireBranchAccess(ctx, s.branchRepo, profile, branchUUID) {
+		return
+	}
+
+	adj := dao.StockAdjustment{
+		ClientUUID:      profile.Client.UUID,
+		BranchUUID:      branchUUID,
+		Reason:          reason,
+		Note:            strings.TrimSpace(req.Note),
+		Status:          dao.StockAdjustmentPending,
+		Items:           make([]dao.StockAdjustmentItem, 0, len(req.Items)),
+		RequestedBy:     profile.UUID,
+		RequestedByName: profile.Name,
+	}
+
+	seen := map[string]bool{}
+	for _, it := range req.Items {
+		productUUID := strings.TrimSpace(it.ProductUUID)
+		if productUUID == "" || seen[productUUID] {
+			helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("product_uuid required and must be unique"))
+			return
+		}
+		seen[productUUID] = true
+
+		if it.Delta == 0 || (it.Delta > 0) != reason.IsIncrease() {
+			sign := "< 0"
+			if reason.IsIncrease() {
+				sign = "> 0"
+			}
+			helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, fmt.Errorf("delta for %s must be %s", reason, sign))
+			return
+		}
+
+		p, err := s.prodRepo.DetailProduct(productUUID)
+		if err != nil || p.BranchUUID != branchUUID {
+			helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, fmt.Errorf("product %s not found in branch", productUUID))
+			return
+		}
+		var expiry int64
+		if d := strings.TrimSpace(it.ExpiryDate); d != "" {
+			if expiry, err = helpers.ParseDateWIB(d); err != nil {
+				helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("expiry_date must be YYYY-MM-DD"))
+				return
+			}
+		}
+
+		value := float64(it.Delta) * p.Cost
+		adj.Items = append(adj.Items, dao.StockAdjustmentItem{
+			ProductUUID: p.UUID,
+			SKU:         p.SKU,
+			Name:        p.Name,
+			BaseUnit:    p.BaseUnit,
+			Delta:       it.Delta,
+			Cost:        p.Cost,
+			Value:       value,
+			LotNo:       strings.TrimSpace(it.LotNo),
+			ExpiryDate:  expiry,
+		})
+		adj.TotalQty += int64(math.Abs(float64(it.Delta)))
+		adj.TotalValue += math.Abs(value)
+	}
+
+	policy, err := s.approvalRepo.GetPolicy(profile.Client.UUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load policy", http.StatusInternalServerError, err)
+		return
+	}
+	overQty := policy.AdjustmentMaxQty > 0 && adj.TotalQty > policy.AdjustmentMaxQty
+	overValue := policy.AdjustmentMaxValue > 0 && adj.TotalValue > policy.AdjustmentMaxValue
+	adj.ApprovalRequired = (overQty || overValue) && !isOwnerRole(profile.Role.Value)
+
+	saved, err := s.repo.Insert(&adj)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to save stock adjustment", http.StatusInternalServerError, err)
+		return
+	}
+
+	if saved.ApprovalRequired {
+		// BranchUUID kosong => hanya owner yang lihat
+		_, _ = s.notifRepo.Insert(&dao.Notification{
+			ClientUUID: saved.ClientUUID,
+			Title:      "Butuh Approval Adjustment Stock",
+			Message: fmt.Sprintf("%s • %s • %s • %d item • %d qty • %s",
+				saved.Code, saved.RequestedByName, saved.Reason, len(saved.Items), saved.TotalQty, newIDRCurrency(saved.TotalValue)),
+			Icon: "warning",
+			Type: "STOCK_ADJUSTMENT",
+			Ref:  saved.UUID,
+		})
+		helpers.JsonOK(ctx, "approval pending", saved)
+		return
+	}
+
+	out, err := s.apply(saved, profile.UUID, nil)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to apply stock adjustment", http.StatusConflict, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", out)
+}
+
+func (s *StockAdjustmentServiceImpl) List(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.FilterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if req.FilterBy == nil {
+		req.FilterBy = map[string]any{}
+	}
+	req.FilterBy["client_uuid"] = profile.Client.UUID
+	if !isOwnerRole(profile.Role.Value) {
+		req.FilterBy["branch_uuid"] = profile.Branch.UUID
+	}
+
+	data, err := s.repo.List(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to list stock adjustment", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", data)
+}
+
+func (s *StockAdjustmentServiceImpl) Detail(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	adj, ok := s.load(ctx, profile)
+	if !ok {
+		return
+	}
+	helpers.JsonOK(ctx, "success", adj)
+}
+
+// POST /stock-adjustments/:uuid/approve body: { "note": "" } (OWNER)
+func (s *StockAdjustmentServiceImpl) Approve(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	if !isOwnerRole(profile.Role.Value) {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("only OWNER can approve stock adjustment"))
+		return
+	}
+	var req dto.ApprovalDecisionRequest
+	_ = ctx.ShouldBindJSON(&req)
+
+	adj, ok := s.load(ctx, profile)
+	if !ok {
+		return
+	}
+
+	out, err := s.apply(adj, profile.UUID, bson.M{
+		"decided_by":      profile.UUID,
+		"decided_by_name": profile.Name,
+		"decision_note":   strings.TrimSpace(req.Note),
+		"decided_at":      time.Now().Unix(),
+	})
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to apply stock adjustment", http.StatusConflict, err)
+		return
+	}
+	s.notifyDecision(out, "Adjustment Stock Disetujui", "success", profile.Name)
+	helpers.JsonOK(ctx, "success", out)
+}
+
+// POST /stock-adjustments/:uuid/reject body: { "note": "" } (OWNER)
+func (s *StockAdjustmentServiceImpl) Reject(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	if !isOwnerRole(profile.Role.Value) {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("only OWNER can reject stock adjustment"))
+		return
+	}
+	var req dto.ApprovalDecisionRequest
+	_ = ctx.ShouldBindJSON(&req)
+
+	adj, ok := s.load(ctx, profile)
+	if !ok {
+		return
+	}
+
+	out, err := s.repo.SetStatus(adj.UUID, dao.StockAdjustmentPending, dao.StockAdjustmentRejected, bson.M{
+		"decided_by":      profile.UUID,
+		"decided_by_name": profile.Name,
+		"decision_note":   strings.TrimSpace(req.Note),
+		"decided_at":      time.Now().Unix(),
+	})
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to reject stock adjustment", http.StatusConflict, err)
+		return
+	}
+	s.notifyDecision(out, "Adjustment Stock Ditolak", "warning", profile.Name)
+	helpers.JsonOK(ctx, "success", out)
+}
+
+// apply: claim PENDING -> APPLIED lalu $inc per item lewat ledger.
+// Kalau satu item gagal (stock tidak cukup), item yang sudah jalan dibalik dan status jadi FAILED.
+func (s *StockAdjustmentServiceImpl) apply(adj dao.StockAdjustment, by string, extra bson.M) (dao.StockAdjustment, error) {
+	now := time.Now()
+	set := bson.M{"applied_at": now.Unix()}
+	for k, v := range extra {
+		set[k] = v
+	}
+	claimed, err := s.repo.SetStatus(adj.UUID, dao.StockAdjustmentPending, dao.StockAdjustmentApplied, set)
+	if err != nil {
+		return dao.StockAdjustment{}, err
+	}
+
+	note := string(adj.Reason)
+	if adj.Note != "" {
+		note += ": " + adj.Note
+	}
+	ref := dao.StockRef{
+		Reason:    dao.StockMoveAdjustment,
+		RefUUID:   adj.UUID,
+		RefNo:     adj.Code,
+		Note:      note,
+		CreatedBy: by,
+	}
+
+	rollback := ref
+	rollback.Note = "rollback adjustment"
+	items := claimed.Items
+	// hasil + stock/cost sebelum tiap item: rollback mengembalikan weighted-average cost juga
+	done := make([]dao.Product, 0, len(items))
+	befores := make([]dao.StockBefore, 0, len(items))
+	for i := range items {
+		p, before, err := s.prodRepo.AdjustStock(items[i].ProductUUID, items[i].Delta, items[i].Cost, ref)
+		if err == nil && p.TrackLots {
+			if items[i].Lots, err = s.adjustLots(p, items[i], adj.Code); err != nil {
+				_ = s.prodRepo.RevertStock(p, items[i].Delta, before, rollback)
+			}
+		}
+		if err != nil {
+			for j := range done {
+				_ = s.prodRepo.RevertStock(done[j], items[j].Delta, befores[j], rollback)
+				if items[j].Delta < 0 {
+					_ = s.lotRepo.Restore(items[j].Lots)
+				} else {
+					_ = s.lotRepo.Revert(items[j].Lots)
+				}
+			}
+			msg := fmt.Sprintf("%s: %v", items[i].Name, err)
+			_, _ = s.repo.SetStatus(adj.UUID, dao.StockAdjustmentApplied, dao.StockAdjustmentFailed, bson.M{"last_error": msg})
+			return dao.StockAdjustment{}, errors.New(msg)
+		}
+		items[i].BalanceAfter = p.Stock
+		done = append(done, p)
+		befores = append(befores, before)
+	}
+
+	return s.repo.SetStatus(adj.UUID, dao.StockAdjustmentApplied, dao.StockAdjustmentApplied, bson.M{"items": items})
+}
+
+// adjustLots: keluar => FEFO termasuk lot kedaluwarsa (EXPIRED/DAMAGED biasanya lot lama), masuk => 1 lot tujuan
+func (s *StockAdjustmentServiceImpl) adjustLots(p dao.Product, it dao.StockAdjustmentItem, code string) ([]dao.LotAllocation, error) {
+	if it.Delta < 0 {
+		return s.lotRepo.Allocate(p, -it.Delta, true)
+	}
+	lotNo := it.LotNo
+	if lotNo == "" {
+		lotNo = code
+	}
+	lot, err := s.lotRepo.Receive(p, lotNo, it.ExpiryDate, it.Delta)
+	if err != nil {
+		return nil, err
+	}
+	return []dao.LotAllocation{lot}, nil
+}
+
+func (s *StockAdjustmentServiceImpl) load(ctx *gin.Context, profile *dto.UserProfile) (dao.StockAdjustment, bool) {
+	uuid := strings.TrimSpace(ctx.Param("uuid"))
+	adj, err := s.repo.Detail(uuid)
+	if err != nil || adj.ClientUUID != profile.Client.UUID {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("stock adjustment not found"))
+		return dao.StockAdjustment{}, false
+	}
+	if !isOwnerRole(profile.Role.Value) && adj.BranchUUID != profile.Branch.UUID {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("stock adjustment belongs to another branch"))
+		return dao.StockAdjustment{}, false
+	}
+	return adj, true
+}
+
+func (s *StockAdjustmentServiceImpl) notifyDecision(adj dao.StockAdjustment, title, icon, by string) {
+	_, _ = s.notifRepo.Insert(&dao.Notification{
+		ClientUUID: adj.ClientUUID,
+		BranchUUID: adj.BranchUUID,
+		UserUUID:   adj.RequestedBy,
+		Title:      title,
+		Message:    fmt.Sprintf("%s • %s • oleh %s", adj.Code, adj.Reason, by),
+		Icon:       icon,
+		Type:       "STOCK_ADJUSTMENT",
+		Ref:        adj.UUID,
+	})
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+	"harjonan.id/user-service/app/repository"
+)
+
+// lot yang expiry-nya <= sekian hari lagi masuk laporan & notifikasi "segera kedaluwarsa"
+const lotExpiryWarnDays = 30
+
+type StockLotService interface {
+	List(ctx *gin.Context)
+	Expiring(ctx *gin.Context)
+
+	// NotifyExpiring: dipanggil worker, 1 notifikasi per branch per run
+	NotifyExpiring() (int, error)
+}
+
+type StockLotServiceImpl struct {
+	repo          repository.StockLotRepository
+	dashboardRepo repository.DashboardRepository
+	branchRepo    repository.ClientBranchRepository
+	authRepo      repository.AuthRepository
+	notifRepo     repository.NotificationRepository
+}
+
+func NewStockLotService(repo repository.StockLotRepository, dashboardRepo repository.DashboardRepository, branchRepo repository.ClientBranchRepository, authRepo repository.AuthRepository, notifRepo repository.NotificationRepository) *StockLotServiceImpl {
+	return &StockLotServiceImpl{repo: repo, dashboardRepo: dashboardRepo, branchRepo: branchRepo, authRepo: authRepo, notifRepo: notifRepo}
+}
+
+// scopeBranches: branch_uuid diisi => cek akses, kosong => semua branch client (OWNER) / branch sendiri
+func (s *StockLotServiceImpl) scopeBranches(ctx *gin.Context, profile *dto.UserProfile, branchUUID string) ([]string, bool) {
+	if branchUUID != "" {
+		if !requireBranchAccess(ctx, s.branchRepo, profile, branchUUID) {
+			return nil, false
+		}
+		return []string{branchUUID}, true
+	}
+	if !isOwnerRole(profile.Role.Value) {
+		return []string{profile.Branch.UUID}, true
+	}
+	branches, err := s.dashboardRepo.GetCompanyBranchUUIDs(profile.Client.UUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load branches", http.StatusInternalServerError, err)
+		return nil, false
+	}
+	return branches, true
+}
+
+// POST /stock-lots/fetch (filter_by product_uuid / branch_uuid / lot_no)
+func (s *StockLotServiceImpl) List(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.FilterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if req.FilterBy == nil {
+		req.FilterBy = map[string]any{}
+	}
+	branchUUID, _ := req.FilterBy["branch_uuid"].(string)
+	branches, ok := s.scopeBranches(ctx, profile, branchUUID)
+	if !ok {
+		return
+	}
+	req.FilterBy["branch_uuid"] = branches
+
+	data, err := s.repo.List(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to list stock lot", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", data)
+}
+
+// POST /stock-lots/expiring body: { "branch_uuid": "", "days": 30 }
+func (s *StockLotServiceImpl) Expiring(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.StockLotExpiringRequest
+	_ = ctx.ShouldBindJSON(&req)
+	if req.Days <= 0 {
+		req.Days = lotExpiryWarnDays
+	}
+	if req.Limit <= 0 || req.Limit > 1000 {
+		req.Limit = 200
+	}
+
+	branches, ok := s.scopeBranches(ctx, profile, req.BranchUUID)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	lots, err := s.repo.ListExpiring(branches, now.AddDate(0, 0, req.Days).Unix(), req.Limit)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load expiring lots", http.StatusInternalServerError, err)
+		return
+	}
+
+	rows := make([]dto.StockLotExpiringRow, 0, len(lots))
+	for _, l := range lots {
+		rows = append(rows, dto.StockLotExpiringRow{
+			StockLot: l,
+			DaysLeft: int(time.Unix(l.ExpiryDate, 0).Sub(now).Hours() / 24),
+			Expired:  l.Expired(now.Unix()),
+		})
+	}
+	helpers.JsonOK(ctx, "success", rows)
+}
+
+func (s *StockLotServiceImpl) NotifyExpiring() (int, error) {
+	now := time.Now()
+	lots, err := s.repo.ListToNotify(now.AddDate(0, 0, lotExpiryWarnDays).Unix(), now.Unix(), 500)
+	if err != nil {
+		return 0, err
+	}
+
+	type branchSum struct {
+		soon, expired []dao.StockLot
+	}
+	byBranch := map[string]*branchSum{}
+	order := []string{}
+	for _, l := range lots {
+		b := byBranch[l.BranchUUID]
+		if b == nil {
+			b = &branchSum{}
+			byBranch[l.BranchUUID] = b
+			order = append(order, l.BranchUUID)
+		}
+		if l.Expired(now.Unix()) {
+			b.expired = append(b.expired, l)
+		} else {
+			b.soon = append(b.soon, l)
+		}
+	}
+
+	sent := 0
+	for _, branchUUID := range order {
+		b := byBranch[branchUUID]
+		branch, err := s.branchRepo.DetailClientBranch(branchUUID)
+		if err != nil {
+			continue
+		}
+
+		title, icon, first := "Stock Segera Kedaluwarsa", "info", dao.StockLot{}
+		if len(b.expired) > 0 {
+			title, icon, first = "Stock Kedaluwarsa", "warning", b.expired[0]
+		} else {
+			first = b.soon[0]
+		}
+		msg := fmt.Sprintf("%d lot kedaluwarsa • %d lot kedaluwarsa ≤ %d hari • mis. %s lot %s (%s)",
+			len(b.expired), len(b.soon), lotExpiryWarnDays, first.Name, first.LotNo, helpers.FormatPOSDate(first.ExpiryDate))
+
+		_, err = s.notifRepo.Insert(&dao.Notification{
+			ClientUUID: branch.ClientUUID,
+			BranchUUID: branchUUID,
+			Title:      title,
+			Message:    msg,
+			Icon:       icon,
+			Type:       "STOCK_EXPIRY",
+			Ref:        branchUUID,
+		})
+		if err != nil {
+			return sent, err
+		}
+		for _, l := range b.expired {
+			_ = s.repo.MarkNotified(l.UUID, 2)
+		}
+		for _, l := range b.soon {
+			_ = s.repo.MarkNotified(l.UUID, 1)
+		}
+		sent++
+	}
+	return sent, nil
+}
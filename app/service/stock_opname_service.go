@@ -0,0 +1,420 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+	"harjonan.id/user-service/app/repository"
+)
+
+type StockOpnameService interface {
+	Start(ctx *gin.Context)
+	List(ctx *gin.Context)
+	Detail(ctx *gin.Context)
+	Count(ctx *gin.Context)
+	Variance(ctx *gin.Context)
+	Approve(ctx *gin.Context)
+	Cancel(ctx *gin.Context)
+}
+
+type StockOpnameServiceImpl struct {
+	repo         repository.StockOpnameRepository
+	prodRepo     repository.ProductRepository
+	movementRepo repository.StockMovementRepository
+	branchRepo   repository.ClientBranchRepository
+	authRepo     repository.AuthRepository
+	notifRepo    repository.NotificationRepository
+	lotRepo      repository.StockLotRepository
+}
+
+func NewStockOpnameService(repo repository.StockOpnameRepository, prodRepo repository.ProductRepository, movementRepo repository.StockMovementRepository, branchRepo repository.ClientBranchRepository, authRepo repository.AuthRepository, notifRepo repository.NotificationRepository, lotRepo repository.StockLotRepository) *StockOpnameServiceImpl {
+	return &StockOpnameServiceImpl{repo: repo, prodRepo: prodRepo, movementRepo: movementRepo, branchRepo: branchRepo, authRepo: authRepo, notifRepo: notifRepo, lotRepo: lotRepo}
+}
+
+// POST /stock-opnames/start body: { "branch_uuid": "", "note": "" }
+// stock sistem semua produk aktif di-snapshot saat sesi dibuka
+func (s *StockOpnameServiceImpl) Start(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.StockOpnameStartRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	branchUUID := strings.TrimSpace(req.BranchUUID)
+	if branchUUID == "" {
+		branchUUID = profile.Branch.UUID
+	}
+	if !requireBranchAccess(ctx, s.branchRepo, profile, branchUUID) {
+		return
+	}
+
+	op, err := s.repo.Start(&dao.StockOpname{
+		ClientUUID: profile.Client.UUID,
+		BranchUUID: branchUUID,
+		Note:       req.Note,
+		StartedBy:  profile.UUID,
+	})
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to start stock opname", http.StatusBadRequest, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", op)
+}
+
+func (s *StockOpnameServiceImpl) List(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.FilterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if req.FilterBy == nil {
+		req.FilterBy = map[string]any{}
+	}
+	req.FilterBy["client_uuid"] = profile.Client.UUID
+	if !isOwnerRole(profile.Role.Value) {
+		req.FilterBy["branch_uuid"] = profile.Branch.UUID
+	}
+
+	data, err := s.repo.List(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to list stock opname", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", data)
+}
+
+func (s *StockOpnameServiceImpl) Detail(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	op, ok := s.loadOpname(ctx, profile)
+	if !ok {
+		return
+	}
+	items, err := s.repo.ListItems(op.UUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load items", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", gin.H{
+		"opname": op,
+		"items":  items,
+	})
+}
+
+// POST /stock-opnames/:uuid/count body: { "code": "899...", "qty": 1 }
+// beberapa counter boleh scan paralel, qty dijumlah per produk
+func (s *StockOpnameServiceImpl) Count(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.StockOpnameCountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	code := strings.TrimSpace(req.Code)
+	if code == "" {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("code required"))
+		return
+	}
+	if req.Qty == 0 {
+		req.Qty = 1
+	}
+
+	op, ok := s.loadOpname(ctx, profile)
+	if !ok {
+		return
+	}
+	if op.Status != dao.StockOpnameOpen {
+		helpers.JsonErr[any](ctx, "invalid status", http.StatusConflict, errors.New("stock opname is "+string(op.Status)))
+		return
+	}
+
+	item, err := s.repo.FindItem(op.UUID, code)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("product not in stock opname snapshot"))
+		return
+	}
+
+	out, err := s.repo.AddCount(item.UUID, dao.StockOpnameCount{
+		CounterUUID: profile.UUID,
+		CounterName: profile.Name,
+		Qty:         req.Qty,
+	})
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to save count", http.StatusBadRequest, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", out)
+}
+
+// GET /stock-opnames/:uuid/variance
+func (s *StockOpnameServiceImpl) Variance(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	op, ok := s.loadOpname(ctx, profile)
+	if !ok {
+		return
+	}
+	res, _, err := s.computeVariance(op)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to compute variance", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", res)
+}
+
+// POST /stock-opnames/:uuid/approve (OWNER)
+// variance tiap produk diposting sebagai movement OPNAME. Item yang gagal (mis. stock tidak cukup)
+// dilaporkan dan sesi tetap OPEN, approve ulang hanya memposting item yang belum.
+func (s *StockOpnameServiceImpl) Approve(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	if !isOwnerRole(profile.Role.Value) {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("only OWNER can approve stock opname"))
+		return
+	}
+	op, ok := s.loadOpname(ctx, profile)
+	if !ok {
+		return
+	}
+	if op.Status != dao.StockOpnameOpen {
+		helpers.JsonErr[any](ctx, "invalid status", http.StatusConflict, errors.New("stock opname is "+string(op.Status)))
+		return
+	}
+
+	res, items, err := s.computeVariance(op)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to compute variance", http.StatusInternalServerError, err)
+		return
+	}
+
+	failed := make([]gin.H, 0)
+	for _, it := range items {
+		if !it.Counted || it.Posted {
+			continue
+		}
+		_ = s.repo.SaveItemResult(it)
+		if it.VarianceQty == 0 {
+			continue
+		}
+		if err := s.repo.ClaimItemPost(it.UUID); err != nil {
+			continue
+		}
+		ref := dao.StockRef{
+			Reason:    dao.StockMoveOpname,
+			RefUUID:   op.UUID,
+			RefNo:     op.Code,
+			Note:      "stock opname",
+			CreatedBy: profile.UUID,
+		}
+		p, before, err := s.prodRepo.AdjustStock(it.ProductUUID, it.VarianceQty, it.Cost, ref)
+		if err == nil && p.TrackLots {
+			// selisih kurang diambil FEFO (lot kedaluwarsa ikut), selisih lebih masuk lot bernomor kode opname
+			if it.VarianceQty < 0 {
+				_, err = s.lotRepo.Allocate(p, -it.VarianceQty, true)
+			} else {
+				_, err = s.lotRepo.Receive(p, op.Code, 0, it.VarianceQty)
+			}
+			if err != nil {
+				ref.Note = "rollback stock opname"
+				_ = s.prodRepo.RevertStock(p, it.VarianceQty, before, ref)
+			}
+		}
+		if err != nil {
+			_ = s.repo.UnclaimItemPost(it.UUID)
+			failed = append(failed, gin.H{"product_uuid": it.ProductUUID, "sku": it.SKU, "name": it.Name, "error": err.Error()})
+		}
+	}
+	if len(failed) > 0 {
+		ctx.JSON(http.StatusConflict, dto.APIResponse[gin.H]{
+			Error:   true,
+			Message: "some adjustments failed, stock opname stays OPEN",
+			Data:    gin.H{"failed": failed, "variance": res},
+		})
+		return
+	}
+
+	now := time.Now()
+	out, err := s.repo.SetStatus(op.UUID, dao.StockOpnameOpen, dao.StockOpnameApproved, bson.M{
+		"approved_by":     profile.UUID,
+		"approved_at":     now.Unix(),
+		"adjusted_items":  res.VarianceItems,
+		"variance_qty":    res.VarianceQty,
+		"variance_value":  res.VarianceValue,
+		"uncounted_items": len(res.Uncounted),
+	})
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to approve stock opname", http.StatusConflict, err)
+		return
+	}
+
+	_, _ = s.notifRepo.Insert(&dao.Notification{
+		ClientUUID: out.ClientUUID,
+		Title:      "Stock Opname Disetujui",
+		Message: fmt.Sprintf("%s • %d item selisih • nilai %s • %d item tidak dihitung",
+			out.Code, res.VarianceItems, newIDRCurrency(res.VarianceValue), len(res.Uncounted)),
+		Icon: "success",
+		Type: "STOCK_OPNAME",
+		Ref:  out.UUID,
+	})
+	helpers.JsonOK(ctx, "success", gin.H{
+		"opname":   out,
+		"variance": res,
+	})
+}
+
+// POST /stock-opnames/:uuid/cancel
+func (s *StockOpnameServiceImpl) Cancel(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	op, ok := s.loadOpname(ctx, profile)
+	if !ok {
+		return
+	}
+	if !isOwnerRole(profile.Role.Value) && op.StartedBy != profile.UUID {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("only OWNER or starter can cancel stock opname"))
+		return
+	}
+
+	items, err := s.repo.ListItems(op.UUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load items", http.StatusInternalServerError, err)
+		return
+	}
+	for _, it := range items {
+		if it.Posted {
+			helpers.JsonErr[any](ctx, "cannot cancel", http.StatusConflict, errors.New("some adjustments already posted, approve to finish"))
+			return
+		}
+	}
+
+	out, err := s.repo.SetStatus(op.UUID, dao.StockOpnameOpen, dao.StockOpnameCancelled, bson.M{"cancelled_by": profile.UUID})
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to cancel stock opname", http.StatusConflict, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", out)
+}
+
+// computeVariance: expected = snapshot + movement (sale, transfer, dll) setelah snapshot s/d hitungan
+// terakhir produk tsb. Jadi penjualan selama hitung tidak dianggap selisih.
+// Movement milik opname ini sendiri (hasil approve) tidak ikut.
+func (s *StockOpnameServiceImpl) computeVariance(op dao.StockOpname) (dto.StockOpnameVarianceResponse, []dao.StockOpnameItem, error) {
+	items, err := s.repo.ListItems(op.UUID)
+	if err != nil {
+		return dto.StockOpnameVarianceResponse{}, nil, err
+	}
+	movements, err := s.movementRepo.ListSince(op.BranchUUID, op.SnapshotAt, op.UUID)
+	if err != nil {
+		return dto.StockOpnameVarianceResponse{}, nil, err
+	}
+	return opnameVariance(items, movements), items, nil
+}
+
+// opnameVariance: isi movement/expected/variance di items (in place) + ringkasan.
+// movements harus urut created_at naik (hasil ListSince).
+func opnameVariance(items []dao.StockOpnameItem, movements []dao.StockMovement) dto.StockOpnameVarianceResponse {
+	res := dto.StockOpnameVarianceResponse{
+		Items:     []dto.StockOpnameVarianceRow{},
+		Uncounted: []dto.StockOpnameVarianceRow{},
+	}
+	byProduct := map[string][]dao.StockMovement{}
+	for _, m := range movements {
+		byProduct[m.ProductUUID] = append(byProduct[m.ProductUUID], m)
+	}
+
+	res.TotalItems = len(items)
+	for i := range items {
+		it := &items[i]
+		row := dto.StockOpnameVarianceRow{
+			ItemUUID:    it.UUID,
+			ProductUUID: it.ProductUUID,
+			SKU:         it.SKU,
+			Name:        it.Name,
+			BaseUnit:    it.BaseUnit,
+			Cost:        it.Cost,
+			SnapshotQty: it.SnapshotQty,
+			Posted:      it.Posted,
+		}
+		if !it.Counted {
+			row.ExpectedQty = it.SnapshotQty
+			res.Uncounted = append(res.Uncounted, row)
+			continue
+		}
+
+		var moved int64
+		for _, m := range byProduct[it.ProductUUID] {
+			if m.CreatedAt.After(it.LastCountedAt) {
+				break
+			}
+			moved += m.Delta
+		}
+		it.MovementQty = moved
+		it.ExpectedQty = it.SnapshotQty + moved
+		it.VarianceQty = it.CountedQty - it.ExpectedQty
+		it.VarianceValue = float64(it.VarianceQty) * it.Cost
+
+		row.MovementQty = it.MovementQty
+		row.ExpectedQty = it.ExpectedQty
+		row.CountedQty = it.CountedQty
+		row.VarianceQty = it.VarianceQty
+		row.VarianceValue = it.VarianceValue
+		res.Items = append(res.Items, row)
+
+		res.CountedItems++
+		if it.VarianceQty != 0 {
+			res.VarianceItems++
+			res.VarianceQty += it.VarianceQty
+			res.VarianceValue += it.VarianceValue
+			if it.VarianceValue < 0 {
+				res.ShortageValue += -it.VarianceValue
+			} else {
+				res.SurplusValue += it.VarianceValue
+			}
+		}
+	}
+	return res
+}
+
+func (s *StockOpnameServiceImpl) loadOpname(ctx *gin.Context, profile *dto.UserProfile) (dao.StockOpname, bool) {
+	uuid := strings.TrimSpace(ctx.Param("uuid"))
+	op, err := s.repo.Detail(uuid)
+	if err != nil || op.ClientUUID != profile.Client.UUID {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("stock opname not found"))
+		return dao.StockOpname{}, false
+	}
+	if !isOwnerRole(profile.Role.Value) && op.BranchUUID != profile.Branch.UUID {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("stock opname belongs to another branch"))
+		return dao.StockOpname{}, false
+	}
+	return op, true
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"harjonan.id/user-service/app/domain/dao"
+)
+
+func TestOpnameVariance(t *testing.T) {
+	snap := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	counted := snap.Add(2 * time.Hour)
+	move := func(product string, at time.Time, delta int64) dao.StockMovement {
+		return dao.StockMovement{BaseModel: dao.BaseModel{CreatedAt: at}, ProductUUID: product, Delta: delta}
+	}
+	item := func(snapshot, count int64) dao.StockOpnameItem {
+		return dao.StockOpnameItem{ProductUUID: "P1", Cost: 2500, SnapshotQty: snapshot, Counted: true, CountedQty: count, LastCountedAt: counted}
+	}
+
+	cases := []struct {
+		name         string
+		item         dao.StockOpnameItem
+		movements    []dao.StockMovement
+		wantMoved    int64
+		wantExpected int64
+		wantVariance int64
+		wantValue    float64
+	}{
+		{"match", item(10, 10), nil, 0, 10, 0, 0},
+		{"shortage", item(10, 7), nil, 0, 10, -3, -7500},
+		{"surplus", item(10, 12), nil, 0, 10, 2, 5000},
+		{"sale during count is not variance", item(10, 8), []dao.StockMovement{
+			move("P1", snap.Add(time.Hour), -2),
+		}, -2, 8, 0, 0},
+		{"movement at last count included", item(10, 11), []dao.StockMovement{
+			move("P1", counted, 1),
+		}, 1, 11, 0, 0},
+		{"movement after last count ignored", item(10, 10), []dao.StockMovement{
+			move("P1", snap.Add(time.Hour), -1),
+			move("P1", counted.Add(time.Minute), -5),
+		}, -1, 9, 1, 2500},
+		{"other product ignored", item(10, 10), []dao.StockMovement{
+			move("P2", snap.Add(time.Hour), -4),
+		}, 0, 10, 0, 0},
+	}
+	for _, c := range cases {
+		items := []dao.StockOpnameItem{c.item}
+		res := opnameVariance(items, c.movements)
+		it := items[0]
+		if it.MovementQty != c.wantMoved || it.ExpectedQty != c.wantExpected || it.VarianceQty != c.wantVariance || it.VarianceValue != c.wantValue {
+			t.Errorf("%s: want moved %d expected %d variance %d (%v), got %d %d %d (%v)", c.name,
+				c.wantMoved, c.wantExpected, c.wantVariance, c.wantValue,
+				it.MovementQty, it.ExpectedQty, it.VarianceQty, it.VarianceValue)
+		}
+		if len(res.Items) != 1 || res.Items[0].VarianceQty != c.wantVariance {
+			t.Errorf("%s: response row does not match item, got %+v", c.name, res.Items)
+		}
+	}
+}
+
+func TestOpnameVarianceSummary(t *testing.T) {
+	items := []dao.StockOpnameItem{
+		{ProductUUID: "P1", Cost: 1000, SnapshotQty: 10, Counted: true, CountedQty: 7}, // -3000
+		{ProductUUID: "P2", Cost: 500, SnapshotQty: 4, Counted: true, CountedQty: 6},   // +1000
+		{ProductUUID: "P3", Cost: 800, SnapshotQty: 5, Counted: true, CountedQty: 5},   // cocok
+		{ProductUUID: "P4", Cost: 900, SnapshotQty: 3},                                 // belum dihitung
+	}
+	res := opnameVariance(items, nil)
+
+	if res.TotalItems != 4 || res.CountedItems != 3 || res.VarianceItems != 2 {
+		t.Errorf("want total 4 counted 3 variance 2, got %d %d %d", res.TotalItems, res.CountedItems, res.VarianceItems)
+	}
+	if res.VarianceQty != -1 || res.VarianceValue != -2000 || res.ShortageValue != 3000 || res.SurplusValue != 1000 {
+		t.Errorf("want qty -1 value -2000 shortage 3000 surplus 1000, got %d %v %v %v",
+			res.VarianceQty, res.VarianceValue, res.ShortageValue, res.SurplusValue)
+	}
+	if len(res.Items) != 3 || len(res.Uncounted) != 1 || res.Uncounted[0].ExpectedQty != 3 {
+		t.Errorf("want 3 counted rows and 1 uncounted (expected = snapshot), got %+v / %+v", res.Items, res.Uncounted)
+	}
+}
@@ -3,12 +3,17 @@ package service
 import (
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
 	"harjonan.id/user-service/app/domain/dao"
 	"harjonan.id/user-service/app/domain/dto"
 	"harjonan.id/user-service/app/helpers"
+	"harjonan.id/user-service/app/infra/document"
 	"harjonan.id/user-service/app/repository"
 )
 
@@ -19,19 +24,38 @@ type StockTransferService interface {
 	WarehouseApprove(ctx *gin.Context)
 	DriverAccept(ctx *gin.Context)
 	ReceiveDone(ctx *gin.Context)
+	Reject(ctx *gin.Context)
+	Cancel(ctx *gin.Context)
+	DriverDecline(ctx *gin.Context)
+	DeliveryNote(ctx *gin.Context)
+
+	ListDiscrepancies(ctx *gin.Context)
+	DetailDiscrepancy(ctx *gin.Context)
+	ResolveDiscrepancy(ctx *gin.Context)
+	DiscrepancyReport(ctx *gin.Context)
+
+	// ExpireStale: dipanggil worker, return jumlah transfer yang kedaluwarsa
+	ExpireStale() (int, error)
 }
 
 type StockTransferServiceImpl struct {
-	repo      repository.StockTransferRepository
-	authRepo  repository.AuthRepository
-	notifRepo repository.NotificationRepository
+	repo       repository.StockTransferRepository
+	authRepo   repository.AuthRepository
+	notifRepo  repository.NotificationRepository
+	branchRepo repository.ClientBranchRepository
+	userRepo   repository.UserRepository
 }
 
-func NewStockTransferService(repo repository.StockTransferRepository, authRepo repository.AuthRepository, notifRepo repository.NotificationRepository) *StockTransferServiceImpl {
-	return &StockTransferServiceImpl{repo: repo, authRepo: authRepo, notifRepo: notifRepo}
+func NewStockTransferService(repo repository.StockTransferRepository, authRepo repository.AuthRepository, notifRepo repository.NotificationRepository, branchRepo repository.ClientBranchRepository, userRepo repository.UserRepository) *StockTransferServiceImpl {
+	return &StockTransferServiceImpl{repo: repo, authRepo: authRepo, notifRepo: notifRepo, branchRepo: branchRepo, userRepo: userRepo}
 }
 
 func (s *StockTransferServiceImpl) Create(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
 	var req dto.StockTransferCreateReq
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
@@ -52,7 +76,7 @@ func (s *StockTransferServiceImpl) Create(ctx *gin.Context) {
 		ToBranchUUID:   req.ToBranchUUID,
 		DriverUUID:     req.DriverUUID,
 		RequesterNote:  req.Notes,
-		RequestedBy:    req.RequestedBy,
+		RequestedBy:    profile.UUID, // peminta = user token (menentukan hak cancel)
 		Items:          []dao.StockTransferItem{},
 	}
 
@@ -64,11 +88,13 @@ func (s *StockTransferServiceImpl) Create(ctx *gin.Context) {
 		})
 	}
 
-	res, err := s.repo.CreateDraft(&tr)
+	res, err := s.repo.CreateDraft(&tr, transferActor(profile))
 	if err != nil {
-		helpers.JsonErr[any](ctx, "failed to create stock transfer", http.StatusInternalServerError, err)
+		helpers.JsonErr[any](ctx, "failed to create stock transfer", transferErrStatus(err), err)
 		return
 	}
+
+	_ = s.createStockTransferNotifs(profile.Client.UUID, res, string(dao.StockTransferEventRequest))
 	helpers.JsonOK(ctx, "success", res)
 }
 
@@ -125,16 +151,16 @@ func (s *StockTransferServiceImpl) WarehouseApprove(ctx *gin.Context) {
 		return
 	}
 
-	res, err := s.repo.WarehouseApprove(uuid, req.Notes, profile.UUID)
+	res, err := s.repo.WarehouseApprove(uuid, req.Notes, transferActor(profile))
 	if err != nil {
-		helpers.JsonErr[any](ctx, "failed to approve (warehouse)", http.StatusBadRequest, err)
+		helpers.JsonErr[any](ctx, "failed to approve (warehouse)", transferErrStatus(err), err)
 		return
 	}
 
 	// ✅ Create notifications (gudang/from, driver personal, to branch)
-	err = s.createStockTransferNotifs(profile.Client.UUID, res, "WAREHOUSE_APPROVED")
+	err = s.createStockTransferNotifs(profile.Client.UUID, res, string(dao.StockTransferEventApprove))
 	if err != nil {
-		fmt.Printf("failed to create notification: %v\n", err)
+		log.Printf("stock transfer %s notification %s: %v", res.UUID, dao.StockTransferEventApprove, err)
 		// notifikasi gagal tidak kita anggap fatal, jadi tetap return success
 	}
 
@@ -166,15 +192,15 @@ func (s *StockTransferServiceImpl) DriverAccept(ctx *gin.Context) {
 		return
 	}
 
-	res, err := s.repo.DriverAccept(uuid, req.Notes, profile.UUID)
+	res, err := s.repo.DriverAccept(uuid, req.Notes, transferActor(profile))
 	if err != nil {
-		helpers.JsonErr[any](ctx, "failed to accept (driver)", http.StatusBadRequest, err)
+		helpers.JsonErr[any](ctx, "failed to accept (driver)", transferErrStatus(err), err)
 		return
 	}
 
-	err = s.createStockTransferNotifs(profile.Client.UUID, res, "DRIVER_ACCEPTED")
+	err = s.createStockTransferNotifs(profile.Client.UUID, res, string(dao.StockTransferEventDriverAccept))
 	if err != nil {
-		fmt.Printf("failed to create notification: %v\n", err)
+		log.Printf("stock transfer %s notification %s: %v", res.UUID, dao.StockTransferEventDriverAccept, err)
 		// notifikasi gagal tidak kita anggap fatal, jadi tetap return success
 	}
 
@@ -206,21 +232,181 @@ func (s *StockTransferServiceImpl) ReceiveDone(ctx *gin.Context) {
 		return
 	}
 
-	res, err := s.repo.ReceiveDone(uuid, req.Notes, profile.UUID)
+	res, err := s.repo.ReceiveDone(uuid, req, profile.Client.UUID, transferActor(profile))
 	if err != nil {
-		helpers.JsonErr[any](ctx, "failed to receive (cashier)", http.StatusBadRequest, err)
+		helpers.JsonErr[any](ctx, "failed to receive (cashier)", transferErrStatus(err), err)
 		return
 	}
 
-	err = s.createStockTransferNotifs(profile.Client.UUID, res, "RECEIVE_DONE")
+	event := string(dao.StockTransferEventReceive)
+	if res.HasDiscrepancy {
+		event = "RECEIVE_PARTIAL"
+		s.notifyOwnerDiscrepancy(profile.Client.UUID, res)
+	}
+	err = s.createStockTransferNotifs(profile.Client.UUID, res, event)
 	if err != nil {
-		fmt.Printf("failed to create notification: %v\n", err)
+		log.Printf("stock transfer %s notification %s: %v", res.UUID, event, err)
 		// notifikasi gagal tidak kita anggap fatal, jadi tetap return success
 	}
 
 	helpers.JsonOK(ctx, "success", res)
 }
 
+// POST /stock-transfers/:uuid/reject body: { "reason": "" } (GUDANG / OWNER, sebelum driver jalan)
+func (s *StockTransferServiceImpl) Reject(ctx *gin.Context) {
+	s.close(ctx, dao.StockTransferEventReject)
+}
+
+// POST /stock-transfers/:uuid/cancel body: { "reason": "" } (peminta / OWNER, sebelum driver jalan)
+func (s *StockTransferServiceImpl) Cancel(ctx *gin.Context) {
+	s.close(ctx, dao.StockTransferEventCancel)
+}
+
+// POST /stock-transfers/:uuid/driver-decline body: { "reason": "" } (driver yang ditugaskan)
+func (s *StockTransferServiceImpl) DriverDecline(ctx *gin.Context) {
+	s.close(ctx, dao.StockTransferEventDriverDecline)
+}
+
+// close: transfer berhenti sebelum barang jalan, stock yang sudah dipotong gudang kembali otomatis.
+// Role + guard (GUDANG / peminta / driver ditugaskan) dicek state machine.
+func (s *StockTransferServiceImpl) close(ctx *gin.Context, ev dao.StockTransferEvent) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.StockTransferCloseReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("reason required"))
+		return
+	}
+
+	res, err := s.repo.Close(strings.TrimSpace(ctx.Param("uuid")), ev, req.Reason, transferActor(profile))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to close stock transfer", transferErrStatus(err), err)
+		return
+	}
+
+	_ = s.createStockTransferNotifs(profile.Client.UUID, res, string(ev))
+	helpers.JsonOK(ctx, "success", res)
+}
+
+// GET /stock-transfers/:uuid/delivery-note: surat jalan PDF, tersedia sejak WAITING_DRIVER
+// (OWNER, branch asal / tujuan, driver yang ditugaskan)
+func (s *StockTransferServiceImpl) DeliveryNote(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	tr, err := s.repo.Detail(strings.TrimSpace(ctx.Param("uuid")))
+	if err != nil {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("stock transfer not found"))
+		return
+	}
+	from, err := s.branchRepo.DetailClientBranch(tr.FromBranchUUID)
+	if err != nil || from.ClientUUID != profile.Client.UUID {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("stock transfer not found"))
+		return
+	}
+	if !isOwnerRole(profile.Role.Value) && profile.UUID != tr.DriverUUID &&
+		profile.Branch.UUID != tr.FromBranchUUID && profile.Branch.UUID != tr.ToBranchUUID {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("cannot access another branch"))
+		return
+	}
+	switch tr.Status {
+	case dao.StockTransferWaitingDriver, dao.StockTransferInProgress, dao.StockTransferDone:
+	default:
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("delivery note available after warehouse approve"))
+		return
+	}
+
+	to, err := s.branchRepo.DetailClientBranch(tr.ToBranchUUID)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load destination branch", http.StatusInternalServerError, err)
+		return
+	}
+	// driver tidak ketemu: surat jalan tetap dicetak dengan placeholder (DriverName kosong)
+	driver, err := s.userRepo.DetailUser(tr.DriverUUID)
+	if err != nil {
+		log.Printf("stock transfer %s delivery note: driver %s: %v", tr.UUID, tr.DriverUUID, err)
+	}
+	approver, _ := s.userRepo.DetailUser(tr.ApprovedBy)
+
+	branding := clientBranding(profile.Client)
+	branding.BranchName = from.Name
+	branding.Address = from.Address
+
+	out, err := document.RenderDeliveryNotePDF(document.DeliveryNoteDoc{
+		Transfer:    tr,
+		Branding:    branding,
+		From:        from,
+		To:          to,
+		DriverName:  driver.Name,
+		DriverPhone: driver.PhoneNumber,
+		ReceiveURL:  helpers.ProvideAppURL() + "/stock-transfers/" + tr.UUID + "/receive",
+		IssuedBy:    approver.Name,
+	})
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to render delivery note", http.StatusInternalServerError, err)
+		return
+	}
+	filename := strings.ReplaceAll(document.DeliveryNoteNo(tr), "/", "-")
+	ctx.Header("Content-Disposition", `inline; filename="`+filename+`.pdf"`)
+	ctx.Data(http.StatusOK, "application/pdf", out)
+}
+
+func transferActor(profile *dto.UserProfile) dao.StockTransferActor {
+	return dao.StockTransferActor{UserUUID: profile.UUID, Role: profile.Role.Value, BranchUUID: profile.Branch.UUID}
+}
+
+// transferErrStatus: data salah => 400, role / actor => 403, status tidak cocok (atau keburu berubah) => 409
+func transferErrStatus(err error) int {
+	switch {
+	case errors.Is(err, dao.ErrStockTransferInvalid):
+		return http.StatusBadRequest
+	case errors.Is(err, dao.ErrStockTransferForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, dao.ErrStockTransferState):
+		return http.StatusConflict
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+const transferExpireBatch = 200
+
+func (s *StockTransferServiceImpl) ExpireStale() (int, error) {
+	ttl := helpers.ProvideTransferExpiry()
+	list, err := s.repo.ExpireStale(time.Now().Add(-ttl).Unix(),
+		fmt.Sprintf("tidak diproses dalam %s", ttl), transferExpireBatch)
+	if err != nil {
+		return 0, err
+	}
+	for _, tr := range list {
+		branch, err := s.branchRepo.DetailClientBranch(tr.FromBranchUUID)
+		if err != nil {
+			continue
+		}
+		_ = s.createStockTransferNotifs(branch.ClientUUID, tr, string(dao.StockTransferEventExpire))
+	}
+
+	// transfer tertutup yang stock-nya belum kembali (gagal di tengah)
+	if _, err := s.repo.RestorePending(transferExpireBatch); err != nil {
+		return len(list), err
+	}
+	return len(list), nil
+}
+
 // =====================================================
 // NOTIF BUILDER (3 target):
 // - from_branch_uuid (gudang)
@@ -242,18 +428,42 @@ func (s *StockTransferServiceImpl) createStockTransferNotifs(clientUUID string,
 	icon := "info"
 
 	switch event {
-	case "WAREHOUSE_APPROVED":
+	case string(dao.StockTransferEventRequest):
+		title = "Stock Transfer Request"
+		msg = fmt.Sprintf("Request transfer #%s (%d item) menunggu approve gudang.", shortRef(tr.UUID), len(tr.Items))
+		icon = "info"
+	case string(dao.StockTransferEventApprove):
 		title = "Stock Transfer Approved"
-		msg = fmt.Sprintf("Transfer #%s sudah di-approve. Silakan cek gudang tujuan.", shortRef(tr.UUID))
+		msg = fmt.Sprintf("Transfer #%s sudah di-approve. Surat jalan %s siap dicetak.", shortRef(tr.UUID), document.DeliveryNoteNo(tr))
 		icon = "success"
-	case "DRIVER_ACCEPTED":
+	case string(dao.StockTransferEventDriverAccept):
 		title = "Driver Accepted Job"
 		msg = fmt.Sprintf("Driver menerima job transfer #%s. Status: IN_PROGRESS.", shortRef(tr.UUID))
 		icon = "info"
-	case "RECEIVE_DONE":
+	case string(dao.StockTransferEventReceive):
 		title = "Stock Transfer Received"
 		msg = fmt.Sprintf("Transfer #%s sudah diterima oleh cabang tujuan. Status: DONE.", shortRef(tr.UUID))
 		icon = "success"
+	case "RECEIVE_PARTIAL":
+		title = "Stock Transfer Partially Received"
+		msg = fmt.Sprintf("Transfer #%s diterima sebagian, ada barang rusak / hilang. Gudang perlu memutuskan return / write-off / kirim ulang.", shortRef(tr.UUID))
+		icon = "warning"
+	case string(dao.StockTransferEventReject):
+		title = "Stock Transfer Rejected"
+		msg = fmt.Sprintf("Transfer #%s ditolak gudang: %s.%s", shortRef(tr.UUID), tr.CloseReason, restoredNote(tr))
+		icon = "warning"
+	case string(dao.StockTransferEventCancel):
+		title = "Stock Transfer Cancelled"
+		msg = fmt.Sprintf("Transfer #%s dibatalkan: %s.%s", shortRef(tr.UUID), tr.CloseReason, restoredNote(tr))
+		icon = "warning"
+	case string(dao.StockTransferEventDriverDecline):
+		title = "Driver Declined Job"
+		msg = fmt.Sprintf("Driver menolak job transfer #%s: %s.%s", shortRef(tr.UUID), tr.CloseReason, restoredNote(tr))
+		icon = "warning"
+	case string(dao.StockTransferEventExpire):
+		title = "Stock Transfer Expired"
+		msg = fmt.Sprintf("Transfer #%s kedaluwarsa, %s.%s", shortRef(tr.UUID), tr.CloseReason, restoredNote(tr))
+		icon = "warning"
 	default:
 		title = "Stock Transfer Update"
 		msg = fmt.Sprintf("Update transfer #%s.", shortRef(tr.UUID))
@@ -302,6 +512,230 @@ func (s *StockTransferServiceImpl) createStockTransferNotifs(clientUUID string,
 	return nil
 }
 
+// -------------------------------
+// Discrepancy (selisih in-transit)
+// -------------------------------
+
+// POST /stock-transfers/discrepancies/fetch: selain OWNER, GUDANG lihat selisih kiriman branch-nya, lainnya selisih yang diterima branch-nya
+func (s *StockTransferServiceImpl) ListDiscrepancies(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	var req dto.FilterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if req.FilterBy == nil {
+		req.FilterBy = map[string]any{}
+	}
+	req.FilterBy["client_uuid"] = profile.Client.UUID
+	switch {
+	case isOwnerRole(profile.Role.Value):
+	case profile.Role.Value == "GUDANG":
+		req.FilterBy["from_branch_uuid"] = profile.Branch.UUID
+	default:
+		req.FilterBy["to_branch_uuid"] = profile.Branch.UUID
+	}
+
+	res, err := s.repo.ListDiscrepancies(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to list discrepancy", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", res)
+}
+
+func (s *StockTransferServiceImpl) DetailDiscrepancy(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	d, ok := s.loadDiscrepancy(ctx, profile)
+	if !ok {
+		return
+	}
+	helpers.JsonOK(ctx, "success", d)
+}
+
+// POST /stock-transfers/discrepancies/:uuid/resolve body: { "resolution": "RETURN|WRITE_OFF|RESHIP", "note": "", "driver_uuid": "" }
+// GUDANG branch asal / OWNER. RESHIP membuat transfer baru (PENDING_WAREHOUSE, stock dipotong lagi saat approve).
+func (s *StockTransferServiceImpl) ResolveDiscrepancy(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	d, ok := s.loadDiscrepancy(ctx, profile)
+	if !ok {
+		return
+	}
+	if !isOwnerRole(profile.Role.Value) && (profile.Role.Value != "GUDANG" || profile.Branch.UUID != d.FromBranchUUID) {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("only GUDANG of from_branch or OWNER can resolve"))
+		return
+	}
+
+	var req dto.StockDiscrepancyResolveReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	resolution := dao.StockDiscrepancyResolution(strings.TrimSpace(strings.ToUpper(req.Resolution)))
+	note := strings.TrimSpace(req.Note)
+	switch resolution {
+	case dao.DiscrepancyReturn, dao.DiscrepancyWriteOff, dao.DiscrepancyReship:
+	default:
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("resolution must be RETURN/WRITE_OFF/RESHIP"))
+		return
+	}
+	if d.Status != dao.DiscrepancyOpen {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("discrepancy already resolved"))
+		return
+	}
+
+	var reship dao.StockTransfer
+	if resolution == dao.DiscrepancyReship {
+		driver := strings.TrimSpace(req.DriverUUID)
+		if driver == "" {
+			driver = d.DriverUUID
+		}
+		var err error
+		reship, err = s.repo.CreateDraft(&dao.StockTransfer{
+			FromBranchUUID: d.FromBranchUUID,
+			ToBranchUUID:   d.ToBranchUUID,
+			DriverUUID:     driver,
+			RequestedBy:    profile.UUID,
+			RequesterNote:  fmt.Sprintf("kirim ulang selisih transfer #%s", shortRef(d.TransferUUID)),
+			ReshipOf:       d.TransferUUID,
+			Items:          []dao.StockTransferItem{{ProductUUID: d.ProductUUID, Qty: d.Qty}},
+		}, transferActor(profile))
+		if err != nil {
+			helpers.JsonErr[any](ctx, "failed to create reship transfer", http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	out, err := s.repo.ResolveDiscrepancy(d.UUID, resolution, note, profile.UUID, reship.UUID)
+	if err != nil {
+		if reship.UUID != "" {
+			_, _ = s.repo.Close(reship.UUID, dao.StockTransferEventCancel, "discrepancy resolve failed", transferActor(profile))
+		}
+		helpers.JsonErr[any](ctx, "failed to resolve discrepancy", http.StatusBadRequest, err)
+		return
+	}
+
+	msg := fmt.Sprintf("Selisih %s (%d %s) transfer #%s: %s oleh %s.", out.Name, out.Qty, out.BaseUnit,
+		shortRef(out.TransferUUID), out.Resolution, profile.Name)
+	if reship.UUID != "" {
+		_ = s.createStockTransferNotifs(profile.Client.UUID, reship, string(dao.StockTransferEventRequest))
+		msg += fmt.Sprintf(" Transfer kirim ulang #%s.", shortRef(reship.UUID))
+	}
+	// BranchUUID kosong => hanya owner yang lihat
+	_, _ = s.notifRepo.Insert(&dao.Notification{
+		ClientUUID: profile.Client.UUID,
+		Title:      "Selisih Transfer Diselesaikan",
+		Message:    msg,
+		Icon:       "info",
+		Type:       "STOCK_TRANSFER",
+		Ref:        out.TransferUUID,
+	})
+	helpers.JsonOK(ctx, "success", out)
+}
+
+// POST /stock-transfers/discrepancies/report body: ReportRequest (date_from, date_to, limit) — OWNER / GUDANG
+func (s *StockTransferServiceImpl) DiscrepancyReport(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	if !isOwnerRole(profile.Role.Value) && profile.Role.Value != "GUDANG" {
+		helpers.JsonErr[any](ctx, "forbidden", http.StatusForbidden, errors.New("only OWNER or GUDANG can view discrepancy report"))
+		return
+	}
+
+	var req dto.ReportRequest
+	_ = ctx.ShouldBindJSON(&req)
+	from, to, err := helpers.ParseDateRange(req.DateFrom, req.DateTo, 30)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("date_from/date_to must be YYYY-MM-DD"))
+		return
+	}
+	if !to.After(from) {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("date_to must be >= date_from"))
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 500 {
+		req.Limit = 100
+	}
+
+	rows, err := s.repo.DiscrepancyReport(profile.Client.UUID, from, to, req.Limit)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to load report", http.StatusInternalServerError, err)
+		return
+	}
+
+	total := dto.StockDiscrepancyReportRow{}
+	for _, row := range rows {
+		total.Transfers += row.Transfers
+		total.Items += row.Items
+		total.Damaged += row.Damaged
+		total.Missing += row.Missing
+		total.Value += row.Value
+		total.Open += row.Open
+		total.Returned += row.Returned
+		total.WriteOff += row.WriteOff
+		total.Reship += row.Reship
+	}
+	helpers.JsonOK(ctx, "success", dto.StockDiscrepancyReport{
+		DateFrom: helpers.FormatDateISO(from.Unix()),
+		DateTo:   helpers.FormatDateISO(to.Add(-time.Second).Unix()),
+		Total:    total,
+		Rows:     rows,
+	})
+}
+
+func (s *StockTransferServiceImpl) loadDiscrepancy(ctx *gin.Context, profile *dto.UserProfile) (dao.StockTransferDiscrepancy, bool) {
+	d, err := s.repo.DetailDiscrepancy(strings.TrimSpace(ctx.Param("uuid")))
+	if err != nil || d.ClientUUID != profile.Client.UUID ||
+		(!isOwnerRole(profile.Role.Value) && profile.Branch.UUID != d.FromBranchUUID && profile.Branch.UUID != d.ToBranchUUID) {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("discrepancy not found"))
+		return dao.StockTransferDiscrepancy{}, false
+	}
+	return d, true
+}
+
+// notifyOwnerDiscrepancy: ringkasan barang rusak / hilang per transfer untuk owner
+func (s *StockTransferServiceImpl) notifyOwnerDiscrepancy(clientUUID string, tr dao.StockTransfer) {
+	var damaged, missing int64
+	var value float64
+	for _, it := range tr.Items {
+		damaged += it.DamagedQty
+		missing += it.MissingQty
+		value += float64(it.DamagedQty+it.MissingQty) * it.Cost
+	}
+	// BranchUUID kosong => hanya owner yang lihat
+	_, _ = s.notifRepo.Insert(&dao.Notification{
+		ClientUUID: clientUUID,
+		Title:      "Selisih Stock Transfer",
+		Message: fmt.Sprintf("Transfer #%s: %d rusak, %d hilang (± Rp %s). Menunggu keputusan gudang.",
+			shortRef(tr.UUID), damaged, missing, helpers.FormatIDR(value)),
+		Icon: "warning",
+		Type: "STOCK_TRANSFER",
+		Ref:  tr.UUID,
+	})
+}
+
+// restoredNote: info stock kembali ke gudang (hanya kalau sudah dipotong saat approve)
+func restoredNote(tr dao.StockTransfer) string {
+	if tr.ApprovedAt == 0 {
+		return ""
+	}
+	if tr.StockRestored {
+		return " Stock sudah dikembalikan ke gudang."
+	}
+	return " Stock sedang dikembalikan ke gudang."
+}
+
 func shortRef(u string) string {
 	if u == "" {
 		return "-"
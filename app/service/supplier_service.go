@@ -0,0 +1,161 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"harjonan.id/user-service/app/domain/dao"
+	"harjonan.id/user-service/app/domain/dto"
+	"harjonan.id/user-service/app/helpers"
+	"harjonan.id/user-service/app/repository"
+)
+
+type SupplierService interface {
+	Upsert(ctx *gin.Context)
+	List(ctx *gin.Context)
+	Detail(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+	Prices(ctx *gin.Context)
+}
+
+type SupplierServiceImpl struct {
+	repo     repository.SupplierRepository
+	authRepo repository.AuthRepository
+}
+
+func NewSupplierService(repo repository.SupplierRepository, authRepo repository.AuthRepository) *SupplierServiceImpl {
+	return &SupplierServiceImpl{repo: repo, authRepo: authRepo}
+}
+
+func (s *SupplierServiceImpl) Upsert(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dao.Supplier
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	req.Code = strings.TrimSpace(strings.ToUpper(req.Code))
+	req.Phone = strings.TrimSpace(req.Phone)
+	req.Email = strings.TrimSpace(req.Email)
+	if req.Name == "" {
+		helpers.JsonErr[any](ctx, "missing identifier", http.StatusBadRequest, errors.New("name required"))
+		return
+	}
+	if req.PaymentTermDays < 0 {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, errors.New("payment_term_days must be >= 0"))
+		return
+	}
+
+	if req.UUID != "" {
+		existing, err := s.repo.DetailSupplier(req.UUID)
+		if err != nil || existing.ClientUUID != profile.Client.UUID {
+			helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("supplier not found"))
+			return
+		}
+	}
+
+	req.ClientUUID = profile.Client.UUID
+	req.CreatedBy = profile.UUID
+
+	res, err := s.repo.SaveSupplier(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to save supplier", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", res)
+}
+
+func (s *SupplierServiceImpl) List(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+
+	var req dto.FilterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if req.FilterBy == nil {
+		req.FilterBy = map[string]any{}
+	}
+	req.FilterBy["client_uuid"] = profile.Client.UUID
+
+	data, err := s.repo.ListSupplier(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to list supplier", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", data)
+}
+
+func (s *SupplierServiceImpl) Detail(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	sp, err := s.repo.DetailSupplier(strings.TrimSpace(ctx.Param("uuid")))
+	if err != nil || sp.ClientUUID != profile.Client.UUID {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("supplier not found"))
+		return
+	}
+	helpers.JsonOK(ctx, "success", sp)
+}
+
+func (s *SupplierServiceImpl) Delete(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	uuid := strings.TrimSpace(ctx.Param("uuid"))
+	sp, err := s.repo.DetailSupplier(uuid)
+	if err != nil || sp.ClientUUID != profile.Client.UUID {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("supplier not found"))
+		return
+	}
+	if err := s.repo.DeleteSupplier(uuid); err != nil {
+		helpers.JsonErr[any](ctx, "failed to delete supplier", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK[struct{}](ctx, "success", struct{}{})
+}
+
+// POST /suppliers/:uuid/prices body: FilterRequest (filter_by.product_uuid, pagination)
+// histori harga beli dari goods receipt, default terbaru dulu
+func (s *SupplierServiceImpl) Prices(ctx *gin.Context) {
+	profile, ok := requireClientProfile(ctx, s.authRepo)
+	if !ok {
+		return
+	}
+	uuid := strings.TrimSpace(ctx.Param("uuid"))
+	sp, err := s.repo.DetailSupplier(uuid)
+	if err != nil || sp.ClientUUID != profile.Client.UUID {
+		helpers.JsonErr[any](ctx, "not found", http.StatusNotFound, errors.New("supplier not found"))
+		return
+	}
+
+	var req dto.FilterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.JsonErr[any](ctx, "invalid request", http.StatusBadRequest, err)
+		return
+	}
+	if req.FilterBy == nil {
+		req.FilterBy = map[string]any{}
+	}
+	req.FilterBy["supplier_uuid"] = sp.UUID
+
+	data, err := s.repo.ListPrices(&req)
+	if err != nil {
+		helpers.JsonErr[any](ctx, "failed to list supplier price", http.StatusInternalServerError, err)
+		return
+	}
+	helpers.JsonOK(ctx, "success", data)
+}
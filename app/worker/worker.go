@@ -0,0 +1,110 @@
+package worker
+
+import (
+	"log"
+	"time"
+
+	"harjonan.id/user-service/app/config"
+)
+
+// Start: job periodik di background (reminder, dsb).
+// Dipanggil sekali dari main setelah config.Init().
+func Start(init *config.Initialization) {
+	every(time.Hour, "receivable-reminder", func() {
+		n, err := init.ReceivableSvc.SendDueReminders()
+		if err != nil {
+			log.Printf("worker receivable-reminder: %v", err)
+			return
+		}
+		if n > 0 {
+			log.Printf("worker receivable-reminder: %d reminder sent", n)
+		}
+	})
+
+	every(time.Hour, "stock-expiry", func() {
+		n, err := init.StockLotSvc.NotifyExpiring()
+		if err != nil {
+			log.Printf("worker stock-expiry: %v", err)
+			return
+		}
+		if n > 0 {
+			log.Printf("worker stock-expiry: %d notification sent", n)
+		}
+	})
+
+	every(time.Hour, "low-stock", func() {
+		n, err := init.ReplenishmentSvc.NotifyLowStock()
+		if err != nil {
+			log.Printf("worker low-stock: %v", err)
+			return
+		}
+		if n > 0 {
+			log.Printf("worker low-stock: %d notification sent", n)
+		}
+	})
+
+	every(time.Hour, "stock-transfer-expiry", func() {
+		n, err := init.StockTransferSvc.ExpireStale()
+		if err != nil {
+			log.Printf("worker stock-transfer-expiry: %v", err)
+			return
+		}
+		if n > 0 {
+			log.Printf("worker stock-transfer-expiry: %d transfer expired", n)
+		}
+	})
+
+	every(time.Minute, "product-import", func() {
+		n, err := init.ProductImportSvc.ProcessJobs()
+		if err != nil {
+			log.Printf("worker product-import: %v", err)
+			return
+		}
+		if n > 0 {
+			log.Printf("worker product-import: %d job processed", n)
+		}
+	})
+
+	every(time.Minute, "price-change", func() {
+		n, err := init.PriceChangeSvc.ApplyDue()
+		if err != nil {
+			log.Printf("worker price-change: %v", err)
+			return
+		}
+		if n > 0 {
+			log.Printf("worker price-change: %d price change applied", n)
+		}
+	})
+
+	every(time.Minute, "email-outbox", func() {
+		n, err := init.ReceiptSvc.ProcessEmailOutbox()
+		if err != nil {
+			log.Printf("worker email-outbox: %v", err)
+			return
+		}
+		if n > 0 {
+			log.Printf("worker email-outbox: %d email sent", n)
+		}
+	})
+}
+
+// every: jalankan job langsung lalu tiap interval. Panic di job tidak mematikan service.
+func every(interval time.Duration, name string, job func()) {
+	run := func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("worker %s panic: %v", name, r)
+			}
+		}()
+		job()
+	}
+
+	go func() {
+		run()
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for range t.C {
+			run()
+		}
+	}()
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/joho/godotenv"
 	"harjonan.id/user-service/app/config"
 	"harjonan.id/user-service/app/router"
+	"harjonan.id/user-service/app/worker"
 )
 
 func init() {
@@ -30,6 +31,7 @@ func main() {
 
 	init := config.Init()
 	app := router.Init(init)
+	worker.Start(init)
 
 	app.Run(":" + port)
 }